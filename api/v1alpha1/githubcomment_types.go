@@ -0,0 +1,92 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubCommentSpec defines the desired state of GithubComment. The parent issue is given either by issueRef,
+// or by repo and issueNumber.
+// +kubebuilder:validation:XValidation:rule="has(self.issueRef) != has(self.repo)",message="exactly one of issueRef and repo must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.repo) == has(self.issueNumber)",message="repo and issueNumber must be set together"
+type GithubCommentSpec struct {
+	// IssueRef names the GithubIssue in the GithubComment namespace the comment is posted on
+	// +optional
+	IssueRef *corev1.LocalObjectReference `json:"issueRef,omitempty"`
+	// Repo URL of the repository of the parent issue, for issues not managed by a GithubIssue
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	Repo string `json:"repo,omitempty"`
+	// IssueNumber of the parent issue in Repo
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	IssueNumber int `json:"issueNumber,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Body of the comment. A comment edited on GitHub is no longer updated from the spec.
+	Body string `json:"body"`
+}
+
+// GithubCommentStatus defines the observed state of GithubComment.
+type GithubCommentStatus struct {
+	// Conditions represent the latest available observations of the comment's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Repo URL of the repository the comment was posted in
+	// +optional
+	Repo string `json:"repo,omitempty"`
+	// IssueNumber of the issue the comment was posted on
+	// +optional
+	IssueNumber int `json:"issueNumber,omitempty"`
+	// CommentID is the ID of the upstream comment
+	// +optional
+	CommentID int64 `json:"commentID,omitempty"`
+	// Hash of the body last written upstream
+	// +optional
+	Hash string `json:"hash,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Issue",type=string,JSONPath=".spec.issueRef.name"
+// +kubebuilder:printcolumn:name="Number",type=integer,JSONPath=".status.issueNumber"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// GithubComment is the Schema for the githubcomments API. It manages a single comment on an issue, so several
+// teams or controllers can each own their comments. Deleting it deletes the comment unless it was edited on GitHub.
+type GithubComment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubCommentSpec   `json:"spec,omitempty"`
+	Status GithubCommentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubCommentList contains a list of GithubComment.
+type GithubCommentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubComment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubComment{}, &GithubCommentList{})
+}
@@ -17,39 +17,476 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // GithubIssueSpec defines the desired state of GithubIssue.
+// +kubebuilder:validation:XValidation:rule="!(has(self.milestone) && has(self.milestoneRef))",message="milestone and milestoneRef are mutually exclusive"
 type GithubIssueSpec struct {
-	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
 	// Repo URL of the repository where the issue should be created
+	// +optional
 	Repo string `json:"repo,omitempty"`
+	// RepositoryRef selects a GithubRepository in the same namespace in place of repo. The issue is created in
+	// its repository, with its credentials, default labels, default assignees and sync interval.
+	// +optional
+	RepositoryRef *corev1.LocalObjectReference `json:"repositoryRef,omitempty"`
 	// Title is the title of the issue
 	Title string `json:"title,omitempty"`
 	// Description is used as a description for the issue
 	Description string `json:"description,omitempty"`
+	// DescriptionFrom renders the description from a ConfigMap or Secret key instead of Description.
+	// The issue is updated whenever the referenced data changes.
+	// +optional
+	DescriptionFrom *DescriptionSource `json:"descriptionFrom,omitempty"`
+	// Localizations are translations of the issue keyed by language, such as "en" or "fr".
+	// The selected localization replaces Title and Description, each only when it sets them.
+	// +optional
+	Localizations map[string]IssueLocalization `json:"localizations,omitempty"`
+	// Language selects the localization rendered upstream. Defaults to the defaultLanguage of the
+	// GithubRepository for the issue repository, in the GithubIssue namespace.
+	// +optional
+	Language string `json:"language,omitempty"`
+	// IncludeTranslations appends the other localizations to the body as collapsible sections
+	// +optional
+	IncludeTranslations bool `json:"includeTranslations,omitempty"`
+	// TemplateRef selects a ConfigMap key holding a Go template the issue body is rendered from.
+	// The template sees .Name, .Namespace, .Labels, .Description and .Values.
+	// +optional
+	TemplateRef *corev1.ConfigMapKeySelector `json:"templateRef,omitempty"`
+	// TemplateValues are custom parameters exposed to the template as .Values
+	// +optional
+	TemplateValues map[string]string `json:"templateValues,omitempty"`
+	// Milestone the issue is assigned to, given by number or by title. Removing Milestone and MilestoneRef
+	// leaves the upstream issue in its milestone: status.milestoneNumber mirrors the upstream issue, so it can't
+	// tell a milestone the operator set from one set by hand.
+	// +optional
+	Milestone *intstr.IntOrString `json:"milestone,omitempty"`
+	// MilestoneRef names a GithubMilestone in the GithubIssue namespace the issue is assigned to, instead of
+	// Milestone. The GithubMilestone must be defined in the issue repository.
+	// +optional
+	MilestoneRef *corev1.LocalObjectReference `json:"milestoneRef,omitempty"`
+	// Labels applied to the issue
+	// +optional
+	// +listType=set
+	Labels []string `json:"labels,omitempty"`
+	// LabelPolicy decides what happens to labels added to the issue outside of the spec.
+	// Merge keeps them next to the spec labels, Replace removes them so the issue carries exactly
+	// the labels the operator manages. Defaults to Merge.
+	// +optional
+	// +kubebuilder:validation:Enum=Merge;Replace
+	// +kubebuilder:default=Merge
+	LabelPolicy LabelPolicy `json:"labelPolicy,omitempty"`
+	// Assignees are the logins of the users the issue is assigned to
+	// +optional
+	// +listType=set
+	Assignees []string `json:"assignees,omitempty"`
+	// IssueType is the organization issue type of the issue, such as Bug, Feature or Task.
+	// Removing it leaves the upstream type unchanged.
+	// +optional
+	IssueType string `json:"issueType,omitempty"`
+	// Priority of the issue, applied as the label the operator maps it to (priority/P0 to priority/P3 by default)
+	// replacing the label of any other priority.
+	// +optional
+	// +kubebuilder:validation:Enum=critical;high;medium;low
+	Priority string `json:"priority,omitempty"`
+	// Estimate is the story points or weight of the issue. GitHub has no estimate field, so it is
+	// applied as an "estimate/<n>" label replacing any other estimate label on the issue.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Estimate *int32 `json:"estimate,omitempty"`
+	// Projects are the Projects V2 boards the issue is added to, given by URL
+	// (https://github.com/orgs/<org>/projects/<n>) or node ID. Removing a board does not remove the issue from it.
+	// +optional
+	// +listType=set
+	Projects []string `json:"projects,omitempty"`
+	// Locked locks the issue conversation so only collaborators can comment
+	// +optional
+	Locked bool `json:"locked,omitempty"`
+	// LockReason is shown on the locked conversation
+	// +optional
+	// +kubebuilder:validation:Enum=off-topic;too heated;resolved;spam
+	LockReason string `json:"lockReason,omitempty"`
+	// Pinned pins the issue to the top of the repository issue list
+	// +optional
+	Pinned bool `json:"pinned,omitempty"`
+	// Suspend stops all GitHub API calls for this issue until it is set back to false,
+	// which triggers a fresh full sync. Deleting a suspended GithubIssue leaves the upstream issue open.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// NotBefore holds back creating the upstream issue until this time, so the GithubIssue can be applied
+	// ahead of time. The Scheduled condition is set until then. It has no effect once the issue exists.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// DueDate is when the issue is due. GitHub has no due date field, so it is rendered in the issue body.
+	// The Overdue condition is set while the issue is open past its due date.
+	// +optional
+	DueDate *metav1.Time `json:"dueDate,omitempty"`
+	// DueReminder posts a comment on the issue when spec.dueDate approaches
+	// +optional
+	DueReminder *DueReminder `json:"dueReminder,omitempty"`
+	// SyncIntervalSeconds overrides the global resync period for this issue
+	// +optional
+	// +kubebuilder:validation:Minimum=10
+	SyncIntervalSeconds *int32 `json:"syncIntervalSeconds,omitempty"`
+	// DeletionProtection holds the deletion of the GithubIssue, and so the closing of the upstream issue.
+	// WhileLinkedPROpen waits until the issue has no open linked pull request. Defaults to None.
+	// +optional
+	// +kubebuilder:validation:Enum=None;WhileLinkedPROpen
+	// +kubebuilder:default=None
+	DeletionProtection DeletionProtection `json:"deletionProtection,omitempty"`
+	// ConflictPolicy decides what happens when the issue description is edited on GitHub.
+	// CRWins overwrites the edit, GitHubWins keeps it and records it in status.externalDescription,
+	// Manual sets the Conflict condition and stops editing the issue until the spec changes. Defaults to CRWins.
+	// +optional
+	// +kubebuilder:validation:Enum=CRWins;GitHubWins;Manual
+	// +kubebuilder:default=CRWins
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+	// CredentialsSecretRef selects a Secret key holding the GitHub token used for this issue
+	// instead of the operator token.
+	// +optional
+	CredentialsSecretRef *CredentialsSecretReference `json:"credentialsSecretRef,omitempty"`
+	// Comments are posted on the issue and kept in sync: editing a body edits the comment upstream
+	// and removing an entry deletes its comment. Comments edited on GitHub are left as they are.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Comments []IssueComment `json:"comments,omitempty"`
+	// CloseComment is posted on the issue right before the operator closes it
+	// +optional
+	CloseComment string `json:"closeComment,omitempty"`
+	// Mirrors are secondary repositories the issue is mirrored to, e.g. on another GitHub host during a migration.
+	// Each mirror issue follows the title and the open or closed state of the issue, and is closed with it.
+	// Removing a mirror leaves its issue as it is.
+	// +optional
+	// +listType=map
+	// +listMapKey=repo
+	Mirrors []IssueMirror `json:"mirrors,omitempty"`
+	// DuplicatePolicy decides what happens when the upstream issue is marked as a duplicate of another issue.
+	// Ignore keeps syncing the issue, Repoint records the canonical issue in status.duplicateOf and stops
+	// editing the duplicate, Delete deletes the GithubIssue. Defaults to Ignore.
+	// +optional
+	// +kubebuilder:validation:Enum=Ignore;Repoint;Delete
+	// +kubebuilder:default=Ignore
+	DuplicatePolicy DuplicatePolicy `json:"duplicatePolicy,omitempty"`
+}
+
+// DeletionProtection names a deletion protection policy.
+type DeletionProtection string
+
+const (
+	// DeletionProtectionNone closes the upstream issue as soon as the GithubIssue is deleted.
+	DeletionProtectionNone DeletionProtection = "None"
+	// DeletionProtectionWhileLinkedPROpen holds the deletion while a linked pull request is open.
+	DeletionProtectionWhileLinkedPROpen DeletionProtection = "WhileLinkedPROpen"
+)
+
+// LabelPolicy names how labels added outside of the spec are handled.
+type LabelPolicy string
+
+const (
+	// LabelPolicyMerge keeps labels added outside of the spec.
+	LabelPolicyMerge LabelPolicy = "Merge"
+	// LabelPolicyReplace removes labels added outside of the spec.
+	LabelPolicyReplace LabelPolicy = "Replace"
+)
+
+// ConflictPolicy names how upstream description edits are handled.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyCRWins overwrites upstream edits with the spec.
+	ConflictPolicyCRWins ConflictPolicy = "CRWins"
+	// ConflictPolicyGitHubWins keeps upstream edits.
+	ConflictPolicyGitHubWins ConflictPolicy = "GitHubWins"
+	// ConflictPolicyManual waits for the conflict to be resolved by hand.
+	ConflictPolicyManual ConflictPolicy = "Manual"
+)
+
+// DuplicatePolicy names how an upstream issue marked as a duplicate is handled.
+type DuplicatePolicy string
+
+const (
+	// DuplicatePolicyIgnore keeps syncing the duplicate.
+	DuplicatePolicyIgnore DuplicatePolicy = "Ignore"
+	// DuplicatePolicyRepoint records the canonical issue and stops editing the duplicate.
+	DuplicatePolicyRepoint DuplicatePolicy = "Repoint"
+	// DuplicatePolicyDelete deletes the GithubIssue.
+	DuplicatePolicyDelete DuplicatePolicy = "Delete"
+)
+
+// DescriptionSource selects the key holding the issue description. Exactly one reference must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapKeyRef) != has(self.secretKeyRef)",message="exactly one of configMapKeyRef and secretKeyRef must be set"
+type DescriptionSource struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap in the GithubIssue namespace
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// SecretKeyRef selects a key of a Secret in the GithubIssue namespace
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// IssueLocalization is a translation of the issue.
+type IssueLocalization struct {
+	// Title in this language
+	// +optional
+	Title string `json:"title,omitempty"`
+	// Body in this language
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+// IssueComment is a comment managed by the operator.
+type IssueComment struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Name identifies the comment across spec changes
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Body of the comment
+	Body string `json:"body"`
+}
+
+// IssueMirror is a repository the issue is mirrored to.
+type IssueMirror struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// Repo URL of the repository the issue is mirrored to
+	Repo string `json:"repo"`
+	// CredentialsSecretRef selects a Secret key holding the token used for the mirror, for mirrors on
+	// another host. Defaults to the token used for the issue.
+	// +optional
+	CredentialsSecretRef *CredentialsSecretReference `json:"credentialsSecretRef,omitempty"`
 }
 
+// CredentialsSecretReference selects a Secret key holding a token.
+type CredentialsSecretReference struct {
+	// Namespace of the Secret. Defaults to the namespace of the referencing resource; any other namespace
+	// must be the central secrets namespace allowed by the operator.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Required
+	// Name of the Secret
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	// Key of the Secret holding the token
+	Key string `json:"key"`
+}
+
+// SecretNamespace returns the namespace of the Secret referenced from namespace. Only namespace itself and
+// centralNamespace, when set, are allowed.
+func (in *CredentialsSecretReference) SecretNamespace(namespace, centralNamespace string) (string, error) {
+	switch in.Namespace {
+	case "", namespace:
+		return namespace, nil
+	case centralNamespace:
+		return centralNamespace, nil
+	}
+	return "", fmt.Errorf("credentials Secret %s/%s is outside of namespace %s", in.Namespace, in.Name, namespace)
+}
+
+// MirrorStatus records the issue mirrored for a spec.mirrors entry.
+type MirrorStatus struct {
+	// Repo of the spec.mirrors entry
+	Repo string `json:"repo"`
+	// IssueNumber is the number of the mirror issue
+	// +optional
+	IssueNumber int `json:"issueNumber,omitempty"`
+	// URL of the mirror issue
+	// +optional
+	URL string `json:"url,omitempty"`
+	// State of the mirror issue, open or closed
+	// +optional
+	State string `json:"state,omitempty"`
+	// Error is why the last sync of the mirror failed, cleared once it succeeds
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// CommentStatus records a comment posted for spec.comments.
+type CommentStatus struct {
+	// Name of the spec.comments entry
+	Name string `json:"name"`
+	// ID of the upstream comment
+	ID int64 `json:"id"`
+	// Hash of the body last written upstream
+	Hash string `json:"hash"`
+}
+
+// IssuePhase summarizes where a GithubIssue is in its lifecycle.
+// +kubebuilder:validation:Enum=Pending;Synced;Error;Terminating
+type IssuePhase string
+
+const (
+	// PhasePending is set until the issue is synced for the first time.
+	PhasePending IssuePhase = "Pending"
+	// PhaseSynced is set once the spec of status.observedGeneration is applied to GitHub.
+	PhaseSynced IssuePhase = "Synced"
+	// PhaseError is set while the last reconcile failed.
+	PhaseError IssuePhase = "Error"
+	// PhaseTerminating is set while the upstream issue is being closed.
+	PhaseTerminating IssuePhase = "Terminating"
+)
+
 // GithubIssueStatus defines the observed state of GithubIssue.
 type GithubIssueStatus struct {
+	// Phase is Pending until the first sync, then Synced, Error or Terminating
+	// +optional
+	Phase IssuePhase `json:"phase,omitempty"`
+	// ObservedGeneration is the generation of the spec last applied to GitHub
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastSyncTime is when the issue was last fully reconciled against GitHub, refreshed at most once a minute
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// LastSyncError is the error of the last failed reconcile, cleared by the next successful sync
+	// +optional
+	LastSyncError string `json:"lastSyncError,omitempty"`
+	// ConsecutiveFailures counts the reconciles that failed since the last successful sync
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
 	// Conditions represent the latest available observations of the issue's state.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// MilestoneNumber is the number of the milestone the upstream issue is assigned to
+	MilestoneNumber int `json:"milestoneNumber,omitempty"`
+	// Pinned is true while the operator keeps the upstream issue pinned
+	Pinned bool `json:"pinned,omitempty"`
+	// Projects are the boards of spec.projects the issue has been added to
+	Projects []string `json:"projects,omitempty"`
+	// Comments are the comments posted for spec.comments
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Comments []CommentStatus `json:"comments,omitempty"`
+	// Mirrors are the issues mirrored for spec.mirrors
+	// +optional
+	// +listType=map
+	// +listMapKey=repo
+	Mirrors []MirrorStatus `json:"mirrors,omitempty"`
+	// Repo is the repository URL resolved from spec.repositoryRef
+	// +optional
+	Repo string `json:"repo,omitempty"`
+	// IssueNumber is the number of the upstream issue
+	IssueNumber int `json:"issueNumber,omitempty"`
+	// NodeID is the GraphQL node ID of the upstream issue
+	// +optional
+	NodeID string `json:"nodeID,omitempty"`
+	// ConvertedToDiscussionURL is the discussion the upstream issue was converted to.
+	// Once set, the operator no longer edits or closes the issue.
+	ConvertedToDiscussionURL string `json:"convertedToDiscussionURL,omitempty"`
+	// AppliedDescriptionHash is the hash of the description last written upstream by the operator
+	AppliedDescriptionHash string `json:"appliedDescriptionHash,omitempty"`
+	// ExternalDescription is the upstream description adopted under the GitHubWins conflict policy
+	ExternalDescription string `json:"externalDescription,omitempty"`
+	// CloseCommentID is the ID of the spec.closeComment posted before closing the upstream issue, so a close
+	// that fails and is retried doesn't post it again
+	// +optional
+	CloseCommentID int64 `json:"closeCommentID,omitempty"`
+	// RemindedDueDate is the due date the last reminder comment was posted for. Changing spec.dueDate
+	// posts a new reminder.
+	RemindedDueDate *metav1.Time `json:"remindedDueDate,omitempty"`
+	// LastDecision records what the operator did in the last reconcile and why
+	// +optional
+	LastDecision *ReconcileDecision `json:"lastDecision,omitempty"`
+	// Creation tracks the creation of the upstream issue until it completes, so an operator restarting midway
+	// resumes it instead of opening a second issue or losing the issue number
+	// +optional
+	Creation *IssueCreation `json:"creation,omitempty"`
+	// DuplicateOf is the canonical issue the upstream issue was marked a duplicate of, under the Repoint
+	// duplicate policy. While set, the operator no longer edits the duplicate.
+	// +optional
+	DuplicateOf *CanonicalIssue `json:"duplicateOf,omitempty"`
+}
+
+// CanonicalIssue is the issue a duplicate was marked a duplicate of.
+type CanonicalIssue struct {
+	// Number of the canonical issue
+	Number int `json:"number"`
+	// URL of the canonical issue, which may be in another repository
+	URL string `json:"url"`
+}
+
+// CreationStep is the last recorded step of the creation of the upstream issue.
+// +kubebuilder:validation:Enum=Creating;Created
+type CreationStep string
+
+const (
+	// CreationStepCreating is recorded before the issue is created. The issue may or may not exist upstream.
+	CreationStepCreating CreationStep = "Creating"
+	// CreationStepCreated is recorded with the issue number once the issue exists upstream. The events
+	// announcing it may not have been emitted yet.
+	CreationStepCreated CreationStep = "Created"
+)
+
+// IssueCreation is the progress of the creation of the upstream issue.
+type IssueCreation struct {
+	// Step is the last step recorded
+	Step CreationStep `json:"step"`
+	// StartedAt is when the creation started
+	StartedAt metav1.Time `json:"startedAt"`
+	// URL of the created issue, recorded with the Created step
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// DueReminder is the comment posted on an open issue when its due date approaches.
+type DueReminder struct {
+	// Before is how long before spec.dueDate the reminder is posted
+	// +kubebuilder:default="24h"
+	// +optional
+	Before metav1.Duration `json:"before,omitempty"`
+	// Body of the reminder comment. Defaults to a message giving the due date.
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+// ReconcileDecision is what the operator did in a reconcile and why.
+type ReconcileDecision struct {
+	// Action taken: Created, Updated, Unchanged, Skipped, Waiting or Failed
+	Action string `json:"action"`
+	// Reason is a CamelCase reason for the action
+	Reason string `json:"reason"`
+	// Message gives the details of the decision
+	// +optional
+	Message string `json:"message,omitempty"`
+	// InputsHash identifies the spec and upstream issue the decision was made from
+	// +optional
+	InputsHash string `json:"inputsHash,omitempty"`
+	// Time is when the decision was first made. Reconciles repeating it leave it unchanged.
+	Time metav1.Time `json:"time"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 
 // GithubIssue is the Schema for the githubissues API.
 type GithubIssue struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	// +kubebuilder:validation:XValidation:rule="has(self.repo) != has(self.repositoryRef)",message="exactly one of repo and repositoryRef must be set"
 	Spec   GithubIssueSpec   `json:"spec,omitempty"`
 	Status GithubIssueStatus `json:"status,omitempty"`
 }
 
+// RepoURL returns the repository URL of the issue: spec.repo, else the URL last resolved from
+// spec.repositoryRef.
+func (in *GithubIssue) RepoURL() string {
+	if in.Spec.Repo != "" {
+		return in.Spec.Repo
+	}
+	return in.Status.Repo
+}
+
 // +kubebuilder:object:root=true
 
 // GithubIssueList contains a list of GithubIssue.
@@ -0,0 +1,111 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubIssueSetSpec defines the desired state of GithubIssueSet.
+// +kubebuilder:validation:XValidation:rule="has(self.repos) || has(self.repositorySelector)",message="at least one of repos and repositorySelector must be set"
+type GithubIssueSetSpec struct {
+	// Template is the GithubIssue created in every target repository
+	Template GithubIssueTemplate `json:"template"`
+	// Repos lists the URLs of the target repositories
+	// +kubebuilder:validation:items:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// +optional
+	Repos []string `json:"repos,omitempty"`
+	// RepositorySelector selects GithubRepositories in the same namespace as target repositories.
+	// Their GithubIssues select them through spec.repositoryRef.
+	// +optional
+	RepositorySelector *metav1.LabelSelector `json:"repositorySelector,omitempty"`
+}
+
+// GithubIssueTemplate describes the GithubIssues created by a GithubIssueSet or an IssueRule.
+type GithubIssueTemplate struct {
+	// Metadata holds the labels and annotations of the GithubIssues
+	// +optional
+	Metadata GithubIssueTemplateMetadata `json:"metadata,omitempty"`
+	// Spec of the GithubIssues. Repo and repositoryRef are set for each target repository.
+	// +kubebuilder:validation:XValidation:rule="!has(self.repo) && !has(self.repositoryRef)",message="repo and repositoryRef are set by the GithubIssueSet or IssueRule"
+	Spec GithubIssueSpec `json:"spec"`
+}
+
+// GithubIssueTemplateMetadata holds the labels and annotations of the GithubIssues created by a GithubIssueSet.
+type GithubIssueTemplateMetadata struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GithubIssueSetStatus defines the observed state of GithubIssueSet.
+type GithubIssueSetStatus struct {
+	// Conditions represent the latest available observations of the set's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Repositories reports the GithubIssue of every target repository
+	Repositories []IssueSetRepositoryStatus `json:"repositories,omitempty"`
+	// Total is the number of target repositories
+	Total int32 `json:"total,omitempty"`
+	// Created is the number of target repositories their issue was created in
+	Created int32 `json:"created,omitempty"`
+}
+
+// IssueSetRepositoryStatus reports the GithubIssue a GithubIssueSet created for a target repository.
+type IssueSetRepositoryStatus struct {
+	// Repo is the URL of the target repository
+	Repo string `json:"repo,omitempty"`
+	// RepositoryRef is the name of the selected GithubRepository, empty for the repos listed in spec.repos
+	RepositoryRef string `json:"repositoryRef,omitempty"`
+	// Issue is the name of the GithubIssue
+	Issue string `json:"issue"`
+	// IssueNumber is the number of the upstream issue, once created
+	IssueNumber int `json:"issueNumber,omitempty"`
+	// Phase is the phase of the GithubIssue
+	Phase IssuePhase `json:"phase,omitempty"`
+	// Error is the last sync error of the GithubIssue
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Created",type=integer,JSONPath=".status.created"
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=".status.total"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// GithubIssueSet is the Schema for the githubissuesets API. It files the same GithubIssue in many repositories.
+type GithubIssueSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubIssueSetSpec   `json:"spec,omitempty"`
+	Status GithubIssueSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubIssueSetList contains a list of GithubIssueSet.
+type GithubIssueSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubIssueSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubIssueSet{}, &GithubIssueSetList{})
+}
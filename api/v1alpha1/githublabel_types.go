@@ -0,0 +1,79 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubLabelSpec defines the desired state of GithubLabel.
+type GithubLabelSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// Repo URL of the repository the label is defined in
+	Repo string `json:"repo,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=50
+	// Name of the label. Renaming it renames the upstream label, keeping it on its issues.
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{6}$`
+	// Color of the label as a hexadecimal RGB value without the leading #
+	Color string `json:"color,omitempty"`
+	// Description of the label
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// GithubLabelStatus defines the observed state of GithubLabel.
+type GithubLabelStatus struct {
+	// Conditions represent the latest available observations of the label's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// AppliedName is the name of the label last written upstream, used to rename it
+	AppliedName string `json:"appliedName,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Label",type=string,JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Repo",type=string,JSONPath=".spec.repo"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// GithubLabel is the Schema for the githublabels API. Deleting it deletes the upstream label.
+type GithubLabel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubLabelSpec   `json:"spec,omitempty"`
+	Status GithubLabelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubLabelList contains a list of GithubLabel.
+type GithubLabelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubLabel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubLabel{}, &GithubLabelList{})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MilestoneState names the state of a milestone.
+type MilestoneState string
+
+const (
+	// MilestoneStateOpen keeps the milestone open.
+	MilestoneStateOpen MilestoneState = "open"
+	// MilestoneStateClosed closes the milestone.
+	MilestoneStateClosed MilestoneState = "closed"
+)
+
+// GithubMilestoneSpec defines the desired state of GithubMilestone.
+type GithubMilestoneSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// Repo URL of the repository the milestone is defined in
+	Repo string `json:"repo,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Title of the milestone. An existing milestone with this title is adopted.
+	Title string `json:"title,omitempty"`
+	// Description of the milestone
+	// +optional
+	Description string `json:"description,omitempty"`
+	// DueDate of the milestone. GitHub only keeps the date.
+	// +optional
+	DueDate *metav1.Time `json:"dueDate,omitempty"`
+	// State of the milestone, open or closed. Defaults to open.
+	// +optional
+	// +kubebuilder:validation:Enum=open;closed
+	// +kubebuilder:default=open
+	State MilestoneState `json:"state,omitempty"`
+}
+
+// GithubMilestoneStatus defines the observed state of GithubMilestone.
+type GithubMilestoneStatus struct {
+	// Conditions represent the latest available observations of the milestone's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Number of the upstream milestone
+	// +optional
+	Number int `json:"number,omitempty"`
+	// URL of the upstream milestone
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Milestone",type=string,JSONPath=".spec.title"
+// +kubebuilder:printcolumn:name="Number",type=integer,JSONPath=".status.number"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=".spec.state"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// GithubMilestone is the Schema for the githubmilestones API. Deleting it closes the upstream milestone,
+// which stays on its issues.
+type GithubMilestone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubMilestoneSpec   `json:"spec,omitempty"`
+	Status GithubMilestoneStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubMilestoneList contains a list of GithubMilestone.
+type GithubMilestoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubMilestone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubMilestone{}, &GithubMilestoneList{})
+}
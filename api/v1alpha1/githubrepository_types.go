@@ -0,0 +1,137 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubRepositorySpec defines the desired state of GithubRepository.
+type GithubRepositorySpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// Repo URL of the repository
+	Repo string `json:"repo,omitempty"`
+	// IssueSource makes the operator pull issue definitions from files in this repository
+	// and materialize them as GithubIssue resources.
+	// +optional
+	IssueSource *IssueSource `json:"issueSource,omitempty"`
+	// WebhookSecretRef selects the Secret key holding the secret GitHub signs this repository's
+	// webhook deliveries with. Deliveries for repositories without a secret are rejected.
+	// +optional
+	WebhookSecretRef *corev1.SecretKeySelector `json:"webhookSecretRef,omitempty"`
+	// DefaultLanguage selects the localization of the GithubIssues for this repository
+	// that do not set spec.language
+	// +optional
+	DefaultLanguage string `json:"defaultLanguage,omitempty"`
+	// TitlePrefix is prepended to the title of the GithubIssues for this repository, e.g. "[prod-eu] ",
+	// so issues opened by clusters sharing the repository can be told apart. It takes precedence over the
+	// issues.dana.io/title-prefix annotation of the namespace. Issues are found by title: changing the
+	// prefix opens new issues.
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	TitlePrefix string `json:"titlePrefix,omitempty"`
+	// CredentialsSecretRef selects the Secret key holding the token used for the GithubIssues selecting
+	// this repository through spec.repositoryRef that set no spec.credentialsSecretRef
+	// +optional
+	CredentialsSecretRef *CredentialsSecretReference `json:"credentialsSecretRef,omitempty"`
+	// DefaultLabels are added to the labels of the GithubIssues selecting this repository through spec.repositoryRef
+	// +optional
+	DefaultLabels []string `json:"defaultLabels,omitempty"`
+	// DefaultAssignees are assigned to the GithubIssues selecting this repository through spec.repositoryRef
+	// that set no spec.assignees
+	// +optional
+	DefaultAssignees []string `json:"defaultAssignees,omitempty"`
+	// SyncIntervalSeconds is the sync interval of the GithubIssues selecting this repository through
+	// spec.repositoryRef that set no spec.syncIntervalSeconds
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	SyncIntervalSeconds *int32 `json:"syncIntervalSeconds,omitempty"`
+	// Triage overrides the triage policy of the operator for the GithubIssues of this repository
+	// +optional
+	Triage *RepositoryTriage `json:"triage,omitempty"`
+}
+
+// RepositoryTriage overrides the triage policy of the operator for a repository. Unset fields keep the
+// values of the operator flags.
+type RepositoryTriage struct {
+	// Enabled turns triage labeling on or off for the repository
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// StaleAfter is how long a triaged issue may go without upstream activity before it is marked stale
+	// +optional
+	StaleAfter *metav1.Duration `json:"staleAfter,omitempty"`
+	// EscalateAfter is how long an issue may stay untriaged, measured from the GithubIssue creation,
+	// before it is escalated
+	// +optional
+	EscalateAfter *metav1.Duration `json:"escalateAfter,omitempty"`
+}
+
+// IssueSource points to a directory holding issue definitions, one YAML file per issue.
+// Each file holds the fields of a GithubIssue spec; repo defaults to the GithubRepository repo.
+type IssueSource struct {
+	// +kubebuilder:validation:Required
+	// Path of the directory holding the issue definitions
+	Path string `json:"path,omitempty"`
+	// Ref is the branch, tag or commit to read from. Defaults to the default branch.
+	// +optional
+	Ref string `json:"ref,omitempty"`
+	// Interval between pulls of the issue definitions
+	// +kubebuilder:default="5m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Prune deletes GithubIssues whose definition file was removed
+	// +kubebuilder:default=true
+	// +optional
+	Prune *bool `json:"prune,omitempty"`
+}
+
+// GithubRepositoryStatus defines the observed state of GithubRepository.
+type GithubRepositoryStatus struct {
+	// Conditions represent the latest available observations of the repository's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ManagedIssues lists the GithubIssues materialized from the issue source
+	ManagedIssues []string `json:"managedIssues,omitempty"`
+	// LastSyncTime is when the issue source was last pulled successfully
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// GithubRepository is the Schema for the githubrepositories API.
+type GithubRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubRepositorySpec   `json:"spec,omitempty"`
+	Status GithubRepositoryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubRepositoryList contains a list of GithubRepository.
+type GithubRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubRepository `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubRepository{}, &GithubRepositoryList{})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// WebhookSecretKey is the key of the Secret of a GithubWebhook holding the secret signing its deliveries.
+	WebhookSecretKey = "secret"
+	// WebhookPreviousSecretKey is the key holding the secret replaced by the last rotation, still accepted
+	// by the webhook receiver.
+	WebhookPreviousSecretKey = "previous"
+)
+
+// GithubWebhookSpec defines the desired state of GithubWebhook.
+type GithubWebhookSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// Repo URL of the repository the webhook is registered on
+	Repo string `json:"repo,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?:\/\/`
+	// URL of the operator's webhook receiver, as reachable from GitHub
+	URL string `json:"url,omitempty"`
+	// Events the webhook is delivered for
+	// +kubebuilder:default={"issues"}
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Events []string `json:"events,omitempty"`
+	// SecretRotationInterval is how often the secret signing the deliveries is replaced. The previous secret
+	// stays valid until the next rotation, so deliveries in flight during a rotation are accepted. Unset never rotates.
+	// +optional
+	SecretRotationInterval *metav1.Duration `json:"secretRotationInterval,omitempty"`
+}
+
+// WebhookDelivery is a delivery attempt of the webhook.
+type WebhookDelivery struct {
+	// ID of the delivery on GitHub
+	ID int64 `json:"id"`
+	// Event delivered
+	Event string `json:"event,omitempty"`
+	// StatusCode the receiver answered with. Zero when GitHub could not connect.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Status describes the result of the delivery
+	Status string `json:"status,omitempty"`
+	// DeliveredAt is when GitHub attempted the delivery
+	DeliveredAt metav1.Time `json:"deliveredAt,omitempty"`
+}
+
+// GithubWebhookStatus defines the observed state of GithubWebhook.
+type GithubWebhookStatus struct {
+	// Conditions represent the latest available observations of the webhook's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// HookID is the ID of the webhook on GitHub
+	HookID int64 `json:"hookID,omitempty"`
+	// SecretName is the Secret holding the secret signing the deliveries, owned by the GithubWebhook
+	SecretName string `json:"secretName,omitempty"`
+	// SecretRotatedAt is when the secret was last replaced
+	SecretRotatedAt *metav1.Time `json:"secretRotatedAt,omitempty"`
+	// AppliedSecretVersion is the resourceVersion of the Secret last written to the webhook
+	AppliedSecretVersion string `json:"appliedSecretVersion,omitempty"`
+	// LastDelivery is the most recent delivery of the webhook
+	LastDelivery *WebhookDelivery `json:"lastDelivery,omitempty"`
+	// FailedDeliveries counts the failed deliveries among the most recent ones
+	FailedDeliveries int32 `json:"failedDeliveries,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Repo",type=string,JSONPath=".spec.repo"
+// +kubebuilder:printcolumn:name="Hook",type=integer,JSONPath=".status.hookID"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Delivering",type=string,JSONPath=".status.conditions[?(@.type==\"DeliveriesHealthy\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// GithubWebhook is the Schema for the githubwebhooks API. It registers a webhook delivering the events of
+// a repository to the operator's webhook receiver. Deleting it deletes the upstream webhook.
+type GithubWebhook struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubWebhookSpec   `json:"spec,omitempty"`
+	Status GithubWebhookStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubWebhookList contains a list of GithubWebhook.
+type GithubWebhookList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubWebhook `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubWebhook{}, &GithubWebhookList{})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitProviderType names the API spoken by a GitProvider.
+// +kubebuilder:validation:Enum=github;gitlab;gitea;sourcehut
+type GitProviderType string
+
+const (
+	// GitProviderGitHub is github.com or a GitHub Enterprise Server.
+	GitProviderGitHub GitProviderType = "github"
+	// GitProviderGitLab is a GitLab instance.
+	GitProviderGitLab GitProviderType = "gitlab"
+	// GitProviderGitea is a Gitea instance.
+	GitProviderGitea GitProviderType = "gitea"
+	// GitProviderSourceHut is todo.sr.ht or a self-hosted SourceHut todo tracker. Its repository URLs name a
+	// tracker, e.g. https://todo.sr.ht/~owner/tracker.
+	GitProviderSourceHut GitProviderType = "sourcehut"
+)
+
+// GitProviderSpec defines the desired state of GitProvider.
+type GitProviderSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+(:[0-9]+)?$`
+	// Host of the repositories served by this provider, e.g. github.example.com. The GithubIssues whose repo
+	// is on this host use the provider.
+	Host string `json:"host,omitempty"`
+	// Type of the provider API
+	// +kubebuilder:default=github
+	// +optional
+	Type GitProviderType `json:"type,omitempty"`
+	// APIURL is the base URL of the provider API. Defaults to https://api.github.com/ for github.com,
+	// https://<host>/api/v3/ for other GitHub hosts and https://<host>/query for SourceHut.
+	// +kubebuilder:validation:Pattern=`^https?:\/\/`
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+	// +kubebuilder:validation:Required
+	// AuthSecretRef selects the Secret key holding the token of the provider
+	AuthSecretRef SecretKeyReference `json:"authSecretRef"`
+	// TLS configures the verification of the provider certificate
+	// +optional
+	TLS *GitProviderTLS `json:"tls,omitempty"`
+	// RequestsPerHour caps the requests sent to the provider, keeping part of a shared rate limit for other
+	// clients. Zero is unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RequestsPerHour int32 `json:"requestsPerHour,omitempty"`
+	// ProxyURL is the proxy the requests to the provider go through, e.g. http://proxy.example.com:3128.
+	// Defaults to the proxy of the operator.
+	// +kubebuilder:validation:Pattern=`^(https?|socks5):\/\/`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+}
+
+// SecretKeyReference selects a key of a Secret in any namespace.
+type SecretKeyReference struct {
+	// +kubebuilder:validation:Required
+	// Namespace of the Secret
+	Namespace string `json:"namespace"`
+	// +kubebuilder:validation:Required
+	// Name of the Secret
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	// Key of the Secret holding the value
+	Key string `json:"key"`
+}
+
+// GitProviderTLS configures the verification of a provider certificate.
+type GitProviderTLS struct {
+	// CABundle holds the PEM encoded certificates trusted for the provider, in addition to the system ones
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+	// InsecureSkipVerify disables the verification of the provider certificate. Only meant for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// GitProviderStatus defines the observed state of GitProvider.
+type GitProviderStatus struct {
+	// Conditions represent the latest available observations of the provider's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=".spec.host"
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// GitProvider is the Schema for the gitproviders API. It configures the endpoint, credentials, TLS, proxy and
+// rate-limit budget used for the repositories of a host.
+type GitProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitProviderSpec   `json:"spec,omitempty"`
+	Status GitProviderStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GitProviderList contains a list of GitProvider.
+type GitProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitProvider{}, &GitProviderList{})
+}
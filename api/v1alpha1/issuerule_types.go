@@ -0,0 +1,156 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IssueRuleSpec defines the desired state of IssueRule.
+// +kubebuilder:validation:XValidation:rule="has(self.event) != has(self.condition)",message="exactly one of event and condition must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.repo) != has(self.repositoryRef)",message="exactly one of repo and repositoryRef must be set"
+type IssueRuleSpec struct {
+	// Event matches the Kubernetes Events of the rule namespace. Each involved object and reason is a match.
+	// +optional
+	Event *EventMatch `json:"event,omitempty"`
+	// Condition matches a condition of the objects of a kind in the rule namespace. Each object is a match.
+	// The operator must be allowed to list the kind.
+	// +optional
+	Condition *ConditionMatch `json:"condition,omitempty"`
+	// For is how long a match must last before its issue is filed, e.g. 10m for a pod crash looping for ten minutes
+	// +kubebuilder:default="0s"
+	// +optional
+	For metav1.Duration `json:"for,omitempty"`
+	// ResolveAfter is how long after the last matching Event an Event match is resolved
+	// +kubebuilder:default="15m"
+	// +optional
+	ResolveAfter metav1.Duration `json:"resolveAfter,omitempty"`
+	// CloseWhenResolved deletes the GithubIssue of a resolved match, which closes its upstream issue
+	// +kubebuilder:default=true
+	// +optional
+	CloseWhenResolved *bool `json:"closeWhenResolved,omitempty"`
+	// MaxIssues caps the GithubIssues of the rule, so a storm of events doesn't file a storm of issues.
+	// The oldest matches are filed first.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxIssues int32 `json:"maxIssues,omitempty"`
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// Repo URL of the repository the issues are filed in
+	// +optional
+	Repo string `json:"repo,omitempty"`
+	// RepositoryRef selects a GithubRepository in the same namespace in place of repo
+	// +optional
+	RepositoryRef *corev1.LocalObjectReference `json:"repositoryRef,omitempty"`
+	// Template is the GithubIssue filed for a match. Its title and description are Go templates seeing .Kind,
+	// .Name and .Namespace of the matched object, and .Reason, .Message and .Since of the match. The title
+	// defaults to "{{.Kind}} {{.Name}}: {{.Reason}}".
+	Template GithubIssueTemplate `json:"template"`
+}
+
+// EventMatch selects Kubernetes Events.
+type EventMatch struct {
+	// Reasons the Event must have one of, such as BackOff
+	// +kubebuilder:validation:MinItems=1
+	Reasons []string `json:"reasons"`
+	// Type of the Event
+	// +kubebuilder:validation:Enum=Normal;Warning
+	// +kubebuilder:default=Warning
+	// +optional
+	Type string `json:"type,omitempty"`
+	// InvolvedObjectKind restricts the match to the Events of a kind, such as Pod
+	// +optional
+	InvolvedObjectKind string `json:"involvedObjectKind,omitempty"`
+	// MessagePattern is a regular expression the Event message must match
+	// +optional
+	MessagePattern string `json:"messagePattern,omitempty"`
+}
+
+// ConditionMatch selects objects by a condition in their status.
+type ConditionMatch struct {
+	// APIVersion of the objects, such as apps/v1
+	APIVersion string `json:"apiVersion"`
+	// Kind of the objects, such as Deployment
+	Kind string `json:"kind"`
+	// Selector restricts the match to the objects it selects
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Type of the condition, such as Available
+	Type string `json:"type"`
+	// Status the condition must have
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	// +kubebuilder:default=False
+	// +optional
+	Status metav1.ConditionStatus `json:"status,omitempty"`
+}
+
+// IssueRuleMatch is an object matched by an IssueRule.
+type IssueRuleMatch struct {
+	// Kind of the matched object
+	Kind string `json:"kind"`
+	// Name of the matched object
+	Name string `json:"name"`
+	// Reason of the matching Event or condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Since is when the match started
+	Since metav1.Time `json:"since"`
+	// Issue is the name of the GithubIssue filed for the match, empty until the match lasted spec.for
+	// +optional
+	Issue string `json:"issue,omitempty"`
+}
+
+// IssueRuleStatus defines the observed state of IssueRule.
+type IssueRuleStatus struct {
+	// Conditions represent the latest available observations of the rule's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Matches are the objects currently matched by the rule
+	// +optional
+	Matches []IssueRuleMatch `json:"matches,omitempty"`
+	// FiledIssues counts the GithubIssues of the rule
+	FiledIssues int32 `json:"filedIssues,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Filed",type=integer,JSONPath=".status.filedIssues"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// IssueRule is the Schema for the issuerules API. It files a GithubIssue for every object matched by a
+// Kubernetes Event or a condition for longer than spec.for. The GithubIssues are owned by the rule.
+type IssueRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IssueRuleSpec   `json:"spec,omitempty"`
+	Status IssueRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IssueRuleList contains a list of IssueRule.
+type IssueRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IssueRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IssueRule{}, &IssueRuleList{})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// RepositoryBindingSpec defines the repositories the GithubIssues of some namespaces may be filed in.
+// +kubebuilder:validation:XValidation:rule="has(self.namespaces) || has(self.namespaceSelector)",message="at least one of namespaces and namespaceSelector must be set"
+type RepositoryBindingSpec struct {
+	// Namespaces lists the namespaces the binding applies to
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector selects the namespaces the binding applies to, besides the namespaces listed
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Repositories lists the patterns of the repositories allowed, as host/owner/repo or owner/repo
+	// for any host. Each segment may use shell globs, e.g. "github.com/platform/*" or "*/docs".
+	// +kubebuilder:validation:MinItems=1
+	Repositories []string `json:"repositories"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// RepositoryBinding is the Schema for the repositorybindings API. When the operator enforces repository
+// bindings, the GithubIssues of a namespace may only be filed in the repositories a binding allows it.
+type RepositoryBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RepositoryBindingSpec `json:"spec,omitempty"`
+}
+
+// Covers reports whether the binding applies to the namespace with the given name and labels.
+func (in *RepositoryBinding) Covers(namespace string, namespaceLabels map[string]string) (bool, error) {
+	if slices.Contains(in.Spec.Namespaces, namespace) {
+		return true, nil
+	}
+	if in.Spec.NamespaceSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(in.Spec.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// Allows reports whether the repository URL matches one of the repository patterns of the binding.
+func (in *RepositoryBinding) Allows(repoURL string) bool {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return false
+	}
+	repository := strings.ToLower(parsed.Host + strings.TrimSuffix(parsed.Path, "/"))
+	for _, pattern := range in.Spec.Repositories {
+		pattern = strings.ToLower(pattern)
+		if strings.Count(pattern, "/") == 1 {
+			// owner/repo matches any host
+			pattern = "*/" + pattern
+		}
+		if matched, _ := path.Match(pattern, repository); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+
+// RepositoryBindingList contains a list of RepositoryBinding.
+type RepositoryBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RepositoryBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RepositoryBinding{}, &RepositoryBindingList{})
+}
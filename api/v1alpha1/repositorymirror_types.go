@@ -0,0 +1,83 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RepositoryMirrorSpec defines the desired state of RepositoryMirror.
+type RepositoryMirrorSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https:\/\/[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)+\/[^\/]+\/[^\/]+$`
+	// Repo URL of the repository whose issues are imported
+	Repo string `json:"repo,omitempty"`
+	// Labels restricts the import to the open issues carrying all of these labels. Empty imports every open issue.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+	// KeepUpdated copies upstream edits of the title, description, labels, assignees and lock of the imported
+	// issues into their GithubIssues on every sync. False imports each issue once and leaves it to its GithubIssue.
+	// +kubebuilder:default=true
+	// +optional
+	KeepUpdated *bool `json:"keepUpdated,omitempty"`
+	// Interval between two imports
+	// +kubebuilder:default="5m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// RepositoryMirrorStatus defines the observed state of RepositoryMirror.
+type RepositoryMirrorStatus struct {
+	// Conditions represent the latest available observations of the mirror's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ImportedIssues counts the GithubIssues imported by the mirror
+	ImportedIssues int32 `json:"importedIssues,omitempty"`
+	// LastSyncTime is when the repository issues were last imported
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Repo",type=string,JSONPath=".spec.repo"
+// +kubebuilder:printcolumn:name="Imported",type=integer,JSONPath=".status.importedIssues"
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=".status.conditions[?(@.type==\"MirrorSynced\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// RepositoryMirror is the Schema for the repositorymirrors API. It imports the open issues of a repository as
+// GithubIssues in its namespace, named after the mirror and the issue number, so existing issues can be managed
+// declaratively without recreating them. The imported GithubIssues are not owned by the mirror: deleting the
+// mirror stops the import and leaves them, and their upstream issues, in place.
+type RepositoryMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RepositoryMirrorSpec   `json:"spec,omitempty"`
+	Status RepositoryMirrorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RepositoryMirrorList contains a list of RepositoryMirror.
+type RepositoryMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RepositoryMirror `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RepositoryMirror{}, &RepositoryMirrorList{})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RepositoryStatusSpec defines the repository a RepositoryStatus summarizes. It is set by the operator.
+type RepositoryStatusSpec struct {
+	// Repo URL of the summarized repository
+	Repo string `json:"repo"`
+}
+
+// FailingIssue is a GithubIssue whose last sync failed.
+type FailingIssue struct {
+	// Namespace of the GithubIssue
+	Namespace string `json:"namespace"`
+	// Name of the GithubIssue
+	Name string `json:"name"`
+	// Error of the last failed sync
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// RepositoryStatusStatus summarizes the GithubIssues of the repository.
+type RepositoryStatusStatus struct {
+	// Conditions represent the latest available observations of the repository's state.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ManagedIssues counts the GithubIssues of the repository, in every namespace
+	ManagedIssues int32 `json:"managedIssues"`
+	// OpenIssues counts the GithubIssues whose upstream issue is open
+	OpenIssues int32 `json:"openIssues"`
+	// ClosedIssues counts the GithubIssues whose upstream issue is closed
+	ClosedIssues int32 `json:"closedIssues"`
+	// PendingIssues counts the GithubIssues that were never synced
+	PendingIssues int32 `json:"pendingIssues"`
+	// FailingIssues counts the GithubIssues whose last sync failed
+	FailingIssues int32 `json:"failingIssues"`
+	// Failing lists the first GithubIssues whose last sync failed, by namespace and name
+	// +optional
+	Failing []FailingIssue `json:"failing,omitempty"`
+	// RateLimitedIssues counts the GithubIssues waiting for a GitHub rate limit to reset
+	RateLimitedIssues int32 `json:"rateLimitedIssues"`
+	// RateLimitedUntil is the latest reset of the rate limits of the tokens used for the repository, while
+	// one of them is rate limited
+	// +optional
+	RateLimitedUntil *metav1.Time `json:"rateLimitedUntil,omitempty"`
+	// LastUpdateTime is when the summary was last computed
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Repo",type=string,JSONPath=".spec.repo"
+// +kubebuilder:printcolumn:name="Issues",type=integer,JSONPath=".status.managedIssues"
+// +kubebuilder:printcolumn:name="Open",type=integer,JSONPath=".status.openIssues"
+// +kubebuilder:printcolumn:name="Failing",type=integer,JSONPath=".status.failingIssues"
+// +kubebuilder:printcolumn:name="Healthy",type=string,JSONPath=".status.conditions[?(@.type==\"Healthy\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// RepositoryStatus is the Schema for the repositorystatuses API. The operator keeps one per repository with
+// GithubIssues, summarizing them across namespaces, and deletes it once the repository has none left.
+type RepositoryStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RepositoryStatusSpec   `json:"spec,omitempty"`
+	Status RepositoryStatusStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RepositoryStatusList contains a list of RepositoryStatus.
+type RepositoryStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RepositoryStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RepositoryStatus{}, &RepositoryStatusList{})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command audit compares the selected GithubIssues with their upstream issues and reports the missing,
+// drifted and orphaned issues, to stdout or a ConfigMap. It never writes to GitHub or the GithubIssues.
+//
+// It authenticates to GitHub with GITHUB_TOKEN and to the cluster with the current kubeconfig.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/audit"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/controller"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+func main() {
+	var namespace, selector, configMap, githubURL, centralSecretsNamespace string
+	flag.StringVar(&namespace, "namespace", "", "Audit the GithubIssues of this namespace. Empty audits every namespace.")
+	flag.StringVar(&selector, "selector", "", "Audit the GithubIssues matching this label selector.")
+	flag.StringVar(&configMap, "configmap", "",
+		"Write the report as JSON to this ConfigMap, as <namespace>/<name>, instead of printing it.")
+	flag.StringVar(&githubURL, "github-url", "", "GitHub Enterprise API base URL. Empty uses github.com.")
+	flag.StringVar(&centralSecretsNamespace, "central-secrets-namespace", "",
+		"Namespace description Secrets may be referenced in, like the operator flag of the same name.")
+	flag.Parse()
+
+	if err := run(namespace, selector, configMap, githubURL, centralSecretsNamespace); err != nil {
+		fmt.Fprintf(os.Stderr, "audit: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(namespace, selector, configMap, githubURL, centralSecretsNamespace string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(issuesv1alpha1.AddToScheme(scheme))
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	k8sClient, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %v", err)
+	}
+
+	githubClient := github.NewClient(&http.Client{Transport: &git.TokenFailoverTransport{Primary: os.Getenv("GITHUB_TOKEN")}})
+	if githubURL != "" {
+		if githubClient, err = githubClient.WithEnterpriseURLs(githubURL, githubURL); err != nil {
+			return fmt.Errorf("invalid --github-url: %v", err)
+		}
+	}
+
+	auditor := &audit.Auditor{
+		Client:      k8sClient,
+		IssueClient: &git.GitHubIssueClient{Client: githubClient},
+		Previewer: &controller.GithubIssueReconciler{
+			Client:                  k8sClient,
+			Scheme:                  scheme,
+			Log:                     zap.NewNop(),
+			CentralSecretsNamespace: centralSecretsNamespace,
+		},
+		Namespace: namespace,
+	}
+	if selector != "" {
+		if auditor.Selector, err = labels.Parse(selector); err != nil {
+			return fmt.Errorf("invalid --selector: %v", err)
+		}
+	}
+
+	report, err := auditor.Run(ctx)
+	if err != nil {
+		return err
+	}
+	if configMap == "" {
+		return report.Write(os.Stdout)
+	}
+	targetNamespace, name, ok := strings.Cut(configMap, "/")
+	if !ok || targetNamespace == "" || name == "" {
+		return fmt.Errorf("invalid --configmap %q, expected <namespace>/<name>", configMap)
+	}
+	return audit.WriteConfigMap(ctx, k8sClient, types.NamespacedName{Namespace: targetNamespace, Name: name}, report)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command scale-sim reconciles synthetic GithubIssues against an in-memory GitHub and reports the reconcile
+// throughput, the GitHub API calls and the queue latencies.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/scalesim"
+)
+
+func main() {
+	var opts scalesim.Options
+	flag.IntVar(&opts.Issues, "issues", 1000, "Number of synthetic GithubIssues.")
+	flag.IntVar(&opts.Repositories, "repositories", 10, "Number of repositories the issues are spread over.")
+	flag.IntVar(&opts.Workers, "workers", 1, "Number of concurrent reconciles, like --max-concurrent-reconciles.")
+	flag.IntVar(&opts.Passes, "passes", 2, "Number of times every issue is reconciled. The first pass creates the issues.")
+	flag.DurationVar(&opts.APILatency, "api-latency", 20*time.Millisecond, "Latency added to every GitHub API call.")
+	flag.IntVar(&opts.MaxInFlightPerRepo, "max-inflight-per-repo", 0,
+		"Reconciles in flight per repository, like --max-inflight-per-repo. 0 is unlimited.")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := scalesim.Run(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scale-sim: %v\n", err)
+		os.Exit(1)
+	}
+	if err := report.Write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "scale-sim: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,270 @@
+// Package audit compares GithubIssues with their upstream issues without writing to GitHub or the cluster,
+// for periodic compliance checks.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReportKey is the ConfigMap key holding the report.
+const ReportKey = "audit.json"
+
+// Kinds of findings.
+const (
+	// FindingMissing is a GithubIssue without an upstream issue.
+	FindingMissing = "Missing"
+	// FindingDrifted is an upstream issue that differs from its GithubIssue.
+	FindingDrifted = "Drifted"
+	// FindingOrphaned is an upstream issue carrying the marker of a GithubIssue that does not exist.
+	FindingOrphaned = "Orphaned"
+	// FindingError is a GithubIssue that could not be audited, e.g. because its repository could not be listed.
+	FindingError = "Error"
+)
+
+// Previewer renders the issue the operator would send to GitHub for a GithubIssue.
+type Previewer interface {
+	Preview(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error)
+}
+
+// Finding is a mismatch between a GithubIssue and GitHub.
+type Finding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Repo      string `json:"repo"`
+	Number    int    `json:"number,omitempty"`
+	// Fields lists the drifted fields of a Drifted finding.
+	Fields []string `json:"fields,omitempty"`
+	// Message explains Orphaned and Error findings.
+	Message string `json:"message,omitempty"`
+}
+
+// Result is the report of an audit.
+type Result struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Audited counts the GithubIssues selected for the audit.
+	Audited  int       `json:"audited"`
+	Findings []Finding `json:"findings"`
+}
+
+// Auditor lists the selected GithubIssues and the issues of their repositories, and reports the mismatches.
+// It only reads.
+type Auditor struct {
+	Client      client.Reader
+	IssueClient git.IssueClient
+	Previewer   Previewer
+	// Namespace restricts the audit to a namespace. Empty audits every namespace.
+	Namespace string
+	// Selector restricts the audit to the GithubIssues it matches. Nil audits every GithubIssue.
+	Selector labels.Selector
+}
+
+// Run audits the selected GithubIssues.
+func (a *Auditor) Run(ctx context.Context) (*Result, error) {
+	// Every GithubIssue is listed: an upstream issue is only orphaned if no GithubIssue carries its marker.
+	var all issuesv1alpha1.GithubIssueList
+	if err := a.Client.List(ctx, &all); err != nil {
+		return nil, fmt.Errorf("failed to list GithubIssues: %v", err)
+	}
+	known := map[string]bool{}
+	var selected []*issuesv1alpha1.GithubIssue
+	for i := range all.Items {
+		issueObject := &all.Items[i]
+		known[issueObject.Namespace+"/"+issueObject.Name] = true
+		if a.Namespace != "" && issueObject.Namespace != a.Namespace {
+			continue
+		}
+		if a.Selector != nil && !a.Selector.Matches(labels.Set(issueObject.Labels)) {
+			continue
+		}
+		selected = append(selected, issueObject)
+	}
+
+	report := &Result{GeneratedAt: time.Now().UTC(), Audited: len(selected), Findings: []Finding{}}
+	byRepo := map[string][]*issuesv1alpha1.GithubIssue{}
+	for _, issueObject := range selected {
+		byRepo[issueObject.RepoURL()] = append(byRepo[issueObject.RepoURL()], issueObject)
+	}
+	repos := make([]string, 0, len(byRepo))
+	for repoURL := range byRepo {
+		repos = append(repos, repoURL)
+	}
+	sort.Strings(repos)
+
+	for _, repoURL := range repos {
+		issueObjects := byRepo[repoURL]
+		upstream, err := a.listIssues(ctx, repoURL)
+		if err != nil {
+			for _, issueObject := range issueObjects {
+				report.Findings = append(report.Findings, Finding{
+					Kind: FindingError, Namespace: issueObject.Namespace, Name: issueObject.Name, Repo: repoURL, Message: err.Error(),
+				})
+			}
+			continue
+		}
+		for _, issueObject := range issueObjects {
+			finding, err := a.audit(ctx, issueObject, upstream)
+			if err != nil {
+				finding = &Finding{Kind: FindingError, Message: err.Error()}
+			}
+			if finding != nil {
+				finding.Namespace, finding.Name, finding.Repo = issueObject.Namespace, issueObject.Name, repoURL
+				report.Findings = append(report.Findings, *finding)
+			}
+		}
+		for _, issue := range upstream {
+			key, ok := git.ParseMarker(issue.Description)
+			if !ok || known[key] || issue.State == "closed" {
+				continue
+			}
+			report.Findings = append(report.Findings, Finding{
+				Kind: FindingOrphaned, Repo: repoURL, Number: issue.Number,
+				Message: fmt.Sprintf("The issue carries the marker of GithubIssue %s, which does not exist", key),
+			})
+		}
+	}
+	return report, nil
+}
+
+// listIssues lists the issues of the repository.
+func (a *Auditor) listIssues(ctx context.Context, repoURL string) ([]*git.Issue, error) {
+	owner, repo, err := git.ParseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	return a.IssueClient.List(ctx, owner, repo)
+}
+
+// audit compares a GithubIssue with its upstream issue. It returns nil when they match.
+func (a *Auditor) audit(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, upstream []*git.Issue) (*Finding, error) {
+	desired, err := a.Previewer.Preview(ctx, issueObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the issue: %v", err)
+	}
+	issue := findUpstream(issueObject, desired.Title, upstream)
+	if issue == nil {
+		return &Finding{Kind: FindingMissing}, nil
+	}
+
+	var fields []string
+	if issue.Title != desired.Title {
+		fields = append(fields, "title")
+	}
+	if issue.Description != desired.Body && issueObject.Spec.ConflictPolicy != issuesv1alpha1.ConflictPolicyGitHubWins {
+		fields = append(fields, "description")
+	}
+	for _, label := range desired.Labels {
+		if !slices.Contains(issue.Labels, label) {
+			fields = append(fields, "labels")
+			break
+		}
+	}
+	if len(desired.Assignees) > 0 && !sameSet(issue.Assignees, desired.Assignees) {
+		fields = append(fields, "assignees")
+	}
+	if issue.State == "closed" && issueObject.DeletionTimestamp.IsZero() {
+		fields = append(fields, "state")
+	}
+	if issue.Locked != issueObject.Spec.Locked {
+		fields = append(fields, "locked")
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return &Finding{Kind: FindingDrifted, Number: issue.Number, Fields: fields}, nil
+}
+
+// findUpstream finds the upstream issue of a GithubIssue the way the operator does: by its marker, then by
+// the issue number recorded in its status, then by title.
+func findUpstream(issueObject *issuesv1alpha1.GithubIssue, title string, upstream []*git.Issue) *git.Issue {
+	key := issueObject.Namespace + "/" + issueObject.Name
+	for _, issue := range upstream {
+		if marker, ok := git.ParseMarker(issue.Description); ok && marker == key {
+			return issue
+		}
+	}
+	for _, issue := range upstream {
+		if issueObject.Status.IssueNumber != 0 && issue.Number == issueObject.Status.IssueNumber {
+			return issue
+		}
+	}
+	for _, issue := range upstream {
+		if issue.Title == title {
+			return issue
+		}
+	}
+	return nil
+}
+
+func sameSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// Write prints the findings of the report as a table.
+func (r *Result) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "KIND\tGITHUBISSUE\tREPO\tISSUE\tDETAIL\n")
+	for _, finding := range r.Findings {
+		name, number, detail := "-", "-", finding.Message
+		if finding.Name != "" {
+			name = finding.Namespace + "/" + finding.Name
+		}
+		if finding.Number != 0 {
+			number = fmt.Sprintf("#%d", finding.Number)
+		}
+		if len(finding.Fields) > 0 {
+			detail = fmt.Sprintf("%v", finding.Fields)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", finding.Kind, name, finding.Repo, number, detail)
+	}
+	fmt.Fprintf(tw, "\n%d GithubIssues audited, %d findings\n", r.Audited, len(r.Findings))
+	return tw.Flush()
+}
+
+// WriteConfigMap writes the report as JSON to the target ConfigMap, creating it when missing.
+func WriteConfigMap(ctx context.Context, c client.Client, target types.NamespacedName, report *Result) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = c.Get(ctx, target, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap.Namespace, configMap.Name = target.Namespace, target.Name
+		configMap.Data = map[string]string{ReportKey: string(data)}
+		if err := c.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create report ConfigMap %s: %v", target, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get report ConfigMap %s: %v", target, err)
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[ReportKey] = string(data)
+	if err := c.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update report ConfigMap %s: %v", target, err)
+	}
+	return nil
+}
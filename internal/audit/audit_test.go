@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeIssueClient serves a fixed issue list. The auditor must not call anything else.
+type fakeIssueClient struct {
+	git.IssueClient
+	issues []*git.Issue
+}
+
+func (f *fakeIssueClient) List(_ context.Context, _, _ string) ([]*git.Issue, error) {
+	return f.issues, nil
+}
+
+// previewFunc adapts a function to the Previewer interface.
+type previewFunc func(githubIssue *issuesv1alpha1.GithubIssue) *git.DesiredIssue
+
+func (f previewFunc) Preview(_ context.Context, githubIssue *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error) {
+	return f(githubIssue), nil
+}
+
+var _ = Describe("Auditor", func() {
+	const repo = "https://github.com/example-org/example-repo"
+
+	newIssue := func(name, title string) *issuesv1alpha1.GithubIssue {
+		return &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a", Labels: map[string]string{"audit": "yes"}},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: repo, Title: title, Labels: []string{"bug"}},
+		}
+	}
+
+	It("reports missing, drifted and orphaned issues", func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			newIssue("synced", "Synced"),
+			newIssue("drifted", "Drifted"),
+			newIssue("missing", "Missing"),
+		).Build()
+		issues := &fakeIssueClient{issues: []*git.Issue{
+			{Number: 1, Title: "Synced", Description: git.WithMarker("Body", "team-a/synced"), State: "open", Labels: []string{"bug"}},
+			{Number: 2, Title: "Drifted upstream", Description: git.WithMarker("Edited", "team-a/drifted"), State: "closed", Labels: []string{"bug"}},
+			{Number: 3, Title: "Deleted", Description: git.WithMarker("Body", "team-a/deleted"), State: "open"},
+			{Number: 4, Title: "Unmanaged", Description: "Opened by hand", State: "open"},
+		}}
+		auditor := &Auditor{
+			Client:      k8sClient,
+			IssueClient: issues,
+			Previewer: previewFunc(func(githubIssue *issuesv1alpha1.GithubIssue) *git.DesiredIssue {
+				key := githubIssue.Namespace + "/" + githubIssue.Name
+				return &git.DesiredIssue{Title: githubIssue.Spec.Title, Body: git.WithMarker("Body", key), Labels: githubIssue.Spec.Labels}
+			}),
+		}
+
+		report, err := auditor.Run(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Audited).To(Equal(3))
+		Expect(report.Findings).To(ConsistOf(
+			Finding{Kind: FindingDrifted, Namespace: "team-a", Name: "drifted", Repo: repo, Number: 2, Fields: []string{"title", "description", "state"}},
+			Finding{Kind: FindingMissing, Namespace: "team-a", Name: "missing", Repo: repo},
+			Finding{Kind: FindingOrphaned, Repo: repo, Number: 3, Message: "The issue carries the marker of GithubIssue team-a/deleted, which does not exist"},
+		))
+
+		target := types.NamespacedName{Namespace: "operator-system", Name: "githubissue-audit"}
+		Expect(WriteConfigMap(context.Background(), k8sClient, target, report)).To(Succeed())
+		Expect(WriteConfigMap(context.Background(), k8sClient, target, report)).To(Succeed())
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(context.Background(), target, configMap)).To(Succeed())
+		var written Result
+		Expect(json.Unmarshal([]byte(configMap.Data[ReportKey]), &written)).To(Succeed())
+		Expect(written.Findings).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,153 @@
+// Package cleanup contains periodic maintenance jobs run by the manager.
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DuplicateCleaner periodically closes upstream issues that carry the same operator marker as an older issue.
+// Such duplicates are left behind by past create races. Each pass is idempotent, so a pass interrupted
+// halfway is simply completed by the next one.
+type DuplicateCleaner struct {
+	Client      client.Reader
+	IssueClient git.IssueClient
+	Interval    time.Duration
+	Log         *zap.Logger
+}
+
+// Start runs a cleanup pass every Interval until ctx is done. It implements manager.Runnable.
+func (c *DuplicateCleaner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := c.Run(ctx); err != nil {
+				c.Log.Error("Duplicate cleanup pass failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Run performs a single cleanup pass over every repository referenced by a GithubIssue.
+// A failing repository does not stop the pass; the errors are reported together.
+func (c *DuplicateCleaner) Run(ctx context.Context) error {
+	var issues issuesv1alpha1.GithubIssueList
+	if err := c.Client.List(ctx, &issues); err != nil {
+		metrics.DuplicateCleanupRuns.WithLabelValues("error").Inc()
+		return fmt.Errorf("failed to list GithubIssues: %v", err)
+	}
+
+	repos := map[string]bool{}
+	for _, issueObject := range issues.Items {
+		if repoURL := issueObject.RepoURL(); repoURL != "" {
+			repos[repoURL] = true
+		}
+	}
+
+	var failed []string
+	for repoURL := range repos {
+		if err := c.cleanRepository(ctx, repoURL); err != nil {
+			c.Log.Warn("Failed to clean up duplicates", zap.String("repository", repoURL), zap.Error(err))
+			failed = append(failed, repoURL)
+		}
+	}
+
+	if len(failed) > 0 {
+		metrics.DuplicateCleanupRuns.WithLabelValues("error").Inc()
+		sort.Strings(failed)
+		return fmt.Errorf("failed to clean up duplicates in %v", failed)
+	}
+	metrics.DuplicateCleanupRuns.WithLabelValues("success").Inc()
+	return nil
+}
+
+func (c *DuplicateCleaner) cleanRepository(ctx context.Context, repoURL string) error {
+	owner, repo, err := git.ParseRepoURL(repoURL)
+	if err != nil {
+		return err
+	}
+
+	upstream, err := c.IssueClient.List(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, group := range Duplicates(upstream) {
+		original := group[0]
+		for _, duplicate := range group[1:] {
+			if err := c.closeDuplicate(ctx, owner, repo, original, duplicate); err != nil {
+				lastErr = err
+				continue
+			}
+			metrics.DuplicateIssuesClosed.WithLabelValues(owner + "/" + repo).Inc()
+			c.Log.Info("Closed duplicate issue",
+				zap.String("repository", owner+"/"+repo),
+				zap.Int("duplicate", duplicate.Number),
+				zap.Int("original", original.Number),
+			)
+		}
+	}
+	return lastErr
+}
+
+// closeDuplicate comments on the duplicate before closing it, so a failed close never leaves an
+// issue closed without a pointer to the original.
+func (c *DuplicateCleaner) closeDuplicate(ctx context.Context, owner, repo string, original, duplicate *git.Issue) error {
+	comment := fmt.Sprintf("Closing as a duplicate of #%d, which tracks the same GithubIssue.", original.Number)
+	if _, err := c.IssueClient.Comment(ctx, owner, repo, duplicate.Number, comment); err != nil {
+		return err
+	}
+	if _, err := c.IssueClient.Close(ctx, owner, repo, duplicate.Number); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Duplicates groups open issues by operator marker and returns the groups holding more than one issue,
+// oldest first.
+func Duplicates(upstream []*git.Issue) [][]*git.Issue {
+	byMarker := map[string][]*git.Issue{}
+	for _, issue := range upstream {
+		if issue == nil || issue.State != "open" {
+			continue
+		}
+		if key, ok := git.ParseMarker(issue.Description); ok {
+			byMarker[key] = append(byMarker[key], issue)
+		}
+	}
+
+	keys := make([]string, 0, len(byMarker))
+	for key := range byMarker {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var groups [][]*git.Issue
+	for _, key := range keys {
+		group := byMarker[key]
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if !group[i].CreatedAt.Equal(group[j].CreatedAt) {
+				return group[i].CreatedAt.Before(group[j].CreatedAt)
+			}
+			return group[i].Number < group[j].Number
+		})
+		groups = append(groups, group)
+	}
+	return groups
+}
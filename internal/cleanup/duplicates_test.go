@@ -0,0 +1,91 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeIssueClient serves a fixed issue list and records comments and closes.
+type fakeIssueClient struct {
+	git.IssueClient
+	issues    []*git.Issue
+	failClose map[int]bool
+	comments  map[int]string
+	closed    []int
+}
+
+func (f *fakeIssueClient) List(_ context.Context, _, _ string) ([]*git.Issue, error) {
+	return f.issues, nil
+}
+
+func (f *fakeIssueClient) Comment(_ context.Context, _, _ string, issueNumber int, body string) (int64, error) {
+	f.comments[issueNumber] = body
+	return int64(issueNumber), nil
+}
+
+func (f *fakeIssueClient) Close(_ context.Context, _, _ string, issueNumber int) (*git.Issue, error) {
+	if f.failClose[issueNumber] {
+		return nil, fmt.Errorf("close failed")
+	}
+	f.closed = append(f.closed, issueNumber)
+	return &git.Issue{Number: issueNumber, State: "closed"}, nil
+}
+
+func markedIssue(number int, key string, created time.Time) *git.Issue {
+	return &git.Issue{
+		Number:      number,
+		State:       "open",
+		Description: git.WithMarker("body", key),
+		CreatedAt:   created,
+	}
+}
+
+var _ = Describe("DuplicateCleaner", func() {
+	now := time.Now()
+
+	It("groups open issues by marker, oldest first", func() {
+		groups := Duplicates([]*git.Issue{
+			markedIssue(3, "default/a", now),
+			markedIssue(1, "default/a", now.Add(-time.Hour)),
+			markedIssue(2, "default/b", now),
+			{Number: 4, State: "open", Description: "no marker"},
+			{Number: 5, State: "closed", Description: git.Marker("default/b")},
+		})
+		Expect(groups).To(HaveLen(1))
+		Expect(groups[0][0].Number).To(Equal(1))
+		Expect(groups[0][1].Number).To(Equal(3))
+	})
+
+	It("closes newer duplicates with a reference to the oldest issue", func() {
+		scheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/test/test", Title: "a"},
+		}).Build()
+		issueClient := &fakeIssueClient{
+			issues: []*git.Issue{
+				markedIssue(7, "default/a", now.Add(-2*time.Hour)),
+				markedIssue(8, "default/a", now.Add(-time.Hour)),
+				markedIssue(9, "default/a", now),
+			},
+			failClose: map[int]bool{8: true},
+			comments:  map[int]string{},
+		}
+		cleaner := &DuplicateCleaner{Client: k8sClient, IssueClient: issueClient, Log: zap.NewNop()}
+
+		Expect(cleaner.Run(context.Background())).NotTo(Succeed())
+		Expect(issueClient.closed).To(ConsistOf(9))
+		Expect(issueClient.comments).To(HaveKeyWithValue(9, ContainSubstring("#7")))
+	})
+})
@@ -0,0 +1,179 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MarkerMigration adds the operator marker to the upstream issues created by operator versions that predate it,
+// and links them to their GithubIssues. Without the marker, a change of how issues are found, e.g. a title
+// prefix, would orphan them and open new issues. It runs once; issues already carrying a marker are left alone,
+// so it is safe to run again.
+type MarkerMigration struct {
+	Client      client.Client
+	IssueClient git.IssueClient
+	// Repos lists the repository URLs to migrate. Empty migrates every repository referenced by a GithubIssue.
+	Repos []string
+	// LegacyFooter must match the body of an issue for it to be claimed by title. Nil claims issues by title alone.
+	LegacyFooter *regexp.Regexp
+	Log          *zap.Logger
+}
+
+// Start runs the migration once. It implements manager.Runnable.
+func (m *MarkerMigration) Start(ctx context.Context) error {
+	if err := m.Run(ctx); err != nil {
+		m.Log.Error("Legacy marker migration failed", zap.Error(err))
+	}
+	return nil
+}
+
+// Run migrates the repositories. A failing repository does not stop the migration; the errors are reported together.
+func (m *MarkerMigration) Run(ctx context.Context) error {
+	var issues issuesv1alpha1.GithubIssueList
+	if err := m.Client.List(ctx, &issues); err != nil {
+		return fmt.Errorf("failed to list GithubIssues: %v", err)
+	}
+
+	byRepo := map[string][]*issuesv1alpha1.GithubIssue{}
+	for i := range issues.Items {
+		issueObject := &issues.Items[i]
+		if key, err := repoKey(issueObject.RepoURL()); err == nil {
+			byRepo[key] = append(byRepo[key], issueObject)
+		}
+	}
+	repos := m.Repos
+	if len(repos) == 0 {
+		for _, issueObjects := range byRepo {
+			repos = append(repos, issueObjects[0].RepoURL())
+		}
+		sort.Strings(repos)
+	}
+
+	var failed []string
+	for _, repoURL := range repos {
+		key, err := repoKey(repoURL)
+		if err == nil {
+			err = m.migrateRepository(ctx, repoURL, byRepo[key])
+		}
+		if err != nil {
+			m.Log.Warn("Failed to migrate legacy issues", zap.String("repository", repoURL), zap.Error(err))
+			failed = append(failed, repoURL)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to migrate legacy issues in %v", failed)
+	}
+	return nil
+}
+
+func (m *MarkerMigration) migrateRepository(ctx context.Context, repoURL string, issueObjects []*issuesv1alpha1.GithubIssue) error {
+	owner, repo, err := git.ParseRepoURL(repoURL)
+	if err != nil {
+		return err
+	}
+	upstream, err := m.IssueClient.List(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	marked := map[string]bool{}
+	var legacy []*git.Issue
+	for _, issue := range upstream {
+		if key, ok := git.ParseMarker(issue.Description); ok {
+			marked[key] = true
+		} else {
+			legacy = append(legacy, issue)
+		}
+	}
+
+	var lastErr error
+	migrated := 0
+	for _, issueObject := range issueObjects {
+		key := issueObject.Namespace + "/" + issueObject.Name
+		if marked[key] {
+			continue
+		}
+		candidates := m.legacyIssues(issueObject, legacy)
+		if len(candidates) > 1 {
+			numbers := make([]int, 0, len(candidates))
+			for _, issue := range candidates {
+				numbers = append(numbers, issue.Number)
+			}
+			m.Log.Warn("Skipping GithubIssue matching several legacy issues",
+				zap.String("githubissue", key), zap.String("repository", owner+"/"+repo), zap.Ints("issues", numbers))
+			continue
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		if err := m.link(ctx, owner, repo, issueObject, candidates[0]); err != nil {
+			lastErr = err
+			continue
+		}
+		migrated++
+	}
+	m.Log.Info("Migrated legacy issues", zap.String("repository", owner+"/"+repo), zap.Int("issues", migrated))
+	return lastErr
+}
+
+// legacyIssues returns the unmarked upstream issues the GithubIssue created: the issue number recorded in its
+// status, or else the issues with its title whose body matches LegacyFooter.
+func (m *MarkerMigration) legacyIssues(issueObject *issuesv1alpha1.GithubIssue, legacy []*git.Issue) []*git.Issue {
+	if number := issueObject.Status.IssueNumber; number != 0 {
+		for _, issue := range legacy {
+			if issue.Number == number {
+				return []*git.Issue{issue}
+			}
+		}
+		return nil
+	}
+	var candidates []*git.Issue
+	for _, issue := range legacy {
+		if issue.Title != issueObject.Spec.Title {
+			continue
+		}
+		if m.LegacyFooter != nil && !m.LegacyFooter.MatchString(issue.Description) {
+			continue
+		}
+		candidates = append(candidates, issue)
+	}
+	return candidates
+}
+
+// link adds the marker of the GithubIssue to the upstream issue and records the issue number in its status.
+func (m *MarkerMigration) link(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	key := issueObject.Namespace + "/" + issueObject.Name
+	desired := &git.DesiredIssue{Title: issue.Title, Body: git.WithMarker(issue.Description, key)}
+	if _, err := m.IssueClient.Edit(ctx, owner, repo, issue.Number, desired); err != nil {
+		return fmt.Errorf("failed to add the marker to issue #%d: %v", issue.Number, err)
+	}
+	m.Log.Info("Added marker to legacy issue",
+		zap.String("githubissue", key), zap.String("repository", owner+"/"+repo), zap.Int("issue", issue.Number))
+
+	if issueObject.Status.IssueNumber != 0 {
+		return nil
+	}
+	patch := client.MergeFrom(issueObject.DeepCopy())
+	issueObject.Status.IssueNumber = issue.Number
+	if err := m.Client.Status().Patch(ctx, issueObject, patch); err != nil {
+		return fmt.Errorf("failed to link GithubIssue %s to issue #%d: %v", key, issue.Number, err)
+	}
+	return nil
+}
+
+// repoKey normalizes a repository URL to its lower-cased owner/name.
+func repoKey(repoURL string) (string, error) {
+	owner, repo, err := git.ParseRepoURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(owner + "/" + repo), nil
+}
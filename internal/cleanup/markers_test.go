@@ -0,0 +1,87 @@
+package cleanup
+
+import (
+	"context"
+	"regexp"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// editingIssueClient serves a fixed issue list and records the bodies of edited issues.
+type editingIssueClient struct {
+	git.IssueClient
+	issues []*git.Issue
+	edited map[int]string
+}
+
+func (f *editingIssueClient) List(_ context.Context, _, _ string) ([]*git.Issue, error) {
+	return f.issues, nil
+}
+
+func (f *editingIssueClient) Edit(_ context.Context, _, _ string, issueNumber int, desired *git.DesiredIssue) (*git.Issue, error) {
+	f.edited[issueNumber] = desired.Body
+	return &git.Issue{Number: issueNumber, Title: desired.Title, Description: desired.Body}, nil
+}
+
+var _ = Describe("MarkerMigration", func() {
+	const repo = "https://github.com/test/test"
+
+	It("marks the legacy issues of GithubIssues and links them", func() {
+		scheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		byTitle := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "by-title", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: repo, Title: "Legacy"},
+		}
+		byNumber := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "by-number", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: repo, Title: "Renamed"},
+			Status:     issuesv1alpha1.GithubIssueStatus{IssueNumber: 2},
+		}
+		ambiguous := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "ambiguous", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: repo, Title: "Twice"},
+		}
+		marked := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "marked", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: repo, Title: "Marked"},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(byTitle, byNumber, ambiguous, marked).
+			WithStatusSubresource(byTitle, byNumber, ambiguous, marked).Build()
+		issueClient := &editingIssueClient{
+			issues: []*git.Issue{
+				{Number: 1, Title: "Legacy", Description: "Body\n\nCreated by github-issue-operator", State: "closed"},
+				{Number: 2, Title: "Old title", Description: "Body", State: "open"},
+				{Number: 3, Title: "Twice", Description: "Created by github-issue-operator", State: "open"},
+				{Number: 4, Title: "Twice", Description: "Created by github-issue-operator", State: "open"},
+				{Number: 5, Title: "Marked", Description: git.WithMarker("Body", "default/marked"), State: "open"},
+				{Number: 6, Title: "Legacy", Description: "Opened by hand", State: "open"},
+			},
+			edited: map[int]string{},
+		}
+		migration := &MarkerMigration{
+			Client:       k8sClient,
+			IssueClient:  issueClient,
+			LegacyFooter: regexp.MustCompile(`Created by github-issue-operator`),
+			Log:          zap.NewNop(),
+		}
+
+		Expect(migration.Run(context.Background())).To(Succeed())
+		Expect(issueClient.edited).To(Equal(map[int]string{
+			1: git.WithMarker("Body\n\nCreated by github-issue-operator", "default/by-title"),
+			2: git.WithMarker("Body", "default/by-number"),
+		}))
+		linked := &issuesv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "by-title", Namespace: "default"}, linked)).To(Succeed())
+		Expect(linked.Status.IssueNumber).To(Equal(1))
+	})
+})
@@ -0,0 +1,146 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SnapshotKey is the ConfigMap key holding the snapshot.
+const SnapshotKey = "snapshot.json"
+
+// Upstream states recorded in a snapshot.
+const (
+	SnapshotStateOpen    = "open"
+	SnapshotStateClosed  = "closed"
+	SnapshotStateUnknown = "unknown"
+)
+
+// SnapshotEntry links a GithubIssue to its upstream issue.
+type SnapshotEntry struct {
+	UID       types.UID `json:"uid"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	NodeID    string    `json:"nodeID,omitempty"`
+	State     string    `json:"state"`
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+
+// SnapshotExporter periodically writes the upstream issue of every GithubIssue to a ConfigMap, so the linkage
+// can be rebuilt after an etcd restore or a CRD reinstall instead of creating the issues again.
+// GithubIssues that were never synced are left out.
+type SnapshotExporter struct {
+	Client   client.Client
+	Target   types.NamespacedName
+	Interval time.Duration
+	Log      *zap.Logger
+}
+
+// Start writes a snapshot every Interval until ctx is done. It implements manager.Runnable.
+func (e *SnapshotExporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := e.Run(ctx); err != nil {
+				e.Log.Error("Snapshot export failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Run writes a single snapshot.
+func (e *SnapshotExporter) Run(ctx context.Context) error {
+	var issues issuesv1alpha1.GithubIssueList
+	if err := e.Client.List(ctx, &issues); err != nil {
+		return fmt.Errorf("failed to list GithubIssues: %v", err)
+	}
+	data, err := json.Marshal(Snapshot(issues.Items))
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = e.Client.Get(ctx, e.Target, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap.Namespace, configMap.Name = e.Target.Namespace, e.Target.Name
+		configMap.Data = map[string]string{SnapshotKey: string(data)}
+		if err := e.Client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create snapshot ConfigMap %s: %v", e.Target, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot ConfigMap %s: %v", e.Target, err)
+	}
+	if configMap.Data[SnapshotKey] == string(data) {
+		return nil
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[SnapshotKey] = string(data)
+	if err := e.Client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update snapshot ConfigMap %s: %v", e.Target, err)
+	}
+	e.Log.Debug("Wrote issue snapshot", zap.Int("issues", len(issues.Items)))
+	return nil
+}
+
+// Snapshot returns the entries of the synced GithubIssues, ordered by namespace and name.
+func Snapshot(issues []issuesv1alpha1.GithubIssue) []SnapshotEntry {
+	entries := []SnapshotEntry{}
+	for _, issueObject := range issues {
+		if issueObject.Status.IssueNumber == 0 {
+			continue
+		}
+		entries = append(entries, SnapshotEntry{
+			UID:       issueObject.UID,
+			Namespace: issueObject.Namespace,
+			Name:      issueObject.Name,
+			Repo:      issueObject.RepoURL(),
+			Number:    issueObject.Status.IssueNumber,
+			NodeID:    issueObject.Status.NodeID,
+			State:     snapshotState(issueObject.Status.Conditions),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// snapshotState reads the upstream state from the IssueIsOpen condition.
+func snapshotState(conditions []metav1.Condition) string {
+	condition := meta.FindStatusCondition(conditions, "IssueIsOpen")
+	switch {
+	case condition == nil:
+		return SnapshotStateUnknown
+	case condition.Status == metav1.ConditionTrue:
+		return SnapshotStateOpen
+	case condition.Status == metav1.ConditionFalse:
+		return SnapshotStateClosed
+	default:
+		return SnapshotStateUnknown
+	}
+}
@@ -0,0 +1,53 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("SnapshotExporter", func() {
+	It("writes the synced issues to the snapshot ConfigMap", func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+
+		synced := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "team-a", UID: "uid-1"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/example-org/example-repo"},
+			Status: issuesv1alpha1.GithubIssueStatus{
+				IssueNumber: 7,
+				Conditions:  []metav1.Condition{{Type: "IssueIsOpen", Status: metav1.ConditionFalse}},
+			},
+		}
+		pending := &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "team-a", UID: "uid-2"}}
+		k8sClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(synced, pending).Build()
+		target := types.NamespacedName{Namespace: "operator-system", Name: "githubissue-snapshot"}
+		exporter := &SnapshotExporter{Client: k8sClient, Target: target, Log: zap.NewNop()}
+
+		Expect(exporter.Run(context.Background())).To(Succeed())
+		Expect(exporter.Run(context.Background())).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(context.Background(), target, configMap)).To(Succeed())
+		var entries []SnapshotEntry
+		Expect(json.Unmarshal([]byte(configMap.Data[SnapshotKey]), &entries)).To(Succeed())
+		Expect(entries).To(Equal([]SnapshotEntry{{
+			UID:       "uid-1",
+			Namespace: "team-a",
+			Name:      "synced",
+			Repo:      "https://github.com/example-org/example-repo",
+			Number:    7,
+			State:     SnapshotStateClosed,
+		}}))
+	})
+})
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// CreateIssueAnnotation on an object of a scanned kind holds the JSON spec of a GithubIssue to file for it.
+	CreateIssueAnnotation = "issues.dana.io/create"
+	// SourceObjectAnnotation records the object a GithubIssue was materialized from, as Kind/name.
+	SourceObjectAnnotation = "issues.dana.io/source-object"
+)
+
+// AnnotationScanner materializes a GithubIssue from the CreateIssueAnnotation of the objects of a kind, so
+// teams can request issues from their existing manifests. The GithubIssue is named after the kind and the
+// object and is owned by it: removing the annotation or deleting the object deletes the GithubIssue, which
+// closes its upstream issue. Only the metadata of the objects is watched.
+type AnnotationScanner struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      *zap.Logger
+	Recorder record.EventRecorder
+	// GVK is the kind scanned. The operator must be allowed to get, list and watch it, and to update its finalizers.
+	GVK schema.GroupVersionKind
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues,verbs=get;list;watch;create;update;patch;delete
+
+func (r *AnnotationScanner) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	object := &metav1.PartialObjectMetadata{}
+	object.SetGroupVersionKind(r.GVK)
+	if err := r.Get(ctx, req.NamespacedName, object); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch scanned object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	object.SetGroupVersionKind(r.GVK)
+
+	name := annotationIssueName(r.GVK.Kind, object.Name)
+	payload, ok := object.Annotations[CreateIssueAnnotation]
+	if !ok || !object.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.deleteIssue(ctx, object, name)
+	}
+
+	spec, err := parseIssueAnnotation(payload)
+	if err != nil {
+		log.Warn("Ignoring invalid issue annotation", zap.Error(err))
+		if r.Recorder != nil {
+			r.Recorder.Eventf(object, corev1.EventTypeWarning, "InvalidIssueAnnotation",
+				"Invalid %s annotation: %v", CreateIssueAnnotation, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	issueObject := &issuesv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: object.Namespace},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, issueObject, func() error {
+		if issueObject.ResourceVersion != "" && !metav1.IsControlledBy(issueObject, object) {
+			return fmt.Errorf("GithubIssue %s exists and is not owned by %s %s", name, r.GVK.Kind, object.Name)
+		}
+		if issueObject.Annotations == nil {
+			issueObject.Annotations = map[string]string{}
+		}
+		issueObject.Annotations[SourceObjectAnnotation] = r.GVK.Kind + "/" + object.Name
+		issueObject.Spec = spec
+		return controllerutil.SetControllerReference(object, issueObject, r.Scheme)
+	})
+	if err != nil {
+		if r.Recorder != nil {
+			r.Recorder.Eventf(object, corev1.EventTypeWarning, "IssueFailed", "Failed to apply GithubIssue %s: %v", name, err)
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to apply issue %s: %v", name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("Applied issue from annotation", zap.String("githubIssue", name), zap.String("operation", string(result)))
+	}
+	return ctrl.Result{}, nil
+}
+
+// deleteIssue deletes the GithubIssue materialized from the annotation of the object, if any.
+func (r *AnnotationScanner) deleteIssue(ctx context.Context, object *metav1.PartialObjectMetadata, name string) error {
+	issueObject := &issuesv1alpha1.GithubIssue{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: object.Namespace, Name: name}, issueObject); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(issueObject, object) || !issueObject.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if err := r.Delete(ctx, issueObject); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete issue %s: %v", name, err)
+	}
+	r.Log.Info("Deleted issue whose annotation was removed", zap.String("namespace", object.Namespace), zap.String("githubIssue", name))
+	return nil
+}
+
+// parseIssueAnnotation decodes the annotation into a GithubIssue spec. Unknown fields are rejected so typos
+// don't go unnoticed.
+func parseIssueAnnotation(payload string) (issuesv1alpha1.GithubIssueSpec, error) {
+	var spec issuesv1alpha1.GithubIssueSpec
+	decoder := json.NewDecoder(bytes.NewReader([]byte(payload)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return spec, err
+	}
+	if spec.Title == "" {
+		return spec, fmt.Errorf("title is required")
+	}
+	if spec.Repo == "" && spec.RepositoryRef == nil {
+		return spec, fmt.Errorf("one of repo and repositoryRef is required")
+	}
+	return spec, nil
+}
+
+// annotationIssueName derives the name of the GithubIssue of an annotated object from its kind and name.
+func annotationIssueName(kind, name string) string {
+	return issueSetIssueName(strings.ToLower(kind), name)
+}
+
+// SetupWithManager sets up the controller with the Manager. Cluster-scoped kinds are not supported: the
+// GithubIssue is created in the namespace of the object.
+func (r *AnnotationScanner) SetupWithManager(mgr ctrl.Manager) error {
+	object := &metav1.PartialObjectMetadata{}
+	object.SetGroupVersionKind(r.GVK)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(object, builder.OnlyMetadata, builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		Owns(&issuesv1alpha1.GithubIssue{}).
+		Named("annotationscanner-" + strings.ToLower(strings.ReplaceAll(r.GVK.GroupKind().String(), ".", "-"))).
+		Complete(r)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("AnnotationScanner", func() {
+	var (
+		scanner    *AnnotationScanner
+		recorder   *record.FakeRecorder
+		deployment *appsv1.Deployment
+		key        = types.NamespacedName{Name: "api", Namespace: "default"}
+		issueKey   = types.NamespacedName{Name: "deployment-api", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(testScheme)).To(Succeed())
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, Annotations: map[string]string{
+				CreateIssueAnnotation: `{"repo": "https://github.com/example-org/example-repo", "title": "Migrate api to the new base image", "labels": ["chore"]}`,
+			}},
+		}
+		recorder = record.NewFakeRecorder(10)
+		scanner = &AnnotationScanner{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(deployment).Build(),
+			Scheme:   testScheme,
+			Log:      zap.NewNop(),
+			Recorder: recorder,
+			GVK:      appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		}
+	})
+
+	reconcile := func() {
+		_, err := scanner.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("files the GithubIssue of the annotation, owned by the object", func() {
+		reconcile()
+		issueObject := &issuesv1alpha1.GithubIssue{}
+		Expect(scanner.Get(context.Background(), issueKey, issueObject)).To(Succeed())
+		Expect(issueObject.Spec.Title).To(Equal("Migrate api to the new base image"))
+		Expect(issueObject.Spec.Labels).To(Equal([]string{"chore"}))
+		Expect(issueObject.Annotations).To(HaveKeyWithValue(SourceObjectAnnotation, "Deployment/api"))
+		Expect(issueObject.OwnerReferences).To(HaveLen(1))
+		Expect(issueObject.OwnerReferences[0].Kind).To(Equal("Deployment"))
+	})
+
+	It("deletes the GithubIssue once the annotation is removed", func() {
+		reconcile()
+		Expect(scanner.Get(context.Background(), key, deployment)).To(Succeed())
+		delete(deployment.Annotations, CreateIssueAnnotation)
+		Expect(scanner.Update(context.Background(), deployment)).To(Succeed())
+
+		reconcile()
+		err := scanner.Get(context.Background(), issueKey, &issuesv1alpha1.GithubIssue{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("reports an invalid annotation on the object", func() {
+		Expect(scanner.Get(context.Background(), key, deployment)).To(Succeed())
+		deployment.Annotations[CreateIssueAnnotation] = `{"title": "Typo", "lables": ["bug"]}`
+		Expect(scanner.Update(context.Background(), deployment)).To(Succeed())
+
+		reconcile()
+		Expect(recorder.Events).To(Receive(ContainSubstring("InvalidIssueAnnotation")))
+		err := scanner.Get(context.Background(), issueKey, &issuesv1alpha1.GithubIssue{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
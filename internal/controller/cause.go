@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcileRequestAnnotation can be set or bumped on a GithubIssue to request a reconcile manually.
+const ReconcileRequestAnnotation = "issues.dana.io/reconcile-requested-at"
+
+// Reconcile causes reported in logs and in the reconcile trigger metric.
+const (
+	causeGeneration = "generation"
+	causeResync     = "resync"
+	causeWebhook    = "webhook"
+	causeAnnotation = "annotation"
+	causeDeletion   = "deletion"
+	causeReference  = "reference" // a ConfigMap or Secret referenced by the spec changed
+	causeOther      = "other"
+	// causeRequeue is used when no event queued the request, i.e. it was requeued by a previous reconcile.
+	causeRequeue = "requeue"
+)
+
+// causeTracker remembers why a request was queued until the reconcile for it starts.
+type causeTracker struct {
+	mu     sync.Mutex
+	causes map[types.NamespacedName]string
+	// repos counts the queued requests per repository. Optional.
+	repos *repoQueue
+}
+
+func newCauseTracker() *causeTracker {
+	return &causeTracker{causes: map[types.NamespacedName]string{}}
+}
+
+// record stores the cause of a queued request. Events coalesced into an already queued request keep the first
+// cause, except that any other cause replaces a resync: a resync reconcile may return early, which would hold
+// back the change that was coalesced into it.
+func (t *causeTracker) record(key types.NamespacedName, cause string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if recorded, ok := t.causes[key]; !ok || recorded == causeResync {
+		t.causes[key] = cause
+	}
+}
+
+// take returns and clears the cause of a request, counting it in the trigger metric.
+func (t *causeTracker) take(key types.NamespacedName) string {
+	cause := causeRequeue
+	if t != nil {
+		t.mu.Lock()
+		if recorded, ok := t.causes[key]; ok {
+			cause = recorded
+			delete(t.causes, key)
+		}
+		t.mu.Unlock()
+	}
+	metrics.ReconcileTriggers.WithLabelValues(cause).Inc()
+	return cause
+}
+
+// enqueue records the cause and queues the request for obj.
+func (t *causeTracker) enqueue(obj client.Object, cause string, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if obj == nil {
+		return
+	}
+	key := client.ObjectKeyFromObject(obj)
+	t.record(key, cause)
+	t.repos.enqueued(obj)
+	q.Add(reconcile.Request{NamespacedName: key})
+}
+
+// handler returns an event handler that queues the changed object, tagged with the cause of the change.
+func (t *causeTracker) handler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			t.enqueue(e.Object, causeGeneration, q)
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if syncBookkeepingOnly(e.ObjectOld, e.ObjectNew) {
+				return
+			}
+			t.enqueue(e.ObjectNew, updateCause(e.ObjectOld, e.ObjectNew), q)
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			t.enqueue(e.Object, causeDeletion, q)
+		},
+		GenericFunc: func(_ context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			t.enqueue(e.Object, causeWebhook, q)
+		},
+	}
+}
+
+// updateCause classifies an update event by what changed between the two versions of the object.
+func updateCause(old, new client.Object) string {
+	switch {
+	case old.GetResourceVersion() == new.GetResourceVersion():
+		return causeResync
+	case !new.GetDeletionTimestamp().IsZero() && old.GetDeletionTimestamp().IsZero():
+		return causeDeletion
+	case old.GetGeneration() != new.GetGeneration():
+		return causeGeneration
+	case old.GetAnnotations()[ReconcileRequestAnnotation] != new.GetAnnotations()[ReconcileRequestAnnotation]:
+		return causeAnnotation
+	default:
+		return causeOther
+	}
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("reconcile causes", func() {
+	issueVersion := func(resourceVersion string, generation int64, annotations map[string]string) *issuesv1alpha1.GithubIssue {
+		return &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{
+			Name:            "a",
+			Namespace:       "default",
+			ResourceVersion: resourceVersion,
+			Generation:      generation,
+			Annotations:     annotations,
+		}}
+	}
+
+	It("classifies update events", func() {
+		Expect(updateCause(issueVersion("1", 1, nil), issueVersion("1", 1, nil))).To(Equal(causeResync))
+		Expect(updateCause(issueVersion("1", 1, nil), issueVersion("2", 2, nil))).To(Equal(causeGeneration))
+		Expect(updateCause(issueVersion("1", 1, nil), issueVersion("2", 1, map[string]string{
+			ReconcileRequestAnnotation: "now",
+		}))).To(Equal(causeAnnotation))
+		Expect(updateCause(issueVersion("1", 1, nil), issueVersion("2", 1, nil))).To(Equal(causeOther))
+
+		deleting := issueVersion("2", 1, nil)
+		deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		Expect(updateCause(issueVersion("1", 1, nil), deleting)).To(Equal(causeDeletion))
+	})
+
+	It("keeps the first cause until the reconcile takes it", func() {
+		tracker := newCauseTracker()
+		key := types.NamespacedName{Namespace: "default", Name: "a"}
+
+		tracker.record(key, causeWebhook)
+		tracker.record(key, causeResync)
+		Expect(tracker.take(key)).To(Equal(causeWebhook))
+		Expect(tracker.take(key)).To(Equal(causeRequeue))
+	})
+
+	It("lets a change coalesced behind a queued resync replace its cause", func() {
+		tracker := newCauseTracker()
+		key := types.NamespacedName{Namespace: "default", Name: "a"}
+
+		for _, cause := range []string{causeGeneration, causeDeletion, causeAnnotation, causeReference, causeWebhook} {
+			tracker.record(key, causeResync)
+			tracker.record(key, cause)
+			tracker.record(key, causeResync)
+			Expect(tracker.take(key)).To(Equal(cause))
+		}
+	})
+})
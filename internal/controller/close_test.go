@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeCloseClient records the comments it is sent and fails the first closeFailures closes.
+type fakeCloseClient struct {
+	*fakeMirrorClient
+	comments      []string
+	closeFailures int
+}
+
+func (f *fakeCloseClient) Comment(_ context.Context, _, _ string, _ int, body string) (int64, error) {
+	f.comments = append(f.comments, body)
+	return int64(len(f.comments)), nil
+}
+
+func (f *fakeCloseClient) Close(ctx context.Context, owner, repo string, number int) (*git.Issue, error) {
+	if f.closeFailures > 0 {
+		f.closeFailures--
+		return nil, errors.New("server error")
+	}
+	return f.fakeMirrorClient.Close(ctx, owner, repo, number)
+}
+
+var _ = Describe("closing the issue", func() {
+	It("posts the close comment once when the close is retried", func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", CloseComment: "Fixed in v2"},
+		}
+		upstream := &fakeCloseClient{
+			fakeMirrorClient: &fakeMirrorClient{issues: map[int]*git.Issue{1: {Number: 1, State: "open"}}},
+			closeFailures:    1,
+		}
+		reconciler := &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Clients:  &git.Clients{Default: upstream},
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+
+		err := reconciler.CloseIssue(context.Background(), "org", "repo", issueObject, upstream.issues[1])
+		Expect(err).To(MatchError(ContainSubstring("failed to close issue")))
+		Expect(issueObject.Status.CloseCommentID).To(Equal(int64(1)))
+
+		Expect(reconciler.CloseIssue(context.Background(), "org", "repo", issueObject, upstream.issues[1])).To(Succeed())
+		Expect(upstream.comments).To(Equal([]string{"Fixed in v2"}))
+		Expect(upstream.issues[1].State).To(Equal("closed"))
+	})
+})
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// syncComments posts, edits and deletes the managed comments to match spec.comments. Posted comment IDs are
+// recorded in status.comments, which is written after every upstream change so a comment is never posted twice.
+// Changes are merged three ways: a managed comment is only edited or deleted while its upstream body is still
+// the one the operator last wrote, so edits made on GitHub are never overwritten, and comments the operator
+// did not post are never touched.
+func (r *GithubIssueReconciler) syncComments(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	desired := issueObject.Spec.Comments
+	posted := issueObject.Status.Comments
+
+	var synced []issuesv1alpha1.CommentStatus
+	for _, comment := range desired {
+		hash := commentHash(comment.Body)
+		index := slices.IndexFunc(posted, func(status issuesv1alpha1.CommentStatus) bool { return status.Name == comment.Name })
+		if index >= 0 && posted[index].Hash == hash {
+			synced = append(synced, posted[index])
+			continue
+		}
+
+		if index >= 0 {
+			edited, err := r.editedUpstream(ctx, owner, repo, posted[index])
+			if err != nil && !errors.Is(err, git.ErrCommentNotFound) {
+				return fmt.Errorf("failed to get comment %s: %v", comment.Name, err)
+			}
+			if edited {
+				r.logger(ctx).Warn("Managed comment was edited on GitHub, not overwriting it", zap.String("comment", comment.Name))
+				r.Recorder.Eventf(issueObject, corev1.EventTypeWarning, "CommentEdited",
+					"Comment %s was edited on GitHub and is no longer updated from spec.comments", comment.Name)
+				synced = append(synced, posted[index])
+				continue
+			}
+			if err == nil {
+				err = r.issueClient(ctx).EditComment(ctx, owner, repo, posted[index].ID, comment.Body)
+			}
+			if err == nil {
+				r.logger(ctx).Info("Edited managed comment", zap.String("comment", comment.Name))
+				synced = append(synced, issuesv1alpha1.CommentStatus{Name: comment.Name, ID: posted[index].ID, Hash: hash})
+				if err := r.recordComments(ctx, issueObject, synced, posted); err != nil {
+					return err
+				}
+				continue
+			}
+			if !errors.Is(err, git.ErrCommentNotFound) {
+				return fmt.Errorf("failed to edit comment %s: %v", comment.Name, err)
+			}
+			// Deleted upstream: post it again.
+		}
+
+		id, err := r.issueClient(ctx).Comment(ctx, owner, repo, issue.Number, comment.Body)
+		if err != nil {
+			return fmt.Errorf("failed to post comment %s: %v", comment.Name, err)
+		}
+		r.logger(ctx).Info("Posted managed comment", zap.String("comment", comment.Name))
+		synced = append(synced, issuesv1alpha1.CommentStatus{Name: comment.Name, ID: id, Hash: hash})
+		if err := r.recordComments(ctx, issueObject, synced, posted); err != nil {
+			return err
+		}
+	}
+
+	for _, status := range posted {
+		if slices.ContainsFunc(desired, func(comment issuesv1alpha1.IssueComment) bool { return comment.Name == status.Name }) {
+			continue
+		}
+		edited, err := r.editedUpstream(ctx, owner, repo, status)
+		if errors.Is(err, git.ErrCommentNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get comment %s: %v", status.Name, err)
+		}
+		if edited {
+			r.logger(ctx).Info("Leaving managed comment edited on GitHub in place", zap.String("comment", status.Name))
+			continue
+		}
+		if err := r.issueClient(ctx).DeleteComment(ctx, owner, repo, status.ID); err != nil {
+			return fmt.Errorf("failed to delete comment %s: %v", status.Name, err)
+		}
+		r.logger(ctx).Info("Deleted managed comment", zap.String("comment", status.Name))
+	}
+
+	if slices.Equal(synced, issueObject.Status.Comments) {
+		return nil
+	}
+	issueObject.Status.Comments = synced
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// editedUpstream reports whether the upstream body of a managed comment differs from the body the operator
+// last wrote. It returns git.ErrCommentNotFound for a comment deleted upstream.
+func (r *GithubIssueReconciler) editedUpstream(ctx context.Context, owner, repo string, status issuesv1alpha1.CommentStatus) (bool, error) {
+	body, err := r.issueClient(ctx).GetComment(ctx, owner, repo, status.ID)
+	if err != nil {
+		return false, err
+	}
+	return commentHash(body) != status.Hash, nil
+}
+
+// recordComments writes the comments synced so far, keeping the not yet processed ones of posted,
+// so an interrupted sync never loses the ID of a comment it posted.
+func (r *GithubIssueReconciler) recordComments(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, synced, posted []issuesv1alpha1.CommentStatus) error {
+	comments := slices.Clone(synced)
+	for _, status := range posted {
+		if !slices.ContainsFunc(synced, func(s issuesv1alpha1.CommentStatus) bool { return s.Name == status.Name }) {
+			comments = append(comments, status)
+		}
+	}
+	issueObject.Status.Comments = comments
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+func commentHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:8])
+}
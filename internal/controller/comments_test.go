@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeCommentClient keeps the comments of a single issue in memory.
+type fakeCommentClient struct {
+	git.IssueClient
+	nextID   int64
+	comments map[int64]string
+}
+
+func (f *fakeCommentClient) Comment(_ context.Context, _, _ string, _ int, body string) (int64, error) {
+	f.nextID++
+	f.comments[f.nextID] = body
+	return f.nextID, nil
+}
+
+func (f *fakeCommentClient) GetComment(_ context.Context, _, _ string, id int64) (string, error) {
+	body, ok := f.comments[id]
+	if !ok {
+		return "", git.ErrCommentNotFound
+	}
+	return body, nil
+}
+
+func (f *fakeCommentClient) EditComment(_ context.Context, _, _ string, id int64, body string) error {
+	if _, ok := f.comments[id]; !ok {
+		return git.ErrCommentNotFound
+	}
+	f.comments[id] = body
+	return nil
+}
+
+func (f *fakeCommentClient) DeleteComment(_ context.Context, _, _ string, id int64) error {
+	delete(f.comments, id)
+	return nil
+}
+
+var _ = Describe("managed comments", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		comments    *fakeCommentClient
+		issue       = &git.Issue{Number: 7}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		comments = &fakeCommentClient{comments: map[int64]string{}}
+		reconciler = &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Clients:  &git.Clients{Default: comments},
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+	})
+
+	sync := func(specComments ...issuesv1alpha1.IssueComment) {
+		issueObject.Spec.Comments = specComments
+		Expect(reconciler.syncComments(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+	}
+
+	It("posts each comment once and edits it when its body changes", func() {
+		sync(issuesv1alpha1.IssueComment{Name: "runbook", Body: "See the runbook."})
+		sync(issuesv1alpha1.IssueComment{Name: "runbook", Body: "See the runbook."})
+		Expect(comments.comments).To(Equal(map[int64]string{1: "See the runbook."}))
+
+		sync(issuesv1alpha1.IssueComment{Name: "runbook", Body: "See the new runbook."})
+		Expect(comments.comments).To(Equal(map[int64]string{1: "See the new runbook."}))
+		Expect(issueObject.Status.Comments).To(HaveLen(1))
+		Expect(issueObject.Status.Comments[0].ID).To(Equal(int64(1)))
+	})
+
+	It("deletes comments removed from the spec", func() {
+		sync(issuesv1alpha1.IssueComment{Name: "a", Body: "A"}, issuesv1alpha1.IssueComment{Name: "b", Body: "B"})
+		sync(issuesv1alpha1.IssueComment{Name: "b", Body: "B"})
+		Expect(comments.comments).To(Equal(map[int64]string{2: "B"}))
+		Expect(issueObject.Status.Comments).To(ConsistOf(HaveField("Name", "b")))
+	})
+
+	It("posts a comment again after it was deleted upstream", func() {
+		sync(issuesv1alpha1.IssueComment{Name: "a", Body: "A"})
+		delete(comments.comments, 1)
+		sync(issuesv1alpha1.IssueComment{Name: "a", Body: "A2"})
+		Expect(comments.comments).To(Equal(map[int64]string{2: "A2"}))
+	})
+
+	It("never overwrites a comment edited on GitHub", func() {
+		sync(issuesv1alpha1.IssueComment{Name: "a", Body: "A"})
+		comments.comments[1] = "A, with a note from the on-call engineer"
+		sync(issuesv1alpha1.IssueComment{Name: "a", Body: "A2"})
+		Expect(comments.comments).To(Equal(map[int64]string{1: "A, with a note from the on-call engineer"}))
+		Expect(issueObject.Status.Comments).To(ConsistOf(HaveField("Hash", commentHash("A"))))
+	})
+
+	It("only deletes comments still holding the body the operator wrote", func() {
+		sync(issuesv1alpha1.IssueComment{Name: "a", Body: "A"}, issuesv1alpha1.IssueComment{Name: "b", Body: "B"})
+		comments.comments[2] = "B, edited on GitHub"
+		comments.comments[99] = "A reply from a human"
+		sync()
+		Expect(comments.comments).To(Equal(map[int64]string{2: "B, edited on GitHub", 99: "A reply from a human"}))
+		Expect(issueObject.Status.Comments).To(BeEmpty())
+	})
+})
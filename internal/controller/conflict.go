@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// externallyEdited reports whether the upstream body was changed by someone else since the operator last wrote it.
+func externallyEdited(issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue, desired *git.DesiredIssue) bool {
+	applied := issueObject.Status.AppliedDescriptionHash
+	return applied != "" && issue.Description != desired.Body && bodyHash(issue.Description) != applied
+}
+
+// resolveConflict applies spec.conflictPolicy to an upstream body edited outside of the operator.
+// It returns false when the edit must be skipped until the conflict is resolved.
+func (r *GithubIssueReconciler) resolveConflict(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue, desired *git.DesiredIssue) (bool, error) {
+	if !externallyEdited(issueObject, issue, desired) {
+		if issueObject.Status.ExternalDescription != "" {
+			// The upstream body is back to the spec: stop adopting it.
+			issueObject.Status.ExternalDescription = ""
+			if err := r.updateStatus(ctx, issueObject); err != nil {
+				return false, fmt.Errorf("failed to update status: %v", err)
+			}
+		}
+		return true, nil
+	}
+
+	switch issueObject.Spec.ConflictPolicy {
+	case issuesv1alpha1.ConflictPolicyGitHubWins:
+		// Keep the upstream body; the other fields are still applied.
+		desired.Body = issue.Description
+		if issueObject.Status.ExternalDescription == issue.Description {
+			return true, nil
+		}
+		r.logger(ctx).Info("Adopting the upstream description edited outside of the operator")
+		issueObject.Status.ExternalDescription = issue.Description
+		if err := r.updateStatus(ctx, issueObject); err != nil {
+			return false, fmt.Errorf("failed to update status: %v", err)
+		}
+		return true, nil
+	case issuesv1alpha1.ConflictPolicyManual:
+		current := meta.FindStatusCondition(issueObject.Status.Conditions, ConflictCondition)
+		if current != nil && current.Status == metav1.ConditionTrue && current.ObservedGeneration < issueObject.Generation {
+			// The spec changed after the conflict was reported: that resolves it in favor of the spec.
+			r.logger(ctx).Info("Spec changed after the conflict was reported, applying it")
+			return true, nil
+		}
+		message := "The issue description was edited on GitHub. Update the spec to apply it, or revert the upstream edit"
+		if !meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+			Type:               ConflictCondition,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ExternalEdit",
+			Message:            message,
+			ObservedGeneration: issueObject.Generation,
+		}) {
+			return false, nil
+		}
+		r.logger(ctx).Warn("Issue description edited outside of the operator, waiting for manual resolution")
+		r.Recorder.Event(issueObject, corev1.EventTypeWarning, ConflictCondition, message)
+		if err := r.updateStatus(ctx, issueObject); err != nil {
+			return false, fmt.Errorf("failed to update status: %v", err)
+		}
+		return false, nil
+	default:
+		r.logger(ctx).Info("Overwriting the upstream description edited outside of the operator")
+		return true, nil
+	}
+}
+
+// clearConflict flips a previously reported Conflict condition once the spec body was applied.
+func (r *GithubIssueReconciler) clearConflict(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, ConflictCondition) {
+		return nil
+	}
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               ConflictCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Resolved",
+		Message:            "The issue description matches the spec",
+		ObservedGeneration: issueObject.Generation,
+	})
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// recordAppliedBody remembers the body the operator wrote upstream, to tell external edits apart later.
+func (r *GithubIssueReconciler) recordAppliedBody(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, body string) error {
+	hash := bodyHash(body)
+	if issueObject.Status.AppliedDescriptionHash == hash {
+		return nil
+	}
+	issueObject.Status.AppliedDescriptionHash = hash
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		r.logger(ctx).Warn("Failed to record the applied description", zap.Error(err))
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+func bodyHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("conflict policy", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default", Generation: 1}}
+		issueObject.Status.AppliedDescriptionHash = bodyHash("from the spec")
+		reconciler = &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+	})
+
+	setPolicy := func(policy issuesv1alpha1.ConflictPolicy) {
+		issueObject.Spec.ConflictPolicy = policy
+		Expect(reconciler.Client.Update(context.Background(), issueObject)).To(Succeed())
+	}
+
+	resolve := func(upstream string) (bool, *git.DesiredIssue) {
+		desired := &git.DesiredIssue{Body: "from the spec"}
+		apply, err := reconciler.resolveConflict(context.Background(), issueObject, &git.Issue{Description: upstream}, desired)
+		Expect(err).NotTo(HaveOccurred())
+		return apply, desired
+	}
+
+	It("overwrites upstream edits by default", func() {
+		apply, desired := resolve("edited upstream")
+		Expect(apply).To(BeTrue())
+		Expect(desired.Body).To(Equal("from the spec"))
+	})
+
+	It("adopts upstream edits with GitHubWins", func() {
+		setPolicy(issuesv1alpha1.ConflictPolicyGitHubWins)
+		apply, desired := resolve("edited upstream")
+		Expect(apply).To(BeTrue())
+		Expect(desired.Body).To(Equal("edited upstream"))
+		Expect(issueObject.Status.ExternalDescription).To(Equal("edited upstream"))
+	})
+
+	It("waits on upstream edits with Manual until the spec changes", func() {
+		setPolicy(issuesv1alpha1.ConflictPolicyManual)
+		apply, _ := resolve("edited upstream")
+		Expect(apply).To(BeFalse())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, ConflictCondition)).To(BeTrue())
+
+		apply, _ = resolve("edited upstream")
+		Expect(apply).To(BeFalse())
+
+		issueObject.Generation = 2
+		apply, _ = resolve("edited upstream")
+		Expect(apply).To(BeTrue())
+	})
+
+	It("does not report a conflict before the operator wrote the body", func() {
+		setPolicy(issuesv1alpha1.ConflictPolicyManual)
+		issueObject.Status.AppliedDescriptionHash = ""
+		apply, _ := resolve("edited upstream")
+		Expect(apply).To(BeTrue())
+	})
+})
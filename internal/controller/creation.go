@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/lifecycle"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createIssue creates the upstream issue so that an operator crashing at any point resumes the creation
+// instead of opening a second issue or leaving the status without the issue number. Every step is recorded in
+// status.creation before the next one starts:
+//
+//  1. Creating is recorded before GitHub is called. A creation resumed from it first looks for an issue
+//     carrying the marker of the GithubIssue, which the interrupted attempt may have created.
+//  2. Created is recorded with the issue number once the issue exists upstream.
+//  3. The events announcing the issue are emitted, then status.creation is cleared. A creation resumed from
+//     Created emits them again, so they are delivered at least once.
+func (r *GithubIssueReconciler) createIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) error {
+	resumed := issueObject.Status.Creation != nil
+	if !resumed {
+		issueObject.Status.Creation = &issuesv1alpha1.IssueCreation{Step: issuesv1alpha1.CreationStepCreating, StartedAt: metav1.Now()}
+		if err := r.updateStatus(ctx, issueObject); err != nil {
+			return fmt.Errorf("failed to record the issue creation: %v", err)
+		}
+	}
+
+	if issueObject.Status.Creation.Step == issuesv1alpha1.CreationStepCreating {
+		var issue *git.Issue
+		if resumed {
+			var err error
+			if issue, err = r.findMarkedIssue(ctx, owner, repo, issueObject); err != nil {
+				return err
+			}
+		}
+		if issue != nil {
+			r.logger(ctx).Info("Resuming the creation of an issue created by an interrupted attempt", zap.Int("issue", issue.Number))
+		} else {
+			var err error
+			if issue, err = r.CreateIssue(ctx, owner, repo, issueObject); err != nil {
+				return err
+			}
+		}
+		if err := r.recordCreated(ctx, issueObject, issue); err != nil {
+			return err
+		}
+	}
+	return r.completeCreation(ctx, issueObject)
+}
+
+// resumeCreation completes a creation interrupted after the issue was created upstream, once the reconcile
+// found the issue by its title.
+func (r *GithubIssueReconciler) resumeCreation(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	creation := issueObject.Status.Creation
+	if creation == nil {
+		return nil
+	}
+	if creation.Step == issuesv1alpha1.CreationStepCreating {
+		r.logger(ctx).Info("Resuming the creation of an issue created by an interrupted attempt", zap.Int("issue", issue.Number))
+		if err := r.recordCreated(ctx, issueObject, issue); err != nil {
+			return err
+		}
+	}
+	return r.completeCreation(ctx, issueObject)
+}
+
+// findMarkedIssue returns the upstream issue carrying the marker of the GithubIssue, nil when there is none.
+func (r *GithubIssueReconciler) findMarkedIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (*git.Issue, error) {
+	issues, err := r.fetchAllIssues(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching issues: %w", err)
+	}
+	for _, issue := range issues {
+		if key, ok := git.ParseMarker(issue.Description); ok && key == objectKey(issueObject) {
+			return issue, nil
+		}
+	}
+	return nil, nil
+}
+
+// recordCreated records the Created step with the number of the upstream issue. A failed write is kept by
+// the pending writes and recorded by the next reconcile.
+func (r *GithubIssueReconciler) recordCreated(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	issueObject.Status.IssueNumber = issue.Number
+	issueObject.Status.Creation.Step = issuesv1alpha1.CreationStepCreated
+	issueObject.Status.Creation.URL = issue.URL
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to record the created issue #%d: %v", issue.Number, err)
+	}
+	return nil
+}
+
+// completeCreation emits the events announcing the created issue and clears status.creation.
+func (r *GithubIssueReconciler) completeCreation(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	creation := issueObject.Status.Creation
+	if creation == nil || creation.Step != issuesv1alpha1.CreationStepCreated {
+		return nil
+	}
+	issue := &git.Issue{Number: issueObject.Status.IssueNumber, URL: creation.URL}
+	r.publish(ctx, lifecycle.Created, issueObject, issue)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(issueObject, corev1.EventTypeNormal, "IssueCreated", "Created issue #%d: %s", issue.Number, issue.URL)
+	}
+
+	issueObject.Status.Creation = nil
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
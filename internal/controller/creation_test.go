@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/lifecycle"
+)
+
+// recordingPublisher keeps the lifecycle events it is given.
+type recordingPublisher struct {
+	events []lifecycle.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event lifecycle.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+var _ = Describe("issue creation", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		upstream    *fakeMirrorClient
+		publisher   *recordingPublisher
+		failStatus  func(*issuesv1alpha1.GithubIssue) bool
+		key         = types.NamespacedName{Name: "issue", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", Title: "Crash", Description: "Stack trace"},
+		}
+		upstream = &fakeMirrorClient{issues: map[int]*git.Issue{}}
+		publisher = &recordingPublisher{}
+		failStatus = func(*issuesv1alpha1.GithubIssue) bool { return false }
+		k8sClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					if failStatus(obj.(*issuesv1alpha1.GithubIssue)) {
+						return errors.New("connection refused")
+					}
+					return c.SubResource(subResource).Update(ctx, obj, opts...)
+				},
+			}).Build()
+		reconciler = &GithubIssueReconciler{
+			Client:    k8sClient,
+			Log:       zap.NewNop(),
+			Clients:   &git.Clients{Default: upstream},
+			Recorder:  record.NewFakeRecorder(10),
+			Publisher: publisher,
+			pending:   newPendingWrites(),
+		}
+	})
+
+	// restart drops the state kept in memory and returns the GithubIssue as last written, as after a crash.
+	restart := func() *issuesv1alpha1.GithubIssue {
+		reconciler.pending = newPendingWrites()
+		fetched := &issuesv1alpha1.GithubIssue{}
+		Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+		return fetched
+	}
+
+	It("records the issue number before emitting the events and clears the progress", func() {
+		Expect(reconciler.createIssue(context.Background(), "org", "repo", issueObject)).To(Succeed())
+
+		fetched := restart()
+		Expect(fetched.Status.IssueNumber).To(Equal(1))
+		Expect(fetched.Status.Creation).To(BeNil())
+		Expect(fetched.Status.AppliedDescriptionHash).NotTo(BeEmpty())
+		Expect(publisher.events).To(HaveLen(1))
+		Expect(publisher.events[0].IssueNumber).To(Equal(1))
+	})
+
+	It("adopts the issue of an attempt interrupted before recording it instead of creating another", func() {
+		failStatus = func(issueObject *issuesv1alpha1.GithubIssue) bool {
+			return issueObject.Status.IssueNumber != 0
+		}
+		Expect(reconciler.createIssue(context.Background(), "org", "repo", issueObject)).NotTo(Succeed())
+		Expect(upstream.issues).To(HaveLen(1))
+
+		fetched := restart()
+		Expect(fetched.Status.Creation.Step).To(Equal(issuesv1alpha1.CreationStepCreating))
+		Expect(publisher.events).To(BeEmpty())
+
+		failStatus = func(*issuesv1alpha1.GithubIssue) bool { return false }
+		fetched.Spec.Title = "Crash on start"
+		Expect(reconciler.createIssue(context.Background(), "org", "repo", fetched)).To(Succeed())
+		Expect(upstream.issues).To(HaveLen(1))
+		Expect(restart().Status.IssueNumber).To(Equal(1))
+		Expect(publisher.events).To(HaveLen(1))
+	})
+
+	It("emits the events of a creation interrupted after recording the issue number", func() {
+		failStatus = func(issueObject *issuesv1alpha1.GithubIssue) bool {
+			return issueObject.Status.Creation == nil
+		}
+		Expect(reconciler.createIssue(context.Background(), "org", "repo", issueObject)).NotTo(Succeed())
+
+		fetched := restart()
+		Expect(fetched.Status.Creation.Step).To(Equal(issuesv1alpha1.CreationStepCreated))
+		Expect(fetched.Status.IssueNumber).To(Equal(1))
+
+		failStatus = func(*issuesv1alpha1.GithubIssue) bool { return false }
+		Expect(reconciler.resumeCreation(context.Background(), fetched, upstream.issues[1])).To(Succeed())
+		Expect(restart().Status.Creation).To(BeNil())
+		Expect(publisher.events).To(HaveLen(2))
+		Expect(upstream.issues).To(HaveLen(1))
+	})
+})
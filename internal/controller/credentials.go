@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type issueClientKey struct{}
+
+// operatorCredentialsKey marks a context whose issue client uses the operator token.
+type operatorCredentialsKey struct{}
+
+// NamespaceCredentialsKey is the key of the token in the namespace credentials Secret.
+const NamespaceCredentialsKey = "token"
+
+// withCredentials returns a context carrying the issue client built from spec.credentialsSecretRef, else the
+// client authenticated with the token of the issue namespace, else the client of the GitProvider serving the
+// repository host, else the client Clients routes the repository to.
+func (r *GithubIssueReconciler) withCredentials(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (context.Context, error) {
+	ref := issueObject.Spec.CredentialsSecretRef
+	if ref == nil {
+		issueClient, ok, err := r.namespaceIssueClient(ctx, issueObject, issueObject.Spec.Repo)
+		if err != nil {
+			return ctx, err
+		}
+		if !ok {
+			issueClient, ok, err = r.providerIssueClient(ctx, issueObject.Spec.Repo)
+			if err != nil {
+				return ctx, err
+			}
+		}
+		if !ok {
+			if issueClient, err = r.Clients.ForRepo(issueObject.Spec.Repo); err != nil {
+				return ctx, err
+			}
+			ctx = context.WithValue(ctx, operatorCredentialsKey{}, true)
+		}
+		return context.WithValue(ctx, issueClientKey{}, issueClient), nil
+	}
+	issueClient, err := r.secretIssueClient(ctx, issueObject, issueObject.Spec.Repo, ref)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, issueClientKey{}, issueClient), nil
+}
+
+// secretIssueClient returns an issue client for the host of repoURL, authenticated with the token ref selects
+// for issueObject. The Secret must be in the namespace of the issue or in CentralSecretsNamespace.
+func (r *GithubIssueReconciler) secretIssueClient(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, repoURL string, ref *issuesv1alpha1.CredentialsSecretReference) (git.IssueClient, error) {
+	if r.NewIssueClient == nil {
+		return nil, fmt.Errorf("per-issue credentials are not supported by this reconciler")
+	}
+	namespace, err := ref.SecretNamespace(issueObject.Namespace, r.CentralSecretsNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret)
+	logging.AuditSecretRead(r.AuditLog, "GithubIssue", objectKey(issueObject), namespace+"/"+ref.Name, ref.Key, "credentials", err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials from Secret %s: %v", ref.Name, err)
+	}
+	token, ok := secret.Data[ref.Key]
+	if !ok || len(token) == 0 {
+		return nil, fmt.Errorf("key %s not found in Secret %s", ref.Key, ref.Name)
+	}
+	host, err := git.RepoHost(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	return r.NewIssueClient(host, namespace+"/"+ref.Name, string(token))
+}
+
+// namespaceIssueClient returns an issue client for the host of repoURL, authenticated with the token of the
+// namespace of issueObject. It reports false when the namespace has no NamespaceCredentialsSecret, or
+// namespace credentials are disabled.
+func (r *GithubIssueReconciler) namespaceIssueClient(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, repoURL string) (git.IssueClient, bool, error) {
+	if r.NamespaceCredentialsSecret == "" || r.NewIssueClient == nil {
+		return nil, false, nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: issueObject.Namespace, Name: r.NamespaceCredentialsSecret}
+	err := r.Get(ctx, key, secret)
+	if apierrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	logging.AuditSecretRead(r.AuditLog, "GithubIssue", objectKey(issueObject), key.String(), NamespaceCredentialsKey, "credentials", err)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read namespace credentials from Secret %s: %v", key.Name, err)
+	}
+	token := secret.Data[NamespaceCredentialsKey]
+	if len(token) == 0 {
+		return nil, false, fmt.Errorf("key %s not found in Secret %s", NamespaceCredentialsKey, key.Name)
+	}
+	host, err := git.RepoHost(repoURL)
+	if err != nil {
+		return nil, false, err
+	}
+	issueClient, err := r.NewIssueClient(host, key.String(), string(token))
+	if err != nil {
+		return nil, false, err
+	}
+	return issueClient, true, nil
+}
+
+// credential names the token used for issueObject in TokenExpiry.
+func credential(issueObject *issuesv1alpha1.GithubIssue) string {
+	if ref := issueObject.Spec.CredentialsSecretRef; ref != nil {
+		if ref.Namespace != "" {
+			return ref.Namespace + "/" + ref.Name
+		}
+		return issueObject.Namespace + "/" + ref.Name
+	}
+	return git.CredentialOperator
+}
+
+// checkTokenExpiry sets the TokenExpiring condition while the token used for the issue is about to expire,
+// and clears it once the token was replaced.
+func (r *GithubIssueReconciler) checkTokenExpiry(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if r.TokenExpiry == nil {
+		return nil
+	}
+	expiresAt, known := r.TokenExpiry.ExpiresAt(credential(issueObject))
+	expiring := known && time.Until(expiresAt) < r.TokenExpiryWarning
+
+	condition := metav1.Condition{
+		Type:               TokenExpiringCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "TokenValid",
+		Message:            "GitHub token does not expire soon",
+		ObservedGeneration: issueObject.Generation,
+	}
+	if expiring {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "TokenExpiring"
+		condition.Message = fmt.Sprintf("GitHub token expires at %s", expiresAt.UTC().Format(time.RFC3339))
+	} else if meta.FindStatusCondition(issueObject.Status.Conditions, TokenExpiringCondition) == nil {
+		// Only issues that were warned get the condition cleared.
+		return nil
+	}
+	if !meta.SetStatusCondition(&issueObject.Status.Conditions, condition) {
+		return nil
+	}
+
+	if expiring {
+		r.logger(ctx).Warn("GitHub token expires soon", zap.Time("expiresAt", expiresAt))
+		r.Recorder.Event(issueObject, corev1.EventTypeWarning, TokenExpiringCondition, condition.Message)
+	}
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// checkTokenPermissions sets the TokenPermissionsMissing condition while the token preflight reports the
+// operator token can't manage the issues of spec.repo, so the problem shows before a GitHub call fails.
+func (r *GithubIssueReconciler) checkTokenPermissions(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if r.Preflight == nil {
+		return nil
+	}
+	problem, missing := "", false
+	if operator, _ := ctx.Value(operatorCredentialsKey{}).(bool); operator {
+		problem, missing = r.Preflight.Problem(issueObject.Spec.Repo)
+	}
+
+	condition := metav1.Condition{
+		Type:               TokenPermissionsMissingCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Permitted",
+		Message:            "GitHub token can manage the issues of the repository",
+		ObservedGeneration: issueObject.Generation,
+	}
+	if missing {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "MissingPermissions"
+		condition.Message = fmt.Sprintf("GitHub token can't manage the issues of the repository: %s", problem)
+	} else if meta.FindStatusCondition(issueObject.Status.Conditions, TokenPermissionsMissingCondition) == nil {
+		// Only issues that were warned get the condition cleared.
+		return nil
+	}
+	if !meta.SetStatusCondition(&issueObject.Status.Conditions, condition) {
+		return nil
+	}
+
+	if missing {
+		r.logger(ctx).Warn("GitHub token can't manage the issues of the repository", zap.String("problem", problem))
+		r.Recorder.Event(issueObject, corev1.EventTypeWarning, TokenPermissionsMissingCondition, condition.Message)
+	}
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// issueClient returns the issue client for the reconcile carried by ctx. A context withCredentials did not
+// prepare gets the default client of Clients.
+func (r *GithubIssueReconciler) issueClient(ctx context.Context) git.IssueClient {
+	if issueClient, ok := ctx.Value(issueClientKey{}).(git.IssueClient); ok {
+		return issueClient
+	}
+	return r.Clients.Default
+}
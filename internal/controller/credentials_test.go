@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("per-issue credentials", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		tokens      []string
+	)
+
+	BeforeEach(func() {
+		tokens = nil
+		reconciler = &GithubIssueReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-token", Namespace: "default"},
+				Data:       map[string][]byte{"token": []byte("ghp_team")},
+			}).Build(),
+			Clients: &git.Clients{Default: &git.GitHubIssueClient{}},
+			NewIssueClient: func(_, credential, token string) (git.IssueClient, error) {
+				tokens = append(tokens, credential+"="+token)
+				return &git.GitHubIssueClient{}, nil
+			},
+		}
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo"},
+		}
+	})
+
+	It("uses the operator client without a credentials reference", func() {
+		ctx, err := reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.issueClient(ctx)).To(BeIdenticalTo(reconciler.Clients.Default))
+		Expect(tokens).To(BeEmpty())
+	})
+
+	It("builds a client from the referenced Secret", func() {
+		issueObject.Spec.CredentialsSecretRef = &issuesv1alpha1.CredentialsSecretReference{Name: "team-token", Key: "token"}
+		ctx, err := reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.issueClient(ctx)).NotTo(BeIdenticalTo(reconciler.Clients.Default))
+		Expect(tokens).To(Equal([]string{"default/team-token=ghp_team"}))
+	})
+
+	It("uses the namespace token when the namespace credentials Secret exists", func() {
+		reconciler.NamespaceCredentialsSecret = "team-token"
+		ctx, err := reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.issueClient(ctx)).NotTo(BeIdenticalTo(reconciler.Clients.Default))
+		Expect(tokens).To(Equal([]string{"default/team-token=ghp_team"}))
+
+		issueObject.Namespace = "other"
+		ctx, err = reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.issueClient(ctx)).To(BeIdenticalTo(reconciler.Clients.Default))
+	})
+
+	It("fails when the key is missing", func() {
+		issueObject.Spec.CredentialsSecretRef = &issuesv1alpha1.CredentialsSecretReference{Name: "team-token", Key: "missing"}
+		_, err := reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).To(MatchError(ContainSubstring("key missing not found")))
+	})
+
+	It("only reads Secrets of the central secrets namespace besides the issue namespace, and audits the reads", func() {
+		core, audited := observer.New(zap.InfoLevel)
+		reconciler.AuditLog = zap.New(core)
+		issueObject.Spec.CredentialsSecretRef = &issuesv1alpha1.CredentialsSecretReference{Namespace: "secrets", Name: "team-token", Key: "token"}
+		_, err := reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).To(MatchError(ContainSubstring("outside of namespace default")))
+		Expect(audited.Len()).To(BeZero())
+
+		reconciler.CentralSecretsNamespace = "secrets"
+		_, err = reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).To(MatchError(ContainSubstring("failed to read credentials")))
+		Expect(audited.FilterMessage("Secret read failed").FilterField(zap.String("consumer.name", "default/issue")).
+			FilterField(zap.String("secret", "secrets/team-token")).Len()).To(Equal(1))
+	})
+})
+
+var _ = Describe("token expiry", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		tracker     *git.ExpiryTracker
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		tracker = &git.ExpiryTracker{}
+		reconciler = &GithubIssueReconciler{
+			Client:             fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:                zap.NewNop(),
+			Recorder:           record.NewFakeRecorder(10),
+			TokenExpiry:        tracker,
+			TokenExpiryWarning: 24 * time.Hour,
+			pending:            newPendingWrites(),
+		}
+	})
+
+	observe := func(expiresAt time.Time) {
+		transport := tracker.Transport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set(git.TokenExpirationHeader, expiresAt.UTC().Format("2006-01-02 15:04:05 MST"))
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: http.NoBody}, nil
+		}), git.CredentialOperator)
+		_, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.github.com/", nil))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("does not add the condition for tokens far from expiry", func() {
+		observe(time.Now().Add(30 * 24 * time.Hour))
+		Expect(reconciler.checkTokenExpiry(context.Background(), issueObject)).To(Succeed())
+		Expect(issueObject.Status.Conditions).To(BeEmpty())
+	})
+
+	It("warns about an expiring token and clears the warning once it was replaced", func() {
+		observe(time.Now().Add(time.Hour))
+		Expect(reconciler.checkTokenExpiry(context.Background(), issueObject)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, TokenExpiringCondition)).To(BeTrue())
+
+		observe(time.Now().Add(90 * 24 * time.Hour))
+		Expect(reconciler.checkTokenExpiry(context.Background(), issueObject)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, TokenExpiringCondition)).To(BeTrue())
+	})
+})
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
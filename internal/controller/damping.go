@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pendingTransition records a condition status that was observed upstream but not yet applied.
+type pendingTransition struct {
+	status metav1.ConditionStatus
+	since  time.Time
+}
+
+// conditionDamper holds back condition flips until the upstream state has been stable for a window,
+// so flapping issues don't produce a status update on every reconcile.
+type conditionDamper struct {
+	mu      sync.Mutex
+	window  time.Duration
+	pending map[string]pendingTransition
+}
+
+func newConditionDamper(window time.Duration) *conditionDamper {
+	return &conditionDamper{
+		window:  window,
+		pending: map[string]pendingTransition{},
+	}
+}
+
+// observe reports whether the desired status for key has been stable long enough to be applied.
+// When it has not, it returns how long to wait before checking again.
+func (d *conditionDamper) observe(key string, desired metav1.ConditionStatus, now time.Time) (bool, time.Duration) {
+	if d == nil || d.window <= 0 {
+		return true, 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p, ok := d.pending[key]
+	if !ok || p.status != desired {
+		d.pending[key] = pendingTransition{status: desired, since: now}
+		return false, d.window
+	}
+
+	elapsed := now.Sub(p.since)
+	if elapsed >= d.window {
+		delete(d.pending, key)
+		return true, 0
+	}
+	return false, d.window - elapsed
+}
+
+// reset drops any pending transition for key, used once the upstream state settles back.
+func (d *conditionDamper) reset(key string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.pending, key)
+}
+
+// forget drops all pending transitions that belong to the given object.
+func (d *conditionDamper) forget(objectKey string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key := range d.pending {
+		if strings.HasPrefix(key, objectKey+"/") {
+			delete(d.pending, key)
+		}
+	}
+}
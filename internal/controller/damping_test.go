@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("conditionDamper", func() {
+	It("applies changes immediately when the window is disabled", func() {
+		d := newConditionDamper(0)
+		stable, wait := d.observe("default/a/IssueIsOpen", metav1.ConditionFalse, time.Now())
+		Expect(stable).To(BeTrue())
+		Expect(wait).To(BeZero())
+	})
+
+	It("holds a change back until it has been stable for the window", func() {
+		d := newConditionDamper(time.Minute)
+		start := time.Now()
+
+		stable, wait := d.observe("default/a/IssueIsOpen", metav1.ConditionFalse, start)
+		Expect(stable).To(BeFalse())
+		Expect(wait).To(Equal(time.Minute))
+
+		stable, wait = d.observe("default/a/IssueIsOpen", metav1.ConditionFalse, start.Add(20*time.Second))
+		Expect(stable).To(BeFalse())
+		Expect(wait).To(Equal(40 * time.Second))
+
+		stable, _ = d.observe("default/a/IssueIsOpen", metav1.ConditionFalse, start.Add(time.Minute))
+		Expect(stable).To(BeTrue())
+	})
+
+	It("restarts the window when the upstream state flaps", func() {
+		d := newConditionDamper(time.Minute)
+		start := time.Now()
+
+		d.observe("default/a/IssueIsOpen", metav1.ConditionFalse, start)
+		d.reset("default/a/IssueIsOpen")
+
+		stable, wait := d.observe("default/a/IssueIsOpen", metav1.ConditionFalse, start.Add(time.Minute))
+		Expect(stable).To(BeFalse())
+		Expect(wait).To(Equal(time.Minute))
+	})
+
+	It("forgets every pending transition of a deleted object", func() {
+		d := newConditionDamper(time.Minute)
+		d.observe("default/a/IssueIsOpen", metav1.ConditionFalse, time.Now())
+		d.observe("default/a/IssueHasPR", metav1.ConditionTrue, time.Now())
+		d.observe("default/ab/IssueIsOpen", metav1.ConditionFalse, time.Now())
+
+		d.forget("default/a")
+		Expect(d.pending).To(HaveLen(1))
+		Expect(d.pending).To(HaveKey("default/ab/IssueIsOpen"))
+	})
+})
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Actions recorded in status.lastDecision.
+const (
+	DecisionCreated   = "Created"
+	DecisionUpdated   = "Updated"
+	DecisionUnchanged = "Unchanged"
+	DecisionSkipped   = "Skipped"
+	DecisionWaiting   = "Waiting"
+	DecisionFailed    = "Failed"
+)
+
+// maxDecisionMessage bounds status.lastDecision.message, which may carry an upstream error.
+const maxDecisionMessage = 256
+
+type decisionLogKey struct{}
+
+// decisionLog collects the decision of a reconcile and the upstream issue it was made from.
+type decisionLog struct {
+	action, reason, message string
+	upstream                *git.Issue
+}
+
+func withDecisionLog(ctx context.Context) (context.Context, *decisionLog) {
+	decisions := &decisionLog{}
+	return context.WithValue(ctx, decisionLogKey{}, decisions), decisions
+}
+
+// decide records the decision of the reconcile carried by ctx. The last decision of a reconcile wins.
+func decide(ctx context.Context, action, reason, message string) {
+	decisions, _ := ctx.Value(decisionLogKey{}).(*decisionLog)
+	if decisions == nil {
+		return
+	}
+	decisions.action, decisions.reason, decisions.message = action, reason, message
+}
+
+// observe records the upstream issue the reconcile carried by ctx works from.
+func observe(ctx context.Context, issue *git.Issue) {
+	if decisions, _ := ctx.Value(decisionLogKey{}).(*decisionLog); decisions != nil {
+		decisions.upstream = issue
+	}
+}
+
+// recordDecision writes the decision of the reconcile to status.lastDecision. A failed or rate limited reconcile
+// overrides the recorded decision. Reconciles of an object being deleted record nothing: the object is going away.
+func (r *GithubIssueReconciler) recordDecision(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, decisions *decisionLog, reconcileErr error, terminal bool, wait time.Duration) {
+	action, reason, message := decisions.action, decisions.reason, decisions.message
+	switch {
+	case wait > 0:
+		action, reason, message = DecisionWaiting, "RateLimited", "Waiting for the GitHub rate limit to reset"
+	case terminal:
+		action, reason, message = DecisionFailed, "TerminalError", reconcileErr.Error()
+	case reconcileErr != nil:
+		action, reason, message = DecisionFailed, "ReconcileError", reconcileErr.Error()
+	}
+	if action == "" || !issueObject.DeletionTimestamp.IsZero() {
+		return
+	}
+	if len(message) > maxDecisionMessage {
+		message = message[:maxDecisionMessage-3] + "..."
+	}
+
+	decision := issuesv1alpha1.ReconcileDecision{
+		Action:     action,
+		Reason:     reason,
+		Message:    message,
+		InputsHash: inputsHash(issueObject, decisions.upstream),
+		Time:       metav1.Now(),
+	}
+	if last := issueObject.Status.LastDecision; last != nil {
+		decision.Time = last.Time
+		if *last == decision {
+			return
+		}
+		decision.Time = metav1.Now()
+	}
+	issueObject.Status.LastDecision = &decision
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		r.logger(ctx).Warn("Failed to record the reconcile decision", zap.Error(err))
+	}
+}
+
+// inputsHash hashes the spec and the upstream fields the operator manages. Upstream timestamps are left out:
+// every write moves them.
+func inputsHash(issueObject *issuesv1alpha1.GithubIssue, upstream *git.Issue) string {
+	inputs := struct {
+		Spec     issuesv1alpha1.GithubIssueSpec
+		Upstream *git.Issue `json:",omitempty"`
+	}{Spec: issueObject.Spec}
+	if upstream != nil {
+		observed := *upstream
+		observed.CreatedAt, observed.UpdatedAt = time.Time{}, time.Time{}
+		inputs.Upstream = &observed
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
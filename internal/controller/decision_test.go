@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("decision log", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", Title: "Fix login"},
+		}
+		reconciler = &GithubIssueReconciler{
+			Client:  fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:     zap.NewNop(),
+			pending: newPendingWrites(),
+		}
+	})
+
+	It("records the last decision and keeps its time while it repeats", func() {
+		ctx, decisions := withDecisionLog(context.Background())
+		observe(ctx, &git.Issue{Number: 1, Title: "Fix login", State: "open"})
+		decide(ctx, DecisionSkipped, "Suspended", "first")
+		decide(ctx, DecisionUnchanged, "InSync", "The upstream issue matches the spec")
+		reconciler.recordDecision(ctx, issueObject, decisions, nil, false, 0)
+
+		first := issueObject.Status.LastDecision
+		Expect(first).NotTo(BeNil())
+		Expect(first.Action).To(Equal(DecisionUnchanged))
+		Expect(first.Reason).To(Equal("InSync"))
+		Expect(first.InputsHash).To(HaveLen(16))
+
+		resourceVersion := issueObject.ResourceVersion
+		reconciler.recordDecision(ctx, issueObject, decisions, nil, false, 0)
+		Expect(issueObject.ResourceVersion).To(Equal(resourceVersion))
+		Expect(issueObject.Status.LastDecision.Time).To(Equal(first.Time))
+	})
+
+	It("records failures and rate limits over the decision", func() {
+		ctx, decisions := withDecisionLog(context.Background())
+		decide(ctx, DecisionUpdated, "Drifted", "")
+		reconciler.recordDecision(ctx, issueObject, decisions, errors.New("failed to edit issue: 502"), false, 0)
+		Expect(issueObject.Status.LastDecision.Action).To(Equal(DecisionFailed))
+		Expect(issueObject.Status.LastDecision.Message).To(Equal("failed to edit issue: 502"))
+
+		reconciler.recordDecision(ctx, issueObject, decisions, errors.New("rate limited"), false, time.Minute)
+		Expect(issueObject.Status.LastDecision.Action).To(Equal(DecisionWaiting))
+		Expect(issueObject.Status.LastDecision.Reason).To(Equal("RateLimited"))
+	})
+
+	It("hashes the inputs without the upstream timestamps", func() {
+		upstream := &git.Issue{Number: 1, Title: "Fix login", UpdatedAt: time.Now()}
+		later := *upstream
+		later.UpdatedAt = upstream.UpdatedAt.Add(time.Hour)
+		Expect(inputsHash(issueObject, &later)).To(Equal(inputsHash(issueObject, upstream)))
+
+		later.State = "closed"
+		Expect(inputsHash(issueObject, &later)).NotTo(Equal(inputsHash(issueObject, upstream)))
+	})
+})
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/triage"
+)
+
+// fakeLinkedPRClient serves the pull requests linked to its issues.
+type fakeLinkedPRClient struct {
+	*fakeMirrorClient
+	pullRequests []git.LinkedPullRequest
+}
+
+func (f *fakeLinkedPRClient) LinkedPullRequests(_ context.Context, _, _ string, _ int) ([]git.LinkedPullRequest, error) {
+	return f.pullRequests, nil
+}
+
+var _ = Describe("deletion protection", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		upstream    *fakeLinkedPRClient
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "issue",
+				Namespace:         "default",
+				Finalizers:        []string{"issues.dana.io/finalizer"},
+				DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+			},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:               "https://github.com/org/repo",
+				DeletionProtection: issuesv1alpha1.DeletionProtectionWhileLinkedPROpen,
+			},
+		}
+		upstream = &fakeLinkedPRClient{fakeMirrorClient: &fakeMirrorClient{issues: map[int]*git.Issue{
+			1: {Number: 1, State: "open"},
+		}}}
+		reconciler = &GithubIssueReconciler{
+			Client:             fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:                zap.NewNop(),
+			Clients:            &git.Clients{Default: upstream},
+			Recorder:           record.NewFakeRecorder(10),
+			pending:            newPendingWrites(),
+			triageDistribution: triage.NewDistribution(),
+		}
+	})
+
+	It("holds the deletion while a linked pull request is open", func() {
+		upstream.pullRequests = []git.LinkedPullRequest{
+			{URL: "https://github.com/org/repo/pull/2", State: "CLOSED"},
+			{URL: "https://github.com/org/repo/pull/3", State: "OPEN"},
+		}
+
+		result, err := reconciler.handleDeletion(context.Background(), "org", "repo", upstream.issues[1], issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(deletionProtectionRecheck))
+		Expect(upstream.issues[1].State).To(Equal("open"))
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, DeletionBlockedCondition)).To(BeTrue())
+		Expect(issueObject.Finalizers).NotTo(BeEmpty())
+	})
+
+	It("closes the issue once the linked pull requests are merged or closed", func() {
+		upstream.pullRequests = []git.LinkedPullRequest{
+			{URL: "https://github.com/org/repo/pull/2", State: "CLOSED"},
+			{URL: "https://github.com/org/repo/pull/3", State: "MERGED"},
+		}
+
+		result, err := reconciler.handleDeletion(context.Background(), "org", "repo", upstream.issues[1], issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(upstream.issues[1].State).To(Equal("closed"))
+		Expect(issueObject.Finalizers).To(BeEmpty())
+	})
+})
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Field indexes listing the GithubIssues that reference a ConfigMap, Secret or GithubMilestone.
+const (
+	configMapRefIndex = "spec.configMapRefs"
+	secretRefIndex    = "spec.secretRefs"
+	milestoneRefIndex = "spec.milestoneRef"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// resolveDescription returns the issue description, reading it from the referenced ConfigMap or Secret
+// when spec.descriptionFrom is set.
+func (r *GithubIssueReconciler) resolveDescription(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (string, error) {
+	source := issueObject.Spec.DescriptionFrom
+	switch {
+	case source == nil:
+		return issueObject.Spec.Description, nil
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: issueObject.Namespace, Name: ref.Name}, configMap); err != nil {
+			if apierrors.IsNotFound(err) && ptr.Deref(ref.Optional, false) {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to read description from ConfigMap %s: %v", ref.Name, err)
+		}
+		value, ok := configMap.Data[ref.Key]
+		if !ok && !ptr.Deref(ref.Optional, false) {
+			return "", fmt.Errorf("key %s not found in ConfigMap %s", ref.Key, ref.Name)
+		}
+		return value, nil
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: issueObject.Namespace, Name: ref.Name}, secret)
+		logging.AuditSecretRead(r.AuditLog, "GithubIssue", objectKey(issueObject), issueObject.Namespace+"/"+ref.Name, ref.Key, "description", err)
+		if err != nil {
+			if apierrors.IsNotFound(err) && ptr.Deref(ref.Optional, false) {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to read description from Secret %s: %v", ref.Name, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok && !ptr.Deref(ref.Optional, false) {
+			return "", fmt.Errorf("key %s not found in Secret %s", ref.Key, ref.Name)
+		}
+		return string(value), nil
+	default:
+		return "", fmt.Errorf("descriptionFrom sets neither configMapKeyRef nor secretKeyRef")
+	}
+}
+
+func indexConfigMapRefs(obj client.Object) []string {
+	spec := obj.(*issuesv1alpha1.GithubIssue).Spec
+	var names []string
+	if spec.DescriptionFrom != nil && spec.DescriptionFrom.ConfigMapKeyRef != nil {
+		names = append(names, spec.DescriptionFrom.ConfigMapKeyRef.Name)
+	}
+	if spec.TemplateRef != nil {
+		names = append(names, spec.TemplateRef.Name)
+	}
+	return names
+}
+
+func indexSecretRefs(obj client.Object) []string {
+	spec := obj.(*issuesv1alpha1.GithubIssue).Spec
+	var names []string
+	if spec.DescriptionFrom != nil && spec.DescriptionFrom.SecretKeyRef != nil {
+		names = append(names, spec.DescriptionFrom.SecretKeyRef.Name)
+	}
+	// Secrets of the central secrets namespace are not watched: they are read again on every sync.
+	if ref := spec.CredentialsSecretRef; ref != nil && ref.Namespace == "" {
+		names = append(names, ref.Name)
+	}
+	for _, mirror := range spec.Mirrors {
+		if ref := mirror.CredentialsSecretRef; ref != nil && ref.Namespace == "" {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
+
+func indexMilestoneRefs(obj client.Object) []string {
+	if ref := obj.(*issuesv1alpha1.GithubIssue).Spec.MilestoneRef; ref != nil {
+		return []string{ref.Name}
+	}
+	return nil
+}
+
+// referenceHandler queues the GithubIssues referencing the changed object.
+func (r *GithubIssueReconciler) referenceHandler(index string) handler.EventHandler {
+	enqueue := func(ctx context.Context, obj client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		var issues issuesv1alpha1.GithubIssueList
+		if err := r.List(ctx, &issues, client.InNamespace(obj.GetNamespace()), client.MatchingFields{index: obj.GetName()}); err != nil {
+			r.Log.Error("Failed to list issues referencing a changed object", zap.Error(err))
+			return
+		}
+		for i := range issues.Items {
+			r.causes.enqueue(&issues.Items[i], causeReference, q)
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if e.ObjectOld.GetResourceVersion() != e.ObjectNew.GetResourceVersion() {
+				enqueue(ctx, e.ObjectNew, q)
+			}
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, e.Object, q)
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// markConverted records that the upstream issue became a discussion. The ConvertedToDiscussion condition is terminal:
+// later reconciles stop calling GitHub for this GithubIssue.
+func (r *GithubIssueReconciler) markConverted(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, converted *git.ConvertedToDiscussionError) (ctrl.Result, error) {
+	r.logger(ctx).Info("Issue was converted to a discussion", zap.String("DiscussionURL", converted.URL))
+	decide(ctx, DecisionSkipped, "ConvertedToDiscussion", converted.Error())
+
+	issueObject.Status.ConvertedToDiscussionURL = converted.URL
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               ConvertedToDiscussionCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ConvertedToDiscussion",
+		Message:            converted.Error(),
+		ObservedGeneration: issueObject.Generation,
+	})
+	r.Recorder.Event(issueObject, corev1.EventTypeNormal, ConvertedToDiscussionCondition, converted.Error())
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// handleConverted skips a GithubIssue whose upstream issue became a discussion. Deleting it only drops the finalizer:
+// there is no issue left to close.
+func (r *GithubIssueReconciler) handleConverted(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (ctrl.Result, error) {
+	if issueObject.DeletionTimestamp.IsZero() {
+		r.logger(ctx).Debug("Issue was converted to a discussion, nothing to sync")
+		decide(ctx, DecisionSkipped, "ConvertedToDiscussion", fmt.Sprintf("The issue was converted to the discussion %s", issueObject.Status.ConvertedToDiscussionURL))
+		return ctrl.Result{}, nil
+	}
+	r.damper.forget(objectKey(issueObject))
+	r.triageDistribution.Forget(objectKey(issueObject))
+	r.syncs.forget(objectKey(issueObject))
+	return ctrl.Result{}, r.removeFinalizer(ctx, issueObject)
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// DryRunAnnotation set to "true" makes the operator plan its GitHub writes for a GithubIssue without performing them.
+const DryRunAnnotation = "issues.dana.io/dry-run"
+
+func dryRun(issueObject *issuesv1alpha1.GithubIssue) bool {
+	return issueObject.Annotations[DryRunAnnotation] == "true"
+}
+
+// handleDryRun reports the action the reconcile would take on GitHub through the DryRun condition and an event.
+// Deleting a GithubIssue in dry-run only drops its finalizer and leaves the upstream issue open.
+func (r *GithubIssueReconciler) handleDryRun(ctx context.Context, owner, repo string, issue *git.Issue, issueObject *issuesv1alpha1.GithubIssue) (ctrl.Result, error) {
+	reason, message, err := r.planAction(ctx, owner, repo, issue, issueObject)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.logger(ctx).Info("Dry run, skipping GitHub writes", zap.String("plannedAction", reason), zap.String("plan", message))
+	decide(ctx, DecisionSkipped, "DryRun", message)
+
+	if !issueObject.DeletionTimestamp.IsZero() {
+		r.Recorder.Event(issueObject, corev1.EventTypeNormal, DryRunCondition, message)
+		return ctrl.Result{}, r.removeFinalizer(ctx, issueObject)
+	}
+
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               DryRunCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: issueObject.Generation,
+	})
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+	r.Recorder.Event(issueObject, corev1.EventTypeNormal, DryRunCondition, message)
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// planAction returns the reason and description of the write a reconcile would perform on GitHub.
+func (r *GithubIssueReconciler) planAction(ctx context.Context, owner, repo string, issue *git.Issue, issueObject *issuesv1alpha1.GithubIssue) (string, string, error) {
+	if !issueObject.DeletionTimestamp.IsZero() {
+		if !issueExists(issue) || issue.State == "closed" {
+			return "NoChange", "Would leave the upstream issue as is, it is not open", nil
+		}
+		return "PlannedClose", fmt.Sprintf("Would close issue #%d", issue.Number), nil
+	}
+
+	desired, err := r.desiredIssue(ctx, owner, repo, issueObject)
+	if err != nil {
+		return "", "", err
+	}
+	if !issueExists(issue) {
+		return "PlannedCreate", fmt.Sprintf("Would create issue %q in %s/%s", desired.Title, owner, repo), nil
+	}
+
+	var changes []string
+	if issue.Description != desired.Body {
+		changes = append(changes, "description")
+	}
+	if desired.Milestone != 0 && issue.Milestone != desired.Milestone {
+		changes = append(changes, fmt.Sprintf("milestone %d", desired.Milestone))
+	}
+	for _, label := range desired.Labels {
+		if !slices.Contains(issue.Labels, label) {
+			changes = append(changes, "+label "+label)
+		}
+	}
+	for _, label := range extraLabels(issueObject, issue, desired) {
+		changes = append(changes, "-label "+label)
+	}
+	if len(changes) == 0 {
+		return "NoChange", fmt.Sprintf("Issue #%d matches the spec", issue.Number), nil
+	}
+	return "PlannedEdit", fmt.Sprintf("Would edit issue #%d: %s", issue.Number, strings.Join(changes, ", ")), nil
+}
+
+// clearDryRun flips the DryRun condition once the annotation is removed.
+func (r *GithubIssueReconciler) clearDryRun(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, DryRunCondition) {
+		return nil
+	}
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               DryRunCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Disabled",
+		Message:            "GitHub writes are enabled",
+		ObservedGeneration: issueObject.Generation,
+	})
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
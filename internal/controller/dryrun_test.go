@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("dry run", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default", Annotations: map[string]string{DryRunAnnotation: "true"}},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", Title: "Flaky test", Description: "Fails once a week."},
+		}
+		reconciler = &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+	})
+
+	It("plans creating a missing issue", func() {
+		_, err := reconciler.handleDryRun(context.Background(), "org", "repo", nil, issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		condition := meta.FindStatusCondition(issueObject.Status.Conditions, DryRunCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal("PlannedCreate"))
+	})
+
+	It("plans the fields an edit would change", func() {
+		issue := &git.Issue{Number: 3, Title: "Flaky test", Description: "stale", State: "open"}
+		reason, message, err := reconciler.planAction(context.Background(), "org", "repo", issue, issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("PlannedEdit"))
+		Expect(message).To(ContainSubstring("description"))
+	})
+
+	It("plans closing the issue of a deleted GithubIssue", func() {
+		now := metav1.Now()
+		issueObject.DeletionTimestamp = &now
+		reason, _, err := reconciler.planAction(context.Background(), "org", "repo", &git.Issue{Number: 3, State: "open"}, issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("PlannedClose"))
+	})
+})
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dueDateFormat is how due dates are written in issue bodies and reminder comments.
+const dueDateFormat = "2006-01-02 15:04 UTC"
+
+// withDueDate appends spec.dueDate to the issue body: GitHub issues have no due date field.
+func withDueDate(description string, issueObject *issuesv1alpha1.GithubIssue) string {
+	dueDate := issueObject.Spec.DueDate
+	if dueDate == nil {
+		return description
+	}
+	return fmt.Sprintf("%s\n\n**Due:** %s", description, dueDate.UTC().Format(dueDateFormat))
+}
+
+// syncDueDate sets the Overdue condition of the issue and posts the spec.dueReminder comment once the due date
+// is close. It returns how long until the condition or the reminder is due to change, zero for never.
+func (r *GithubIssueReconciler) syncDueDate(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue, now time.Time) (time.Duration, error) {
+	dueDate := issueObject.Spec.DueDate
+	if dueDate == nil {
+		if !meta.RemoveStatusCondition(&issueObject.Status.Conditions, OverdueCondition) {
+			return 0, nil
+		}
+		return 0, r.updateDueDateStatus(ctx, issueObject)
+	}
+
+	condition := metav1.Condition{
+		Type:               OverdueCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NotDue",
+		Message:            fmt.Sprintf("The issue is due at %s", dueDate.UTC().Format(dueDateFormat)),
+		ObservedGeneration: issueObject.Generation,
+	}
+	var wait time.Duration
+	switch {
+	case issue.State == "closed":
+		condition.Reason, condition.Message = "Closed", "The issue was closed"
+	case !now.Before(dueDate.Time):
+		condition.Status, condition.Reason = metav1.ConditionTrue, "PastDueDate"
+		condition.Message = fmt.Sprintf("The issue was due at %s", dueDate.UTC().Format(dueDateFormat))
+	default:
+		wait = dueDate.Sub(now)
+		if reminder := remindAt(issueObject); reminder.After(now) && reminder.Sub(now) < wait {
+			wait = reminder.Sub(now)
+		}
+	}
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, condition)
+	if condition.Status == metav1.ConditionTrue && changed {
+		r.logger(ctx).Info("Issue is overdue", zap.Time("dueDate", dueDate.Time))
+	}
+
+	if reminder := remindAt(issueObject); condition.Reason == "NotDue" && !reminder.IsZero() && !now.Before(reminder) {
+		body := issueObject.Spec.DueReminder.Body
+		if body == "" {
+			body = fmt.Sprintf("This issue is due at %s.", dueDate.UTC().Format(dueDateFormat))
+		}
+		if _, err := r.issueClient(ctx).Comment(ctx, owner, repo, issue.Number, body); err != nil {
+			return 0, fmt.Errorf("failed to post due date reminder: %v", err)
+		}
+		r.logger(ctx).Info("Posted due date reminder", zap.Time("dueDate", dueDate.Time))
+		issueObject.Status.RemindedDueDate = dueDate.DeepCopy()
+		changed = true
+	}
+	if !changed {
+		return wait, nil
+	}
+	return wait, r.updateDueDateStatus(ctx, issueObject)
+}
+
+// remindAt returns when the reminder comment for spec.dueDate is due, zero when there is none to post.
+func remindAt(issueObject *issuesv1alpha1.GithubIssue) time.Time {
+	reminder := issueObject.Spec.DueReminder
+	if reminder == nil || issueObject.Spec.DueDate == nil {
+		return time.Time{}
+	}
+	if reminded := issueObject.Status.RemindedDueDate; reminded != nil && reminded.Equal(issueObject.Spec.DueDate) {
+		return time.Time{}
+	}
+	return issueObject.Spec.DueDate.Add(-reminder.Before.Duration)
+}
+
+func (r *GithubIssueReconciler) updateDueDateStatus(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
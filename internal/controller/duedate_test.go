@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("due date", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		comments    *fakeCommentClient
+		issue       = &git.Issue{Number: 7, State: "open"}
+		dueDate     = time.Date(2030, time.January, 25, 12, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				DueDate:     &metav1.Time{Time: dueDate},
+				DueReminder: &issuesv1alpha1.DueReminder{Before: metav1.Duration{Duration: 24 * time.Hour}},
+			},
+		}
+		comments = &fakeCommentClient{comments: map[int64]string{}}
+		reconciler = &GithubIssueReconciler{
+			Client:  fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:     zap.NewNop(),
+			Clients: &git.Clients{Default: comments},
+			pending: newPendingWrites(),
+		}
+	})
+
+	sync := func(now time.Time) time.Duration {
+		wait, err := reconciler.syncDueDate(context.Background(), "org", "repo", issueObject, issue, now)
+		Expect(err).NotTo(HaveOccurred())
+		return wait
+	}
+
+	It("renders the due date in the issue body", func() {
+		Expect(withDueDate("Renew the certificates.", issueObject)).To(Equal("Renew the certificates.\n\n**Due:** 2030-01-25 12:00 UTC"))
+	})
+
+	It("reminds once before the due date and marks the issue overdue after it", func() {
+		Expect(sync(dueDate.Add(-48 * time.Hour))).To(Equal(24 * time.Hour))
+		Expect(comments.comments).To(BeEmpty())
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, OverdueCondition)).To(BeTrue())
+
+		Expect(sync(dueDate.Add(-time.Hour))).To(Equal(time.Hour))
+		Expect(comments.comments).To(ConsistOf("This issue is due at 2030-01-25 12:00 UTC."))
+		Expect(issueObject.Status.RemindedDueDate.Equal(issueObject.Spec.DueDate)).To(BeTrue())
+
+		Expect(sync(dueDate.Add(-time.Minute))).To(Equal(time.Minute))
+		Expect(comments.comments).To(HaveLen(1))
+
+		Expect(sync(dueDate)).To(BeZero())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, OverdueCondition)).To(BeTrue())
+	})
+
+	It("clears the condition once the issue is closed or the due date removed", func() {
+		sync(dueDate.Add(time.Hour))
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, OverdueCondition)).To(BeTrue())
+		Expect(comments.comments).To(BeEmpty())
+
+		closed := &git.Issue{Number: 7, State: "closed"}
+		_, err := reconciler.syncDueDate(context.Background(), "org", "repo", issueObject, closed, dueDate.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, OverdueCondition).Reason).To(Equal("Closed"))
+
+		issueObject.Spec.DueDate = nil
+		sync(dueDate.Add(time.Hour))
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, OverdueCondition)).To(BeNil())
+	})
+})
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// handleMarkedDuplicate applies spec.duplicatePolicy to an upstream issue closed as a duplicate of another issue,
+// as read from its timeline. It reports whether the reconcile must stop syncing the issue: under Repoint the
+// duplicate is no longer edited until it is reopened, under Delete the GithubIssue is deleted.
+func (r *GithubIssueReconciler) handleMarkedDuplicate(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) (bool, error) {
+	policy := issueObject.Spec.DuplicatePolicy
+	if policy == "" || policy == issuesv1alpha1.DuplicatePolicyIgnore {
+		return false, nil
+	}
+	if issue.State != "closed" {
+		return false, r.clearMarkedDuplicate(ctx, issueObject)
+	}
+	if issueObject.Status.DuplicateOf != nil && meta.IsStatusConditionTrue(issueObject.Status.Conditions, MarkedDuplicateCondition) {
+		// Already repointed: the timeline is read again once the issue is reopened.
+		decide(ctx, DecisionSkipped, "MarkedDuplicate", fmt.Sprintf("The issue is a duplicate of %s", issueObject.Status.DuplicateOf.URL))
+		return true, nil
+	}
+
+	canonical, err := r.issueClient(ctx).DuplicateOf(ctx, owner, repo, issue.Number)
+	if err != nil {
+		return false, err
+	}
+	if canonical == nil {
+		return false, r.clearMarkedDuplicate(ctx, issueObject)
+	}
+	message := fmt.Sprintf("The issue was marked a duplicate of %s", canonical.URL)
+	r.logger(ctx).Info("Issue was marked as a duplicate", zap.String("canonical", canonical.URL), zap.String("policy", string(policy)))
+	decide(ctx, DecisionSkipped, "MarkedDuplicate", message)
+
+	if policy == issuesv1alpha1.DuplicatePolicyDelete {
+		r.Recorder.Event(issueObject, corev1.EventTypeNormal, MarkedDuplicateCondition, message+", deleting the GithubIssue")
+		if err := r.Delete(ctx, issueObject); client.IgnoreNotFound(err) != nil {
+			return true, fmt.Errorf("failed to delete duplicate GithubIssue: %v", err)
+		}
+		return true, nil
+	}
+
+	issueObject.Status.DuplicateOf = &issuesv1alpha1.CanonicalIssue{Number: canonical.Number, URL: canonical.URL}
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               MarkedDuplicateCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "MarkedDuplicate",
+		Message:            message,
+		ObservedGeneration: issueObject.Generation,
+	})
+	r.Recorder.Event(issueObject, corev1.EventTypeNormal, MarkedDuplicateCondition, message)
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return true, fmt.Errorf("failed to update status: %v", err)
+	}
+	return true, nil
+}
+
+// clearMarkedDuplicate resumes syncing an issue that is no longer a duplicate, e.g. because it was reopened.
+func (r *GithubIssueReconciler) clearMarkedDuplicate(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if issueObject.Status.DuplicateOf == nil && meta.FindStatusCondition(issueObject.Status.Conditions, MarkedDuplicateCondition) == nil {
+		return nil
+	}
+	r.logger(ctx).Info("Issue is no longer a duplicate, resuming sync")
+	issueObject.Status.DuplicateOf = nil
+	meta.RemoveStatusCondition(&issueObject.Status.Conditions, MarkedDuplicateCondition)
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
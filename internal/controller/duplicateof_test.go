@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeDuplicateClient reports the canonical issue of every issue it is asked about.
+type fakeDuplicateClient struct {
+	*fakeMirrorClient
+	canonical *git.CanonicalIssue
+	calls     int
+}
+
+func (f *fakeDuplicateClient) DuplicateOf(_ context.Context, _, _ string, _ int) (*git.CanonicalIssue, error) {
+	f.calls++
+	return f.canonical, nil
+}
+
+var _ = Describe("marked duplicates", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		upstream    *fakeDuplicateClient
+		key         = types.NamespacedName{Name: "issue", Namespace: "default"}
+		closed      = &git.Issue{Number: 7, Title: "Crash", State: "closed", StateReason: "duplicate"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo: "https://github.com/org/repo", Title: "Crash", DuplicatePolicy: issuesv1alpha1.DuplicatePolicyRepoint,
+			},
+		}
+		upstream = &fakeDuplicateClient{
+			fakeMirrorClient: &fakeMirrorClient{issues: map[int]*git.Issue{closed.Number: closed}},
+			canonical:        &git.CanonicalIssue{Number: 3, URL: "https://github.com/org/repo/issues/3"},
+		}
+		reconciler = &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace}}).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Clients:  &git.Clients{Default: upstream},
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+	})
+
+	It("records the canonical issue and stops syncing until the duplicate is reopened", func() {
+		duplicate, err := reconciler.handleMarkedDuplicate(context.Background(), "org", "repo", issueObject, closed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeTrue())
+		Expect(issueObject.Status.DuplicateOf).To(Equal(&issuesv1alpha1.CanonicalIssue{Number: 3, URL: "https://github.com/org/repo/issues/3"}))
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, MarkedDuplicateCondition)).To(BeTrue())
+
+		duplicate, err = reconciler.handleMarkedDuplicate(context.Background(), "org", "repo", issueObject, closed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeTrue())
+		Expect(upstream.calls).To(Equal(1))
+
+		duplicate, err = reconciler.handleMarkedDuplicate(context.Background(), "org", "repo", issueObject, &git.Issue{Number: 7, State: "open"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeFalse())
+		Expect(issueObject.Status.DuplicateOf).To(BeNil())
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, MarkedDuplicateCondition)).To(BeNil())
+	})
+
+	It("finds the closed duplicate by its issue number when reconciling", func() {
+		reconciler.Prepare()
+		issueObject.Status.IssueNumber = closed.Number
+		Expect(reconciler.Status().Update(context.Background(), issueObject)).To(Succeed())
+
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstream.issues).To(HaveLen(1))
+		Expect(upstream.calls).To(Equal(1))
+
+		reconciled := &issuesv1alpha1.GithubIssue{}
+		Expect(reconciler.Get(context.Background(), key, reconciled)).To(Succeed())
+		Expect(reconciled.Status.DuplicateOf).To(Equal(&issuesv1alpha1.CanonicalIssue{Number: 3, URL: "https://github.com/org/repo/issues/3"}))
+		Expect(meta.IsStatusConditionTrue(reconciled.Status.Conditions, MarkedDuplicateCondition)).To(BeTrue())
+	})
+
+	It("keeps syncing issues closed for another reason", func() {
+		upstream.canonical = nil
+		duplicate, err := reconciler.handleMarkedDuplicate(context.Background(), "org", "repo", issueObject, closed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeFalse())
+	})
+
+	It("deletes the GithubIssue under the Delete policy", func() {
+		issueObject.Spec.DuplicatePolicy = issuesv1alpha1.DuplicatePolicyDelete
+		duplicate, err := reconciler.handleMarkedDuplicate(context.Background(), "org", "repo", issueObject, closed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(context.Background(), key, &issuesv1alpha1.GithubIssue{}))).To(BeTrue())
+	})
+
+	It("never reads the timeline under the Ignore policy", func() {
+		issueObject.Spec.DuplicatePolicy = issuesv1alpha1.DuplicatePolicyIgnore
+		duplicate, err := reconciler.handleMarkedDuplicate(context.Background(), "org", "repo", issueObject, closed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeFalse())
+		Expect(upstream.calls).To(BeZero())
+	})
+})
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/utils/ptr"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeRemoveLabelClient records the labels it removes.
+type fakeRemoveLabelClient struct {
+	git.IssueClient
+	removed []string
+}
+
+func (f *fakeRemoveLabelClient) RemoveLabel(_ context.Context, _, _ string, _ int, label string) error {
+	f.removed = append(f.removed, label)
+	return nil
+}
+
+var _ = Describe("estimate label", func() {
+	var (
+		reconciler *GithubIssueReconciler
+		upstream   *fakeRemoveLabelClient
+	)
+
+	BeforeEach(func() {
+		upstream = &fakeRemoveLabelClient{}
+		reconciler = &GithubIssueReconciler{Log: zap.NewNop(), Clients: &git.Clients{Default: upstream}}
+	})
+
+	It("adds the estimate label to the spec labels once", func() {
+		issueObject := &issuesv1alpha1.GithubIssue{Spec: issuesv1alpha1.GithubIssueSpec{Estimate: ptr.To[int32](5)}}
+		Expect(reconciler.withManagedLabels(issueObject, []string{"bug"})).To(Equal([]string{"bug", "estimate/5"}))
+		Expect(reconciler.withManagedLabels(issueObject, []string{"estimate/5"})).To(Equal([]string{"estimate/5"}))
+		Expect(reconciler.withManagedLabels(&issuesv1alpha1.GithubIssue{}, []string{"bug"})).To(Equal([]string{"bug"}))
+	})
+
+	It("removes the labels of previous estimates", func() {
+		issue := &git.Issue{Number: 1, Labels: []string{"bug", "estimate/3", "estimate/5", "estimate/8"}}
+		issueObject := &issuesv1alpha1.GithubIssue{Spec: issuesv1alpha1.GithubIssueSpec{Estimate: ptr.To[int32](5)}}
+		Expect(reconciler.removeStaleEstimates(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+		Expect(upstream.removed).To(Equal([]string{"estimate/3", "estimate/8"}))
+	})
+
+	It("leaves the last estimate label when the estimate is cleared", func() {
+		issue := &git.Issue{Number: 1, Labels: []string{"estimate/5"}}
+		Expect(reconciler.removeStaleEstimates(context.Background(), "org", "repo", &issuesv1alpha1.GithubIssue{}, issue)).To(Succeed())
+		Expect(upstream.removed).To(BeEmpty())
+	})
+})
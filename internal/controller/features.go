@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// optionalFeature describes the status an optional feature owns, so it can be dropped once the feature is off.
+type optionalFeature struct {
+	name string
+	// enabled reports whether the feature manages the status of the issue.
+	enabled func(r *GithubIssueReconciler, issueObject *issuesv1alpha1.GithubIssue) bool
+	// conditions are the condition types only the feature sets.
+	conditions []string
+	// clear resets the status fields only the feature sets and reports whether any was set.
+	clear func(status *issuesv1alpha1.GithubIssueStatus) bool
+}
+
+// optionalFeatures lists the features that can be turned off by operator flags or by the spec.
+// Add an entry with every feature that records its own conditions or status fields.
+var optionalFeatures = []optionalFeature{
+	{
+		name:       "possible-duplicates",
+		enabled:    func(r *GithubIssueReconciler, _ *issuesv1alpha1.GithubIssue) bool { return r.DetectPossibleDuplicates },
+		conditions: []string{PossibleDuplicateCondition},
+	},
+	{
+		name: "token-expiry",
+		enabled: func(r *GithubIssueReconciler, _ *issuesv1alpha1.GithubIssue) bool {
+			return r.TokenExpiry != nil && r.TokenExpiryWarning > 0
+		},
+		conditions: []string{TokenExpiringCondition},
+	},
+	{
+		name: "manual-conflicts",
+		enabled: func(_ *GithubIssueReconciler, issueObject *issuesv1alpha1.GithubIssue) bool {
+			return issueObject.Spec.ConflictPolicy == issuesv1alpha1.ConflictPolicyManual
+		},
+		conditions: []string{ConflictCondition},
+	},
+	{
+		name: "adopt-external-edits",
+		enabled: func(_ *GithubIssueReconciler, issueObject *issuesv1alpha1.GithubIssue) bool {
+			return issueObject.Spec.ConflictPolicy == issuesv1alpha1.ConflictPolicyGitHubWins
+		},
+		clear: func(status *issuesv1alpha1.GithubIssueStatus) bool {
+			set := status.ExternalDescription != ""
+			status.ExternalDescription = ""
+			return set
+		},
+	},
+	{
+		name: "repoint-duplicates",
+		enabled: func(_ *GithubIssueReconciler, issueObject *issuesv1alpha1.GithubIssue) bool {
+			return issueObject.Spec.DuplicatePolicy == issuesv1alpha1.DuplicatePolicyRepoint
+		},
+		conditions: []string{MarkedDuplicateCondition},
+		clear: func(status *issuesv1alpha1.GithubIssueStatus) bool {
+			set := status.DuplicateOf != nil
+			status.DuplicateOf = nil
+			return set
+		},
+	},
+}
+
+// pruneDisabledFeatures removes the conditions and status fields of the optional features that are off,
+// instead of leaving stale entries behind that confuse users and status tooling.
+func (r *GithubIssueReconciler) pruneDisabledFeatures(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	var pruned []string
+	for _, feature := range optionalFeatures {
+		if feature.enabled(r, issueObject) {
+			continue
+		}
+		removed := false
+		for _, conditionType := range feature.conditions {
+			if meta.RemoveStatusCondition(&issueObject.Status.Conditions, conditionType) {
+				removed = true
+			}
+		}
+		if feature.clear != nil && feature.clear(&issueObject.Status) {
+			removed = true
+		}
+		if removed {
+			pruned = append(pruned, feature.name)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	r.logger(ctx).Info("Removing status of disabled features", zap.Strings("features", pruned))
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
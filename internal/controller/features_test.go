@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("disabled features", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		for _, conditionType := range []string{PossibleDuplicateCondition, TokenExpiringCondition, ProjectsSyncedCondition} {
+			meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: "Test"})
+		}
+		issueObject.Status.ExternalDescription = "edited upstream"
+		reconciler = &GithubIssueReconciler{
+			Client:  fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:     zap.NewNop(),
+			pending: newPendingWrites(),
+		}
+	})
+
+	It("removes the status of disabled features only", func() {
+		reconciler.DetectPossibleDuplicates = true
+		Expect(reconciler.pruneDisabledFeatures(context.Background(), issueObject)).To(Succeed())
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, TokenExpiringCondition)).To(BeNil())
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, PossibleDuplicateCondition)).NotTo(BeNil())
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, ProjectsSyncedCondition)).NotTo(BeNil())
+		Expect(issueObject.Status.ExternalDescription).To(BeEmpty())
+	})
+})
@@ -0,0 +1,302 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// deleteCommentFinalizer holds the deletion of a GithubComment until its upstream comment is deleted.
+const deleteCommentFinalizer = "issues.dana.io/finalizer"
+
+// commentIssueRefIndex is the field index listing the GithubComments posted on a GithubIssue.
+const commentIssueRefIndex = "spec.issueRef"
+
+// commentParent is the issue a GithubComment is posted on.
+type commentParent struct {
+	repoURL     string
+	owner, repo string
+	number      int
+}
+
+// GithubCommentReconciler reconciles a GithubComment object
+type GithubCommentReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    *zap.Logger
+	// Clients picks the client of the provider serving the repository of a comment.
+	Clients *git.Clients
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubcomments,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubcomments/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubcomments/finalizers,verbs=update
+
+func (r *GithubCommentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	commentObject := &issuesv1alpha1.GithubComment{}
+	if err := r.Get(ctx, req.NamespacedName, commentObject); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch comment object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !commentObject.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(commentObject, deleteCommentFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.deletePostedComment(ctx, log, commentObject); err != nil {
+			log.Error("Failed to delete comment", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(commentObject, deleteCommentFinalizer)
+		if err := r.Update(ctx, commentObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %v", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(commentObject, deleteCommentFinalizer) {
+		controllerutil.AddFinalizer(commentObject, deleteCommentFinalizer)
+		if err := r.Update(ctx, commentObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %v", err)
+		}
+	}
+
+	observed := commentObject.Status.DeepCopy()
+	parent, pending, err := r.resolveParent(ctx, commentObject)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if pending != "" {
+		// The GithubIssue watch queues the comment again once the issue exists upstream.
+		log.Info("Waiting for the parent issue", zap.String("reason", pending))
+		return ctrl.Result{}, r.setCommentStatus(ctx, commentObject, observed, metav1.ConditionFalse, "IssuePending", pending)
+	}
+
+	if commentObject.Status.CommentID != 0 &&
+		(commentObject.Status.Repo != parent.repoURL || commentObject.Status.IssueNumber != parent.number) {
+		// The comment moved to another issue.
+		if err := r.deletePostedComment(ctx, log, commentObject); err != nil {
+			return ctrl.Result{}, err
+		}
+		commentObject.Status.CommentID, commentObject.Status.Hash = 0, ""
+	}
+
+	if err := r.syncComment(ctx, log, parent, commentObject); err != nil {
+		if errors.Is(err, errCommentEdited) {
+			return ctrl.Result{}, r.setCommentStatus(ctx, commentObject, observed, metav1.ConditionFalse, "EditedUpstream",
+				"The comment was edited on GitHub and is no longer updated from the spec")
+		}
+		log.Error("Failed to sync comment", zap.Error(err))
+		if statusErr := r.setCommentStatus(ctx, commentObject, observed, metav1.ConditionFalse, "SyncFailed", err.Error()); statusErr != nil {
+			log.Error("Failed to update comment status", zap.Error(statusErr))
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, r.setCommentStatus(ctx, commentObject, observed, metav1.ConditionTrue, "Synced", "The comment is synced with GitHub")
+}
+
+// errCommentEdited is returned by syncComment for a comment edited on GitHub.
+var errCommentEdited = errors.New("comment edited on GitHub")
+
+// syncComment posts the comment, or edits it when the spec body changed. Like spec.comments of a GithubIssue,
+// a comment whose upstream body is no longer the one last written is left as it is.
+func (r *GithubCommentReconciler) syncComment(ctx context.Context, log *zap.Logger, parent commentParent, commentObject *issuesv1alpha1.GithubComment) error {
+	issueClient, err := r.Clients.ForRepo(parent.repoURL)
+	if err != nil {
+		return err
+	}
+	hash := commentHash(commentObject.Spec.Body)
+	if id := commentObject.Status.CommentID; id != 0 {
+		body, err := issueClient.GetComment(ctx, parent.owner, parent.repo, id)
+		switch {
+		case errors.Is(err, git.ErrCommentNotFound):
+			log.Info("Comment was deleted upstream, posting it again")
+		case err != nil:
+			return fmt.Errorf("failed to get comment: %v", err)
+		case commentHash(body) != commentObject.Status.Hash:
+			return errCommentEdited
+		case commentObject.Status.Hash == hash:
+			return nil
+		default:
+			if err := issueClient.EditComment(ctx, parent.owner, parent.repo, id, commentObject.Spec.Body); err != nil {
+				return fmt.Errorf("failed to edit comment: %v", err)
+			}
+			log.Info("Edited comment", zap.Int64("id", id))
+			commentObject.Status.Hash = hash
+			return nil
+		}
+	}
+
+	id, err := issueClient.Comment(ctx, parent.owner, parent.repo, parent.number, commentObject.Spec.Body)
+	if err != nil {
+		return fmt.Errorf("failed to post comment: %v", err)
+	}
+	log.Info("Posted comment", zap.Int64("id", id), zap.Int("issue", parent.number))
+	commentObject.Status.Repo, commentObject.Status.IssueNumber = parent.repoURL, parent.number
+	commentObject.Status.CommentID, commentObject.Status.Hash = id, hash
+	// Record the ID right away so the comment is never posted twice.
+	if err := r.Status().Update(ctx, commentObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// deletePostedComment deletes the comment recorded in status unless it was edited on GitHub.
+func (r *GithubCommentReconciler) deletePostedComment(ctx context.Context, log *zap.Logger, commentObject *issuesv1alpha1.GithubComment) error {
+	status := commentObject.Status
+	if status.CommentID == 0 {
+		return nil
+	}
+	owner, repo, err := git.ParseRepoURL(status.Repo)
+	if err != nil {
+		return err
+	}
+	issueClient, err := r.Clients.ForRepo(status.Repo)
+	if err != nil {
+		return err
+	}
+	body, err := issueClient.GetComment(ctx, owner, repo, status.CommentID)
+	if errors.Is(err, git.ErrCommentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %v", err)
+	}
+	if commentHash(body) != status.Hash {
+		log.Info("Leaving comment edited on GitHub in place", zap.Int64("id", status.CommentID))
+		return nil
+	}
+	if err := issueClient.DeleteComment(ctx, owner, repo, status.CommentID); err != nil {
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+	log.Info("Deleted comment", zap.Int64("id", status.CommentID))
+	return nil
+}
+
+// resolveParent returns the issue the comment is posted on. It returns a reason instead while the referenced
+// GithubIssue is missing or has no upstream issue yet.
+func (r *GithubCommentReconciler) resolveParent(ctx context.Context, commentObject *issuesv1alpha1.GithubComment) (commentParent, string, error) {
+	parent := commentParent{repoURL: commentObject.Spec.Repo, number: commentObject.Spec.IssueNumber}
+	if ref := commentObject.Spec.IssueRef; ref != nil {
+		issueObject := &issuesv1alpha1.GithubIssue{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: commentObject.Namespace, Name: ref.Name}, issueObject)
+		if apierrors.IsNotFound(err) {
+			return parent, fmt.Sprintf("GithubIssue %s not found", ref.Name), nil
+		}
+		if err != nil {
+			return parent, "", fmt.Errorf("failed to get GithubIssue %s: %v", ref.Name, err)
+		}
+		if issueObject.Status.IssueNumber == 0 {
+			return parent, fmt.Sprintf("GithubIssue %s has not been created upstream yet", ref.Name), nil
+		}
+		parent.repoURL, parent.number = issueObject.RepoURL(), issueObject.Status.IssueNumber
+	}
+
+	owner, repo, err := git.ParseRepoURL(parent.repoURL)
+	if err != nil {
+		return parent, "", err
+	}
+	parent.owner, parent.repo = owner, repo
+	return parent, "", nil
+}
+
+// setCommentStatus sets the Ready condition and writes the status when it differs from observed.
+func (r *GithubCommentReconciler) setCommentStatus(ctx context.Context, commentObject *issuesv1alpha1.GithubComment, observed *issuesv1alpha1.GithubCommentStatus, status metav1.ConditionStatus, reason, message string) error {
+	meta.SetStatusCondition(&commentObject.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: commentObject.Generation,
+	})
+	if equality.Semantic.DeepEqual(observed, &commentObject.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, commentObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// commentsForIssue queues the GithubComments posted on a GithubIssue.
+func (r *GithubCommentReconciler) commentsForIssue(ctx context.Context, obj client.Object) []reconcile.Request {
+	var comments issuesv1alpha1.GithubCommentList
+	if err := r.List(ctx, &comments, client.InNamespace(obj.GetNamespace()), client.MatchingFields{commentIssueRefIndex: obj.GetName()}); err != nil {
+		r.Log.Error("Failed to list comments of a changed issue", zap.Error(err))
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(comments.Items))
+	for _, comment := range comments.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&comment)})
+	}
+	return requests
+}
+
+// parentIssueMoved reports whether an update changed the upstream issue GithubComments are posted on.
+func parentIssueMoved(e event.UpdateEvent) bool {
+	oldIssue, oldOK := e.ObjectOld.(*issuesv1alpha1.GithubIssue)
+	newIssue, newOK := e.ObjectNew.(*issuesv1alpha1.GithubIssue)
+	if !oldOK || !newOK {
+		return false
+	}
+	return oldIssue.Status.IssueNumber != newIssue.Status.IssueNumber || oldIssue.RepoURL() != newIssue.RepoURL()
+}
+
+func indexCommentIssueRef(obj client.Object) []string {
+	if ref := obj.(*issuesv1alpha1.GithubComment).Spec.IssueRef; ref != nil {
+		return []string{ref.Name}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubCommentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &issuesv1alpha1.GithubComment{}, commentIssueRefIndex, indexCommentIssueRef); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.GithubComment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&issuesv1alpha1.GithubIssue{}, handler.EnqueueRequestsFromMapFunc(r.commentsForIssue),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: parentIssueMoved})).
+		Named("githubcomment").
+		Complete(r)
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("GithubComment controller", func() {
+	var (
+		reconciler  *GithubCommentReconciler
+		comments    *fakeCommentClient
+		issueObject *issuesv1alpha1.GithubIssue
+		key         = types.NamespacedName{Name: "status-update", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/example-org/example-repo"},
+		}
+		commentObject := &issuesv1alpha1.GithubComment{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: issuesv1alpha1.GithubCommentSpec{
+				IssueRef: &corev1.LocalObjectReference{Name: "release"},
+				Body:     "Rollout at 10%",
+			},
+		}
+		comments = &fakeCommentClient{comments: map[int64]string{}}
+		reconciler = &GithubCommentReconciler{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject, commentObject).
+				WithStatusSubresource(issueObject, commentObject).Build(),
+			Log:     zap.NewNop(),
+			Clients: &git.Clients{Default: comments},
+		}
+	})
+
+	reconcile := func() *issuesv1alpha1.GithubComment {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		commentObject := &issuesv1alpha1.GithubComment{}
+		if err := reconciler.Get(context.Background(), key, commentObject); client.IgnoreNotFound(err) != nil {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		return commentObject
+	}
+
+	createIssue := func() {
+		issueObject.Status.IssueNumber = 12
+		Expect(reconciler.Status().Update(context.Background(), issueObject)).To(Succeed())
+	}
+
+	It("waits for the parent issue, then posts and edits the comment", func() {
+		commentObject := reconcile()
+		Expect(comments.comments).To(BeEmpty())
+		Expect(meta.FindStatusCondition(commentObject.Status.Conditions, ReadyCondition).Reason).To(Equal("IssuePending"))
+
+		createIssue()
+		commentObject = reconcile()
+		Expect(comments.comments).To(Equal(map[int64]string{1: "Rollout at 10%"}))
+		Expect(commentObject.Status.IssueNumber).To(Equal(12))
+		Expect(meta.IsStatusConditionTrue(commentObject.Status.Conditions, ReadyCondition)).To(BeTrue())
+
+		commentObject.Spec.Body = "Rollout at 50%"
+		Expect(reconciler.Update(context.Background(), commentObject)).To(Succeed())
+		reconcile()
+		Expect(comments.comments).To(Equal(map[int64]string{1: "Rollout at 50%"}))
+	})
+
+	It("leaves a comment edited on GitHub in place", func() {
+		createIssue()
+		reconcile()
+		comments.comments[1] = "Rollout paused by the on-call engineer"
+
+		commentObject := reconcile()
+		Expect(meta.FindStatusCondition(commentObject.Status.Conditions, ReadyCondition).Reason).To(Equal("EditedUpstream"))
+		Expect(reconciler.Delete(context.Background(), commentObject)).To(Succeed())
+		reconcile()
+		Expect(comments.comments).To(Equal(map[int64]string{1: "Rollout paused by the on-call engineer"}))
+	})
+
+	It("deletes the comment with the GithubComment", func() {
+		createIssue()
+		commentObject := reconcile()
+		Expect(reconciler.Delete(context.Background(), commentObject)).To(Succeed())
+		reconcile()
+		Expect(comments.comments).To(BeEmpty())
+		Expect(reconciler.Get(context.Background(), key, &issuesv1alpha1.GithubComment{})).NotTo(Succeed())
+	})
+})
@@ -18,11 +18,16 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
-	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/finalizer"
 	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/labels"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/lifecycle"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/triage"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,25 +36,182 @@ import (
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"time"
 )
 
+const (
+	// InvalidSpecCondition is set when the GithubIssue spec can't be reconciled until it is changed.
+	InvalidSpecCondition = "InvalidSpec"
+	// SuspendedCondition is true while spec.suspend stops the reconciler from calling GitHub.
+	SuspendedCondition = "Suspended"
+	// LimitsExceededCondition is true while spec labels or assignees are truncated to fit GitHub limits.
+	LimitsExceededCondition = "LimitsExceeded"
+	// TokenExpiringCondition is true while the GitHub token used for the issue expires within TokenExpiryWarning.
+	TokenExpiringCondition = "TokenExpiring"
+	// ProjectsSyncedCondition reports whether the issue was added to every board of spec.projects.
+	ProjectsSyncedCondition = "ProjectsSynced"
+	// MirrorsSyncedCondition reports whether the issue is mirrored to every repository of spec.mirrors.
+	MirrorsSyncedCondition = "MirrorsSynced"
+	// PossibleDuplicateCondition is true while the issue is not created because similar issues are open upstream.
+	PossibleDuplicateCondition = "PossibleDuplicate"
+	// DeletionBlockedCondition is true while spec.deletionProtection holds the deletion of the GithubIssue.
+	DeletionBlockedCondition = "DeletionBlocked"
+	// ConvertedToDiscussionCondition is true once the upstream issue was converted to a discussion. It is terminal.
+	ConvertedToDiscussionCondition = "ConvertedToDiscussion"
+	// MarkedDuplicateCondition is true while the upstream issue is closed as a duplicate under the Repoint
+	// duplicate policy. The operator stops editing the issue until it is reopened.
+	MarkedDuplicateCondition = "MarkedDuplicate"
+	// ConflictCondition is true while an upstream description edit waits for manual resolution.
+	ConflictCondition = "Conflict"
+	// DryRunCondition is true while the dry-run annotation holds back GitHub writes. Its message is the planned action.
+	DryRunCondition = "DryRun"
+	// ScheduledCondition is true while the creation of the issue waits for spec.notBefore.
+	ScheduledCondition = "Scheduled"
+	// RateLimitedCondition is true while GitHub rate limits the token used for the issue.
+	RateLimitedCondition = "RateLimited"
+	// RepoNotFoundCondition is true while spec.repo does not exist or is not visible to the token. It is terminal
+	// until the spec or the credentials change.
+	RepoNotFoundCondition = "RepoNotFound"
+	// AuthenticationFailedCondition is true while GitHub rejects the token used for the issue. It is terminal
+	// until the spec or the credentials change.
+	AuthenticationFailedCondition = "AuthenticationFailed"
+	// IssuesDisabledCondition is true while issues are disabled in the settings of spec.repo. It is terminal
+	// until the GithubIssue changes, e.g. is annotated once issues were enabled.
+	IssuesDisabledCondition = "IssuesDisabled"
+	// OverdueCondition is true while the issue is open past spec.dueDate.
+	OverdueCondition = "Overdue"
+	// TokenPermissionsMissingCondition is true while the token preflight found the operator token can't manage
+	// the issues of spec.repo.
+	TokenPermissionsMissingCondition = "TokenPermissionsMissing"
+)
+
+// deletionProtectionRecheck is how often a deletion held by spec.deletionProtection is checked again.
+const deletionProtectionRecheck = time.Minute
+
 // GithubIssueReconciler reconciles a GithubIssue object
 type GithubIssueReconciler struct {
 	client.Client
-	Scheme      *runtime.Scheme
-	Log         *zap.Logger
-	IssueClient git.IssueClient
-	Recorder    record.EventRecorder
+	Scheme   *runtime.Scheme
+	Log      *zap.Logger
+	Recorder record.EventRecorder
+
+	// Clients serves the repositories of the issues without spec.credentialsSecretRef or a GitProvider,
+	// picking the client of the provider serving the host of spec.repo.
+	Clients *git.Clients
+
+	// NewIssueClient returns the client used for GithubIssues that set spec.credentialsSecretRef, for the host
+	// of spec.repo. credential names the token in TokenExpiry. Nil makes those issues fail to reconcile.
+	NewIssueClient func(host, credential, token string) (git.IssueClient, error)
+
+	// DetectPossibleDuplicates holds back creating issues whose title is close to an open upstream issue.
+	DetectPossibleDuplicates bool
+
+	// WarmupWindow spreads the first reconcile of the objects that existed before startup over this window,
+	// objects reporting a problem first and synced objects last. Zero reconciles them all at once.
+	WarmupWindow time.Duration
+
+	// APIWriteTimeout bounds every status and finalizer write to the API server. Zero disables the timeout.
+	APIWriteTimeout time.Duration
+
+	// TokenExpiry reports when the GitHub tokens expire. Nil disables the TokenExpiring condition.
+	TokenExpiry *git.ExpiryTracker
+	// TokenExpiryWarning is how long before its token expires an issue gets the TokenExpiring condition.
+	TokenExpiryWarning time.Duration
+
+	// RateLimits reports until when GitHub rate limits the tokens. Failed reconciles of a rate limited issue
+	// are requeued once the limit resets instead of retried with backoff. Nil disables the RateLimited condition.
+	RateLimits *git.RateLimitTracker
+
+	// ConditionStabilizationWindow is how long an upstream state must stay unchanged
+	// before an existing condition is flipped. Zero disables damping.
+	ConditionStabilizationWindow time.Duration
+
+	// Publisher receives lifecycle events of managed issues. Nil disables publishing.
+	Publisher lifecycle.Publisher
+
+	// SlowReconcileThreshold is the reconcile duration above which phase timings are logged. Zero disables the log.
+	SlowReconcileThreshold time.Duration
+
+	// PriorityLabels maps spec.priority to labels. Nil uses labels.DefaultPriorityLabels.
+	PriorityLabels labels.PriorityLabels
+
+	// LabelTaxonomy restricts the spec labels applied upstream. Nil allows every label.
+	LabelTaxonomy *labels.Taxonomy
+
+	// EnforceRepositoryBindings only lets GithubIssues file issues in the repositories a RepositoryBinding
+	// allows their namespace.
+	EnforceRepositoryBindings bool
+
+	// CentralSecretsNamespace is the namespace credentials Secrets may be referenced in besides the namespace of
+	// the GithubIssue. Empty only allows the namespace of the GithubIssue.
+	CentralSecretsNamespace string
+	// NamespaceCredentialsSecret names the Secret a namespace holds its own token in, under the key token.
+	// GithubIssues without spec.credentialsSecretRef use the token of their namespace when the Secret exists,
+	// instead of the operator token. Empty disables namespace credentials.
+	NamespaceCredentialsSecret string
+
+	// Preflight reports the repositories the operator token can't manage the issues of. Nil disables the
+	// TokenPermissionsMissing condition.
+	Preflight *TokenPreflight
+
+	// AuditLog records every Secret read. Nil disables the audit.
+	AuditLog *zap.Logger
+
+	// Providers builds the clients of the GitProviders, used for the repositories of their host. Nil ignores
+	// GitProviders.
+	Providers *git.ProviderPool
+
+	// ReflectLabels selects the upstream labels recorded in UpstreamLabelsAnnotation. Nil disables the annotation.
+	ReflectLabels labels.Selector
+
+	// TriagePolicy resolves the triage labeling policy per repository, before spec.triage of its GithubRepository
+	// applies. Nil disables triage.
+	TriagePolicy triage.PolicyResolver
+
+	// UnknownStatesAsFalse reports upstream states the operator does not know as False on IssueIsOpen,
+	// as older releases did, instead of Unknown.
+	UnknownStatesAsFalse bool
+
+	// WebhookEvents delivers GithubIssues to reconcile because of an upstream webhook delivery. Optional.
+	WebhookEvents <-chan event.GenericEvent
+
+	// MaxConcurrentReconciles is the number of GithubIssues reconciled in parallel. Zero reconciles one at a time.
+	MaxConcurrentReconciles int
+	// MaxInFlightPerRepo caps the reconciles in flight per repository, so a repository with many issues
+	// can't take every worker. Zero is unlimited.
+	MaxInFlightPerRepo int
+
+	damper             *conditionDamper
+	triageDistribution *triage.Distribution
+	causes             *causeTracker
+	syncs              *syncTracker
+	pending            *pendingWrites
+	repos              *repoQueue
 }
 
 // +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues/finalizers,verbs=update
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubmilestones,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;watch;list
 
-func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.Log
+func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, reconcileErr error) {
+	cause := r.causes.take(req.NamespacedName)
+	r.repos.started(req.NamespacedName)
+	log := r.Log.With(
+		zap.String("namespace", req.Namespace),
+		zap.String("name", req.Name),
+		zap.String("reconcileID", string(crcontroller.ReconcileIDFromContext(ctx))),
+		zap.String("cause", cause),
+	)
+	ctx = logging.IntoContext(ctx, log)
+	ctx, timer := withPhaseTimer(ctx)
+	defer r.reportPhases(ctx, timer)
+	ctx, decisions := withDecisionLog(ctx)
 
 	var issueObject = &issuesv1alpha1.GithubIssue{}
 	if err := r.Get(ctx, req.NamespacedName, issueObject); err != nil {
@@ -60,117 +222,459 @@ func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	owner, repo, err := parseRepoURL(issueObject.Spec.Repo)
+	repositoryErr := r.applyRepositoryRef(ctx, issueObject)
+	repository := repositoryLabel(issueObject.RepoURL())
+	if !r.repos.acquire(req.NamespacedName, repository) {
+		log.Debug("Too many reconciles in flight for the repository, requeueing", zap.String("repository", repository))
+		return ctrl.Result{RequeueAfter: repoBusyRequeue}, nil
+	}
+	defer r.repos.release(repository)
+
+	if err := r.flushPendingStatus(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		wait := r.setRateLimited(ctx, issueObject, reconcileErr, time.Now())
+		terminal := r.setTerminalError(ctx, issueObject, reconcileErr)
+		r.recordFailure(ctx, issueObject, reconcileErr)
+		r.recordDecision(ctx, issueObject, decisions, reconcileErr, terminal, wait)
+		switch {
+		case wait > 0:
+			result, reconcileErr = ctrl.Result{RequeueAfter: wait}, nil
+		case terminal:
+			result, reconcileErr = ctrl.Result{}, nil
+		}
+		result = r.retryPendingStatus(issueObject, result, reconcileErr)
+	}()
+	if err := r.setPendingPhase(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.pruneDisabledFeatures(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if issueObject.Spec.Suspend {
+		return r.handleSuspended(ctx, issueObject)
+	}
+
+	if cause == causeResync && terminallyFailed(issueObject) {
+		log.Debug("Skipping resync, waiting for the spec or the credentials to change")
+		decide(ctx, DecisionSkipped, "AwaitingChange", "Skipping resyncs after a terminal error until the spec or the credentials change")
+		return ctrl.Result{}, nil
+	}
+	interval := syncInterval(issueObject)
+	if cause == causeResync && interval > 0 && issueObject.DeletionTimestamp.IsZero() {
+		if due, wait := r.syncs.due(objectKey(issueObject), interval, time.Now()); !due {
+			log.Debug("Skipping resync, sync interval has not elapsed", zap.Duration("RequeueAfter", wait))
+			decide(ctx, DecisionSkipped, "SyncIntervalNotElapsed", fmt.Sprintf("The sync interval of %s has not elapsed", interval))
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+	if err := r.clearSuspended(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if errors.Is(repositoryErr, errRepositoryNotFound) {
+		return r.handleInvalidSpec(ctx, issueObject, repositoryErr)
+	}
+	if repositoryErr != nil {
+		return ctrl.Result{}, repositoryErr
+	}
+	owner, repo, err := git.ParseRepoURL(issueObject.Spec.Repo)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed parse repoURL : %v", err)
+		return r.handleInvalidSpec(ctx, issueObject, err)
+	}
+	if err := r.authorizeRepository(ctx, issueObject.Namespace, issueObject.Spec.Repo); err != nil {
+		if errors.Is(err, errRepositoryNotAllowed) {
+			return r.handleInvalidSpec(ctx, issueObject, err)
+		}
+		return ctrl.Result{}, err
+	}
+	if err := r.clearInvalidSpec(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if ctx, err = r.withCredentials(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.checkTokenPermissions(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if meta.IsStatusConditionTrue(issueObject.Status.Conditions, ConvertedToDiscussionCondition) {
+		return r.handleConverted(ctx, issueObject)
 	}
 
 	log.Info(fmt.Sprintf("attempting to get issues from %s/%s", owner, repo))
 	issue, err := r.FindIssue(ctx, owner, repo, issueObject)
+	if converted, ok := git.AsConvertedToDiscussion(err); ok {
+		return r.markConverted(ctx, issueObject, converted)
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	observe(ctx, issue)
+	if dryRun(issueObject) {
+		return r.handleDryRun(ctx, owner, repo, issue, issueObject)
+	}
+	if err := r.clearDryRun(ctx, issueObject); err != nil {
+		return ctrl.Result{}, err
+	}
 	if !issueObject.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := r.setPhase(ctx, issueObject, issuesv1alpha1.PhaseTerminating); err != nil {
+			return ctrl.Result{}, err
+		}
 		return r.handleDeletion(ctx, owner, repo, issue, issueObject)
 	}
-	err = finalizer.Ensure(ctx, r.Client, issueObject, r.Log)
+	err = r.ensureFinalizer(ctx, issueObject)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if !issueExists(issue) {
-		return r.handleNewIssue(ctx, owner, repo, issueObject)
+		result, err = r.handleNewIssue(ctx, owner, repo, issueObject)
 	} else {
-		return r.handleUpdatedIssue(ctx, owner, repo, issueObject, issue)
+		result, err = r.handleUpdatedIssue(ctx, owner, repo, issueObject, issue)
+	}
+	if err != nil {
+		return result, err
+	}
+	if err := r.checkTokenExpiry(ctx, issueObject); err != nil {
+		return result, err
+	}
+	clearRateLimited(issueObject)
+	clearTerminalErrors(issueObject)
+	if err := r.recordSynced(ctx, issueObject); err != nil {
+		return result, err
 	}
+	r.syncs.synced(objectKey(issueObject), time.Now())
+	return withSyncInterval(result, interval), nil
 }
 
-// updateIssueStatus updates the status of the GithubIssue CRD
-func (r *GithubIssueReconciler) updateIssueStatus(ctx context.Context, issue *issuesv1alpha1.GithubIssue, platformIssue *git.Issue) error {
-	conditionType, conditionStatus, reason, message, openChange := checkIfOpen(platformIssue)
+// handleSuspended skips every GitHub call for a suspended issue and reports it through the Suspended condition.
+// A suspended issue being deleted only drops its finalizer.
+func (r *GithubIssueReconciler) handleSuspended(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (ctrl.Result, error) {
+	if !issueObject.DeletionTimestamp.IsZero() {
+		r.logger(ctx).Info("Issue is suspended, leaving the upstream issue open on deletion")
+		r.damper.forget(objectKey(issueObject))
+		r.triageDistribution.Forget(objectKey(issueObject))
+		r.syncs.forget(objectKey(issueObject))
+		return ctrl.Result{}, r.removeFinalizer(ctx, issueObject)
+	}
+
+	decide(ctx, DecisionSkipped, "Suspended", "Reconciliation is suspended by spec.suspend")
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               SuspendedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SpecSuspended",
+		Message:            "Reconciliation is suspended by spec.suspend",
+		ObservedGeneration: issueObject.Generation,
+	})
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+
+	r.logger(ctx).Info("Issue reconciliation suspended")
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// clearSuspended flips the Suspended condition once spec.suspend is unset and resets the state kept
+// between syncs, so the reconcile that follows is a fresh full sync.
+func (r *GithubIssueReconciler) clearSuspended(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, SuspendedCondition) {
+		return nil
+	}
+
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               SuspendedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Resumed",
+		Message:            "Reconciliation resumed",
+		ObservedGeneration: issueObject.Generation,
+	})
+	// Start over from the upstream state: anything observed before the suspension is stale.
+	r.damper.forget(objectKey(issueObject))
+	r.syncs.forget(objectKey(issueObject))
+	r.logger(ctx).Info("Issue reconciliation resumed")
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// handleInvalidSpec reports a spec that can never be reconciled through the InvalidSpec condition and an event.
+// The request is not requeued: the next spec change triggers a new reconcile.
+func (r *GithubIssueReconciler) handleInvalidSpec(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, specErr error) (ctrl.Result, error) {
+	r.logger(ctx).Warn("Issue spec is invalid", zap.Error(specErr))
+
+	if !issueObject.DeletionTimestamp.IsZero() {
+		// Nothing was ever created upstream for an invalid spec, so there is nothing to close.
+		return ctrl.Result{}, r.removeFinalizer(ctx, issueObject)
+	}
+
+	decide(ctx, DecisionSkipped, invalidSpecReason(specErr), specErr.Error())
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               InvalidSpecCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             invalidSpecReason(specErr),
+		Message:            specErr.Error(),
+		ObservedGeneration: issueObject.Generation,
+	})
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+
+	r.Recorder.Event(issueObject, corev1.EventTypeWarning, InvalidSpecCondition, specErr.Error())
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// invalidSpecReason returns the InvalidSpec condition reason for specErr.
+func invalidSpecReason(specErr error) string {
+	if errors.Is(specErr, errRepositoryNotFound) {
+		return "RepositoryNotFound"
+	}
+	if errors.Is(specErr, errRepositoryNotAllowed) {
+		return "RepositoryNotAllowed"
+	}
+	return "InvalidRepoURL"
+}
+
+// clearInvalidSpec flips a previously reported InvalidSpec condition once the spec is valid again.
+func (r *GithubIssueReconciler) clearInvalidSpec(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, InvalidSpecCondition) {
+		return nil
+	}
+
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               InvalidSpecCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "SpecValid",
+		Message:            "Issue spec is valid",
+		ObservedGeneration: issueObject.Generation,
+	})
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// updateIssueStatus updates the status of the GithubIssue CRD.
+// It returns a non-zero requeue delay when a condition change is being held back by the damper.
+func (r *GithubIssueReconciler) updateIssueStatus(ctx context.Context, issue *issuesv1alpha1.GithubIssue, platformIssue *git.Issue) (time.Duration, error) {
+	conditionType, conditionStatus, reason, message, openChange := checkIfOpen(platformIssue, r.unknownStateStatus())
 	PRChangeConditionType, PRChangeConditionStatus, PRChangeReason, PRChangeMessage, prChange := checkForPR(platformIssue)
 
+	var requeueAfter time.Duration
+	statusUpdated := false
+	if issue.Status.IssueNumber != platformIssue.Number {
+		issue.Status.IssueNumber = platformIssue.Number
+		statusUpdated = true
+	}
+	if platformIssue.NodeID != "" && issue.Status.NodeID != platformIssue.NodeID {
+		issue.Status.NodeID = platformIssue.NodeID
+		statusUpdated = true
+	}
+	if issue.Status.MilestoneNumber != platformIssue.Milestone {
+		issue.Status.MilestoneNumber = platformIssue.Milestone
+		statusUpdated = true
+	}
 	if prChange || openChange {
-		r.Log.Info("Updating Issue status", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
+		r.logger(ctx).Info("Updating Issue status", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
 
 		conditionUpdated := false
 
-		if updateCondition(issue, conditionType, conditionStatus, reason, message) {
+		if wait := r.dampCondition(ctx, issue, conditionType, conditionStatus); wait > 0 {
+			requeueAfter = wait
+		} else if updateCondition(issue, conditionType, conditionStatus, reason, message) {
 			conditionUpdated = true
-			r.Log.Info("Condition updated", zap.String("ConditionType", conditionType))
+			r.logger(ctx).Info("Condition updated", zap.String("ConditionType", conditionType))
 		}
 
-		if updateCondition(issue, PRChangeConditionType, PRChangeConditionStatus, PRChangeReason, PRChangeMessage) {
+		if wait := r.dampCondition(ctx, issue, PRChangeConditionType, PRChangeConditionStatus); wait > 0 {
+			if requeueAfter == 0 || wait < requeueAfter {
+				requeueAfter = wait
+			}
+		} else if updateCondition(issue, PRChangeConditionType, PRChangeConditionStatus, PRChangeReason, PRChangeMessage) {
 			conditionUpdated = true
-			r.Log.Info("Condition updated", zap.String("ConditionType", PRChangeConditionType))
+			r.logger(ctx).Info("Condition updated", zap.String("ConditionType", PRChangeConditionType))
 		}
 
 		if conditionUpdated {
-			if err := r.Client.Status().Update(ctx, issue); err != nil {
-				r.Log.Error("Failed to update issue status", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace), zap.Error(err))
-				return fmt.Errorf("failed to update status: %v", err)
-			}
-			r.Log.Info("Issue status updated successfully", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
+			statusUpdated = true
 		} else {
-			r.Log.Info("No changes detected in conditions", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
+			r.logger(ctx).Info("No changes detected in conditions", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
 		}
-	} else {
-		r.Log.Info("No changes detected in issue status", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
 	}
 
-	return nil
+	if !statusUpdated {
+		r.logger(ctx).Info("No changes detected in issue status", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
+		return requeueAfter, nil
+	}
+	if err := r.updateStatus(ctx, issue); err != nil {
+		r.logger(ctx).Error("Failed to update issue status", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace), zap.Error(err))
+		return 0, fmt.Errorf("failed to update status: %v", err)
+	}
+	r.logger(ctx).Info("Issue status updated successfully", zap.String("IssueName", issue.Name), zap.String("Namespace", issue.Namespace))
+
+	return requeueAfter, nil
+}
+
+func (r *GithubIssueReconciler) unknownStateStatus() metav1.ConditionStatus {
+	if r.UnknownStatesAsFalse {
+		return metav1.ConditionFalse
+	}
+	return metav1.ConditionUnknown
+}
+
+// dampCondition reports how long a flip of the given condition should still be held back.
+// Conditions that are set for the first time, or that keep their current status, are never delayed.
+func (r *GithubIssueReconciler) dampCondition(ctx context.Context, issue *issuesv1alpha1.GithubIssue, conditionType string, desired metav1.ConditionStatus) time.Duration {
+	key := objectKey(issue) + "/" + conditionType
+
+	current := meta.FindStatusCondition(issue.Status.Conditions, conditionType)
+	if current == nil || current.Status == desired {
+		r.damper.reset(key)
+		return 0
+	}
+
+	stable, wait := r.damper.observe(key, desired, time.Now())
+	if stable {
+		return 0
+	}
+	r.logger(ctx).Info("Holding back condition change until upstream state is stable",
+		zap.String("IssueName", issue.Name),
+		zap.String("Namespace", issue.Namespace),
+		zap.String("ConditionType", conditionType),
+		zap.String("DesiredStatus", string(desired)),
+		zap.Duration("RequeueAfter", wait),
+	)
+	return wait
 }
 
 // fetchIssue fetches an issue from GitHub.
 func (r *GithubIssueReconciler) fetchIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (*git.Issue, error) {
 	issue, err := r.FindIssue(ctx, owner, repo, issueObject)
 	if err != nil {
-		r.Log.Error("Failed to fetch issue", zap.Error(err))
+		r.logger(ctx).Error("Failed to fetch issue", zap.Error(err))
 		return nil, err
 	}
 	return issue, nil
 }
 
 // updateIssueStatusIfExists updates the status of the given issue if it exists.
-func (r *GithubIssueReconciler) updateIssueStatusIfExists(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
-	if issueExists(issue) {
-		if err := r.updateIssueStatus(ctx, issueObject, issue); err != nil {
-			r.Log.Error("Failed to update issue status", zap.Error(err))
-			return err
-		}
-	} else {
-		r.Log.Warn("Cannot update status: issue is nil", zap.String("IssueName", issueObject.Name), zap.String("Namespace", issueObject.Namespace))
+func (r *GithubIssueReconciler) updateIssueStatusIfExists(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) (ctrl.Result, error) {
+	if !issueExists(issue) {
+		r.logger(ctx).Warn("Cannot update status: issue is nil", zap.String("IssueName", issueObject.Name), zap.String("Namespace", issueObject.Namespace))
+		return ctrl.Result{}, nil
 	}
-	return nil
+
+	requeueAfter, err := r.updateIssueStatus(ctx, issueObject, issue)
+	if err != nil {
+		r.logger(ctx).Error("Failed to update issue status", zap.Error(err))
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // handleNewIssue function manage a creation of new issue.
 func (r *GithubIssueReconciler) handleNewIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (ctrl.Result, error) {
-	r.Log.Info("Creating new issue")
+	if wait, err := r.checkSchedule(ctx, issueObject, time.Now()); wait > 0 || err != nil {
+		decide(ctx, DecisionWaiting, "Scheduled", "Waiting for spec.notBefore to open the issue")
+		return ctrl.Result{RequeueAfter: wait}, err
+	}
+	if duplicate, err := r.checkPossibleDuplicates(ctx, owner, repo, issueObject); duplicate || err != nil {
+		decide(ctx, DecisionSkipped, "PossibleDuplicate", "An upstream issue looks like a duplicate of this one")
+		return ctrl.Result{}, err
+	}
+
+	r.logger(ctx).Info("Creating new issue")
 
-	if err := r.CreateIssue(ctx, owner, repo, issueObject); err != nil {
-		r.Log.Error("Failed to create issue", zap.Error(err))
+	if err := r.createIssue(ctx, owner, repo, issueObject); err != nil {
+		r.logger(ctx).Error("Failed to create issue", zap.Error(err))
 		return ctrl.Result{}, err
 	}
+	decide(ctx, DecisionCreated, "IssueNotFound", "Created the upstream issue, no issue had the title")
 
 	issue, err := r.fetchIssue(ctx, owner, repo, issueObject)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if err := r.updateIssueStatusIfExists(ctx, issueObject, issue); err != nil {
+	if issueExists(issue) {
+		if err := r.syncLock(ctx, owner, repo, issueObject, issue); err != nil {
+			r.logger(ctx).Error("Failed to sync issue lock", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		if err := r.syncPin(ctx, owner, repo, issueObject, issue); err != nil {
+			r.logger(ctx).Error("Failed to sync issue pin", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		if err := r.syncProjects(ctx, owner, repo, issueObject, issue); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.syncComments(ctx, owner, repo, issueObject, issue); err != nil {
+			r.logger(ctx).Error("Failed to sync comments", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		if err := r.syncMirrors(ctx, issueObject, issue); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.updateIssueStatusIfExists(ctx, issueObject, issue)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if issueExists(issue) {
+		wait, err := r.syncDueDate(ctx, owner, repo, issueObject, issue, time.Now())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		result = withSyncInterval(result, wait)
+	}
 
-	r.Log.Info("Issue created successfully")
-	return ctrl.Result{}, nil
+	r.logger(ctx).Info("Issue created successfully")
+	return result, nil
 }
 
 // handleUpdatedIssue manage updating of existing issue.
 func (r *GithubIssueReconciler) handleUpdatedIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) (ctrl.Result, error) {
-	r.Log.Info("Editing issue")
+	if err := r.resumeCreation(ctx, issueObject, issue); err != nil {
+		return ctrl.Result{}, err
+	}
+	if duplicate, err := r.handleMarkedDuplicate(ctx, owner, repo, issueObject, issue); duplicate || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger(ctx).Info("Editing issue")
 
-	if err := r.EditIssue(ctx, owner, repo, issueObject, issue.Number); err != nil {
-		r.Log.Error("Failed to edit issue", zap.Error(err))
+	if err := r.EditIssue(ctx, owner, repo, issueObject, issue); err != nil {
+		if converted, ok := git.AsConvertedToDiscussion(err); ok {
+			return r.markConverted(ctx, issueObject, converted)
+		}
+		r.logger(ctx).Error("Failed to edit issue", zap.Error(err))
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncLock(ctx, owner, repo, issueObject, issue); err != nil {
+		r.logger(ctx).Error("Failed to sync issue lock", zap.Error(err))
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncPin(ctx, owner, repo, issueObject, issue); err != nil {
+		r.logger(ctx).Error("Failed to sync issue pin", zap.Error(err))
+		return ctrl.Result{}, err
+	}
+	if err := r.syncProjects(ctx, owner, repo, issueObject, issue); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.syncComments(ctx, owner, repo, issueObject, issue); err != nil {
+		r.logger(ctx).Error("Failed to sync comments", zap.Error(err))
 		return ctrl.Result{}, err
 	}
 
@@ -178,36 +682,115 @@ func (r *GithubIssueReconciler) handleUpdatedIssue(ctx context.Context, owner, r
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if issueExists(updatedIssue) {
+		if err := r.syncMirrors(ctx, issueObject, updatedIssue); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
-	if err := r.updateIssueStatusIfExists(ctx, issueObject, updatedIssue); err != nil {
+	result, err := r.updateIssueStatusIfExists(ctx, issueObject, updatedIssue)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	r.Log.Info("Issue edited successfully")
-	return ctrl.Result{}, nil
+	if err := r.triageIssue(ctx, owner, repo, issueObject, updatedIssue); err != nil {
+		r.logger(ctx).Error("Failed to triage issue", zap.Error(err))
+		return ctrl.Result{}, err
+	}
+	if issueExists(updatedIssue) {
+		if err := r.reflectUpstreamLabels(ctx, issueObject, updatedIssue); err != nil {
+			return ctrl.Result{}, err
+		}
+		wait, err := r.syncDueDate(ctx, owner, repo, issueObject, updatedIssue, time.Now())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		result = withSyncInterval(result, wait)
+	}
+
+	r.logger(ctx).Info("Issue edited successfully")
+	return result, nil
 }
 
 // handleDeletion perform all the needed cleanup logic for issue object.
 func (r *GithubIssueReconciler) handleDeletion(ctx context.Context, owner, repo string, issue *git.Issue, issueObject *issuesv1alpha1.GithubIssue) (ctrl.Result, error) {
-	r.Log.Info("Closing issue")
+	r.logger(ctx).Info("Closing issue")
 
-	if !issueExists(issue) {
-		return ctrl.Result{}, fmt.Errorf("cannot close issue: issue is nil")
+	closed := r.pending.isClosed(objectKey(issueObject))
+	held := false
+	if !closed && issueExists(issue) && issueObject.Spec.DeletionProtection == issuesv1alpha1.DeletionProtectionWhileLinkedPROpen {
+		open, err := r.linkedPullRequestOpen(ctx, owner, repo, issue.Number)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		held = open
 	}
 
-	if err := r.CloseIssue(ctx, owner, repo, issue); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed closing issue: %v", err)
+	switch {
+	case closed:
+		// A previous reconcile closed the issue but failed to drop the finalizer.
+		r.logger(ctx).Info("Issue already closed, removing finalizer")
+	case !issueExists(issue):
+		return ctrl.Result{}, fmt.Errorf("cannot close issue: issue is nil")
+	case held:
+		return r.blockDeletion(ctx, issueObject)
+	default:
+		if err := r.closeMirrors(ctx, issueObject); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.CloseIssue(ctx, owner, repo, issueObject, issue); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed closing issue: %v", err)
+		}
+		r.pending.setClosed(objectKey(issueObject))
 	}
 
-	if err := finalizer.Cleanup(ctx, r.Client, issueObject, r.Log); err != nil {
-		r.Log.Error("Failed cleaning up finalizer", zap.Error(err))
+	if err := r.removeFinalizer(ctx, issueObject); err != nil {
+		r.logger(ctx).Error("Failed cleaning up finalizer", zap.Error(err))
 		return ctrl.Result{}, err
 	}
 
-	r.Log.Info("Issue closed and finalizer cleaned up successfully")
+	r.damper.forget(objectKey(issueObject))
+	r.triageDistribution.Forget(objectKey(issueObject))
+	r.syncs.forget(objectKey(issueObject))
+	r.logger(ctx).Info("Issue closed and finalizer cleaned up successfully")
 	return ctrl.Result{}, nil
 }
 
+// linkedPullRequestOpen reports whether a pull request closing the issue when merged is still open. Closed and
+// merged pull requests don't hold the deletion.
+func (r *GithubIssueReconciler) linkedPullRequestOpen(ctx context.Context, owner, repo string, issueNumber int) (bool, error) {
+	pullRequests, err := r.issueClient(ctx).LinkedPullRequests(ctx, owner, repo, issueNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to check the linked pull requests: %v", err)
+	}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.State == "OPEN" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// blockDeletion keeps the finalizer while deletion protection applies and checks again after deletionProtectionRecheck.
+func (r *GithubIssueReconciler) blockDeletion(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (ctrl.Result, error) {
+	message := "Deletion is held until the linked pull request is merged or closed"
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               DeletionBlockedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "LinkedPROpen",
+		Message:            message,
+		ObservedGeneration: issueObject.Generation,
+	})
+	if changed {
+		r.logger(ctx).Info("Holding deletion, linked pull request is open")
+		r.Recorder.Event(issueObject, corev1.EventTypeNormal, DeletionBlockedCondition, message)
+		if err := r.updateStatus(ctx, issueObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+		}
+	}
+	return ctrl.Result{RequeueAfter: deletionProtectionRecheck}, nil
+}
+
 // fetchAllIssues function try to fetch all the issues from the repo.
 func (r *GithubIssueReconciler) fetchAllIssues(ctx context.Context, owner, repo string) ([]*git.Issue, error) {
 	var allIssues []*git.Issue
@@ -218,7 +801,7 @@ func (r *GithubIssueReconciler) fetchAllIssues(ctx context.Context, owner, repo
 		Steps:    5,
 	}
 
-	err := retry.OnError(backoff, r.shouldRetry, func() error {
+	err := retry.OnError(backoff, func(err error) bool { return r.shouldRetry(ctx, err) }, func() error {
 		var fetchErr error
 		allIssues, fetchErr = r.fetchIssuesFromGit(ctx, owner, repo)
 		return fetchErr
@@ -228,26 +811,59 @@ func (r *GithubIssueReconciler) fetchAllIssues(ctx context.Context, owner, repo
 		return nil, fmt.Errorf("exceeded retries fetching issues: %w", err)
 	}
 
-	r.Log.Info("Fetched issues successfully")
+	r.logger(ctx).Info("Fetched issues successfully")
 	return allIssues, nil
 }
 
-// shouldRetry defines the condition for retrying (retry on any error)
-func (r *GithubIssueReconciler) shouldRetry(err error) bool {
-	if err != nil {
-		r.Log.Warn("Retrying after error", zap.Error(err))
+// shouldRetry reports whether a failed fetch is retried. Terminal errors are not: retrying can't fix them,
+// and the reconcile reports them through their condition. Neither are rate limits: the failed reconcile is
+// requeued once the limit resets.
+func (r *GithubIssueReconciler) shouldRetry(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, git.ErrRepoNotFound) || errors.Is(err, git.ErrAuthenticationFailed) || errors.Is(err, git.ErrIssuesDisabled) {
+		return false
+	}
+	if git.IsRateLimited(err) {
+		return false
 	}
+	r.logger(ctx).Warn("Retrying after error", zap.Error(err))
 	return true
 }
 
-// FindIssue finds a specific issue in the repository by title.
+// logger returns the reconcile scoped logger carried by ctx, falling back to r.Log.
+func (r *GithubIssueReconciler) logger(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, r.Log)
+}
+
+// FindIssue finds a specific issue in the repository by title, falling back to the issue number recorded in status.
 func (r *GithubIssueReconciler) FindIssue(ctx context.Context, owner, repo string, issue *issuesv1alpha1.GithubIssue) (*git.Issue, error) {
+	defer timePhase(ctx, phaseFind)()
 	allIssues, err := r.fetchAllIssues(ctx, owner, repo)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching issues: %v", err)
+		return nil, fmt.Errorf("error fetching issues: %w", err)
 	}
 
-	return searchForIssue(issue.Spec.Title, allIssues), nil
+	title, err := r.issueTitle(ctx, issue)
+	if err != nil {
+		return nil, err
+	}
+	if found := searchForIssue(title, allIssues); issueExists(found) || issue.Status.IssueNumber == 0 {
+		return found, nil
+	}
+
+	// The issue list only holds open issues: look the synced issue up by number, it may have been closed upstream
+	// (e.g. as a duplicate) or converted to a discussion, before recreating it.
+	synced, err := r.issueClient(ctx).Get(ctx, owner, repo, issue.Status.IssueNumber)
+	if err != nil {
+		if _, ok := git.AsConvertedToDiscussion(err); ok {
+			return nil, err
+		}
+		r.logger(ctx).Warn("Failed to look up the previously synced issue", zap.Error(err))
+		return nil, nil
+	}
+	return synced, nil
 }
 
 // updateCondition is a generic function to update any condition of a GitHub issue.
@@ -267,9 +883,48 @@ func updateCondition(issueObject *issuesv1alpha1.GithubIssue, conditionType stri
 	return false
 }
 
+// Prepare initializes the state kept across reconciles. SetupWithManager calls it; it is only needed to drive
+// Reconcile without a manager, as cmd/scale-sim does.
+func (r *GithubIssueReconciler) Prepare() {
+	r.damper = newConditionDamper(r.ConditionStabilizationWindow)
+	r.triageDistribution = triage.NewDistribution()
+	r.repos = newRepoQueue(r.MaxInFlightPerRepo)
+	r.causes = newCauseTracker()
+	r.causes.repos = r.repos
+	r.syncs = newSyncTracker()
+	r.pending = newPendingWrites()
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *GithubIssueReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&issuesv1alpha1.GithubIssue{}).
-		Complete(r)
+	r.Prepare()
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &issuesv1alpha1.GithubIssue{}, configMapRefIndex, indexConfigMapRefs); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &issuesv1alpha1.GithubIssue{}, secretRefIndex, indexSecretRefs); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &issuesv1alpha1.GithubIssue{}, milestoneRefIndex, indexMilestoneRefs); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &issuesv1alpha1.GithubIssue{}, repositoryRefIndex, indexRepositoryRefs); err != nil {
+		return err
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("githubissue").
+		WithOptions(crcontroller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Watches(&issuesv1alpha1.GithubIssue{}, newWarmup(r.WarmupWindow).handler(r.causes, r.causes.handler())).
+		// Only the metadata of ConfigMaps and Secrets is cached: a change is all the reference handler needs.
+		WatchesMetadata(&corev1.ConfigMap{}, r.referenceHandler(configMapRefIndex)).
+		WatchesMetadata(&corev1.Secret{}, r.referenceHandler(secretRefIndex)).
+		Watches(&issuesv1alpha1.GithubMilestone{}, r.referenceHandler(milestoneRefIndex)).
+		Watches(&issuesv1alpha1.GithubRepository{}, r.referenceHandler(repositoryRefIndex))
+	if r.EnforceRepositoryBindings {
+		b = b.Watches(&issuesv1alpha1.RepositoryBinding{}, r.bindingHandler())
+	}
+	if r.WebhookEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.WebhookEvents, r.causes.handler()))
+	}
+	return b.Complete(r)
 }
@@ -193,4 +193,26 @@ var _ = Describe("githubIssue controller", func() {
 			}, timeout, interval).Should(BeTrue())
 		})
 	})
+
+	Context("When the githubIssue is suspended", func() {
+		It("sets the Suspended condition without calling GitHub", func() {
+			testIssue := GenerateTestIssue()
+			testIssue.Spec.Suspend = true
+
+			MockClient = mock.NewMockedHTTPClient()
+
+			Expect(k8sClient.Create(ctx, testIssue)).To(Succeed())
+
+			req := types.NamespacedName{
+				Name:      testIssue.ObjectMeta.Name,
+				Namespace: testIssue.Namespace,
+			}
+
+			Eventually(func() bool {
+				updatedIssue := &issuesv1alpha1.GithubIssue{}
+				err := k8sClient.Get(ctx, req, updatedIssue)
+				return err == nil && meta.IsStatusConditionTrue(updatedIssue.Status.Conditions, SuspendedCondition)
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,293 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// IssueSetLabel is set on the GithubIssues created by a GithubIssueSet.
+const IssueSetLabel = "issues.dana.io/issue-set"
+
+// GithubIssueSetReconciler reconciles a GithubIssueSet object: it creates a GithubIssue from the template in
+// every target repository, deletes the GithubIssues of the repositories no longer targeted and aggregates
+// their status.
+type GithubIssueSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    *zap.Logger
+}
+
+// issueSetTarget is a repository targeted by a GithubIssueSet.
+type issueSetTarget struct {
+	issue         string // Name of the GithubIssue
+	repo          string
+	repositoryRef string // Name of the selected GithubRepository, empty for the repos of spec.repos
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissuesets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissuesets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues,verbs=get;list;watch;create;update;patch;delete
+
+func (r *GithubIssueSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	set := &issuesv1alpha1.GithubIssueSet{}
+	if err := r.Get(ctx, req.NamespacedName, set); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch issue set object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	// The GithubIssues are owned by the set: the garbage collector deletes them, and their finalizers close the issues.
+	if !set.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	observed := set.Status.DeepCopy()
+	targets, err := r.targets(ctx, set)
+	if err != nil {
+		log.Warn("Invalid repository selector", zap.Error(err))
+		r.setReadyCondition(set, metav1.ConditionFalse, "InvalidSelector", err.Error())
+		return ctrl.Result{}, r.updateStatus(ctx, set, observed)
+	}
+
+	statuses := make([]issuesv1alpha1.IssueSetRepositoryStatus, 0, len(targets))
+	desired := map[string]bool{}
+	var created int32
+	var failed []string
+	for _, target := range targets {
+		issueObject, err := r.applyIssue(ctx, set, target)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		desired[target.issue] = true
+		status := issuesv1alpha1.IssueSetRepositoryStatus{
+			Repo:          target.repo,
+			RepositoryRef: target.repositoryRef,
+			Issue:         target.issue,
+			IssueNumber:   issueObject.Status.IssueNumber,
+			Phase:         issueObject.Status.Phase,
+			Error:         issueObject.Status.LastSyncError,
+		}
+		if status.IssueNumber != 0 {
+			created++
+		}
+		if status.Error != "" {
+			failed = append(failed, target.repo)
+		}
+		statuses = append(statuses, status)
+	}
+	if err := r.pruneIssues(ctx, set, desired); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	set.Status.Repositories = statuses
+	set.Status.Total = int32(len(targets))
+	set.Status.Created = created
+	switch {
+	case len(targets) == 0:
+		r.setReadyCondition(set, metav1.ConditionFalse, "NoRepositories", "No target repository")
+	case len(failed) > 0:
+		r.setReadyCondition(set, metav1.ConditionFalse, "IssueFailed",
+			fmt.Sprintf("Failed to sync the issue in: %s", strings.Join(failed, ", ")))
+	case int(created) < len(targets):
+		r.setReadyCondition(set, metav1.ConditionFalse, "Pending",
+			fmt.Sprintf("Created the issue in %d of %d repositories", created, len(targets)))
+	default:
+		r.setReadyCondition(set, metav1.ConditionTrue, "Created",
+			fmt.Sprintf("Created the issue in %d repositories", created))
+	}
+	return ctrl.Result{}, r.updateStatus(ctx, set, observed)
+}
+
+// targets returns the repositories targeted by the set: spec.repos, then the selected GithubRepositories whose
+// repository is not listed in spec.repos.
+func (r *GithubIssueSetReconciler) targets(ctx context.Context, set *issuesv1alpha1.GithubIssueSet) ([]issueSetTarget, error) {
+	var targets []issueSetTarget
+	listed := map[string]bool{}
+	for _, repoURL := range set.Spec.Repos {
+		if listed[repoURL] {
+			continue
+		}
+		listed[repoURL] = true
+		suffix := repoURL
+		if owner, repo, err := git.ParseRepoURL(repoURL); err == nil {
+			suffix = owner + "-" + repo
+		}
+		targets = append(targets, issueSetTarget{issue: issueSetIssueName(set.Name, suffix), repo: repoURL})
+	}
+
+	if set.Spec.RepositorySelector == nil {
+		return targets, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.RepositorySelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repositorySelector: %v", err)
+	}
+	var repositories issuesv1alpha1.GithubRepositoryList
+	if err := r.List(ctx, &repositories, client.InNamespace(set.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %v", err)
+	}
+	for _, repository := range repositories.Items {
+		if listed[repository.Spec.Repo] {
+			continue
+		}
+		listed[repository.Spec.Repo] = true
+		targets = append(targets, issueSetTarget{
+			issue:         issueSetIssueName(set.Name, repository.Name),
+			repo:          repository.Spec.Repo,
+			repositoryRef: repository.Name,
+		})
+	}
+	return targets, nil
+}
+
+// applyIssue creates or updates the GithubIssue of a target repository from the template of the set.
+func (r *GithubIssueSetReconciler) applyIssue(ctx context.Context, set *issuesv1alpha1.GithubIssueSet, target issueSetTarget) (*issuesv1alpha1.GithubIssue, error) {
+	issueObject := &issuesv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{Name: target.issue, Namespace: set.Namespace},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, issueObject, func() error {
+		if issueObject.Labels == nil {
+			issueObject.Labels = map[string]string{}
+		}
+		for key, value := range set.Spec.Template.Metadata.Labels {
+			issueObject.Labels[key] = value
+		}
+		issueObject.Labels[IssueSetLabel] = set.Name
+		if len(set.Spec.Template.Metadata.Annotations) > 0 && issueObject.Annotations == nil {
+			issueObject.Annotations = map[string]string{}
+		}
+		for key, value := range set.Spec.Template.Metadata.Annotations {
+			issueObject.Annotations[key] = value
+		}
+		spec := *set.Spec.Template.Spec.DeepCopy()
+		if target.repositoryRef != "" {
+			spec.RepositoryRef = &corev1.LocalObjectReference{Name: target.repositoryRef}
+		} else {
+			spec.Repo = target.repo
+		}
+		issueObject.Spec = spec
+		return controllerutil.SetControllerReference(set, issueObject, r.Scheme)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply issue %s: %v", target.issue, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Log.Info("Applied issue of issue set", zap.String("githubIssueSet", set.Name),
+			zap.String("githubIssue", target.issue), zap.String("operation", string(result)))
+	}
+	return issueObject, nil
+}
+
+// pruneIssues deletes the GithubIssues of the set whose repository is no longer targeted.
+func (r *GithubIssueSetReconciler) pruneIssues(ctx context.Context, set *issuesv1alpha1.GithubIssueSet, desired map[string]bool) error {
+	var existing issuesv1alpha1.GithubIssueList
+	if err := r.List(ctx, &existing, client.InNamespace(set.Namespace), client.MatchingLabels{IssueSetLabel: set.Name}); err != nil {
+		return fmt.Errorf("failed to list issues of issue set: %v", err)
+	}
+
+	for i := range existing.Items {
+		issueObject := &existing.Items[i]
+		if desired[issueObject.Name] || !metav1.IsControlledBy(issueObject, set) {
+			continue
+		}
+		if err := r.Delete(ctx, issueObject); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to prune issue %s: %v", issueObject.Name, err)
+		}
+		r.Log.Info("Pruned issue of a repository no longer targeted", zap.String("githubIssueSet", set.Name),
+			zap.String("githubIssue", issueObject.Name))
+	}
+	return nil
+}
+
+func (r *GithubIssueSetReconciler) setReadyCondition(set *issuesv1alpha1.GithubIssueSet, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&set.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: set.Generation,
+	})
+}
+
+// updateStatus writes the status of the set when it differs from observed.
+func (r *GithubIssueSetReconciler) updateStatus(ctx context.Context, set *issuesv1alpha1.GithubIssueSet, observed *issuesv1alpha1.GithubIssueSetStatus) error {
+	if equality.Semantic.DeepEqual(observed, &set.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, set); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// issueSetIssueName derives the name of the GithubIssue of a target repository from the set name.
+func issueSetIssueName(setName, suffix string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(setName+"-"+suffix), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 253 {
+		name = strings.Trim(name[:253], "-")
+	}
+	return name
+}
+
+// repositorySets enqueues the GithubIssueSets selecting repositories in the namespace of a changed
+// GithubRepository, so they follow label changes of the repositories.
+func (r *GithubIssueSetReconciler) repositorySets(ctx context.Context, obj client.Object) []reconcile.Request {
+	var sets issuesv1alpha1.GithubIssueSetList
+	if err := r.List(ctx, &sets, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error("Failed to list issue sets selecting a changed repository", zap.Error(err))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, set := range sets.Items {
+		if set.Spec.RepositorySelector != nil {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: set.Namespace, Name: set.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubIssueSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.GithubIssueSet{}).
+		Owns(&issuesv1alpha1.GithubIssue{}).
+		Watches(&issuesv1alpha1.GithubRepository{}, handler.EnqueueRequestsFromMapFunc(r.repositorySets)).
+		Named("githubissueset").
+		Complete(r)
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssueSet controller", func() {
+	var (
+		c          client.Client
+		reconciler *GithubIssueSetReconciler
+		set        *issuesv1alpha1.GithubIssueSet
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		set = &issuesv1alpha1.GithubIssueSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "go-upgrade", Namespace: "default", UID: "set-uid"},
+			Spec: issuesv1alpha1.GithubIssueSetSpec{
+				Template: issuesv1alpha1.GithubIssueTemplate{
+					Metadata: issuesv1alpha1.GithubIssueTemplateMetadata{Labels: map[string]string{"campaign": "go"}},
+					Spec:     issuesv1alpha1.GithubIssueSpec{Title: "Upgrade Go", Labels: []string{"tech-debt"}},
+				},
+				Repos:              []string{"https://github.com/org/service-a"},
+				RepositorySelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+			},
+		}
+		selected := &issuesv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-b", Namespace: "default", Labels: map[string]string{"team": "platform"}},
+			Spec:       issuesv1alpha1.GithubRepositorySpec{Repo: "https://github.com/org/service-b"},
+		}
+		other := &issuesv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-c", Namespace: "default", Labels: map[string]string{"team": "data"}},
+			Spec:       issuesv1alpha1.GithubRepositorySpec{Repo: "https://github.com/org/service-c"},
+		}
+		c = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(set, selected, other).
+			WithStatusSubresource(set, &issuesv1alpha1.GithubIssue{}).Build()
+		reconciler = &GithubIssueSetReconciler{Client: c, Scheme: testScheme, Log: zap.NewNop()}
+	})
+
+	reconcileSet := func() *issuesv1alpha1.GithubIssueSet {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(set)})
+		Expect(err).NotTo(HaveOccurred())
+		stored := &issuesv1alpha1.GithubIssueSet{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(set), stored)).To(Succeed())
+		return stored
+	}
+
+	It("creates a GithubIssue per listed and selected repository", func() {
+		stored := reconcileSet()
+
+		listed := &issuesv1alpha1.GithubIssue{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "go-upgrade-org-service-a"}, listed)).To(Succeed())
+		Expect(listed.Spec.Repo).To(Equal("https://github.com/org/service-a"))
+		Expect(listed.Spec.Title).To(Equal("Upgrade Go"))
+		Expect(listed.Labels).To(HaveKeyWithValue(IssueSetLabel, "go-upgrade"))
+		Expect(listed.Labels).To(HaveKeyWithValue("campaign", "go"))
+		Expect(metav1.IsControlledBy(listed, stored)).To(BeTrue())
+
+		selected := &issuesv1alpha1.GithubIssue{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "go-upgrade-service-b"}, selected)).To(Succeed())
+		Expect(selected.Spec.Repo).To(BeEmpty())
+		Expect(selected.Spec.RepositoryRef.Name).To(Equal("service-b"))
+
+		Expect(stored.Status.Total).To(Equal(int32(2)))
+		Expect(stored.Status.Created).To(BeZero())
+		Expect(stored.Status.Repositories).To(HaveLen(2))
+		Expect(meta.FindStatusCondition(stored.Status.Conditions, ReadyCondition).Reason).To(Equal("Pending"))
+	})
+
+	It("aggregates the status of the GithubIssues", func() {
+		reconcileSet()
+		for name, number := range map[string]int{"go-upgrade-org-service-a": 4, "go-upgrade-service-b": 9} {
+			issueObject := &issuesv1alpha1.GithubIssue{}
+			Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, issueObject)).To(Succeed())
+			issueObject.Status.IssueNumber = number
+			Expect(c.Status().Update(context.Background(), issueObject)).To(Succeed())
+		}
+
+		stored := reconcileSet()
+		Expect(stored.Status.Created).To(Equal(int32(2)))
+		Expect(stored.Status.Repositories).To(ContainElement(issuesv1alpha1.IssueSetRepositoryStatus{
+			Repo: "https://github.com/org/service-b", RepositoryRef: "service-b", Issue: "go-upgrade-service-b", IssueNumber: 9,
+		}))
+		Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, ReadyCondition)).To(BeTrue())
+	})
+
+	It("deletes the GithubIssue of a repository no longer targeted", func() {
+		reconcileSet()
+		stored := &issuesv1alpha1.GithubIssueSet{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(set), stored)).To(Succeed())
+		stored.Spec.Repos = nil
+		Expect(c.Update(context.Background(), stored)).To(Succeed())
+
+		Expect(reconcileSet().Status.Total).To(Equal(int32(1)))
+		var issues issuesv1alpha1.GithubIssueList
+		Expect(c.List(context.Background(), &issues, client.MatchingLabels{IssueSetLabel: "go-upgrade"})).To(Succeed())
+		Expect(issues.Items).To(HaveLen(1))
+		Expect(issues.Items[0].Name).To(Equal("go-upgrade-service-b"))
+	})
+})
@@ -0,0 +1,172 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// deleteLabelFinalizer holds the deletion of a GithubLabel until its upstream label is deleted.
+const deleteLabelFinalizer = "issues.dana.io/finalizer"
+
+// GithubLabelReconciler reconciles a GithubLabel object
+type GithubLabelReconciler struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	Log         *zap.Logger
+	LabelClient git.LabelClient
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githublabels,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githublabels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githublabels/finalizers,verbs=update
+
+func (r *GithubLabelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	labelObject := &issuesv1alpha1.GithubLabel{}
+	if err := r.Get(ctx, req.NamespacedName, labelObject); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch label object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	owner, repo, err := git.ParseRepoURL(labelObject.Spec.Repo)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed parse repoURL : %v", err)
+	}
+
+	if !labelObject.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(labelObject, deleteLabelFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.LabelClient.DeleteLabel(ctx, owner, repo, appliedLabelName(labelObject)); err != nil {
+			log.Error("Failed to delete label", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		log.Info("Deleted label", zap.String("label", appliedLabelName(labelObject)))
+		controllerutil.RemoveFinalizer(labelObject, deleteLabelFinalizer)
+		if err := r.Update(ctx, labelObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %v", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(labelObject, deleteLabelFinalizer) {
+		controllerutil.AddFinalizer(labelObject, deleteLabelFinalizer)
+		if err := r.Update(ctx, labelObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %v", err)
+		}
+	}
+
+	if err := r.syncLabel(ctx, owner, repo, labelObject); err != nil {
+		log.Error("Failed to sync label", zap.Error(err))
+		r.setLabelCondition(labelObject, metav1.ConditionFalse, "SyncFailed", err.Error())
+		if statusErr := r.Status().Update(ctx, labelObject); statusErr != nil {
+			log.Error("Failed to update label status", zap.Error(statusErr))
+		}
+		return ctrl.Result{}, err
+	}
+
+	renamed := labelObject.Status.AppliedName != labelObject.Spec.Name
+	labelObject.Status.AppliedName = labelObject.Spec.Name
+	if r.setLabelCondition(labelObject, metav1.ConditionTrue, "Synced", "The label is synced with GitHub") || renamed {
+		if err := r.Status().Update(ctx, labelObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncLabel creates the label, or edits it when it differs from the spec. A label renamed in the spec is
+// looked up under its applied name, so the rename keeps it on the issues carrying it.
+func (r *GithubLabelReconciler) syncLabel(ctx context.Context, owner, repo string, labelObject *issuesv1alpha1.GithubLabel) error {
+	desired := &git.Label{
+		Name:        labelObject.Spec.Name,
+		Color:       strings.ToLower(labelObject.Spec.Color),
+		Description: labelObject.Spec.Description,
+	}
+	name := appliedLabelName(labelObject)
+	current, err := r.LabelClient.GetLabel(ctx, owner, repo, name)
+	if errors.Is(err, git.ErrLabelNotFound) && name != desired.Name {
+		// The rename may have been applied before the status was written.
+		name = desired.Name
+		current, err = r.LabelClient.GetLabel(ctx, owner, repo, name)
+	}
+	if errors.Is(err, git.ErrLabelNotFound) {
+		if err := r.LabelClient.CreateLabel(ctx, owner, repo, desired); err != nil {
+			return err
+		}
+		r.Log.Info("Created label", zap.String("label", desired.Name), zap.String("repository", owner+"/"+repo))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if current.Name == desired.Name && strings.EqualFold(current.Color, desired.Color) && current.Description == desired.Description {
+		return nil
+	}
+	if err := r.LabelClient.EditLabel(ctx, owner, repo, name, desired); err != nil {
+		return err
+	}
+	r.Log.Info("Edited label", zap.String("label", desired.Name), zap.String("repository", owner+"/"+repo))
+	return nil
+}
+
+// appliedLabelName returns the upstream name of the label: the name last applied, or the spec name before that.
+func appliedLabelName(labelObject *issuesv1alpha1.GithubLabel) string {
+	if labelObject.Status.AppliedName != "" {
+		return labelObject.Status.AppliedName
+	}
+	return labelObject.Spec.Name
+}
+
+func (r *GithubLabelReconciler) setLabelCondition(labelObject *issuesv1alpha1.GithubLabel, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&labelObject.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: labelObject.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubLabelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.GithubLabel{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("githublabel").
+		Complete(r)
+}
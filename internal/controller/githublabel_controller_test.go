@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeLabelClient keeps the labels of a single repository in memory.
+type fakeLabelClient struct {
+	labels map[string]git.Label
+}
+
+func (f *fakeLabelClient) GetLabel(_ context.Context, _, _, name string) (*git.Label, error) {
+	label, ok := f.labels[name]
+	if !ok {
+		return nil, git.ErrLabelNotFound
+	}
+	return &label, nil
+}
+
+func (f *fakeLabelClient) CreateLabel(_ context.Context, _, _ string, label *git.Label) error {
+	f.labels[label.Name] = *label
+	return nil
+}
+
+func (f *fakeLabelClient) EditLabel(_ context.Context, _, _, name string, label *git.Label) error {
+	if _, ok := f.labels[name]; !ok {
+		return git.ErrLabelNotFound
+	}
+	delete(f.labels, name)
+	f.labels[label.Name] = *label
+	return nil
+}
+
+func (f *fakeLabelClient) DeleteLabel(_ context.Context, _, _, name string) error {
+	delete(f.labels, name)
+	return nil
+}
+
+var _ = Describe("GithubLabel controller", func() {
+	var (
+		reconciler *GithubLabelReconciler
+		labels     *fakeLabelClient
+		key        = types.NamespacedName{Name: "docs", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		labelObject := &issuesv1alpha1.GithubLabel{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: issuesv1alpha1.GithubLabelSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Name:        "area/docs",
+				Color:       "0E8A16",
+				Description: "Documentation changes",
+			},
+		}
+		labels = &fakeLabelClient{labels: map[string]git.Label{}}
+		reconciler = &GithubLabelReconciler{
+			Client:      fake.NewClientBuilder().WithScheme(testScheme).WithObjects(labelObject).WithStatusSubresource(labelObject).Build(),
+			Log:         zap.NewNop(),
+			LabelClient: labels,
+		}
+	})
+
+	reconcile := func() *issuesv1alpha1.GithubLabel {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		labelObject := &issuesv1alpha1.GithubLabel{}
+		if err := reconciler.Get(context.Background(), key, labelObject); client.IgnoreNotFound(err) != nil {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		return labelObject
+	}
+
+	It("creates the label and renames it in place", func() {
+		labelObject := reconcile()
+		Expect(labels.labels).To(Equal(map[string]git.Label{
+			"area/docs": {Name: "area/docs", Color: "0e8a16", Description: "Documentation changes"},
+		}))
+		Expect(labelObject.Status.AppliedName).To(Equal("area/docs"))
+		Expect(meta.IsStatusConditionTrue(labelObject.Status.Conditions, ReadyCondition)).To(BeTrue())
+
+		labelObject.Spec.Name = "kind/docs"
+		Expect(reconciler.Update(context.Background(), labelObject)).To(Succeed())
+		labelObject = reconcile()
+		Expect(labels.labels).To(HaveKey("kind/docs"))
+		Expect(labels.labels).NotTo(HaveKey("area/docs"))
+		Expect(labelObject.Status.AppliedName).To(Equal("kind/docs"))
+	})
+
+	It("deletes the upstream label before releasing the GithubLabel", func() {
+		labelObject := reconcile()
+		Expect(labelObject.Finalizers).To(ContainElement(deleteLabelFinalizer))
+		Expect(reconciler.Delete(context.Background(), labelObject)).To(Succeed())
+
+		reconcile()
+		Expect(labels.labels).To(BeEmpty())
+		Expect(reconciler.Get(context.Background(), key, &issuesv1alpha1.GithubLabel{})).NotTo(Succeed())
+	})
+})
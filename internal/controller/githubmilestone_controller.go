@@ -0,0 +1,212 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// closeMilestoneFinalizer holds the deletion of a GithubMilestone until its upstream milestone is closed.
+const closeMilestoneFinalizer = "issues.dana.io/finalizer"
+
+// GithubMilestoneReconciler reconciles a GithubMilestone object
+type GithubMilestoneReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	Log             *zap.Logger
+	MilestoneClient git.MilestoneClient
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubmilestones,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubmilestones/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubmilestones/finalizers,verbs=update
+
+func (r *GithubMilestoneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	milestoneObject := &issuesv1alpha1.GithubMilestone{}
+	if err := r.Get(ctx, req.NamespacedName, milestoneObject); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch milestone object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	owner, repo, err := git.ParseRepoURL(milestoneObject.Spec.Repo)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed parse repoURL : %v", err)
+	}
+
+	if !milestoneObject.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(milestoneObject, closeMilestoneFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.closeMilestone(ctx, owner, repo, milestoneObject); err != nil {
+			log.Error("Failed to close milestone", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(milestoneObject, closeMilestoneFinalizer)
+		if err := r.Update(ctx, milestoneObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %v", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(milestoneObject, closeMilestoneFinalizer) {
+		controllerutil.AddFinalizer(milestoneObject, closeMilestoneFinalizer)
+		if err := r.Update(ctx, milestoneObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %v", err)
+		}
+	}
+
+	milestone, err := r.syncMilestone(ctx, owner, repo, milestoneObject)
+	if err != nil {
+		log.Error("Failed to sync milestone", zap.Error(err))
+		r.setMilestoneCondition(milestoneObject, metav1.ConditionFalse, "SyncFailed", err.Error())
+		if statusErr := r.Status().Update(ctx, milestoneObject); statusErr != nil {
+			log.Error("Failed to update milestone status", zap.Error(statusErr))
+		}
+		return ctrl.Result{}, err
+	}
+
+	changed := milestoneObject.Status.Number != milestone.Number || milestoneObject.Status.URL != milestone.URL
+	milestoneObject.Status.Number, milestoneObject.Status.URL = milestone.Number, milestone.URL
+	if r.setMilestoneCondition(milestoneObject, metav1.ConditionTrue, "Synced", "The milestone is synced with GitHub") || changed {
+		if err := r.Status().Update(ctx, milestoneObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncMilestone creates the milestone, or edits it when it differs from the spec. The milestone is looked up by
+// the number recorded in status, or by title before, so existing milestones are adopted.
+func (r *GithubMilestoneReconciler) syncMilestone(ctx context.Context, owner, repo string, milestoneObject *issuesv1alpha1.GithubMilestone) (*git.Milestone, error) {
+	desired := desiredMilestone(milestoneObject)
+	var current *git.Milestone
+	var err error
+	if number := milestoneObject.Status.Number; number != 0 {
+		current, err = r.MilestoneClient.GetMilestone(ctx, owner, repo, number)
+	} else {
+		current, err = r.MilestoneClient.GetMilestoneByTitle(ctx, owner, repo, desired.Title)
+	}
+	if errors.Is(err, git.ErrMilestoneNotFound) {
+		created, err := r.MilestoneClient.CreateMilestone(ctx, owner, repo, desired)
+		if err != nil {
+			return nil, err
+		}
+		r.Log.Info("Created milestone", zap.String("milestone", desired.Title), zap.String("repository", owner+"/"+repo))
+		return created, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Title == desired.Title && current.Description == desired.Description &&
+		current.State == desired.State && sameDueDate(current.DueOn, desired.DueOn) {
+		return current, nil
+	}
+	edited, err := r.MilestoneClient.EditMilestone(ctx, owner, repo, current.Number, desired)
+	if err != nil {
+		return nil, err
+	}
+	r.Log.Info("Edited milestone", zap.String("milestone", desired.Title), zap.String("repository", owner+"/"+repo))
+	return edited, nil
+}
+
+// closeMilestone closes the upstream milestone, leaving it on its issues. A milestone deleted upstream is skipped.
+func (r *GithubMilestoneReconciler) closeMilestone(ctx context.Context, owner, repo string, milestoneObject *issuesv1alpha1.GithubMilestone) error {
+	number := milestoneObject.Status.Number
+	if number == 0 {
+		return nil
+	}
+	current, err := r.MilestoneClient.GetMilestone(ctx, owner, repo, number)
+	if errors.Is(err, git.ErrMilestoneNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.State == string(issuesv1alpha1.MilestoneStateClosed) {
+		return nil
+	}
+	current.State = string(issuesv1alpha1.MilestoneStateClosed)
+	if _, err := r.MilestoneClient.EditMilestone(ctx, owner, repo, number, current); err != nil {
+		return err
+	}
+	r.Log.Info("Closed milestone", zap.String("milestone", current.Title), zap.String("repository", owner+"/"+repo))
+	return nil
+}
+
+// desiredMilestone builds the upstream milestone requested by the spec.
+func desiredMilestone(milestoneObject *issuesv1alpha1.GithubMilestone) *git.Milestone {
+	desired := &git.Milestone{
+		Title:       milestoneObject.Spec.Title,
+		Description: milestoneObject.Spec.Description,
+		State:       string(milestoneObject.Spec.State),
+	}
+	if desired.State == "" {
+		desired.State = string(issuesv1alpha1.MilestoneStateOpen)
+	}
+	if dueDate := milestoneObject.Spec.DueDate; dueDate != nil {
+		dueOn := dueDate.Time
+		desired.DueOn = &dueOn
+	}
+	return desired
+}
+
+// sameDueDate compares due dates by day, the precision GitHub keeps.
+func sameDueDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.UTC().Format(time.DateOnly) == b.UTC().Format(time.DateOnly)
+}
+
+func (r *GithubMilestoneReconciler) setMilestoneCondition(milestoneObject *issuesv1alpha1.GithubMilestone, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&milestoneObject.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: milestoneObject.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubMilestoneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.GithubMilestone{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("githubmilestone").
+		Complete(r)
+}
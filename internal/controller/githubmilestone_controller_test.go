@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeMilestoneClient keeps the milestones of a single repository in memory.
+type fakeMilestoneClient struct {
+	milestones map[int]git.Milestone
+	edits      int
+}
+
+func (f *fakeMilestoneClient) GetMilestone(_ context.Context, _, _ string, number int) (*git.Milestone, error) {
+	milestone, ok := f.milestones[number]
+	if !ok {
+		return nil, git.ErrMilestoneNotFound
+	}
+	return &milestone, nil
+}
+
+func (f *fakeMilestoneClient) GetMilestoneByTitle(_ context.Context, _, _, title string) (*git.Milestone, error) {
+	for _, milestone := range f.milestones {
+		if milestone.Title == title {
+			return &milestone, nil
+		}
+	}
+	return nil, git.ErrMilestoneNotFound
+}
+
+func (f *fakeMilestoneClient) CreateMilestone(_ context.Context, _, _ string, milestone *git.Milestone) (*git.Milestone, error) {
+	created := *milestone
+	created.Number = len(f.milestones) + 1
+	f.milestones[created.Number] = created
+	return &created, nil
+}
+
+func (f *fakeMilestoneClient) EditMilestone(_ context.Context, _, _ string, number int, milestone *git.Milestone) (*git.Milestone, error) {
+	if _, ok := f.milestones[number]; !ok {
+		return nil, git.ErrMilestoneNotFound
+	}
+	f.edits++
+	edited := *milestone
+	edited.Number = number
+	f.milestones[number] = edited
+	return &edited, nil
+}
+
+var _ = Describe("GithubMilestone controller", func() {
+	var (
+		reconciler *GithubMilestoneReconciler
+		milestones *fakeMilestoneClient
+		key        = types.NamespacedName{Name: "v1-0", Namespace: "default"}
+		dueDate    = time.Date(2030, time.January, 31, 0, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		milestoneObject := &issuesv1alpha1.GithubMilestone{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: issuesv1alpha1.GithubMilestoneSpec{
+				Repo:    "https://github.com/example-org/example-repo",
+				Title:   "v1.0",
+				DueDate: &metav1.Time{Time: dueDate},
+			},
+		}
+		milestones = &fakeMilestoneClient{milestones: map[int]git.Milestone{}}
+		reconciler = &GithubMilestoneReconciler{
+			Client:          fake.NewClientBuilder().WithScheme(testScheme).WithObjects(milestoneObject).WithStatusSubresource(milestoneObject).Build(),
+			Log:             zap.NewNop(),
+			MilestoneClient: milestones,
+		}
+	})
+
+	reconcile := func() *issuesv1alpha1.GithubMilestone {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		milestoneObject := &issuesv1alpha1.GithubMilestone{}
+		if err := reconciler.Get(context.Background(), key, milestoneObject); client.IgnoreNotFound(err) != nil {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		return milestoneObject
+	}
+
+	It("creates the milestone and closes it through the spec", func() {
+		milestoneObject := reconcile()
+		Expect(milestoneObject.Status.Number).To(Equal(1))
+		Expect(milestones.milestones[1].State).To(Equal("open"))
+		Expect(meta.IsStatusConditionTrue(milestoneObject.Status.Conditions, ReadyCondition)).To(BeTrue())
+
+		// GitHub keeps the date only, so a due date returned at another hour of the day is left alone.
+		stored := milestones.milestones[1]
+		storedDueOn := dueDate.Add(8 * time.Hour)
+		stored.DueOn = &storedDueOn
+		milestones.milestones[1] = stored
+		reconcile()
+		Expect(milestones.edits).To(BeZero())
+
+		milestoneObject.Spec.State = issuesv1alpha1.MilestoneStateClosed
+		Expect(reconciler.Update(context.Background(), milestoneObject)).To(Succeed())
+		reconcile()
+		Expect(milestones.milestones[1].State).To(Equal("closed"))
+	})
+
+	It("adopts an existing milestone and closes it on deletion", func() {
+		milestones.milestones[4] = git.Milestone{Number: 4, Title: "v1.0", State: "open", DueOn: &dueDate}
+		milestoneObject := reconcile()
+		Expect(milestoneObject.Status.Number).To(Equal(4))
+		Expect(milestones.milestones).To(HaveLen(1))
+
+		Expect(reconciler.Delete(context.Background(), milestoneObject)).To(Succeed())
+		reconcile()
+		Expect(milestones.milestones[4].State).To(Equal("closed"))
+		Expect(reconciler.Get(context.Background(), key, &issuesv1alpha1.GithubMilestone{})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,241 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RepositoryLabel is set on GithubIssues materialized from a GithubRepository issue source.
+	RepositoryLabel = "issues.dana.io/repository"
+	// SourcePathAnnotation records the definition file a GithubIssue was materialized from.
+	SourcePathAnnotation = "issues.dana.io/source-path"
+
+	// IssueSourceSyncedCondition reports whether the issue source was pulled and applied.
+	IssueSourceSyncedCondition = "IssueSourceSynced"
+
+	defaultIssueSourceInterval = 5 * time.Minute
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// GithubRepositoryReconciler reconciles a GithubRepository object
+type GithubRepositoryReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	Log        *zap.Logger
+	FileClient git.FileClient
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubrepositories,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubrepositories/status,verbs=get;update;patch
+
+func (r *GithubRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	repository := &issuesv1alpha1.GithubRepository{}
+	if err := r.Get(ctx, req.NamespacedName, repository); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch repository object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	source := repository.Spec.IssueSource
+	if source == nil || !repository.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	owner, repo, err := git.ParseRepoURL(repository.Spec.Repo)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed parse repoURL : %v", err)
+	}
+
+	files, err := r.FileClient.ListFiles(ctx, owner, repo, source.Path, source.Ref)
+	if err != nil {
+		log.Error("Failed to pull issue definitions", zap.Error(err))
+		r.setSourceCondition(repository, metav1.ConditionFalse, "PullFailed", err.Error())
+		if statusErr := r.Status().Update(ctx, repository); statusErr != nil {
+			log.Error("Failed to update repository status", zap.Error(statusErr))
+		}
+		return ctrl.Result{}, err
+	}
+
+	desired := map[string]bool{}
+	var invalid []string
+	for _, file := range files {
+		if ext := path.Ext(file.Path); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		name := definitionName(repository.Name, file.Path)
+		// A broken file must not prune the issue it used to define.
+		desired[name] = true
+
+		spec, err := parseIssueDefinition(file.Content, repository.Spec.Repo)
+		if err != nil {
+			log.Warn("Skipping invalid issue definition", zap.String("path", file.Path), zap.Error(err))
+			invalid = append(invalid, file.Path)
+			continue
+		}
+		if err := r.applyIssue(ctx, repository, name, file.Path, spec); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if source.Prune == nil || *source.Prune {
+		if err := r.pruneIssues(ctx, repository, desired); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	managed := make([]string, 0, len(desired))
+	for name := range desired {
+		managed = append(managed, name)
+	}
+	sort.Strings(managed)
+	repository.Status.ManagedIssues = managed
+	now := metav1.Now()
+	repository.Status.LastSyncTime = &now
+	if len(invalid) > 0 {
+		r.setSourceCondition(repository, metav1.ConditionFalse, "InvalidDefinition",
+			fmt.Sprintf("invalid issue definitions: %s", strings.Join(invalid, ", ")))
+	} else {
+		r.setSourceCondition(repository, metav1.ConditionTrue, "Synced",
+			fmt.Sprintf("%d issue definitions applied", len(managed)))
+	}
+	if err := r.Status().Update(ctx, repository); err != nil {
+		log.Error("Failed to update repository status", zap.Error(err))
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+
+	interval := source.Interval.Duration
+	if interval <= 0 {
+		interval = defaultIssueSourceInterval
+	}
+	log.Info("Issue source synced", zap.Int("issues", len(managed)), zap.Duration("requeueAfter", interval))
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// applyIssue creates or updates the GithubIssue materialized from a definition file.
+func (r *GithubRepositoryReconciler) applyIssue(ctx context.Context, repository *issuesv1alpha1.GithubRepository, name, sourcePath string, spec issuesv1alpha1.GithubIssueSpec) error {
+	issueObject := &issuesv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: repository.Namespace},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, issueObject, func() error {
+		if issueObject.Labels == nil {
+			issueObject.Labels = map[string]string{}
+		}
+		issueObject.Labels[RepositoryLabel] = repository.Name
+		if issueObject.Annotations == nil {
+			issueObject.Annotations = map[string]string{}
+		}
+		issueObject.Annotations[SourcePathAnnotation] = sourcePath
+		issueObject.Spec = spec
+		return controllerutil.SetControllerReference(repository, issueObject, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply issue %s: %v", name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Log.Info("Applied issue definition", zap.String("githubIssue", name), zap.String("operation", string(result)))
+	}
+	return nil
+}
+
+// pruneIssues deletes GithubIssues materialized from the repository whose definition file is gone.
+func (r *GithubRepositoryReconciler) pruneIssues(ctx context.Context, repository *issuesv1alpha1.GithubRepository, desired map[string]bool) error {
+	var existing issuesv1alpha1.GithubIssueList
+	if err := r.List(ctx, &existing, client.InNamespace(repository.Namespace), client.MatchingLabels{RepositoryLabel: repository.Name}); err != nil {
+		return fmt.Errorf("failed to list materialized issues: %v", err)
+	}
+
+	for i := range existing.Items {
+		issueObject := &existing.Items[i]
+		if desired[issueObject.Name] || !metav1.IsControlledBy(issueObject, repository) {
+			continue
+		}
+		if err := r.Delete(ctx, issueObject); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to prune issue %s: %v", issueObject.Name, err)
+		}
+		r.Log.Info("Pruned issue whose definition was removed", zap.String("githubIssue", issueObject.Name))
+	}
+	return nil
+}
+
+func (r *GithubRepositoryReconciler) setSourceCondition(repository *issuesv1alpha1.GithubRepository, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&repository.Status.Conditions, metav1.Condition{
+		Type:               IssueSourceSyncedCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: repository.Generation,
+	})
+}
+
+// parseIssueDefinition decodes a definition file into a GithubIssue spec, defaulting the repo.
+func parseIssueDefinition(content []byte, defaultRepo string) (issuesv1alpha1.GithubIssueSpec, error) {
+	var spec issuesv1alpha1.GithubIssueSpec
+	if err := yaml.UnmarshalStrict(content, &spec); err != nil {
+		return spec, err
+	}
+	if spec.Title == "" {
+		return spec, fmt.Errorf("title is required")
+	}
+	if spec.Repo == "" {
+		spec.Repo = defaultRepo
+	}
+	return spec, nil
+}
+
+// definitionName derives a GithubIssue name from the repository name and the definition file path.
+func definitionName(repositoryName, filePath string) string {
+	base := strings.TrimSuffix(path.Base(filePath), path.Ext(filePath))
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(repositoryName+"-"+base), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 253 {
+		name = strings.Trim(name[:253], "-")
+	}
+	return name
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.GithubRepository{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
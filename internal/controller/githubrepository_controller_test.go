@@ -0,0 +1,27 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("issue source definitions", func() {
+	It("derives valid object names from definition paths", func() {
+		Expect(definitionName("platform", "issues/Fix_Login.yaml")).To(Equal("platform-fix-login"))
+		Expect(definitionName("Platform", "issues/--weird--.yml")).To(Equal("platform---weird"))
+	})
+
+	It("defaults the repo of a definition to the repository", func() {
+		spec, err := parseIssueDefinition([]byte("title: Fix login\ndescription: Users cannot log in\n"), "https://github.com/org/repo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.Title).To(Equal("Fix login"))
+		Expect(spec.Repo).To(Equal("https://github.com/org/repo"))
+	})
+
+	It("rejects definitions with unknown fields or without a title", func() {
+		_, err := parseIssueDefinition([]byte("title: Fix login\nbody: typo\n"), "https://github.com/org/repo")
+		Expect(err).To(HaveOccurred())
+		_, err = parseIssueDefinition([]byte("description: no title\n"), "https://github.com/org/repo")
+		Expect(err).To(HaveOccurred())
+	})
+})
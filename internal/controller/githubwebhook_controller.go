@@ -0,0 +1,318 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// deleteWebhookFinalizer holds the deletion of a GithubWebhook until its upstream webhook is deleted.
+	deleteWebhookFinalizer = "issues.dana.io/finalizer"
+	// webhookRotatedAtAnnotation records on the Secret of a GithubWebhook when its secret was last replaced.
+	webhookRotatedAtAnnotation = "issues.dana.io/rotated-at"
+	// DeliveriesHealthyCondition is true while the most recent delivery of the webhook succeeded.
+	DeliveriesHealthyCondition = "DeliveriesHealthy"
+	// webhookHealthInterval is how often the deliveries of a webhook are checked.
+	webhookHealthInterval = 5 * time.Minute
+)
+
+// GithubWebhookReconciler reconciles a GithubWebhook object
+type GithubWebhookReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	Log        *zap.Logger
+	HookClient git.HookClient
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubwebhooks,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubwebhooks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubwebhooks/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+
+func (r *GithubWebhookReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	webhookObject := &issuesv1alpha1.GithubWebhook{}
+	if err := r.Get(ctx, req.NamespacedName, webhookObject); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch webhook object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	owner, repo, err := git.ParseRepoURL(webhookObject.Spec.Repo)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed parse repoURL : %v", err)
+	}
+
+	if !webhookObject.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(webhookObject, deleteWebhookFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if id := webhookObject.Status.HookID; id != 0 {
+			if err := r.HookClient.DeleteHook(ctx, owner, repo, id); err != nil {
+				log.Error("Failed to delete webhook", zap.Error(err))
+				return ctrl.Result{}, err
+			}
+			log.Info("Deleted webhook", zap.Int64("hook", id))
+		}
+		controllerutil.RemoveFinalizer(webhookObject, deleteWebhookFinalizer)
+		if err := r.Update(ctx, webhookObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %v", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(webhookObject, deleteWebhookFinalizer) {
+		controllerutil.AddFinalizer(webhookObject, deleteWebhookFinalizer)
+		if err := r.Update(ctx, webhookObject); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %v", err)
+		}
+	}
+
+	now := time.Now()
+	secret, err := r.ensureSecret(ctx, webhookObject, now)
+	if err == nil {
+		err = r.syncHook(ctx, owner, repo, webhookObject, secret)
+	}
+	if err == nil {
+		err = r.checkDeliveries(ctx, owner, repo, webhookObject)
+	}
+	if err != nil {
+		log.Error("Failed to sync webhook", zap.Error(err))
+		r.setWebhookCondition(webhookObject, ReadyCondition, metav1.ConditionFalse, "SyncFailed", err.Error())
+		if statusErr := r.Status().Update(ctx, webhookObject); statusErr != nil {
+			log.Error("Failed to update webhook status", zap.Error(statusErr))
+		}
+		return ctrl.Result{}, err
+	}
+
+	r.setWebhookCondition(webhookObject, ReadyCondition, metav1.ConditionTrue, "Synced", "The webhook is registered on GitHub")
+	if err := r.Status().Update(ctx, webhookObject); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+	return ctrl.Result{RequeueAfter: r.nextCheck(webhookObject, now)}, nil
+}
+
+// ensureSecret returns the Secret signing the deliveries of the webhook, creating it on first use and
+// replacing its secret once spec.secretRotationInterval has passed since the last rotation.
+func (r *GithubWebhookReconciler) ensureSecret(ctx context.Context, webhookObject *issuesv1alpha1.GithubWebhook, now time.Time) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	name := webhookObject.Name + "-webhook"
+	err := r.Get(ctx, client.ObjectKey{Namespace: webhookObject.Namespace, Name: name}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: webhookObject.Namespace, Name: name}}
+		if err := controllerutil.SetControllerReference(webhookObject, secret, r.Scheme); err != nil {
+			return nil, fmt.Errorf("failed to set owner of webhook secret: %v", err)
+		}
+		if err := rotateWebhookSecret(secret, now); err != nil {
+			return nil, err
+		}
+		if err := r.Create(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed to create webhook secret: %v", err)
+		}
+		r.Log.Info("Created webhook secret", zap.String("secret", webhookObject.Namespace+"/"+name))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get webhook secret: %v", err)
+	} else if rotationDue(webhookObject, secret, now) {
+		if err := rotateWebhookSecret(secret, now); err != nil {
+			return nil, err
+		}
+		if err := r.Update(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed to rotate webhook secret: %v", err)
+		}
+		r.Log.Info("Rotated webhook secret", zap.String("secret", webhookObject.Namespace+"/"+name))
+	}
+
+	webhookObject.Status.SecretName = name
+	if rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[webhookRotatedAtAnnotation]); err == nil {
+		webhookObject.Status.SecretRotatedAt = &metav1.Time{Time: rotatedAt}
+	}
+	return secret, nil
+}
+
+// rotationDue reports whether the secret of the webhook is missing or older than spec.secretRotationInterval.
+func rotationDue(webhookObject *issuesv1alpha1.GithubWebhook, secret *corev1.Secret, now time.Time) bool {
+	if len(secret.Data[issuesv1alpha1.WebhookSecretKey]) == 0 {
+		return true
+	}
+	interval := webhookObject.Spec.SecretRotationInterval
+	if interval == nil || interval.Duration <= 0 {
+		return false
+	}
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[webhookRotatedAtAnnotation])
+	return err != nil || !now.Before(rotatedAt.Add(interval.Duration))
+}
+
+// rotateWebhookSecret replaces the secret, keeping the replaced one as the previous secret.
+func rotateWebhookSecret(secret *corev1.Secret, now time.Time) error {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %v", err)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if current := secret.Data[issuesv1alpha1.WebhookSecretKey]; len(current) > 0 {
+		secret.Data[issuesv1alpha1.WebhookPreviousSecretKey] = current
+	}
+	secret.Data[issuesv1alpha1.WebhookSecretKey] = []byte(hex.EncodeToString(value))
+	metav1.SetMetaDataAnnotation(&secret.ObjectMeta, webhookRotatedAtAnnotation, now.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// syncHook creates the upstream webhook, or edits it when it differs from the spec or the Secret changed since
+// it was last written. A webhook deleted upstream is created again.
+func (r *GithubWebhookReconciler) syncHook(ctx context.Context, owner, repo string, webhookObject *issuesv1alpha1.GithubWebhook, secret *corev1.Secret) error {
+	desired := &git.Hook{URL: webhookObject.Spec.URL, Events: webhookObject.Spec.Events, Active: true}
+	if len(desired.Events) == 0 {
+		desired.Events = []string{"issues"}
+	}
+	value := string(secret.Data[issuesv1alpha1.WebhookSecretKey])
+
+	var current *git.Hook
+	if id := webhookObject.Status.HookID; id != 0 {
+		hook, err := r.HookClient.GetHook(ctx, owner, repo, id)
+		if err != nil && !errors.Is(err, git.ErrHookNotFound) {
+			return err
+		}
+		current = hook
+	}
+	if current == nil {
+		created, err := r.HookClient.CreateHook(ctx, owner, repo, desired, value)
+		if err != nil {
+			return err
+		}
+		r.Log.Info("Created webhook", zap.Int64("hook", created.ID), zap.String("repository", owner+"/"+repo))
+		webhookObject.Status.HookID = created.ID
+		webhookObject.Status.AppliedSecretVersion = secret.ResourceVersion
+		return nil
+	}
+
+	if current.URL == desired.URL && current.Active && sameEvents(current.Events, desired.Events) &&
+		webhookObject.Status.AppliedSecretVersion == secret.ResourceVersion {
+		return nil
+	}
+	if err := r.HookClient.EditHook(ctx, owner, repo, current.ID, desired, value); err != nil {
+		return err
+	}
+	r.Log.Info("Edited webhook", zap.Int64("hook", current.ID), zap.String("repository", owner+"/"+repo))
+	webhookObject.Status.AppliedSecretVersion = secret.ResourceVersion
+	return nil
+}
+
+func sameEvents(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// checkDeliveries reports the most recent deliveries of the webhook in its status and the DeliveriesHealthy condition.
+func (r *GithubWebhookReconciler) checkDeliveries(ctx context.Context, owner, repo string, webhookObject *issuesv1alpha1.GithubWebhook) error {
+	deliveries, err := r.HookClient.ListHookDeliveries(ctx, owner, repo, webhookObject.Status.HookID)
+	if err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		webhookObject.Status.LastDelivery, webhookObject.Status.FailedDeliveries = nil, 0
+		r.setWebhookCondition(webhookObject, DeliveriesHealthyCondition, metav1.ConditionUnknown, "NoDeliveries", "GitHub has not delivered the webhook yet")
+		return nil
+	}
+
+	var failed int32
+	for _, delivery := range deliveries {
+		if !deliverySucceeded(delivery) {
+			failed++
+		}
+	}
+	last := deliveries[0]
+	webhookObject.Status.FailedDeliveries = failed
+	webhookObject.Status.LastDelivery = &issuesv1alpha1.WebhookDelivery{
+		ID:          last.ID,
+		Event:       last.Event,
+		StatusCode:  last.StatusCode,
+		Status:      last.Status,
+		DeliveredAt: metav1.Time{Time: last.DeliveredAt},
+	}
+	if !deliverySucceeded(last) {
+		r.setWebhookCondition(webhookObject, DeliveriesHealthyCondition, metav1.ConditionFalse, "DeliveryFailed",
+			fmt.Sprintf("The last delivery failed: %s. %d of the last %d deliveries failed", last.Status, failed, len(deliveries)))
+		return nil
+	}
+	r.setWebhookCondition(webhookObject, DeliveriesHealthyCondition, metav1.ConditionTrue, "Delivered",
+		fmt.Sprintf("The last delivery succeeded. %d of the last %d deliveries failed", failed, len(deliveries)))
+	return nil
+}
+
+func deliverySucceeded(delivery *git.HookDelivery) bool {
+	return delivery.StatusCode >= 200 && delivery.StatusCode < 300
+}
+
+// nextCheck returns when the webhook is reconciled again: for its next health check, or its next rotation if sooner.
+func (r *GithubWebhookReconciler) nextCheck(webhookObject *issuesv1alpha1.GithubWebhook, now time.Time) time.Duration {
+	next := webhookHealthInterval
+	interval := webhookObject.Spec.SecretRotationInterval
+	if interval == nil || interval.Duration <= 0 || webhookObject.Status.SecretRotatedAt == nil {
+		return next
+	}
+	if untilRotation := webhookObject.Status.SecretRotatedAt.Add(interval.Duration).Sub(now); untilRotation < next {
+		next = max(untilRotation, time.Second)
+	}
+	return next
+}
+
+func (r *GithubWebhookReconciler) setWebhookCondition(webhookObject *issuesv1alpha1.GithubWebhook, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&webhookObject.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: webhookObject.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubWebhookReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.GithubWebhook{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.Secret{}, builder.OnlyMetadata).
+		Named("githubwebhook").
+		Complete(r)
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeHookClient keeps the webhooks of a single repository in memory.
+type fakeHookClient struct {
+	nextID     int64
+	hooks      map[int64]git.Hook
+	secrets    map[int64]string
+	deliveries []*git.HookDelivery
+}
+
+func (f *fakeHookClient) GetHook(_ context.Context, _, _ string, id int64) (*git.Hook, error) {
+	hook, ok := f.hooks[id]
+	if !ok {
+		return nil, git.ErrHookNotFound
+	}
+	return &hook, nil
+}
+
+func (f *fakeHookClient) CreateHook(_ context.Context, _, _ string, hook *git.Hook, secret string) (*git.Hook, error) {
+	f.nextID++
+	created := *hook
+	created.ID = f.nextID
+	f.hooks[created.ID] = created
+	f.secrets[created.ID] = secret
+	return &created, nil
+}
+
+func (f *fakeHookClient) EditHook(_ context.Context, _, _ string, id int64, hook *git.Hook, secret string) error {
+	if _, ok := f.hooks[id]; !ok {
+		return git.ErrHookNotFound
+	}
+	edited := *hook
+	edited.ID = id
+	f.hooks[id] = edited
+	f.secrets[id] = secret
+	return nil
+}
+
+func (f *fakeHookClient) DeleteHook(_ context.Context, _, _ string, id int64) error {
+	delete(f.hooks, id)
+	return nil
+}
+
+func (f *fakeHookClient) ListHookDeliveries(_ context.Context, _, _ string, id int64) ([]*git.HookDelivery, error) {
+	if _, ok := f.hooks[id]; !ok {
+		return nil, git.ErrHookNotFound
+	}
+	return f.deliveries, nil
+}
+
+var _ = Describe("GithubWebhook controller", func() {
+	var (
+		reconciler *GithubWebhookReconciler
+		hooks      *fakeHookClient
+		key        = types.NamespacedName{Name: "events", Namespace: "default"}
+		secretKey  = types.NamespacedName{Name: "events-webhook", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		webhookObject := &issuesv1alpha1.GithubWebhook{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: issuesv1alpha1.GithubWebhookSpec{
+				Repo:                   "https://github.com/example-org/example-repo",
+				URL:                    "https://issues-operator.example.com/",
+				Events:                 []string{"issues"},
+				SecretRotationInterval: &metav1.Duration{Duration: time.Hour},
+			},
+		}
+		hooks = &fakeHookClient{hooks: map[int64]git.Hook{}, secrets: map[int64]string{}}
+		reconciler = &GithubWebhookReconciler{
+			Client:     fake.NewClientBuilder().WithScheme(testScheme).WithObjects(webhookObject).WithStatusSubresource(webhookObject).Build(),
+			Scheme:     testScheme,
+			Log:        zap.NewNop(),
+			HookClient: hooks,
+		}
+	})
+
+	reconcile := func() *issuesv1alpha1.GithubWebhook {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		webhookObject := &issuesv1alpha1.GithubWebhook{}
+		Expect(reconciler.Get(context.Background(), key, webhookObject)).To(Succeed())
+		return webhookObject
+	}
+
+	secret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(context.Background(), secretKey, secret)).To(Succeed())
+		return secret
+	}
+
+	It("registers the webhook with a generated secret and recreates it when deleted upstream", func() {
+		webhookObject := reconcile()
+		Expect(webhookObject.Status.HookID).To(Equal(int64(1)))
+		Expect(webhookObject.Status.SecretName).To(Equal(secretKey.Name))
+		Expect(hooks.hooks[1].URL).To(Equal("https://issues-operator.example.com/"))
+		Expect(hooks.secrets[1]).To(Equal(string(secret().Data[issuesv1alpha1.WebhookSecretKey])))
+		Expect(meta.IsStatusConditionTrue(webhookObject.Status.Conditions, ReadyCondition)).To(BeTrue())
+		Expect(meta.FindStatusCondition(webhookObject.Status.Conditions, DeliveriesHealthyCondition).Reason).To(Equal("NoDeliveries"))
+
+		delete(hooks.hooks, 1)
+		Expect(reconcile().Status.HookID).To(Equal(int64(2)))
+	})
+
+	It("rotates the secret once the rotation interval passed, keeping the previous one", func() {
+		reconcile()
+		original := secret()
+		original.Annotations[webhookRotatedAtAnnotation] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+		Expect(reconciler.Update(context.Background(), original)).To(Succeed())
+
+		webhookObject := reconcile()
+		rotated := secret()
+		Expect(rotated.Data[issuesv1alpha1.WebhookPreviousSecretKey]).To(Equal(original.Data[issuesv1alpha1.WebhookSecretKey]))
+		Expect(rotated.Data[issuesv1alpha1.WebhookSecretKey]).NotTo(Equal(original.Data[issuesv1alpha1.WebhookSecretKey]))
+		Expect(hooks.secrets[1]).To(Equal(string(rotated.Data[issuesv1alpha1.WebhookSecretKey])))
+		Expect(webhookObject.Status.SecretRotatedAt.Time).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("reports the health of the recent deliveries", func() {
+		reconcile()
+		hooks.deliveries = []*git.HookDelivery{
+			{ID: 3, Event: "issues", StatusCode: 503, Status: "Invalid HTTP Response: 503"},
+			{ID: 2, Event: "issues", StatusCode: 202, Status: "OK"},
+		}
+		webhookObject := reconcile()
+		Expect(webhookObject.Status.LastDelivery.ID).To(Equal(int64(3)))
+		Expect(webhookObject.Status.FailedDeliveries).To(Equal(int32(1)))
+		Expect(meta.IsStatusConditionFalse(webhookObject.Status.Conditions, DeliveriesHealthyCondition)).To(BeTrue())
+	})
+
+	It("deletes the upstream webhook before the GithubWebhook goes away", func() {
+		webhookObject := reconcile()
+		Expect(reconciler.Delete(context.Background(), webhookObject)).To(Succeed())
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hooks.hooks).To(BeEmpty())
+		Expect(apierrors.IsNotFound(reconciler.Get(context.Background(), key, webhookObject))).To(BeTrue())
+	})
+})
@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// GitProviderReconciler reconciles a GitProvider object: it reports through the Ready condition whether the
+// provider can serve the GithubIssues of its host.
+type GitProviderReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    *zap.Logger
+	// AuditLog records every Secret read. Nil disables the audit.
+	AuditLog *zap.Logger
+	// Providers is the pool of the GithubIssue reconciler. Deleted providers are dropped from it. Optional.
+	Providers *git.ProviderPool
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=gitproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=gitproviders/status,verbs=get;update;patch
+
+func (r *GitProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("name", req.Name))
+
+	provider := &issuesv1alpha1.GitProvider{}
+	if err := r.Get(ctx, req.NamespacedName, provider); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch provider object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		if r.Providers != nil {
+			r.Providers.Forget(req.Name)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	observed := provider.Status.DeepCopy()
+	status, reason, message := metav1.ConditionTrue, "Configured", fmt.Sprintf("Serving the repositories of %s", provider.Spec.Host)
+	if _, ok := git.LookupProvider(string(provider.Spec.Type)); provider.Spec.Type != "" && !ok {
+		status, reason, message = metav1.ConditionFalse, "UnsupportedType",
+			fmt.Sprintf("Provider type %s is not supported yet", provider.Spec.Type)
+	} else if config, err := providerConfig(ctx, r.Client, r.AuditLog, provider); err != nil {
+		status, reason, message = metav1.ConditionFalse, "CredentialsUnavailable", err.Error()
+	} else if _, err := git.FixedProxy(config.ProxyURL); config.ProxyURL != "" && err != nil {
+		status, reason, message = metav1.ConditionFalse, "InvalidProxy", err.Error()
+	} else if _, err := git.ProviderTransport(config); err != nil {
+		status, reason, message = metav1.ConditionFalse, "InvalidTLS", err.Error()
+	}
+	if status == metav1.ConditionFalse {
+		log.Warn("GitProvider is not usable", zap.String("reason", reason), zap.String("message", message))
+	}
+
+	meta.SetStatusCondition(&provider.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: provider.Generation,
+	})
+	if equality.Semantic.DeepEqual(observed, &provider.Status) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Status().Update(ctx, provider); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GitProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.GitProvider{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("gitprovider").
+		Complete(r)
+}
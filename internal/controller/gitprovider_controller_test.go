@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("GitProvider controller", func() {
+	var (
+		c        client.Client
+		provider *issuesv1alpha1.GitProvider
+		pool     *git.ProviderPool
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		provider = &issuesv1alpha1.GitProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "enterprise"},
+			Spec: issuesv1alpha1.GitProviderSpec{
+				Host:            "github.example.com",
+				Type:            issuesv1alpha1.GitProviderGitHub,
+				AuthSecretRef:   issuesv1alpha1.SecretKeyReference{Namespace: "operator", Name: "token", Key: "token"},
+				RequestsPerHour: 3600,
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "operator"},
+			Data:       map[string][]byte{"token": []byte("ghp_enterprise")},
+		}
+		c = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(provider, secret).WithStatusSubresource(provider).Build()
+		pool = &git.ProviderPool{}
+	})
+
+	reconcileProvider := func() *issuesv1alpha1.GitProvider {
+		reconciler := &GitProviderReconciler{Client: c, Log: zap.NewNop(), Providers: pool}
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: provider.Name}})
+		Expect(err).NotTo(HaveOccurred())
+		stored := &issuesv1alpha1.GitProvider{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: provider.Name}, stored)).To(Succeed())
+		return stored
+	}
+
+	It("reports a usable provider as ready", func() {
+		stored := reconcileProvider()
+		Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, ReadyCondition)).To(BeTrue())
+	})
+
+	It("reports unsupported types and unreadable credentials", func() {
+		stored := reconcileProvider()
+		stored.Spec.Type = issuesv1alpha1.GitProviderGitLab
+		Expect(c.Update(context.Background(), stored)).To(Succeed())
+		stored = reconcileProvider()
+		Expect(meta.FindStatusCondition(stored.Status.Conditions, ReadyCondition).Reason).To(Equal("UnsupportedType"))
+
+		stored.Spec.Type = issuesv1alpha1.GitProviderGitHub
+		stored.Spec.AuthSecretRef.Key = "missing"
+		Expect(c.Update(context.Background(), stored)).To(Succeed())
+		Expect(meta.FindStatusCondition(reconcileProvider().Status.Conditions, ReadyCondition).Reason).To(Equal("CredentialsUnavailable"))
+	})
+
+	It("reports a proxy URL it can't use", func() {
+		provider.Spec.ProxyURL = "ftp://proxy.example.com"
+		Expect(c.Update(context.Background(), provider)).To(Succeed())
+		stored := reconcileProvider()
+		Expect(meta.FindStatusCondition(stored.Status.Conditions, ReadyCondition).Reason).To(Equal("InvalidProxy"))
+
+		stored.Spec.ProxyURL = "http://proxy.example.com:3128"
+		Expect(c.Update(context.Background(), stored)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(reconcileProvider().Status.Conditions, ReadyCondition)).To(BeTrue())
+	})
+
+	It("serves the issues of its host only", func() {
+		reconciler := &GithubIssueReconciler{Client: c, Log: zap.NewNop(), Providers: pool}
+		issueClient, ok, err := reconciler.providerIssueClient(context.Background(), "https://GitHub.example.com/org/repo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		again, _, err := reconciler.providerIssueClient(context.Background(), "https://github.example.com/org/other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(issueClient))
+
+		_, ok, err = reconciler.providerIssueClient(context.Background(), "https://github.com/org/repo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("serves SourceHut trackers with the SourceHut client", func() {
+		provider.Spec.Host, provider.Spec.Type = "todo.example.org", issuesv1alpha1.GitProviderSourceHut
+		Expect(c.Update(context.Background(), provider)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(reconcileProvider().Status.Conditions, ReadyCondition)).To(BeTrue())
+
+		reconciler := &GithubIssueReconciler{Client: c, Log: zap.NewNop(), Providers: pool}
+		issueClient, ok, err := reconciler.providerIssueClient(context.Background(), "https://todo.example.org/~owner/tracker")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(issueClient).To(BeAssignableToTypeOf(&git.SourceHutIssueClient{}))
+		Expect(issueClient.(*git.SourceHutIssueClient).GraphQL.Endpoint).To(Equal("https://todo.example.org/query"))
+	})
+})
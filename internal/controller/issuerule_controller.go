@@ -0,0 +1,447 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// IssueRuleLabel is set on the GithubIssues filed by an IssueRule.
+	IssueRuleLabel = "issues.dana.io/issue-rule"
+	// IssueRuleSubjectAnnotation records the object a GithubIssue was filed for, as Kind/name.
+	IssueRuleSubjectAnnotation = "issues.dana.io/rule-subject"
+
+	defaultIssueRuleTitle = "{{.Kind}} {{.Name}}: {{.Reason}}"
+	// issueRuleResync is how often the matches are evaluated again, to resolve Event matches and to follow
+	// the conditions of kinds the rule can't watch.
+	issueRuleResync = time.Minute
+)
+
+// IssueRuleReconciler reconciles an IssueRule object: it files a GithubIssue for every match lasting longer
+// than spec.for and deletes the GithubIssues of resolved matches.
+type IssueRuleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    *zap.Logger
+}
+
+// issueRuleMatch is an object matched by a rule, and the data its issue title and description are rendered from.
+type issueRuleMatch struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Reason    string
+	Message   string
+	Since     time.Time
+}
+
+func (m *issueRuleMatch) key() string {
+	return m.Kind + "/" + m.Name + "/" + m.Reason
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=issuerules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=issuerules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+
+func (r *IssueRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	rule := &issuesv1alpha1.IssueRule{}
+	if err := r.Get(ctx, req.NamespacedName, rule); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch issue rule object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	// The GithubIssues are owned by the rule: the garbage collector deletes them, and their finalizers close the issues.
+	if !rule.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	observed := rule.Status.DeepCopy()
+	now := time.Now()
+	matches, err := r.matches(ctx, rule, now)
+	if err != nil {
+		log.Warn("Failed to evaluate issue rule", zap.Error(err))
+		r.setReadyCondition(rule, metav1.ConditionFalse, "InvalidRule", err.Error())
+		return ctrl.Result{RequeueAfter: issueRuleResync}, r.updateStatus(ctx, rule, observed)
+	}
+
+	var existing issuesv1alpha1.GithubIssueList
+	if err := r.List(ctx, &existing, client.InNamespace(rule.Namespace), client.MatchingLabels{IssueRuleLabel: rule.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list issues of issue rule: %v", err)
+	}
+	filed := map[string]bool{}
+	for _, issueObject := range existing.Items {
+		filed[issueObject.Name] = true
+	}
+
+	requeueAfter := issueRuleResync
+	desired := map[string]bool{}
+	statuses := make([]issuesv1alpha1.IssueRuleMatch, 0, len(matches))
+	for _, match := range matches {
+		status := issuesv1alpha1.IssueRuleMatch{Kind: match.Kind, Name: match.Name, Reason: match.Reason, Since: metav1.NewTime(match.Since)}
+		name := issueRuleIssueName(rule.Name, match)
+		if wait := match.Since.Add(rule.Spec.For.Duration).Sub(now); wait > 0 && !filed[name] {
+			requeueAfter = min(requeueAfter, wait)
+		} else if len(desired) < int(max(rule.Spec.MaxIssues, 1)) {
+			if err := r.applyIssue(ctx, rule, name, match); err != nil {
+				r.setReadyCondition(rule, metav1.ConditionFalse, "IssueFailed", err.Error())
+				if statusErr := r.updateStatus(ctx, rule, observed); statusErr != nil {
+					log.Error("Failed to update issue rule status", zap.Error(statusErr))
+				}
+				return ctrl.Result{}, err
+			}
+			desired[name] = true
+			status.Issue = name
+		}
+		statuses = append(statuses, status)
+	}
+	if rule.Spec.CloseWhenResolved == nil || *rule.Spec.CloseWhenResolved {
+		if err := r.pruneIssues(ctx, rule, existing.Items, desired); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	rule.Status.Matches = statuses
+	rule.Status.FiledIssues = int32(len(desired))
+	if len(matches) > len(desired) && len(desired) == int(max(rule.Spec.MaxIssues, 1)) {
+		r.setReadyCondition(rule, metav1.ConditionFalse, "MaxIssuesReached",
+			fmt.Sprintf("%d matches, only %d issues filed", len(matches), len(desired)))
+	} else {
+		r.setReadyCondition(rule, metav1.ConditionTrue, "Evaluated",
+			fmt.Sprintf("%d matches, %d issues filed", len(matches), len(desired)))
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, r.updateStatus(ctx, rule, observed)
+}
+
+// matches returns the current matches of the rule, oldest first.
+func (r *IssueRuleReconciler) matches(ctx context.Context, rule *issuesv1alpha1.IssueRule, now time.Time) ([]*issueRuleMatch, error) {
+	var matches []*issueRuleMatch
+	var err error
+	switch {
+	case rule.Spec.Event != nil:
+		matches, err = r.eventMatches(ctx, rule, now)
+	case rule.Spec.Condition != nil:
+		matches, err = r.conditionMatches(ctx, rule)
+	default:
+		return nil, fmt.Errorf("one of event and condition must be set")
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].Since.Equal(matches[j].Since) {
+			return matches[i].Since.Before(matches[j].Since)
+		}
+		return matches[i].key() < matches[j].key()
+	})
+	return matches, nil
+}
+
+// eventMatches groups the matching Events by involved object and reason. A group is a match from its first
+// Event until spec.resolveAfter after its last one.
+func (r *IssueRuleReconciler) eventMatches(ctx context.Context, rule *issuesv1alpha1.IssueRule, now time.Time) ([]*issueRuleMatch, error) {
+	match := rule.Spec.Event
+	var pattern *regexp.Regexp
+	if match.MessagePattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(match.MessagePattern); err != nil {
+			return nil, fmt.Errorf("invalid messagePattern: %v", err)
+		}
+	}
+	eventType := match.Type
+	if eventType == "" {
+		eventType = corev1.EventTypeWarning
+	}
+
+	var events corev1.EventList
+	if err := r.List(ctx, &events, client.InNamespace(rule.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list events: %v", err)
+	}
+	groups := map[string]*issueRuleMatch{}
+	lastSeen := map[string]time.Time{}
+	for _, event := range events.Items {
+		if event.Type != eventType || !slices.Contains(match.Reasons, event.Reason) {
+			continue
+		}
+		if match.InvolvedObjectKind != "" && event.InvolvedObject.Kind != match.InvolvedObjectKind {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(event.Message) {
+			continue
+		}
+		first, last := eventTimes(&event)
+		candidate := &issueRuleMatch{
+			Kind:      event.InvolvedObject.Kind,
+			Name:      event.InvolvedObject.Name,
+			Namespace: rule.Namespace,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			Since:     first,
+		}
+		key := candidate.key()
+		group, ok := groups[key]
+		if !ok {
+			groups[key] = candidate
+			lastSeen[key] = last
+			continue
+		}
+		if first.Before(group.Since) {
+			group.Since = first
+		}
+		if last.After(lastSeen[key]) {
+			group.Message = event.Message
+			lastSeen[key] = last
+		}
+	}
+
+	resolveAfter := rule.Spec.ResolveAfter.Duration
+	if resolveAfter <= 0 {
+		resolveAfter = 15 * time.Minute
+	}
+	var matches []*issueRuleMatch
+	for key, group := range groups {
+		if now.Sub(lastSeen[key]) < resolveAfter {
+			matches = append(matches, group)
+		}
+	}
+	return matches, nil
+}
+
+// eventTimes returns when an Event was first and last seen, whichever API wrote it.
+func eventTimes(event *corev1.Event) (time.Time, time.Time) {
+	first, last := event.FirstTimestamp.Time, event.LastTimestamp.Time
+	if first.IsZero() {
+		first = event.EventTime.Time
+	}
+	if first.IsZero() {
+		first = event.CreationTimestamp.Time
+	}
+	if event.Series != nil && event.Series.LastObservedTime.After(last) {
+		last = event.Series.LastObservedTime.Time
+	}
+	if last.IsZero() {
+		last = first
+	}
+	return first, last
+}
+
+// conditionMatches returns the selected objects whose condition has the status of the rule, each matching
+// since the last transition of the condition.
+func (r *IssueRuleReconciler) conditionMatches(ctx context.Context, rule *issuesv1alpha1.IssueRule) ([]*issueRuleMatch, error) {
+	match := rule.Spec.Condition
+	gv, err := schema.ParseGroupVersion(match.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion: %v", err)
+	}
+	options := []client.ListOption{client.InNamespace(rule.Namespace)}
+	if match.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(match.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %v", err)
+		}
+		options = append(options, client.MatchingLabelsSelector{Selector: selector})
+	}
+	objects := &unstructured.UnstructuredList{}
+	objects.SetGroupVersionKind(gv.WithKind(match.Kind + "List"))
+	if err := r.List(ctx, objects, options...); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", match.Kind, err)
+	}
+
+	status := match.Status
+	if status == "" {
+		status = metav1.ConditionFalse
+	}
+	var matches []*issueRuleMatch
+	for _, object := range objects.Items {
+		conditions, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+		for _, value := range conditions {
+			condition, ok := value.(map[string]interface{})
+			if !ok || condition["type"] != match.Type || condition["status"] != string(status) {
+				continue
+			}
+			since := object.GetCreationTimestamp().Time
+			if transition, ok := condition["lastTransitionTime"].(string); ok {
+				if parsed, err := time.Parse(time.RFC3339, transition); err == nil {
+					since = parsed
+				}
+			}
+			reason, _ := condition["reason"].(string)
+			message, _ := condition["message"].(string)
+			matches = append(matches, &issueRuleMatch{
+				Kind:      match.Kind,
+				Name:      object.GetName(),
+				Namespace: rule.Namespace,
+				Reason:    reason,
+				Message:   message,
+				Since:     since,
+			})
+		}
+	}
+	return matches, nil
+}
+
+// applyIssue creates or updates the GithubIssue of a match from the template of the rule.
+func (r *IssueRuleReconciler) applyIssue(ctx context.Context, rule *issuesv1alpha1.IssueRule, name string, match *issueRuleMatch) error {
+	titleTemplate := rule.Spec.Template.Spec.Title
+	if titleTemplate == "" {
+		titleTemplate = defaultIssueRuleTitle
+	}
+	title, err := renderBody(titleTemplate, match)
+	if err != nil {
+		return fmt.Errorf("failed to render the title of issue %s: %v", name, err)
+	}
+	description, err := renderBody(rule.Spec.Template.Spec.Description, match)
+	if err != nil {
+		return fmt.Errorf("failed to render the description of issue %s: %v", name, err)
+	}
+
+	issueObject := &issuesv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: rule.Namespace},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, issueObject, func() error {
+		if issueObject.Labels == nil {
+			issueObject.Labels = map[string]string{}
+		}
+		for key, value := range rule.Spec.Template.Metadata.Labels {
+			issueObject.Labels[key] = value
+		}
+		issueObject.Labels[IssueRuleLabel] = rule.Name
+		if issueObject.Annotations == nil {
+			issueObject.Annotations = map[string]string{}
+		}
+		for key, value := range rule.Spec.Template.Metadata.Annotations {
+			issueObject.Annotations[key] = value
+		}
+		issueObject.Annotations[IssueRuleSubjectAnnotation] = match.Kind + "/" + match.Name
+		spec := *rule.Spec.Template.Spec.DeepCopy()
+		spec.Repo = rule.Spec.Repo
+		spec.RepositoryRef = rule.Spec.RepositoryRef.DeepCopy()
+		spec.Title = title
+		spec.Description = description
+		issueObject.Spec = spec
+		return controllerutil.SetControllerReference(rule, issueObject, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply issue %s: %v", name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Log.Info("Applied issue of issue rule", zap.String("issueRule", rule.Name),
+			zap.String("githubIssue", name), zap.String("operation", string(result)))
+	}
+	return nil
+}
+
+// pruneIssues deletes the GithubIssues of the rule whose match is resolved.
+func (r *IssueRuleReconciler) pruneIssues(ctx context.Context, rule *issuesv1alpha1.IssueRule, existing []issuesv1alpha1.GithubIssue, desired map[string]bool) error {
+	for i := range existing {
+		issueObject := &existing[i]
+		if desired[issueObject.Name] || !metav1.IsControlledBy(issueObject, rule) {
+			continue
+		}
+		if err := r.Delete(ctx, issueObject); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to prune issue %s: %v", issueObject.Name, err)
+		}
+		r.Log.Info("Pruned issue of a resolved match", zap.String("issueRule", rule.Name),
+			zap.String("githubIssue", issueObject.Name))
+	}
+	return nil
+}
+
+func (r *IssueRuleReconciler) setReadyCondition(rule *issuesv1alpha1.IssueRule, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: rule.Generation,
+	})
+}
+
+// updateStatus writes the status of the rule when it differs from observed.
+func (r *IssueRuleReconciler) updateStatus(ctx context.Context, rule *issuesv1alpha1.IssueRule, observed *issuesv1alpha1.IssueRuleStatus) error {
+	if equality.Semantic.DeepEqual(observed, &rule.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, rule); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// issueRuleIssueName derives the name of the GithubIssue of a match from the rule name, the matched object
+// and a hash telling apart the reasons of the same object.
+func issueRuleIssueName(ruleName string, match *issueRuleMatch) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(match.key()))
+	return issueSetIssueName(ruleName, fmt.Sprintf("%s-%s-%08x", match.Kind, match.Name, hash.Sum32()))
+}
+
+// eventRules enqueues the IssueRules matching Events in the namespace of a changed Event.
+func (r *IssueRuleReconciler) eventRules(ctx context.Context, obj client.Object) []reconcile.Request {
+	var rules issuesv1alpha1.IssueRuleList
+	if err := r.List(ctx, &rules, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error("Failed to list issue rules matching a changed event", zap.Error(err))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, rule := range rules.Items {
+		if rule.Spec.Event != nil {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: rule.Namespace, Name: rule.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager. Events are watched; the conditions of other
+// kinds are evaluated again every minute.
+func (r *IssueRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.IssueRule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&issuesv1alpha1.GithubIssue{}).
+		Watches(&corev1.Event{}, handler.EnqueueRequestsFromMapFunc(r.eventRules)).
+		Named("issuerule").
+		Complete(r)
+}
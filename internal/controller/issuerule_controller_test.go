@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("IssueRule controller", func() {
+	const repo = "https://github.com/example-org/example-repo"
+	var (
+		reconciler *IssueRuleReconciler
+		testScheme *runtime.Scheme
+		key        = types.NamespacedName{Name: "crash-loops", Namespace: "default"}
+	)
+
+	backOff := func(name string, first, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name + ".backoff", Namespace: key.Namespace},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: name, Namespace: key.Namespace},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+			FirstTimestamp: metav1.NewTime(first),
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+
+	build := func(rule *issuesv1alpha1.IssueRule, objects ...client.Object) {
+		objects = append(objects, rule)
+		reconciler = &IssueRuleReconciler{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objects...).
+				WithStatusSubresource(rule).Build(),
+			Scheme: testScheme,
+			Log:    zap.NewNop(),
+		}
+	}
+
+	reconcile := func() (*issuesv1alpha1.IssueRule, ctrl.Result) {
+		result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		rule := &issuesv1alpha1.IssueRule{}
+		Expect(reconciler.Get(context.Background(), key, rule)).To(Succeed())
+		return rule, result
+	}
+
+	issues := func() []issuesv1alpha1.GithubIssue {
+		var list issuesv1alpha1.GithubIssueList
+		Expect(reconciler.List(context.Background(), &list, client.MatchingLabels{IssueRuleLabel: key.Name})).To(Succeed())
+		return list.Items
+	}
+
+	BeforeEach(func() {
+		testScheme = runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(testScheme)).To(Succeed())
+	})
+
+	eventRule := func() *issuesv1alpha1.IssueRule {
+		return &issuesv1alpha1.IssueRule{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: issuesv1alpha1.IssueRuleSpec{
+				Event:        &issuesv1alpha1.EventMatch{Reasons: []string{"BackOff"}, InvolvedObjectKind: "Pod"},
+				For:          metav1.Duration{Duration: 10 * time.Minute},
+				ResolveAfter: metav1.Duration{Duration: 15 * time.Minute},
+				MaxIssues:    10,
+				Repo:         repo,
+				Template: issuesv1alpha1.GithubIssueTemplate{
+					Spec: issuesv1alpha1.GithubIssueSpec{Description: "{{.Name}}: {{.Message}}", Labels: []string{"bug"}},
+				},
+			},
+		}
+	}
+
+	It("files an issue for an object crash looping longer than spec.for and waits for the others", func() {
+		now := time.Now()
+		build(eventRule(),
+			backOff("api-0", now.Add(-20*time.Minute), now.Add(-time.Minute)),
+			backOff("worker-0", now.Add(-2*time.Minute), now),
+			backOff("old-0", now.Add(-2*time.Hour), now.Add(-time.Hour)))
+
+		rule, result := reconcile()
+		Expect(rule.Status.Matches).To(HaveLen(2))
+		Expect(rule.Status.Matches[1].Name).To(Equal("worker-0"))
+		Expect(rule.Status.Matches[1].Issue).To(BeEmpty())
+		Expect(rule.Status.FiledIssues).To(Equal(int32(1)))
+		Expect(meta.IsStatusConditionTrue(rule.Status.Conditions, ReadyCondition)).To(BeTrue())
+		Expect(result.RequeueAfter).To(Equal(issueRuleResync))
+
+		filed := issues()
+		Expect(filed).To(HaveLen(1))
+		Expect(filed[0].Spec.Repo).To(Equal(repo))
+		Expect(filed[0].Spec.Title).To(Equal("Pod api-0: BackOff"))
+		Expect(filed[0].Spec.Description).To(Equal("api-0: Back-off restarting failed container"))
+		Expect(filed[0].Annotations).To(HaveKeyWithValue(IssueRuleSubjectAnnotation, "Pod/api-0"))
+		Expect(metav1.IsControlledBy(&filed[0], rule)).To(BeTrue())
+	})
+
+	It("deletes the issue once the match is resolved", func() {
+		now := time.Now()
+		event := backOff("api-0", now.Add(-20*time.Minute), now)
+		build(eventRule(), event)
+		reconcile()
+		Expect(issues()).To(HaveLen(1))
+
+		event.LastTimestamp = metav1.NewTime(now.Add(-time.Hour))
+		Expect(reconciler.Update(context.Background(), event)).To(Succeed())
+		rule, _ := reconcile()
+		Expect(rule.Status.Matches).To(BeEmpty())
+		Expect(issues()).To(BeEmpty())
+	})
+
+	It("files an issue for an object whose condition has the status of the rule", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: key.Namespace, Labels: map[string]string{"team": "platform"}},
+			Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{
+				Type:               appsv1.DeploymentAvailable,
+				Status:             corev1.ConditionFalse,
+				Reason:             "MinimumReplicasUnavailable",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			}}},
+		}
+		rule := eventRule()
+		rule.Spec.Event = nil
+		rule.Spec.Condition = &issuesv1alpha1.ConditionMatch{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+			Type:       "Available",
+			Status:     metav1.ConditionFalse,
+		}
+		build(rule, deployment)
+
+		reconcile()
+		filed := issues()
+		Expect(filed).To(HaveLen(1))
+		Expect(filed[0].Spec.Title).To(Equal("Deployment api: MinimumReplicasUnavailable"))
+	})
+})
@@ -5,7 +5,19 @@ import (
 	"fmt"
 	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
 	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/labels"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/lifecycle"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/triage"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"slices"
+	"strings"
+	"time"
 )
 
 // searchForIssue checks if the generic Issue list contains an issue matching the specified CRD.
@@ -18,8 +30,15 @@ func searchForIssue(issueTitle string, platformIssues []*git.Issue) *git.Issue {
 	return nil
 }
 
-// checkIfOpen checks if the issue is open and returns the corresponding condition
-func checkIfOpen(platformIssue *git.Issue) (string, metav1.ConditionStatus, string, string, bool) {
+// Upstream states and state reasons the operator knows how to report.
+var (
+	knownStates       = []string{"open", "closed"}
+	knownStateReasons = []string{"", "completed", "not_planned", "reopened"}
+)
+
+// checkIfOpen checks if the issue is open and returns the corresponding condition.
+// A state or state reason the operator does not know is reported with unknownStatus and counted in a metric.
+func checkIfOpen(platformIssue *git.Issue, unknownStatus metav1.ConditionStatus) (string, metav1.ConditionStatus, string, string, bool) {
 	if platformIssue == nil {
 		return "", "", "", "", false
 	}
@@ -30,7 +49,18 @@ func checkIfOpen(platformIssue *git.Issue) (string, metav1.ConditionStatus, stri
 	reason := "IssueIsOpen"
 	message := "Issue is open"
 
-	if state != "open" {
+	switch {
+	case !slices.Contains(knownStates, state):
+		metrics.UnknownUpstreamStates.WithLabelValues("state", state).Inc()
+		conditionStatus = unknownStatus
+		reason = "UnknownState"
+		message = fmt.Sprintf("GitHub reported an unknown issue state %q", state)
+	case !slices.Contains(knownStateReasons, platformIssue.StateReason):
+		metrics.UnknownUpstreamStates.WithLabelValues("state_reason", platformIssue.StateReason).Inc()
+		conditionStatus = unknownStatus
+		reason = "UnknownStateReason"
+		message = fmt.Sprintf("GitHub reported issue state %q with an unknown state reason %q", state, platformIssue.StateReason)
+	case state != "open":
 		conditionStatus = metav1.ConditionFalse
 		reason = fmt.Sprintf("IssueIs%s", state)
 		message = fmt.Sprintf("Issue is %s", state)
@@ -59,40 +89,494 @@ func checkForPR(platformIssue *git.Issue) (string, metav1.ConditionStatus, strin
 	return conditionType, conditionStatus, reason, message, true
 }
 
-// CloseIssue closes the issue on Git Repo.
-func (r *GithubIssueReconciler) CloseIssue(ctx context.Context, owner, repo string, platformIssue *git.Issue) error {
+// CloseIssue closes the issue on Git Repo, posting spec.closeComment first. The ID of the posted comment is
+// written to status.closeCommentID before closing, so retrying a failed close doesn't post it twice.
+func (r *GithubIssueReconciler) CloseIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, platformIssue *git.Issue) error {
 	if platformIssue == nil {
 		return fmt.Errorf("cannot close issue: issue is nil")
 	}
 
-	closedIssue, err := r.IssueClient.Close(ctx, owner, repo, platformIssue.Number)
+	if comment := issueObject.Spec.CloseComment; comment != "" && platformIssue.State == "open" && issueObject.Status.CloseCommentID == 0 {
+		id, err := r.issueClient(ctx).Comment(ctx, owner, repo, platformIssue.Number, comment)
+		if err != nil {
+			return fmt.Errorf("failed to post close comment: %v", err)
+		}
+		issueObject.Status.CloseCommentID = id
+		if err := r.updateStatus(ctx, issueObject); err != nil {
+			return fmt.Errorf("failed to update status: %v", err)
+		}
+	}
+
+	closedIssue, err := r.issueClient(ctx).Close(ctx, owner, repo, platformIssue.Number)
 	if err != nil {
 		return fmt.Errorf("failed to close issue: %v", err)
 	}
+	r.publish(ctx, lifecycle.Closed, issueObject, closedIssue)
 
-	r.Log.Info(fmt.Sprintf("Closed issue: %s", closedIssue.URL))
+	r.logger(ctx).Info(fmt.Sprintf("Closed issue: %s", closedIssue.URL))
 	return nil
 }
 
-// CreateIssue creates a new issue in the repository.
-func (r *GithubIssueReconciler) CreateIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) error {
-	createdIssue, err := r.IssueClient.Create(ctx, owner, repo, issueObject.Spec.Title, issueObject.Spec.Description)
+// CreateIssue creates a new issue in the repository with every spec field set in a single call. The hash of
+// the body is set on the status, which is left for the caller to write.
+func (r *GithubIssueReconciler) CreateIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (*git.Issue, error) {
+	desired, err := r.desiredIssue(ctx, owner, repo, issueObject)
+	if err != nil {
+		return nil, err
+	}
+	// Apply the initial triage label right away instead of in a follow-up call.
+	if r.TriagePolicy != nil {
+		policy, err := r.triagePolicy(ctx, owner, repo, issueObject)
+		if err != nil {
+			return nil, err
+		}
+		initial := &git.Issue{State: "open", Labels: desired.Labels}
+		label := triage.Evaluate(policy, issueObject.CreationTimestamp.Time, initial, time.Now())
+		if label != "" && len(desired.Labels) < git.MaxLabels {
+			desired.Labels = append(desired.Labels, label)
+		}
+	}
+
+	stopTimer := timePhase(ctx, phaseCreate)
+	createdIssue, err := r.issueClient(ctx).Create(ctx, owner, repo, desired)
+	stopTimer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	issueObject.Status.AppliedDescriptionHash = bodyHash(desired.Body)
+
+	r.logger(ctx).Info(fmt.Sprintf("Created issue: %s", createdIssue.URL))
+	return createdIssue, nil
+}
+
+// desiredIssue builds the upstream issue fields requested by the spec.
+func (r *GithubIssueReconciler) desiredIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error) {
+	milestone, err := r.resolveMilestone(ctx, owner, repo, issueObject)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := r.allowedLabels(ctx, owner, repo, issueObject)
+	if err != nil {
+		return nil, err
+	}
+
+	title, description, err := r.renderIssue(ctx, issueObject)
+	if err != nil {
+		return nil, err
+	}
+	labels = r.withManagedLabels(issueObject, labels)
+
+	assignees := issueObject.Spec.Assignees
+	var truncated []string
+	if len(labels) > git.MaxLabels {
+		truncated = append(truncated, fmt.Sprintf("%d labels dropped, GitHub allows %d", len(labels)-git.MaxLabels, git.MaxLabels))
+		labels = labels[:git.MaxLabels]
+	}
+	if len(assignees) > git.MaxAssignees {
+		truncated = append(truncated, fmt.Sprintf("%d assignees dropped, GitHub allows %d", len(assignees)-git.MaxAssignees, git.MaxAssignees))
+		assignees = assignees[:git.MaxAssignees]
+	}
+	if err := r.setLimitsCondition(ctx, issueObject, truncated); err != nil {
+		return nil, err
+	}
+
+	return &git.DesiredIssue{
+		Title:     title,
+		Body:      description,
+		Labels:    labels,
+		Assignees: assignees,
+		Milestone: milestone,
+		Type:      issueObject.Spec.IssueType,
+	}, nil
+}
+
+// renderIssue renders the upstream title and body of the issue, the body carrying the ownership marker.
+func (r *GithubIssueReconciler) renderIssue(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (string, string, error) {
+	description, err := r.resolveDescription(ctx, issueObject)
+	if err != nil {
+		return "", "", err
+	}
+	if description, err = r.localizeDescription(ctx, issueObject, description); err != nil {
+		return "", "", err
+	}
+	if description, err = r.renderTemplate(ctx, issueObject, description); err != nil {
+		return "", "", err
+	}
+	title, err := r.issueTitle(ctx, issueObject)
 	if err != nil {
-		return fmt.Errorf("failed to create issue: %v", err)
+		return "", "", err
 	}
+	return title, git.WithMarker(withDueDate(description, issueObject), objectKey(issueObject)), nil
+}
 
-	r.Log.Info(fmt.Sprintf("Created issue: %s", createdIssue.URL))
+// withManagedLabels adds the priority and estimate labels to the spec labels.
+func (r *GithubIssueReconciler) withManagedLabels(issueObject *issuesv1alpha1.GithubIssue, labels []string) []string {
+	if label := r.priorityLabels().Label(issueObject.Spec.Priority); label != "" && !slices.Contains(labels, label) {
+		labels = append(labels, label)
+	}
+	if estimate := issueObject.Spec.Estimate; estimate != nil && !slices.Contains(labels, git.EstimateLabel(*estimate)) {
+		labels = append(labels, git.EstimateLabel(*estimate))
+	}
+	return labels
+}
+
+// Preview renders the issue the operator would send to GitHub without calling GitHub: the milestone is
+// left unresolved and the spec labels are not checked against the repository labels.
+func (r *GithubIssueReconciler) Preview(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error) {
+	title, body, err := r.renderIssue(ctx, issueObject)
+	if err != nil {
+		return nil, err
+	}
+	labels := r.withManagedLabels(issueObject, slices.Clone(issueObject.Spec.Labels))
+	if len(labels) > git.MaxLabels {
+		labels = labels[:git.MaxLabels]
+	}
+	return &git.DesiredIssue{
+		Title:     title,
+		Body:      body,
+		Labels:    labels,
+		Assignees: issueObject.Spec.Assignees,
+		Type:      issueObject.Spec.IssueType,
+	}, nil
+}
+
+// setLimitsCondition reports through the LimitsExceeded condition whether the spec had to be truncated
+// to fit GitHub limits. The condition is only written once a truncation happened.
+func (r *GithubIssueReconciler) setLimitsCondition(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, truncated []string) error {
+	condition := metav1.Condition{
+		Type:               LimitsExceededCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WithinLimits",
+		Message:            "Spec is within GitHub limits",
+		ObservedGeneration: issueObject.Generation,
+	}
+	if len(truncated) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Truncated"
+		condition.Message = strings.Join(truncated, "; ")
+	} else if meta.FindStatusCondition(issueObject.Status.Conditions, LimitsExceededCondition) == nil {
+		return nil
+	}
+
+	if !meta.SetStatusCondition(&issueObject.Status.Conditions, condition) {
+		return nil
+	}
+	if len(truncated) > 0 {
+		r.logger(ctx).Warn("Spec exceeds GitHub limits, truncating", zap.Strings("truncated", truncated))
+		r.Recorder.Event(issueObject, corev1.EventTypeWarning, LimitsExceededCondition, condition.Message)
+	}
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
 	return nil
 }
 
-// EditIssue edits the description of an existing issue in the repository.
-func (r *GithubIssueReconciler) EditIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issueNumber int) error {
-	editedIssue, err := r.IssueClient.Edit(ctx, owner, repo, issueNumber, issueObject.Spec.Description)
+// allowedLabels returns the spec labels the operator may apply. Labels outside the taxonomy are skipped,
+// and so are labels missing from the repository unless the taxonomy lets the operator create them.
+func (r *GithubIssueReconciler) allowedLabels(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) ([]string, error) {
+	if len(issueObject.Spec.Labels) == 0 {
+		return nil, nil
+	}
+
+	var existing []string
+	if !r.LabelTaxonomy.CanCreate() {
+		var err error
+		if existing, err = r.issueClient(ctx).ListLabels(ctx, owner, repo); err != nil {
+			return nil, fmt.Errorf("failed to list repository labels: %v", err)
+		}
+	}
+
+	var allowed, rejected []string
+	for _, label := range issueObject.Spec.Labels {
+		if !r.LabelTaxonomy.Allows(label) || (!r.LabelTaxonomy.CanCreate() && !slices.Contains(existing, label)) {
+			rejected = append(rejected, label)
+			continue
+		}
+		allowed = append(allowed, label)
+	}
+
+	if len(rejected) > 0 {
+		r.logger(ctx).Warn("Skipping labels rejected by the label taxonomy", zap.Strings("labels", rejected))
+		r.Recorder.Eventf(issueObject, corev1.EventTypeWarning, "LabelRejected",
+			"Labels %s are outside the label taxonomy or missing from the repository", strings.Join(rejected, ", "))
+	}
+	return allowed, nil
+}
+
+// resolveMilestone returns the milestone number requested by the spec, looking titles up in the repository
+// and reading the number of a referenced GithubMilestone from its status. It returns 0 when no milestone is requested.
+func (r *GithubIssueReconciler) resolveMilestone(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (int, error) {
+	if ref := issueObject.Spec.MilestoneRef; ref != nil {
+		return r.resolveMilestoneRef(ctx, owner, repo, issueObject.Namespace, ref.Name)
+	}
+	milestone := issueObject.Spec.Milestone
+	if milestone == nil {
+		return 0, nil
+	}
+	if milestone.Type == intstr.Int {
+		return milestone.IntValue(), nil
+	}
+
+	number, err := r.issueClient(ctx).FindMilestone(ctx, owner, repo, milestone.StrVal)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve milestone: %v", err)
+	}
+	return number, nil
+}
+
+// resolveMilestoneRef returns the number of the GithubMilestone name, which must be defined in owner/repo.
+func (r *GithubIssueReconciler) resolveMilestoneRef(ctx context.Context, owner, repo, namespace, name string) (int, error) {
+	milestoneObject := &issuesv1alpha1.GithubMilestone{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, milestoneObject); err != nil {
+		return 0, fmt.Errorf("failed to get GithubMilestone %s: %v", name, err)
+	}
+	milestoneOwner, milestoneRepo, err := git.ParseRepoURL(milestoneObject.Spec.Repo)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.EqualFold(milestoneOwner+"/"+milestoneRepo, owner+"/"+repo) {
+		return 0, fmt.Errorf("GithubMilestone %s is defined in %s/%s, not in %s/%s", name, milestoneOwner, milestoneRepo, owner, repo)
+	}
+	if milestoneObject.Status.Number == 0 {
+		return 0, fmt.Errorf("GithubMilestone %s has not been created yet", name)
+	}
+	return milestoneObject.Status.Number, nil
+}
+
+// EditIssue edits the description, assignees and milestone of an existing issue in the repository
+// and adds the spec labels it is missing, removing the other labels under the Replace label policy.
+func (r *GithubIssueReconciler) EditIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	desired, err := r.desiredIssue(ctx, owner, repo, issueObject)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, label := range desired.Labels {
+		if !slices.Contains(issue.Labels, label) {
+			missing = append(missing, label)
+		}
+	}
+	extra := extraLabels(issueObject, issue, desired)
+	apply, err := r.resolveConflict(ctx, issueObject, issue, desired)
+	if err != nil || !apply {
+		decide(ctx, DecisionSkipped, "Conflict", "The upstream description was edited outside of the operator")
+		return err
+	}
+
+	hasDrifted := drifted(issue, desired, missing) || len(extra) > 0
+	if hasDrifted {
+		r.publish(ctx, lifecycle.Drifted, issueObject, issue)
+		decide(ctx, DecisionUpdated, "Drifted", "Applied the spec to the upstream issue, it had drifted")
+	} else {
+		decide(ctx, DecisionUnchanged, "InSync", "The upstream issue matches the spec")
+	}
+
+	stopTimer := timePhase(ctx, phaseEdit)
+	editedIssue, err := r.issueClient(ctx).Edit(ctx, owner, repo, issue.Number, desired)
+	stopTimer()
 	if err != nil {
 		return fmt.Errorf("failed to edit issue: %v", err)
 	}
 
-	r.Log.Info(fmt.Sprintf("Edited issue: %s", editedIssue.URL))
+	if issueObject.Status.ExternalDescription == "" {
+		if err := r.recordAppliedBody(ctx, issueObject, desired.Body); err != nil {
+			return err
+		}
+		if err := r.clearConflict(ctx, issueObject); err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		if err := r.issueClient(ctx).AddLabels(ctx, owner, repo, issue.Number, missing); err != nil {
+			return fmt.Errorf("failed to add labels: %v", err)
+		}
+	}
+	for _, label := range extra {
+		if err := r.issueClient(ctx).RemoveLabel(ctx, owner, repo, issue.Number, label); err != nil {
+			return fmt.Errorf("failed to remove label %s: %v", label, err)
+		}
+	}
+	if err := r.removeStaleEstimates(ctx, owner, repo, issueObject, issue); err != nil {
+		return err
+	}
+	if err := r.removeStalePriorities(ctx, owner, repo, issueObject, issue); err != nil {
+		return err
+	}
+	if hasDrifted {
+		r.publish(ctx, lifecycle.Synced, issueObject, editedIssue)
+	}
+
+	r.logger(ctx).Info(fmt.Sprintf("Edited issue: %s", editedIssue.URL))
+	return nil
+}
+
+// extraLabels returns the upstream labels the Replace label policy removes: every label outside the desired set
+// except the triage labels, which the triage policy manages on its own.
+func extraLabels(issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue, desired *git.DesiredIssue) []string {
+	if issueObject.Spec.LabelPolicy != issuesv1alpha1.LabelPolicyReplace {
+		return nil
+	}
+	var extra []string
+	for _, label := range issue.Labels {
+		if !slices.Contains(desired.Labels, label) && !slices.Contains(triage.ManagedLabels, label) {
+			extra = append(extra, label)
+		}
+	}
+	return extra
+}
+
+// removeStaleEstimates removes the estimate labels left over from a previous spec.estimate.
+// Clearing spec.estimate leaves the last estimate label in place.
+func (r *GithubIssueReconciler) removeStaleEstimates(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	if issueObject.Spec.Estimate == nil {
+		return nil
+	}
+	current := git.EstimateLabel(*issueObject.Spec.Estimate)
+	for _, label := range issue.Labels {
+		if !strings.HasPrefix(label, git.EstimateLabelPrefix) || label == current {
+			continue
+		}
+		if err := r.issueClient(ctx).RemoveLabel(ctx, owner, repo, issue.Number, label); err != nil {
+			return fmt.Errorf("failed to remove estimate label %s: %v", label, err)
+		}
+	}
+	return nil
+}
+
+// removeStalePriorities removes the labels of priorities other than spec.priority.
+// Clearing spec.priority leaves the last priority label in place.
+func (r *GithubIssueReconciler) removeStalePriorities(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	if issueObject.Spec.Priority == "" {
+		return nil
+	}
+	for _, label := range r.priorityLabels().Stale(issueObject.Spec.Priority, issue.Labels) {
+		if err := r.issueClient(ctx).RemoveLabel(ctx, owner, repo, issue.Number, label); err != nil {
+			return fmt.Errorf("failed to remove priority label %s: %v", label, err)
+		}
+	}
+	return nil
+}
+
+func (r *GithubIssueReconciler) priorityLabels() labels.PriorityLabels {
+	if r.PriorityLabels == nil {
+		return labels.DefaultPriorityLabels
+	}
+	return r.PriorityLabels
+}
+
+// syncLock locks or unlocks the issue conversation to match the spec.
+func (r *GithubIssueReconciler) syncLock(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	spec := issueObject.Spec
+	if issue.Locked == spec.Locked && (!spec.Locked || spec.LockReason == "" || issue.LockReason == spec.LockReason) {
+		return nil
+	}
+
+	if !spec.Locked {
+		if err := r.issueClient(ctx).Unlock(ctx, owner, repo, issue.Number); err != nil {
+			return fmt.Errorf("failed to unlock issue: %v", err)
+		}
+		r.logger(ctx).Info(fmt.Sprintf("Unlocked issue: %s", issue.URL))
+		return nil
+	}
+
+	// Changing the reason of a locked conversation requires unlocking it first.
+	if issue.Locked {
+		if err := r.issueClient(ctx).Unlock(ctx, owner, repo, issue.Number); err != nil {
+			return fmt.Errorf("failed to unlock issue: %v", err)
+		}
+	}
+	if err := r.issueClient(ctx).Lock(ctx, owner, repo, issue.Number, spec.LockReason); err != nil {
+		return fmt.Errorf("failed to lock issue: %v", err)
+	}
+	r.logger(ctx).Info(fmt.Sprintf("Locked issue: %s", issue.URL))
+	return nil
+}
+
+// syncPin pins or unpins the issue to match the spec, only unpinning issues the operator pinned. The pin is
+// compared against the upstream state when the issue client reads it, and against status.pinned otherwise, so
+// a pinned issue isn't pinned again on every reconcile.
+func (r *GithubIssueReconciler) syncPin(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	pinned := issueObject.Spec.Pinned
+	if !pinned && !issueObject.Status.Pinned {
+		return nil
+	}
+
+	upstream := issueObject.Status.Pinned
+	if issue.Pinned != nil {
+		upstream = *issue.Pinned
+	}
+	if upstream != pinned {
+		if err := r.issueClient(ctx).SetPinned(ctx, owner, repo, issue.Number, pinned); err != nil {
+			return fmt.Errorf("failed to sync pin: %v", err)
+		}
+	}
+	if issueObject.Status.Pinned == pinned {
+		return nil
+	}
+
+	issueObject.Status.Pinned = pinned
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	r.logger(ctx).Info("Issue pin state updated", zap.Bool("pinned", pinned))
+	return nil
+}
+
+// syncProjects adds the issue to the spec.projects boards it isn't on yet and reports the result through
+// the ProjectsSynced condition. Boards that fail are retried on the next reconcile.
+func (r *GithubIssueReconciler) syncProjects(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	projects := issueObject.Spec.Projects
+	if len(projects) == 0 && meta.FindStatusCondition(issueObject.Status.Conditions, ProjectsSyncedCondition) == nil {
+		return nil
+	}
+
+	var added, failed []string
+	for _, project := range projects {
+		if slices.Contains(issueObject.Status.Projects, project) {
+			added = append(added, project)
+			continue
+		}
+		if err := r.issueClient(ctx).AddToProject(ctx, owner, repo, issue.Number, project); err != nil {
+			r.logger(ctx).Warn("Failed to add issue to project", zap.String("project", project), zap.Error(err))
+			failed = append(failed, project)
+			continue
+		}
+		r.logger(ctx).Info("Added issue to project", zap.String("project", project))
+		added = append(added, project)
+	}
+
+	condition := metav1.Condition{
+		Type:               ProjectsSyncedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Added",
+		Message:            fmt.Sprintf("Added to %d project(s): %s", len(added), strings.Join(added, ", ")),
+		ObservedGeneration: issueObject.Generation,
+	}
+	if len(projects) == 0 {
+		condition.Reason = "NoProjects"
+		condition.Message = "No projects requested"
+	}
+	if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "AddFailed"
+		condition.Message = fmt.Sprintf("Failed to add to project(s): %s", strings.Join(failed, ", "))
+	}
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, condition)
+	if !slices.Equal(issueObject.Status.Projects, added) {
+		issueObject.Status.Projects = added
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if len(failed) > 0 {
+		r.Recorder.Event(issueObject, corev1.EventTypeWarning, ProjectsSyncedCondition, condition.Message)
+	}
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
 	return nil
 }
 
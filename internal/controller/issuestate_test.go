@@ -0,0 +1,36 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+)
+
+var _ = Describe("upstream issue state", func() {
+	It("reports known states", func() {
+		_, status, reason, _, _ := checkIfOpen(&git.Issue{State: "closed", StateReason: "not_planned"}, metav1.ConditionUnknown)
+		Expect(status).To(Equal(metav1.ConditionFalse))
+		Expect(reason).To(Equal("IssueIsclosed"))
+	})
+
+	It("reports unknown state reasons as Unknown with the raw value and counts them", func() {
+		counter := metrics.UnknownUpstreamStates.WithLabelValues("state_reason", "archived")
+		before := testutil.ToFloat64(counter)
+
+		_, status, reason, message, _ := checkIfOpen(&git.Issue{State: "closed", StateReason: "archived"}, metav1.ConditionUnknown)
+		Expect(status).To(Equal(metav1.ConditionUnknown))
+		Expect(reason).To(Equal("UnknownStateReason"))
+		Expect(message).To(ContainSubstring(`"archived"`))
+		Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+	})
+
+	It("uses the configured status for unknown states", func() {
+		_, status, reason, _, _ := checkIfOpen(&git.Issue{State: "hidden"}, metav1.ConditionFalse)
+		Expect(status).To(Equal(metav1.ConditionFalse))
+		Expect(reason).To(Equal("UnknownState"))
+	})
+})
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeTypeClient records the issue types it is sent on create and edit.
+type fakeTypeClient struct {
+	*fakeMirrorClient
+	types []string
+}
+
+func (f *fakeTypeClient) Create(ctx context.Context, owner, repo string, desired *git.DesiredIssue) (*git.Issue, error) {
+	f.types = append(f.types, desired.Type)
+	return f.fakeMirrorClient.Create(ctx, owner, repo, desired)
+}
+
+func (f *fakeTypeClient) Edit(ctx context.Context, owner, repo string, number int, desired *git.DesiredIssue) (*git.Issue, error) {
+	f.types = append(f.types, desired.Type)
+	return f.fakeMirrorClient.Edit(ctx, owner, repo, number, desired)
+}
+
+var _ = Describe("issue type", func() {
+	It("sets spec.issueType when creating and editing the issue", func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		issueObject := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", Title: "Crash", IssueType: "Bug"},
+		}
+		upstream := &fakeTypeClient{fakeMirrorClient: &fakeMirrorClient{issues: map[int]*git.Issue{}}}
+		reconciler := &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Clients:  &git.Clients{Default: upstream},
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+
+		issue, err := reconciler.CreateIssue(context.Background(), "org", "repo", issueObject)
+		Expect(err).NotTo(HaveOccurred())
+
+		issueObject.Spec.IssueType = "Task"
+		Expect(reconciler.EditIssue(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+		Expect(upstream.types).To(Equal([]string{"Bug", "Task"}))
+	})
+})
@@ -0,0 +1,24 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/triage"
+)
+
+var _ = Describe("label policy", func() {
+	issue := &git.Issue{Labels: []string{"bug", "wontfix", triage.LabelNeedsTriage}}
+	desired := &git.DesiredIssue{Labels: []string{"bug"}}
+
+	It("keeps labels added outside of the spec by default", func() {
+		Expect(extraLabels(&issuesv1alpha1.GithubIssue{}, issue, desired)).To(BeEmpty())
+	})
+
+	It("removes labels added outside of the spec with Replace, except triage labels", func() {
+		issueObject := &issuesv1alpha1.GithubIssue{Spec: issuesv1alpha1.GithubIssueSpec{LabelPolicy: issuesv1alpha1.LabelPolicyReplace}}
+		Expect(extraLabels(issueObject, issue, desired)).To(Equal([]string{"wontfix"}))
+	})
+})
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/lifecycle"
+	"go.uber.org/zap"
+)
+
+// publish sends a lifecycle event when a publisher is configured. Publishing is best effort:
+// a failure is logged and never fails the reconcile.
+func (r *GithubIssueReconciler) publish(ctx context.Context, eventType lifecycle.EventType, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) {
+	if r.Publisher == nil {
+		return
+	}
+
+	event := lifecycle.Event{
+		Type:      eventType,
+		Namespace: issueObject.Namespace,
+		Name:      issueObject.Name,
+		Repo:      issueObject.Spec.Repo,
+		Time:      time.Now().UTC(),
+	}
+	if issue != nil {
+		event.IssueNumber = issue.Number
+		event.URL = issue.URL
+	}
+	if err := r.Publisher.Publish(ctx, event); err != nil {
+		r.logger(ctx).Warn("Failed to publish lifecycle event", zap.String("type", string(eventType)), zap.Error(err))
+	}
+}
+
+// drifted reports whether the upstream issue differs from the fields the operator writes.
+func drifted(issue *git.Issue, desired *git.DesiredIssue, missingLabels []string) bool {
+	return issue.Description != desired.Body ||
+		(desired.Milestone != 0 && issue.Milestone != desired.Milestone) ||
+		len(missingLabels) > 0
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// language returns the language the issue is rendered in: spec.language, else the defaultLanguage of the
+// GithubRepository for the issue repository in the same namespace. Empty means no localization applies.
+func (r *GithubIssueReconciler) language(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (string, error) {
+	if issueObject.Spec.Language != "" || len(issueObject.Spec.Localizations) == 0 {
+		return issueObject.Spec.Language, nil
+	}
+	repository, err := r.repository(ctx, issueObject)
+	if err != nil || repository == nil {
+		return "", err
+	}
+	return repository.Spec.DefaultLanguage, nil
+}
+
+// repository returns the GithubRepository for the issue repository in the namespace of the issue, or nil.
+func (r *GithubIssueReconciler) repository(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (*issuesv1alpha1.GithubRepository, error) {
+	owner, repo, err := git.ParseRepoURL(issueObject.Spec.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var repositories issuesv1alpha1.GithubRepositoryList
+	if err := r.List(ctx, &repositories, client.InNamespace(issueObject.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list GithubRepositories: %v", err)
+	}
+	for i, repository := range repositories.Items {
+		repositoryOwner, repositoryName, err := git.ParseRepoURL(repository.Spec.Repo)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(repositoryOwner, owner) && strings.EqualFold(repositoryName, repo) {
+			return &repositories.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// issueTitle returns the upstream title of the issue: the title of the selected localization when it sets one,
+// after the title prefix.
+func (r *GithubIssueReconciler) issueTitle(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (string, error) {
+	language, err := r.language(ctx, issueObject)
+	if err != nil {
+		return "", err
+	}
+	title := issueObject.Spec.Title
+	if localization, ok := issueObject.Spec.Localizations[language]; ok && localization.Title != "" {
+		title = localization.Title
+	}
+	prefix, err := r.titlePrefix(ctx, issueObject)
+	if err != nil {
+		return "", err
+	}
+	return withTitlePrefix(prefix, title), nil
+}
+
+// localizeDescription returns the body of the selected localization in place of description and,
+// with spec.includeTranslations, appends the other localizations as collapsible sections.
+func (r *GithubIssueReconciler) localizeDescription(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, description string) (string, error) {
+	if len(issueObject.Spec.Localizations) == 0 {
+		return description, nil
+	}
+	language, err := r.language(ctx, issueObject)
+	if err != nil {
+		return "", err
+	}
+	if localization, ok := issueObject.Spec.Localizations[language]; ok && localization.Body != "" {
+		description = localization.Body
+	}
+	if !issueObject.Spec.IncludeTranslations {
+		return description, nil
+	}
+
+	var others []string
+	for other := range issueObject.Spec.Localizations {
+		if other != language {
+			others = append(others, other)
+		}
+	}
+	slices.Sort(others)
+	var body strings.Builder
+	body.WriteString(description)
+	for _, other := range others {
+		localization := issueObject.Spec.Localizations[other]
+		fmt.Fprintf(&body, "\n\n<details>\n<summary>%s</summary>\n\n", other)
+		if localization.Title != "" {
+			fmt.Fprintf(&body, "**%s**\n\n", localization.Title)
+		}
+		fmt.Fprintf(&body, "%s\n</details>", localization.Body)
+	}
+	return body.String(), nil
+}
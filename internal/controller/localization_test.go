@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("localizations", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		repository := &issuesv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubRepositorySpec{Repo: "https://github.com/Org/Repo", DefaultLanguage: "fr"},
+		}
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/org/repo",
+				Title:       "Broken link",
+				Description: "The link is broken.",
+				Localizations: map[string]issuesv1alpha1.IssueLocalization{
+					"de": {Body: "Der Link ist kaputt."},
+					"fr": {Title: "Lien cassé", Body: "Le lien est cassé."},
+				},
+			},
+		}
+		reconciler = &GithubIssueReconciler{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(repository).Build(),
+		}
+	})
+
+	It("renders the default language of the repository", func() {
+		title, err := reconciler.issueTitle(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(title).To(Equal("Lien cassé"))
+		body, err := reconciler.localizeDescription(context.Background(), issueObject, issueObject.Spec.Description)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(Equal("Le lien est cassé."))
+	})
+
+	It("keeps the spec title when the selected localization has none", func() {
+		issueObject.Spec.Language = "de"
+		title, err := reconciler.issueTitle(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(title).To(Equal("Broken link"))
+	})
+
+	It("appends the other localizations as collapsible sections", func() {
+		issueObject.Spec.IncludeTranslations = true
+		body, err := reconciler.localizeDescription(context.Background(), issueObject, issueObject.Spec.Description)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(Equal("Le lien est cassé.\n\n<details>\n<summary>de</summary>\n\nDer Link ist kaputt.\n</details>"))
+	})
+})
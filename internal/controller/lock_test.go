@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeLockClient records the lock and unlock calls it receives.
+type fakeLockClient struct {
+	git.IssueClient
+	calls []string
+}
+
+func (f *fakeLockClient) Lock(_ context.Context, _, _ string, _ int, reason string) error {
+	f.calls = append(f.calls, "lock:"+reason)
+	return nil
+}
+
+func (f *fakeLockClient) Unlock(_ context.Context, _, _ string, _ int) error {
+	f.calls = append(f.calls, "unlock")
+	return nil
+}
+
+var _ = Describe("conversation lock", func() {
+	var (
+		reconciler *GithubIssueReconciler
+		upstream   *fakeLockClient
+	)
+
+	BeforeEach(func() {
+		upstream = &fakeLockClient{}
+		reconciler = &GithubIssueReconciler{Log: zap.NewNop(), Clients: &git.Clients{Default: upstream}}
+	})
+
+	sync := func(spec issuesv1alpha1.GithubIssueSpec, issue *git.Issue) []string {
+		upstream.calls = nil
+		issueObject := &issuesv1alpha1.GithubIssue{Spec: spec}
+		Expect(reconciler.syncLock(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+		return upstream.calls
+	}
+
+	It("locks and unlocks the conversation", func() {
+		Expect(sync(issuesv1alpha1.GithubIssueSpec{Locked: true, LockReason: "spam"}, &git.Issue{Number: 1})).
+			To(Equal([]string{"lock:spam"}))
+		Expect(sync(issuesv1alpha1.GithubIssueSpec{}, &git.Issue{Number: 1, Locked: true, LockReason: "spam"})).
+			To(Equal([]string{"unlock"}))
+	})
+
+	It("unlocks the conversation before changing the lock reason", func() {
+		Expect(sync(issuesv1alpha1.GithubIssueSpec{Locked: true, LockReason: "resolved"}, &git.Issue{Number: 1, Locked: true, LockReason: "spam"})).
+			To(Equal([]string{"unlock", "lock:resolved"}))
+	})
+
+	It("leaves a conversation in the requested state alone", func() {
+		Expect(sync(issuesv1alpha1.GithubIssueSpec{Locked: true, LockReason: "spam"}, &git.Issue{Number: 1, Locked: true, LockReason: "spam"})).
+			To(BeEmpty())
+		Expect(sync(issuesv1alpha1.GithubIssueSpec{Locked: true}, &git.Issue{Number: 1, Locked: true, LockReason: "too heated"})).
+			To(BeEmpty())
+		Expect(sync(issuesv1alpha1.GithubIssueSpec{}, &git.Issue{Number: 1})).To(BeEmpty())
+	})
+})
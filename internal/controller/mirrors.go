@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// syncMirrors keeps an issue in every spec.mirrors repository with the title and state of the upstream issue,
+// records them in status.mirrors and reports the result through the MirrorsSynced condition.
+// Mirrors that fail are retried on the next reconcile.
+func (r *GithubIssueReconciler) syncMirrors(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	mirrors := issueObject.Spec.Mirrors
+	if len(mirrors) == 0 && meta.FindStatusCondition(issueObject.Status.Conditions, MirrorsSyncedCondition) == nil {
+		return nil
+	}
+
+	statuses := make([]issuesv1alpha1.MirrorStatus, 0, len(mirrors))
+	var synced, failed []string
+	for _, mirror := range mirrors {
+		status := mirrorStatus(issueObject, mirror.Repo)
+		mirrored, err := r.syncMirror(ctx, issueObject, mirror, status.IssueNumber, issue)
+		if err != nil {
+			r.logger(ctx).Warn("Failed to sync mirror", zap.String("mirror", mirror.Repo), zap.Error(err))
+			status.Error = err.Error()
+			failed = append(failed, mirror.Repo)
+		} else {
+			status = issuesv1alpha1.MirrorStatus{Repo: mirror.Repo, IssueNumber: mirrored.Number, URL: mirrored.URL, State: mirrored.State}
+			synced = append(synced, mirror.Repo)
+		}
+		statuses = append(statuses, status)
+	}
+
+	condition := metav1.Condition{
+		Type:               MirrorsSyncedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Mirrored",
+		Message:            fmt.Sprintf("Mirrored to %d repository(ies): %s", len(synced), strings.Join(synced, ", ")),
+		ObservedGeneration: issueObject.Generation,
+	}
+	if len(mirrors) == 0 {
+		condition.Reason = "NoMirrors"
+		condition.Message = "No mirrors requested"
+		statuses = nil
+	}
+	if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MirrorFailed"
+		condition.Message = fmt.Sprintf("Failed to sync mirror(s): %s", strings.Join(failed, ", "))
+	}
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, condition)
+	if !equality.Semantic.DeepEqual(issueObject.Status.Mirrors, statuses) {
+		issueObject.Status.Mirrors = statuses
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if len(failed) > 0 {
+		r.Recorder.Event(issueObject, corev1.EventTypeWarning, MirrorsSyncedCondition, condition.Message)
+	}
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// syncMirror finds or creates the issue mirroring issue in the mirror repository, then applies its title and state.
+// The mirror issue is looked up by number once known, by title before.
+func (r *GithubIssueReconciler) syncMirror(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, mirror issuesv1alpha1.IssueMirror, number int, issue *git.Issue) (*git.Issue, error) {
+	owner, repo, err := git.ParseRepoURL(mirror.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.authorizeRepository(ctx, issueObject.Namespace, mirror.Repo); err != nil {
+		return nil, err
+	}
+	issueClient, err := r.mirrorClient(ctx, issueObject, mirror)
+	if err != nil {
+		return nil, err
+	}
+
+	var mirrored *git.Issue
+	if number != 0 {
+		if mirrored, err = issueClient.Get(ctx, owner, repo, number); err != nil {
+			return nil, fmt.Errorf("failed to get mirror issue: %v", err)
+		}
+	} else {
+		issues, err := issueClient.List(ctx, owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list mirror issues: %v", err)
+		}
+		mirrored = searchForIssue(issue.Title, issues)
+	}
+
+	desired := &git.DesiredIssue{Title: issue.Title, Body: mirrorBody(issueObject, issue)}
+	if mirrored == nil {
+		if mirrored, err = issueClient.Create(ctx, owner, repo, desired); err != nil {
+			return nil, fmt.Errorf("failed to create mirror issue: %v", err)
+		}
+		r.logger(ctx).Info("Created mirror issue", zap.String("mirror", mirror.Repo), zap.String("url", mirrored.URL))
+	} else if mirrored.Title != issue.Title {
+		if mirrored, err = issueClient.Edit(ctx, owner, repo, mirrored.Number, desired); err != nil {
+			return nil, fmt.Errorf("failed to edit mirror issue: %v", err)
+		}
+	}
+
+	switch {
+	case issue.State == "closed" && mirrored.State == "open":
+		if mirrored, err = issueClient.Close(ctx, owner, repo, mirrored.Number); err != nil {
+			return nil, fmt.Errorf("failed to close mirror issue: %v", err)
+		}
+	case issue.State == "open" && mirrored.State == "closed":
+		if mirrored, err = issueClient.Reopen(ctx, owner, repo, mirrored.Number); err != nil {
+			return nil, fmt.Errorf("failed to reopen mirror issue: %v", err)
+		}
+	}
+	return mirrored, nil
+}
+
+// closeMirrors closes the open mirror issues recorded in status.mirrors, once the GithubIssue is deleted.
+func (r *GithubIssueReconciler) closeMirrors(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	for _, status := range issueObject.Status.Mirrors {
+		if status.IssueNumber == 0 || status.State != "open" {
+			continue
+		}
+		mirror, ok := specMirror(issueObject, status.Repo)
+		if !ok {
+			continue
+		}
+		owner, repo, err := git.ParseRepoURL(mirror.Repo)
+		if err != nil {
+			return err
+		}
+		issueClient, err := r.mirrorClient(ctx, issueObject, mirror)
+		if err != nil {
+			return err
+		}
+		if _, err := issueClient.Close(ctx, owner, repo, status.IssueNumber); err != nil {
+			return fmt.Errorf("failed to close mirror issue in %s: %v", mirror.Repo, err)
+		}
+		r.logger(ctx).Info("Closed mirror issue", zap.String("mirror", mirror.Repo), zap.Int("number", status.IssueNumber))
+	}
+	return nil
+}
+
+// mirrorClient returns the issue client for a mirror: built from its credentials, or the client of the issue.
+func (r *GithubIssueReconciler) mirrorClient(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, mirror issuesv1alpha1.IssueMirror) (git.IssueClient, error) {
+	if mirror.CredentialsSecretRef == nil {
+		return r.issueClient(ctx), nil
+	}
+	return r.secretIssueClient(ctx, issueObject, mirror.Repo, mirror.CredentialsSecretRef)
+}
+
+// mirrorBody links the mirror issue back to the upstream issue. It carries the marker of the GithubIssue.
+func mirrorBody(issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) string {
+	return git.WithMarker(fmt.Sprintf("Mirror of %s", issue.URL), objectKey(issueObject))
+}
+
+// mirrorStatus returns the recorded status of the mirror of repoURL, or an empty one.
+func mirrorStatus(issueObject *issuesv1alpha1.GithubIssue, repoURL string) issuesv1alpha1.MirrorStatus {
+	for _, status := range issueObject.Status.Mirrors {
+		if status.Repo == repoURL {
+			return status
+		}
+	}
+	return issuesv1alpha1.MirrorStatus{Repo: repoURL}
+}
+
+func specMirror(issueObject *issuesv1alpha1.GithubIssue, repoURL string) (issuesv1alpha1.IssueMirror, bool) {
+	for _, mirror := range issueObject.Spec.Mirrors {
+		if mirror.Repo == repoURL {
+			return mirror, true
+		}
+	}
+	return issuesv1alpha1.IssueMirror{}, false
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeMirrorClient keeps the issues of a single repository in memory. Like the issue clients, it only lists the
+// open issues.
+type fakeMirrorClient struct {
+	git.IssueClient
+	issues map[int]*git.Issue
+}
+
+func (f *fakeMirrorClient) List(_ context.Context, _, _ string) ([]*git.Issue, error) {
+	var issues []*git.Issue
+	for _, issue := range f.issues {
+		if issue.State == "open" {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+func (f *fakeMirrorClient) Get(_ context.Context, _, _ string, number int) (*git.Issue, error) {
+	return f.issues[number], nil
+}
+
+func (f *fakeMirrorClient) Create(_ context.Context, _, _ string, desired *git.DesiredIssue) (*git.Issue, error) {
+	number := len(f.issues) + 1
+	f.issues[number] = &git.Issue{Number: number, Title: desired.Title, Description: desired.Body, State: "open"}
+	return f.issues[number], nil
+}
+
+func (f *fakeMirrorClient) Edit(_ context.Context, _, _ string, number int, desired *git.DesiredIssue) (*git.Issue, error) {
+	f.issues[number].Title = desired.Title
+	f.issues[number].Description = desired.Body
+	return f.issues[number], nil
+}
+
+func (f *fakeMirrorClient) Close(_ context.Context, _, _ string, number int) (*git.Issue, error) {
+	f.issues[number].State = "closed"
+	return f.issues[number], nil
+}
+
+func (f *fakeMirrorClient) Reopen(_ context.Context, _, _ string, number int) (*git.Issue, error) {
+	f.issues[number].State = "open"
+	return f.issues[number], nil
+}
+
+var _ = Describe("issue mirrors", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		mirror      *fakeMirrorClient
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:    "https://github.com/org/repo",
+				Mirrors: []issuesv1alpha1.IssueMirror{{Repo: "https://github.example.com/org/mirror"}},
+			},
+		}
+		mirror = &fakeMirrorClient{issues: map[int]*git.Issue{}}
+		reconciler = &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Clients:  &git.Clients{Default: mirror},
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+	})
+
+	It("creates the mirror once and follows the title and state of the issue", func() {
+		issue := &git.Issue{Number: 3, Title: "Deprecate v1", State: "open", URL: "https://github.com/org/repo/issues/3"}
+		Expect(reconciler.syncMirrors(context.Background(), issueObject, issue)).To(Succeed())
+		Expect(reconciler.syncMirrors(context.Background(), issueObject, issue)).To(Succeed())
+		Expect(mirror.issues).To(HaveLen(1))
+		Expect(mirror.issues[1].Description).To(ContainSubstring("Mirror of https://github.com/org/repo/issues/3"))
+		Expect(issueObject.Status.Mirrors).To(Equal([]issuesv1alpha1.MirrorStatus{
+			{Repo: "https://github.example.com/org/mirror", IssueNumber: 1, State: "open"},
+		}))
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, MirrorsSyncedCondition)).To(BeTrue())
+
+		issue.Title, issue.State = "Deprecate the v1 API", "closed"
+		Expect(reconciler.syncMirrors(context.Background(), issueObject, issue)).To(Succeed())
+		Expect(mirror.issues[1].Title).To(Equal("Deprecate the v1 API"))
+		Expect(mirror.issues[1].State).To(Equal("closed"))
+
+		issue.State = "open"
+		Expect(reconciler.syncMirrors(context.Background(), issueObject, issue)).To(Succeed())
+		Expect(mirror.issues[1].State).To(Equal("open"))
+	})
+
+	It("closes open mirrors on deletion", func() {
+		issue := &git.Issue{Number: 3, Title: "Deprecate v1", State: "open"}
+		Expect(reconciler.syncMirrors(context.Background(), issueObject, issue)).To(Succeed())
+		Expect(reconciler.closeMirrors(context.Background(), issueObject)).To(Succeed())
+		Expect(mirror.issues[1].State).To(Equal("closed"))
+	})
+
+	It("reports mirrors that cannot be reached", func() {
+		issueObject.Spec.Mirrors[0].Repo = "https://github.example.com/org"
+		Expect(reconciler.syncMirrors(context.Background(), issueObject, &git.Issue{Title: "Deprecate v1", State: "open"})).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, MirrorsSyncedCondition)).To(BeTrue())
+		Expect(issueObject.Status.Mirrors[0].Error).To(ContainSubstring("invalid repository URL"))
+	})
+})
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("issue node ID", func() {
+	It("records the node ID of the upstream issue and keeps it when the client reads none", func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject := &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		reconciler := &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+		reconciler.Prepare()
+
+		_, err := reconciler.updateIssueStatus(context.Background(), issueObject, &git.Issue{Number: 1, NodeID: "I_kwDO1", State: "open"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issueObject.Status.NodeID).To(Equal("I_kwDO1"))
+
+		_, err = reconciler.updateIssueStatus(context.Background(), issueObject, &git.Issue{Number: 1, State: "open"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issueObject.Status.NodeID).To(Equal("I_kwDO1"))
+	})
+})
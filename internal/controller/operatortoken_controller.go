@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// OperatorTokenReconciler keeps the operator GitHub token in sync with a Secret key, so rotating the token
+// takes no restart: every change of the Secret replaces the primary token of Credentials.
+type OperatorTokenReconciler struct {
+	client.Client
+	Log *zap.Logger
+	// AuditLog records every Secret read. Nil disables the audit.
+	AuditLog    *zap.Logger
+	Secret      types.NamespacedName
+	Key         string
+	Credentials *git.TokenFailoverTransport
+}
+
+// Load reads the token from the Secret through reader and makes it the primary token.
+func (r *OperatorTokenReconciler) Load(ctx context.Context, reader client.Reader) error {
+	secret := &corev1.Secret{}
+	err := reader.Get(ctx, r.Secret, secret)
+	logging.AuditSecretRead(r.AuditLog, "Operator", "github-token", r.Secret.String(), r.Key, "credentials", err)
+	if err != nil {
+		return fmt.Errorf("failed to read Secret %s: %w", r.Secret, err)
+	}
+	token := secret.Data[r.Key]
+	if len(token) == 0 {
+		return fmt.Errorf("key %s not found in Secret %s", r.Key, r.Secret)
+	}
+	r.Credentials.SetPrimary(string(token))
+	return nil
+}
+
+func (r *OperatorTokenReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	if err := r.Load(ctx, r.Client); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Keep the current token until the Secret is recreated.
+			r.Log.Warn("Operator token Secret not found, keeping the current token", zap.String("secret", r.Secret.String()))
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	r.Log.Info("Loaded operator token", zap.String("secret", r.Secret.String()))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorTokenReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isTokenSecret := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetNamespace() == r.Secret.Namespace && object.GetName() == r.Secret.Name
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Secret{}, builder.OnlyMetadata, builder.WithPredicates(isTokenSecret, predicate.ResourceVersionChangedPredicate{})).
+		Named("operatortoken").
+		Complete(r)
+}
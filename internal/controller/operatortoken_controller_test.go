@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("OperatorToken controller", func() {
+	var (
+		reconciler *OperatorTokenReconciler
+		secret     *corev1.Secret
+		key        = types.NamespacedName{Namespace: "operator", Name: "github-token"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data:       map[string][]byte{"token": []byte("ghp_first")},
+		}
+		reconciler = &OperatorTokenReconciler{
+			Client:      fake.NewClientBuilder().WithScheme(testScheme).WithObjects(secret).Build(),
+			Log:         zap.NewNop(),
+			Secret:      key,
+			Key:         "token",
+			Credentials: &git.TokenFailoverTransport{Primary: "ghp_env"},
+		}
+	})
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("replaces the primary token whenever the Secret changes", func() {
+		reconcile()
+		Expect(reconciler.Credentials.Primary).To(Equal("ghp_first"))
+
+		secret.Data["token"] = []byte("ghp_rotated")
+		Expect(reconciler.Update(context.Background(), secret)).To(Succeed())
+		reconcile()
+		Expect(reconciler.Credentials.Primary).To(Equal("ghp_rotated"))
+	})
+
+	It("keeps the current token while the Secret is missing", func() {
+		reconcile()
+		Expect(reconciler.Delete(context.Background(), secret)).To(Succeed())
+		reconcile()
+		Expect(reconciler.Credentials.Primary).To(Equal("ghp_first"))
+	})
+})
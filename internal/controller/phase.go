@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// setPhase records the phase of the issue.
+func (r *GithubIssueReconciler) setPhase(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, phase issuesv1alpha1.IssuePhase) error {
+	if issueObject.Status.Phase == phase {
+		return nil
+	}
+	issueObject.Status.Phase = phase
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// setPendingPhase marks an issue that was never reconciled as Pending.
+func (r *GithubIssueReconciler) setPendingPhase(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if issueObject.Status.Phase != "" {
+		return nil
+	}
+	return r.setPhase(ctx, issueObject, issuesv1alpha1.PhasePending)
+}
+
+// lastSyncTimeResolution is how stale status.lastSyncTime may get before a sync that changed nothing else
+// refreshes it. Every status write triggers a reconcile, so refreshing it on every sync would never settle.
+const lastSyncTimeResolution = time.Minute
+
+// recordSynced moves the issue to the Synced phase after a full sync, recording the generation
+// that was applied to GitHub and resetting the failure counters.
+func (r *GithubIssueReconciler) recordSynced(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	status := issueObject.Status
+	now := metav1.Now()
+	if status.Phase == issuesv1alpha1.PhaseSynced && status.ObservedGeneration == issueObject.Generation &&
+		status.LastSyncTime != nil && now.Sub(status.LastSyncTime.Time) < lastSyncTimeResolution {
+		return nil
+	}
+	if status.ObservedGeneration != issueObject.Generation {
+		if changedAt := specChangedAt(issueObject); !changedAt.IsZero() {
+			metrics.SyncLatency.WithLabelValues(repositoryLabel(issueObject.RepoURL())).Observe(now.Sub(changedAt).Seconds())
+		}
+	}
+	issueObject.Status.Phase = issuesv1alpha1.PhaseSynced
+	issueObject.Status.ObservedGeneration = issueObject.Generation
+	issueObject.Status.LastSyncTime = &now
+	issueObject.Status.LastSyncError = ""
+	issueObject.Status.ConsecutiveFailures = 0
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// specChangedAt returns when the spec of the issue last changed: the latest time a field manager wrote to the spec,
+// as recorded in the managed fields, or else the creation of the issue. It survives operator restarts, unlike
+// the time the operator first observed the generation.
+func specChangedAt(issueObject *issuesv1alpha1.GithubIssue) time.Time {
+	changedAt := issueObject.CreationTimestamp.Time
+	for _, entry := range issueObject.ManagedFields {
+		if entry.Subresource != "" || entry.Time == nil || entry.FieldsV1 == nil {
+			continue
+		}
+		if bytes.Contains(entry.FieldsV1.Raw, []byte(`"f:spec"`)) && entry.Time.After(changedAt) {
+			changedAt = entry.Time.Time
+		}
+	}
+	return changedAt
+}
+
+// recordFailure moves the issue to the Error phase when the reconcile failed and counts the failure.
+// An issue being deleted stays Terminating.
+func (r *GithubIssueReconciler) recordFailure(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, reconcileErr error) {
+	if reconcileErr == nil || !issueObject.DeletionTimestamp.IsZero() {
+		return
+	}
+	issueObject.Status.Phase = issuesv1alpha1.PhaseError
+	issueObject.Status.LastSyncError = reconcileErr.Error()
+	issueObject.Status.ConsecutiveFailures++
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		r.logger(ctx).Warn("Failed to record the reconcile failure", zap.Error(err))
+	}
+}
+
+// syncBookkeepingOnly reports whether an update only changed the phase, sync bookkeeping and readiness of the status.
+// Those writes follow a reconcile and must not queue another one: a failing issue would otherwise be
+// reconciled again right away for every failure it records, bypassing the requeue backoff.
+func syncBookkeepingOnly(old, new client.Object) bool {
+	oldIssue, ok := old.(*issuesv1alpha1.GithubIssue)
+	if !ok {
+		return false
+	}
+	newIssue, ok := new.(*issuesv1alpha1.GithubIssue)
+	if !ok || oldIssue.ResourceVersion == newIssue.ResourceVersion {
+		return false
+	}
+	return equality.Semantic.DeepEqual(withoutSyncBookkeeping(oldIssue), withoutSyncBookkeeping(newIssue))
+}
+
+func withoutSyncBookkeeping(issueObject *issuesv1alpha1.GithubIssue) *issuesv1alpha1.GithubIssue {
+	stripped := issueObject.DeepCopy()
+	stripped.ResourceVersion = ""
+	stripped.ManagedFields = nil
+	stripped.Status.Phase = ""
+	stripped.Status.ObservedGeneration = 0
+	stripped.Status.LastSyncTime = nil
+	stripped.Status.LastSyncError = ""
+	stripped.Status.ConsecutiveFailures = 0
+	stripped.Status.LastDecision = nil
+	// Written by the operator from the upstream labels.
+	delete(stripped.Annotations, UpstreamLabelsAnnotation)
+	// The readiness conditions are derived from the rest of the status.
+	for _, conditionType := range []string{ReadyCondition, ReconcilingCondition, StalledCondition} {
+		meta.RemoveStatusCondition(&stripped.Status.Conditions, conditionType)
+	}
+	return stripped
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("phase", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default", Generation: 3}}
+		reconciler = &GithubIssueReconciler{
+			Client:  fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:     zap.NewNop(),
+			pending: newPendingWrites(),
+		}
+	})
+
+	It("starts Pending and records the generation once synced", func() {
+		Expect(reconciler.setPendingPhase(context.Background(), issueObject)).To(Succeed())
+		Expect(issueObject.Status.Phase).To(Equal(issuesv1alpha1.PhasePending))
+		Expect(issueObject.Status.ObservedGeneration).To(BeZero())
+
+		Expect(reconciler.recordSynced(context.Background(), issueObject)).To(Succeed())
+		Expect(issueObject.Status.Phase).To(Equal(issuesv1alpha1.PhaseSynced))
+		Expect(issueObject.Status.ObservedGeneration).To(Equal(int64(3)))
+		Expect(issueObject.Status.LastSyncTime).NotTo(BeNil())
+	})
+
+	It("counts failures until the next sync, keeping the observed generation", func() {
+		Expect(reconciler.recordSynced(context.Background(), issueObject)).To(Succeed())
+		reconciler.recordFailure(context.Background(), issueObject, errors.New("boom"))
+		reconciler.recordFailure(context.Background(), issueObject, errors.New("rate limited"))
+		Expect(issueObject.Status.Phase).To(Equal(issuesv1alpha1.PhaseError))
+		Expect(issueObject.Status.ObservedGeneration).To(Equal(int64(3)))
+		Expect(issueObject.Status.LastSyncError).To(Equal("rate limited"))
+		Expect(issueObject.Status.ConsecutiveFailures).To(Equal(int32(2)))
+
+		Expect(reconciler.setPendingPhase(context.Background(), issueObject)).To(Succeed())
+		Expect(issueObject.Status.Phase).To(Equal(issuesv1alpha1.PhaseError))
+
+		Expect(reconciler.recordSynced(context.Background(), issueObject)).To(Succeed())
+		Expect(issueObject.Status.LastSyncError).To(BeEmpty())
+		Expect(issueObject.Status.ConsecutiveFailures).To(BeZero())
+	})
+})
+
+var _ = Describe("specChangedAt", func() {
+	It("is the latest spec write, ignoring status and metadata writes", func() {
+		created := time.Now().Add(-time.Hour).Truncate(time.Second)
+		specWrite := metav1.NewTime(created.Add(10 * time.Minute))
+		labelWrite := metav1.NewTime(created.Add(20 * time.Minute))
+		statusWrite := metav1.NewTime(created.Add(30 * time.Minute))
+		issueObject := &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(created),
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Time: &specWrite, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:title":{}}}`)}},
+				{Manager: "kubectl", Time: &labelWrite, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{}}}`)}},
+				{Manager: "operator", Time: &statusWrite, Subresource: "status", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)}},
+			},
+		}}
+		Expect(specChangedAt(issueObject)).To(Equal(specWrite.Time))
+
+		issueObject.ManagedFields = nil
+		Expect(specChangedAt(issueObject)).To(Equal(created))
+	})
+})
+
+var _ = Describe("sync bookkeeping updates", func() {
+	It("are told apart from other updates", func() {
+		old := &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", ResourceVersion: "1"}}
+		bookkeeping := old.DeepCopy()
+		bookkeeping.ResourceVersion = "2"
+		bookkeeping.Status.Phase = issuesv1alpha1.PhaseError
+		bookkeeping.Status.ConsecutiveFailures = 4
+		Expect(syncBookkeepingOnly(old, bookkeeping)).To(BeTrue())
+
+		other := bookkeeping.DeepCopy()
+		other.Status.IssueNumber = 12
+		Expect(syncBookkeepingOnly(old, other)).To(BeFalse())
+		Expect(syncBookkeepingOnly(old, old)).To(BeFalse())
+	})
+})
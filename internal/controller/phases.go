@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// Reconcile phases timed by phaseTimer.
+const (
+	phaseFetch  = "fetch"  // GitHub list calls
+	phaseFind   = "find"   // finding the upstream issue, including retries and backoff
+	phaseCreate = "create" // creating the upstream issue
+	phaseEdit   = "edit"   // editing the upstream issue
+	phaseStatus = "status" // status writes to the API server
+	// phaseBackoff is derived: time spent in find that was not spent fetching.
+	phaseBackoff = "backoff"
+)
+
+type phaseTimerKey struct{}
+
+// phaseTimer accumulates the time a reconcile spends in each phase.
+type phaseTimer struct {
+	mu        sync.Mutex
+	start     time.Time
+	durations map[string]time.Duration
+}
+
+func withPhaseTimer(ctx context.Context) (context.Context, *phaseTimer) {
+	timer := &phaseTimer{start: time.Now(), durations: map[string]time.Duration{}}
+	return context.WithValue(ctx, phaseTimerKey{}, timer), timer
+}
+
+// timePhase starts timing phase for the reconcile carried by ctx; call the returned func when the phase ends.
+func timePhase(ctx context.Context, phase string) func() {
+	timer, _ := ctx.Value(phaseTimerKey{}).(*phaseTimer)
+	if timer == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		timer.mu.Lock()
+		defer timer.mu.Unlock()
+		timer.durations[phase] += time.Since(start)
+	}
+}
+
+// finish exports the phase durations and returns them with the total reconcile time.
+func (t *phaseTimer) finish() (map[string]time.Duration, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if find, ok := t.durations[phaseFind]; ok {
+		if backoff := find - t.durations[phaseFetch]; backoff > 0 {
+			t.durations[phaseBackoff] = backoff
+		}
+	}
+	for phase, duration := range t.durations {
+		metrics.ReconcilePhaseDuration.WithLabelValues(phase).Observe(duration.Seconds())
+	}
+	return t.durations, time.Since(t.start)
+}
+
+// reportPhases exports the phase timings of a reconcile and logs them when it was slower than the threshold.
+func (r *GithubIssueReconciler) reportPhases(ctx context.Context, timer *phaseTimer) {
+	durations, total := timer.finish()
+	if r.SlowReconcileThreshold <= 0 || total < r.SlowReconcileThreshold {
+		return
+	}
+
+	fields := []zap.Field{zap.Duration("total", total)}
+	for _, phase := range []string{phaseFind, phaseFetch, phaseBackoff, phaseCreate, phaseEdit, phaseStatus} {
+		if duration, ok := durations[phase]; ok {
+			fields = append(fields, zap.Duration(phase, duration))
+		}
+	}
+	r.logger(ctx).Warn("Slow reconcile", fields...)
+}
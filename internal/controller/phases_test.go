@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("phaseTimer", func() {
+	It("accumulates phases and derives the backoff spent while finding the issue", func() {
+		ctx, timer := withPhaseTimer(context.Background())
+
+		stopFind := timePhase(ctx, phaseFind)
+		stopFetch := timePhase(ctx, phaseFetch)
+		time.Sleep(5 * time.Millisecond)
+		stopFetch()
+		time.Sleep(20 * time.Millisecond)
+		stopFind()
+
+		durations, total := timer.finish()
+		Expect(durations).To(HaveKey(phaseFetch))
+		Expect(durations[phaseBackoff]).To(BeNumerically(">=", 20*time.Millisecond))
+		Expect(total).To(BeNumerically(">=", durations[phaseFind]))
+	})
+
+	It("ignores phases outside a timed reconcile", func() {
+		Expect(func() { timePhase(context.Background(), phaseEdit)() }).NotTo(Panic())
+	})
+})
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakePinClient records the pin states it is asked to set.
+type fakePinClient struct {
+	git.IssueClient
+	calls []bool
+}
+
+func (f *fakePinClient) SetPinned(_ context.Context, _, _ string, _ int, pinned bool) error {
+	f.calls = append(f.calls, pinned)
+	return nil
+}
+
+var _ = Describe("issue pin", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		upstream    *fakePinClient
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		upstream = &fakePinClient{}
+		reconciler = &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Clients:  &git.Clients{Default: upstream},
+			Recorder: record.NewFakeRecorder(10),
+			pending:  newPendingWrites(),
+		}
+	})
+
+	sync := func(pinned bool, issue *git.Issue) {
+		issueObject.Spec.Pinned = pinned
+		Expect(reconciler.syncPin(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+	}
+
+	It("pins the issue once and unpins it when the spec asks", func() {
+		sync(true, &git.Issue{Number: 1})
+		Expect(upstream.calls).To(Equal([]bool{true}))
+		Expect(issueObject.Status.Pinned).To(BeTrue())
+
+		sync(true, &git.Issue{Number: 1})
+		sync(true, &git.Issue{Number: 1, Pinned: ptr.To(true)})
+		Expect(upstream.calls).To(Equal([]bool{true}))
+
+		sync(false, &git.Issue{Number: 1, Pinned: ptr.To(true)})
+		Expect(upstream.calls).To(Equal([]bool{true, false}))
+		Expect(issueObject.Status.Pinned).To(BeFalse())
+	})
+
+	It("pins the issue again once it was unpinned upstream", func() {
+		sync(true, &git.Issue{Number: 1})
+		sync(true, &git.Issue{Number: 1, Pinned: ptr.To(false)})
+		Expect(upstream.calls).To(Equal([]bool{true, true}))
+	})
+
+	It("never unpins an issue the operator did not pin", func() {
+		sync(false, &git.Issue{Number: 1, Pinned: ptr.To(true)})
+		Expect(upstream.calls).To(BeEmpty())
+	})
+})
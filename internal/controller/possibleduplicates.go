@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AllowDuplicateAnnotation set to "true" lets a GithubIssue create its issue despite possible duplicates upstream.
+const AllowDuplicateAnnotation = "issues.dana.io/allow-duplicate"
+
+// duplicateSimilarity is the share of title words two issues must have in common to be possible duplicates.
+const duplicateSimilarity = 0.8
+
+// checkPossibleDuplicates reports whether the issue about to be created looks like one already open upstream.
+// Possible duplicates hold the creation back and are reported through the PossibleDuplicate condition.
+func (r *GithubIssueReconciler) checkPossibleDuplicates(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (bool, error) {
+	if !r.DetectPossibleDuplicates || issueObject.Annotations[AllowDuplicateAnnotation] == "true" {
+		return false, r.clearPossibleDuplicate(ctx, issueObject)
+	}
+
+	upstream, err := r.fetchAllIssues(ctx, owner, repo)
+	if err != nil {
+		return false, err
+	}
+	candidates := possibleDuplicates(issueObject.Spec.Title, upstream)
+	if len(candidates) == 0 {
+		return false, r.clearPossibleDuplicate(ctx, issueObject)
+	}
+
+	var urls []string
+	for _, candidate := range candidates {
+		urls = append(urls, candidate.URL)
+	}
+	message := fmt.Sprintf("Not creating the issue, similar issues are open: %s. Set the %s annotation to create it anyway",
+		strings.Join(urls, ", "), AllowDuplicateAnnotation)
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               PossibleDuplicateCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SimilarTitle",
+		Message:            message,
+		ObservedGeneration: issueObject.Generation,
+	})
+	if changed {
+		r.logger(ctx).Info("Holding back issue creation, possible duplicates found", zap.Strings("candidates", urls))
+		r.Recorder.Event(issueObject, corev1.EventTypeWarning, PossibleDuplicateCondition, message)
+		if err := r.updateStatus(ctx, issueObject); err != nil {
+			return true, fmt.Errorf("failed to update status: %v", err)
+		}
+	}
+	return true, nil
+}
+
+// clearPossibleDuplicate flips a previously reported PossibleDuplicate condition.
+func (r *GithubIssueReconciler) clearPossibleDuplicate(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, PossibleDuplicateCondition) {
+		return nil
+	}
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               PossibleDuplicateCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Resolved",
+		Message:            "No possible duplicate holds back the issue",
+		ObservedGeneration: issueObject.Generation,
+	})
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
+
+// possibleDuplicates returns the open issues whose normalized title is close to title.
+func possibleDuplicates(title string, upstream []*git.Issue) []*git.Issue {
+	words := titleWords(title)
+	if len(words) == 0 {
+		return nil
+	}
+	var candidates []*git.Issue
+	for _, issue := range upstream {
+		if issue == nil || issue.State == "closed" || issue.HasPR {
+			continue
+		}
+		if similarity(words, titleWords(issue.Title)) >= duplicateSimilarity {
+			candidates = append(candidates, issue)
+		}
+	}
+	return candidates
+}
+
+// titleWords returns the set of lower-cased words of a title, ignoring punctuation.
+func titleWords(title string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// similarity is the Jaccard index of two word sets.
+func similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range a {
+		if b[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
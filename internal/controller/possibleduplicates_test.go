@@ -0,0 +1,30 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("possible duplicates", func() {
+	upstream := []*git.Issue{
+		{Number: 1, Title: "Checkout page returns 500 on submit", State: "open", URL: "https://github.com/org/repo/issues/1"},
+		{Number: 2, Title: "Checkout page returns 500!", State: "closed"},
+		{Number: 3, Title: "Add dark mode", State: "open"},
+	}
+
+	It("matches titles that only differ in case and punctuation", func() {
+		Expect(possibleDuplicates("checkout page: returns 500 on submit", upstream)).To(ConsistOf(upstream[0]))
+	})
+
+	It("ignores closed issues and unrelated titles", func() {
+		Expect(possibleDuplicates("Checkout page returns 500", upstream)).To(BeEmpty())
+		Expect(possibleDuplicates("Support SSO login", upstream)).To(BeEmpty())
+	})
+
+	It("computes the share of common words", func() {
+		Expect(similarity(titleWords("a b c d"), titleWords("a b c e"))).To(BeNumerically("~", 0.6))
+		Expect(similarity(titleWords(""), titleWords("a"))).To(BeZero())
+	})
+})
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TokenPreflight checks at startup and every Interval that the operator token may manage the issues of every
+// repository a GithubIssue using it references. Failures fail the readiness check and set the
+// TokenPermissionsMissing condition of the affected GithubIssues, instead of surfacing as reconcile errors.
+// GithubIssues using their own credentials, a GitProvider or a provider other than GitHub are not checked.
+type TokenPreflight struct {
+	// Reader lists the GithubIssues. It should read from the API server, the check starts before the cache.
+	Reader      client.Reader
+	Permissions git.PermissionClient
+	Interval    time.Duration
+	// NamespaceCredentialsSecret is the namespace credentials Secret of the GithubIssue reconciler.
+	NamespaceCredentialsSecret string
+	Log                        *zap.Logger
+
+	mu       sync.RWMutex
+	checked  bool
+	problems map[string]string // By lower-case owner/repo
+}
+
+// Start checks the repositories once, then every Interval until ctx is done. It implements manager.Runnable.
+func (p *TokenPreflight) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Run(ctx); err != nil {
+			p.Log.Error("Token preflight failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection lets every replica check the token, so each one reports its readiness.
+func (p *TokenPreflight) NeedLeaderElection() bool {
+	return false
+}
+
+// Run checks the repositories once.
+func (p *TokenPreflight) Run(ctx context.Context) error {
+	repos, err := p.operatorRepos(ctx)
+	if err != nil {
+		return err
+	}
+
+	problems := map[string]string{}
+	for key, repoURL := range repos {
+		owner, repo, _ := git.ParseRepoURL(repoURL)
+		access, err := p.Permissions.RepoAccess(ctx, owner, repo)
+		if err != nil {
+			// The repository is checked again on the next run; an outage is not a missing permission.
+			p.Log.Warn("Failed to check token access", zap.String("repository", key), zap.Error(err))
+			continue
+		}
+		if missing := access.Missing(); missing != "" {
+			problems[key] = missing
+			p.Log.Warn("Operator token can't manage the issues of a repository",
+				zap.String("repository", key), zap.String("problem", missing))
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checked, p.problems = true, problems
+	return nil
+}
+
+// operatorRepos returns the repositories of the GithubIssues using the operator token, by lower-case owner/repo.
+func (p *TokenPreflight) operatorRepos(ctx context.Context) (map[string]string, error) {
+	var issues issuesv1alpha1.GithubIssueList
+	if err := p.Reader.List(ctx, &issues); err != nil {
+		return nil, fmt.Errorf("failed to list GithubIssues: %v", err)
+	}
+	var providers issuesv1alpha1.GitProviderList
+	if err := p.Reader.List(ctx, &providers); err != nil {
+		return nil, fmt.Errorf("failed to list GitProviders: %v", err)
+	}
+	configured := map[string]bool{}
+	for _, provider := range providers.Items {
+		configured[strings.ToLower(provider.Spec.Host)] = true
+	}
+
+	namespaceCredentials := map[string]bool{}
+	repos := map[string]string{}
+	for i := range issues.Items {
+		issueObject := &issues.Items[i]
+		if issueObject.Spec.CredentialsSecretRef != nil {
+			continue
+		}
+		host, err := git.RepoHost(issueObject.Spec.Repo)
+		if err != nil || configured[strings.ToLower(host)] {
+			continue
+		}
+		if provider, ok := git.ProviderForHost(host); ok && provider.Name != git.ProviderGitHub {
+			continue
+		}
+		uses, known := namespaceCredentials[issueObject.Namespace]
+		if !known {
+			if uses, err = p.hasNamespaceCredentials(ctx, issueObject.Namespace); err != nil {
+				return nil, err
+			}
+			namespaceCredentials[issueObject.Namespace] = uses
+		}
+		if !uses {
+			repos[strings.ToLower(repositoryLabel(issueObject.Spec.Repo))] = issueObject.Spec.Repo
+		}
+	}
+	return repos, nil
+}
+
+// hasNamespaceCredentials reports whether namespace holds its own token. Only the metadata of the Secret is read.
+func (p *TokenPreflight) hasNamespaceCredentials(ctx context.Context, namespace string) (bool, error) {
+	if p.NamespaceCredentialsSecret == "" {
+		return false, nil
+	}
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	err := p.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: p.NamespaceCredentialsSecret}, secret)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get namespace credentials of %s: %v", namespace, err)
+	}
+	return true, nil
+}
+
+// Problem returns why the operator token can't manage the issues of repoURL. It reports false when the
+// token can, or the repository was not checked yet.
+func (p *TokenPreflight) Problem(repoURL string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	problem, ok := p.problems[strings.ToLower(repositoryLabel(repoURL))]
+	return problem, ok
+}
+
+// Check fails until the first run completed, and while the token can't manage the issues of a repository.
+// It implements healthz.Checker.
+func (p *TokenPreflight) Check(_ *http.Request) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if !p.checked {
+		return errors.New("token preflight has not completed yet")
+	}
+	if len(p.problems) == 0 {
+		return nil
+	}
+	repos := make([]string, 0, len(p.problems))
+	for repo := range p.problems {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return fmt.Errorf("operator token can't manage the issues of %s", strings.Join(repos, ", "))
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakePermissionClient serves the access of its token by owner/repo. Unknown repositories are not found.
+type fakePermissionClient struct {
+	access  map[string]*git.RepoAccess
+	checked []string
+}
+
+func (c *fakePermissionClient) RepoAccess(_ context.Context, owner, repo string) (*git.RepoAccess, error) {
+	c.checked = append(c.checked, owner+"/"+repo)
+	if access, ok := c.access[owner+"/"+repo]; ok {
+		return access, nil
+	}
+	return &git.RepoAccess{}, nil
+}
+
+var _ = Describe("token preflight", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		permissions *fakePermissionClient
+		preflight   *TokenPreflight
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo"},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			issueObject,
+			&issuesv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-issue", Namespace: "team"},
+				Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/private"},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "github-token", Namespace: "team"},
+				Data:       map[string][]byte{"token": []byte("ghp_team")},
+			},
+		).WithStatusSubresource(issueObject).Build()
+
+		permissions = &fakePermissionClient{access: map[string]*git.RepoAccess{
+			"org/repo": {Found: true, HasIssues: true, Permissions: map[string]bool{"pull": true}},
+		}}
+		preflight = &TokenPreflight{
+			Reader:                     k8sClient,
+			Permissions:                permissions,
+			NamespaceCredentialsSecret: "github-token",
+			Log:                        zap.NewNop(),
+		}
+		reconciler = &GithubIssueReconciler{
+			Client:    k8sClient,
+			Log:       zap.NewNop(),
+			Recorder:  record.NewFakeRecorder(10),
+			Clients:   &git.Clients{Default: &git.GitHubIssueClient{}},
+			Preflight: preflight,
+			pending:   newPendingWrites(),
+		}
+	})
+
+	It("is not ready until the first check completed", func() {
+		Expect(preflight.Check(nil)).To(MatchError(ContainSubstring("not completed")))
+	})
+
+	It("only checks the repositories of GithubIssues using the operator token", func() {
+		Expect(preflight.Run(context.Background())).To(Succeed())
+		Expect(permissions.checked).To(Equal([]string{"org/repo"}))
+
+		problem, ok := preflight.Problem("https://github.com/Org/Repo")
+		Expect(ok).To(BeTrue())
+		Expect(problem).To(ContainSubstring("triage role"))
+		Expect(preflight.Check(nil)).To(MatchError(ContainSubstring("org/repo")))
+	})
+
+	It("sets the condition on affected GithubIssues and clears it once the token was granted access", func() {
+		Expect(preflight.Run(context.Background())).To(Succeed())
+		ctx, err := reconciler.withCredentials(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.checkTokenPermissions(ctx, issueObject)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, TokenPermissionsMissingCondition)).To(BeTrue())
+
+		permissions.access["org/repo"].Permissions["triage"] = true
+		Expect(preflight.Run(context.Background())).To(Succeed())
+		Expect(preflight.Check(nil)).To(Succeed())
+		Expect(reconciler.checkTokenPermissions(ctx, issueObject)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, TokenPermissionsMissingCondition)).To(BeTrue())
+	})
+})
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// fakeProjectClient records the boards issues are added to and fails the boards in failing.
+type fakeProjectClient struct {
+	git.IssueClient
+	added   []string
+	failing map[string]bool
+}
+
+func (f *fakeProjectClient) AddToProject(_ context.Context, _, _ string, _ int, project string) error {
+	if f.failing[project] {
+		return errors.New("project not found")
+	}
+	f.added = append(f.added, project)
+	return nil
+}
+
+var _ = Describe("project boards", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		upstream    *fakeProjectClient
+		recorder    *record.FakeRecorder
+		issue       = &git.Issue{Number: 1}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		upstream = &fakeProjectClient{failing: map[string]bool{}}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &GithubIssueReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:      zap.NewNop(),
+			Clients:  &git.Clients{Default: upstream},
+			Recorder: recorder,
+			pending:  newPendingWrites(),
+		}
+	})
+
+	sync := func(projects ...string) *metav1.Condition {
+		issueObject.Spec.Projects = projects
+		Expect(reconciler.syncProjects(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+		return meta.FindStatusCondition(issueObject.Status.Conditions, ProjectsSyncedCondition)
+	}
+
+	It("adds the issue to each board once", func() {
+		condition := sync("PVT_1", "PVT_2")
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("Added"))
+		Expect(issueObject.Status.Projects).To(Equal([]string{"PVT_1", "PVT_2"}))
+
+		sync("PVT_1", "PVT_2")
+		Expect(upstream.added).To(Equal([]string{"PVT_1", "PVT_2"}))
+	})
+
+	It("reports the boards that failed and retries them", func() {
+		upstream.failing["PVT_2"] = true
+		condition := sync("PVT_1", "PVT_2")
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("AddFailed"))
+		Expect(condition.Message).To(Equal("Failed to add to project(s): PVT_2"))
+		Expect(issueObject.Status.Projects).To(Equal([]string{"PVT_1"}))
+		Expect(recorder.Events).To(Receive(ContainSubstring("PVT_2")))
+
+		delete(upstream.failing, "PVT_2")
+		condition = sync("PVT_1", "PVT_2")
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(upstream.added).To(Equal([]string{"PVT_1", "PVT_2"}))
+	})
+
+	It("leaves the condition unset until a board is requested", func() {
+		Expect(sync()).To(BeNil())
+
+		sync("PVT_1")
+		condition := sync()
+		Expect(condition.Reason).To(Equal("NoProjects"))
+		Expect(issueObject.Status.Projects).To(BeEmpty())
+	})
+})
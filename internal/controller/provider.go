@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=gitproviders,verbs=get;list;watch
+
+// providerIssueClient returns the issue client of the GitProvider serving the host of repoURL.
+// It reports false when no GitProvider serves the host, or providers are disabled.
+func (r *GithubIssueReconciler) providerIssueClient(ctx context.Context, repoURL string) (git.IssueClient, bool, error) {
+	if r.Providers == nil {
+		return nil, false, nil
+	}
+	host, err := git.RepoHost(repoURL)
+	if err != nil {
+		return nil, false, err
+	}
+	provider, err := providerForHost(ctx, r.Client, host)
+	if err != nil || provider == nil {
+		return nil, false, err
+	}
+	config, err := providerConfig(ctx, r.Client, r.AuditLog, provider)
+	if err != nil {
+		return nil, false, err
+	}
+	issueClient, err := r.Providers.IssueClient(provider.Name, config)
+	if err != nil {
+		return nil, false, fmt.Errorf("GitProvider %s: %v", provider.Name, err)
+	}
+	return issueClient, true, nil
+}
+
+// providerForHost returns the GitProvider serving host, or nil.
+func providerForHost(ctx context.Context, c client.Client, host string) (*issuesv1alpha1.GitProvider, error) {
+	var providers issuesv1alpha1.GitProviderList
+	if err := c.List(ctx, &providers); err != nil {
+		return nil, fmt.Errorf("failed to list GitProviders: %v", err)
+	}
+	for i, provider := range providers.Items {
+		if strings.EqualFold(provider.Spec.Host, host) {
+			return &providers.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// providerConfig reads the token of a GitProvider and returns its client configuration.
+// The read is recorded in audit.
+func providerConfig(ctx context.Context, c client.Client, audit *zap.Logger, provider *issuesv1alpha1.GitProvider) (git.ProviderConfig, error) {
+	ref := provider.Spec.AuthSecretRef
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret)
+	logging.AuditSecretRead(audit, "GitProvider", provider.Name, ref.Namespace+"/"+ref.Name, ref.Key, "credentials", err)
+	if err != nil {
+		return git.ProviderConfig{}, fmt.Errorf("failed to read credentials from Secret %s/%s: %v", ref.Namespace, ref.Name, err)
+	}
+	token, ok := secret.Data[ref.Key]
+	if !ok || len(token) == 0 {
+		return git.ProviderConfig{}, fmt.Errorf("key %s not found in Secret %s/%s", ref.Key, ref.Namespace, ref.Name)
+	}
+
+	config := git.ProviderConfig{
+		Type:            string(provider.Spec.Type),
+		Host:            provider.Spec.Host,
+		APIURL:          provider.Spec.APIURL,
+		Token:           string(token),
+		RequestsPerHour: provider.Spec.RequestsPerHour,
+		ProxyURL:        provider.Spec.ProxyURL,
+	}
+	if config.Type == "" {
+		config.Type = git.ProviderGitHub
+	}
+	if tls := provider.Spec.TLS; tls != nil {
+		config.CABundle = tls.CABundle
+		config.InsecureSkipVerify = tls.InsecureSkipVerify
+	}
+	return config, nil
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setRateLimited sets the RateLimited condition when the reconcile failed while GitHub rate limits the token
+// of the issue, and returns how long to wait for the limit to reset. The condition is written with the failure.
+func (r *GithubIssueReconciler) setRateLimited(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, reconcileErr error, now time.Time) time.Duration {
+	if reconcileErr == nil || r.RateLimits == nil {
+		return 0
+	}
+	resetAt, limited := r.RateLimits.LimitedUntil(credential(issueObject), now)
+	if !limited {
+		return 0
+	}
+	if meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               RateLimitedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RateLimitExceeded",
+		Message:            fmt.Sprintf("GitHub rate limit resets at %s", resetAt.UTC().Format(time.RFC3339)),
+		ObservedGeneration: issueObject.Generation,
+	}) {
+		r.logger(ctx).Warn("GitHub rate limit exceeded, requeueing once it resets", zap.Time("resetAt", resetAt))
+	}
+	return resetAt.Sub(now)
+}
+
+// clearRateLimited flips the RateLimited condition once the issue synced again. The condition is written
+// with the sync.
+func clearRateLimited(issueObject *issuesv1alpha1.GithubIssue) {
+	if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, RateLimitedCondition) {
+		return
+	}
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               RateLimitedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RateLimitReset",
+		Message:            "GitHub rate limit has reset",
+		ObservedGeneration: issueObject.Generation,
+	})
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("rate limits", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		resetAt     = time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	)
+
+	BeforeEach(func() {
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		reconciler = &GithubIssueReconciler{Log: zap.NewNop(), RateLimits: &git.RateLimitTracker{}}
+	})
+
+	respond := func(status int, headers map[string]string) {
+		header := http.Header{}
+		for key, value := range headers {
+			header.Set(key, value)
+		}
+		transport := reconciler.RateLimits.Transport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Header: header, Body: http.NoBody}, nil
+		}), git.CredentialOperator)
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/o/r/issues", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = transport.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("requeues a failed reconcile once the rate limit resets", func() {
+		respond(http.StatusForbidden, map[string]string{
+			git.RateLimitRemainingHeader: "0",
+			git.RateLimitResetHeader:     strconv.FormatInt(resetAt.Unix(), 10),
+		})
+		now := resetAt.Add(-5 * time.Minute)
+		wait := reconciler.setRateLimited(context.Background(), issueObject, errors.New("failed to list issues"), now)
+		Expect(wait).To(Equal(5 * time.Minute))
+		condition := meta.FindStatusCondition(issueObject.Status.Conditions, RateLimitedCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring(resetAt.UTC().Format(time.RFC3339)))
+
+		clearRateLimited(issueObject)
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, RateLimitedCondition)).To(BeTrue())
+	})
+
+	It("honors Retry-After of secondary rate limits", func() {
+		respond(http.StatusTooManyRequests, map[string]string{git.RetryAfterHeader: "60"})
+		_, limited := reconciler.RateLimits.LimitedUntil(git.CredentialOperator, time.Now())
+		Expect(limited).To(BeTrue())
+	})
+
+	It("does not retry rate limited fetches in-process", func() {
+		secondary := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{git.RetryAfterHeader: {"60"}}}
+		for _, err := range []error{
+			&github.RateLimitError{Message: "API rate limit exceeded"},
+			&github.AbuseRateLimitError{Message: "secondary rate limit"},
+			&github.ErrorResponse{Response: secondary},
+		} {
+			Expect(reconciler.shouldRetry(context.Background(), fmt.Errorf("failed to list issues: %w", err))).To(BeFalse())
+		}
+		Expect(reconciler.shouldRetry(context.Background(), errors.New("connection reset by peer"))).To(BeTrue())
+	})
+
+	It("leaves failures without a rate limit to the backoff", func() {
+		respond(http.StatusForbidden, map[string]string{git.RateLimitRemainingHeader: "42"})
+		Expect(reconciler.setRateLimited(context.Background(), issueObject, errors.New("forbidden"), time.Now())).To(BeZero())
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, RateLimitedCondition)).To(BeNil())
+	})
+})
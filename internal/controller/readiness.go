@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Conditions following the kstatus conventions, so tools like Argo CD, Flux and kubectl wait
+// read the health of a GithubIssue. Reconciling and Stalled are only present while true.
+const (
+	// ReadyCondition summarizes the other conditions: true once the spec is applied to GitHub.
+	ReadyCondition = "Ready"
+	// ReconcilingCondition is true while the operator is still working towards the spec.
+	ReconcilingCondition = "Reconciling"
+	// StalledCondition is true while the operator cannot make progress without a change from the user.
+	StalledCondition = "Stalled"
+)
+
+// stallingConditions are conditions that hold the reconcile back until the user acts.
+var stallingConditions = []string{
+	InvalidSpecCondition, PossibleDuplicateCondition, ConflictCondition,
+	RepoNotFoundCondition, AuthenticationFailedCondition, IssuesDisabledCondition,
+}
+
+// pausingConditions are conditions under which the operator deliberately does not sync the issue.
+var pausingConditions = []string{
+	SuspendedCondition, ConvertedToDiscussionCondition, MarkedDuplicateCondition, DryRunCondition, ScheduledCondition,
+}
+
+// summarizeReadiness derives the Ready, Reconciling and Stalled conditions from the phase and the other conditions.
+func summarizeReadiness(issueObject *issuesv1alpha1.GithubIssue) {
+	reconciling, stalled := false, false
+	ready := metav1.Condition{Type: ReadyCondition, Status: metav1.ConditionFalse}
+
+	if condition := firstTrue(issueObject.Status.Conditions, stallingConditions); condition != nil {
+		stalled = true
+		ready.Reason, ready.Message = condition.Type, condition.Message
+	} else if condition := firstTrue(issueObject.Status.Conditions, pausingConditions); condition != nil {
+		ready.Reason, ready.Message = condition.Type, condition.Message
+	} else {
+		switch issueObject.Status.Phase {
+		case issuesv1alpha1.PhaseSynced:
+			ready.Status, ready.Reason, ready.Message = metav1.ConditionTrue, "Synced", syncedMessage(issueObject)
+		case issuesv1alpha1.PhaseError:
+			reconciling = true
+			ready.Reason, ready.Message = "ReconcileFailed", issueObject.Status.LastSyncError
+		case issuesv1alpha1.PhaseTerminating:
+			reconciling = true
+			ready.Reason, ready.Message = "Terminating", "Closing the upstream issue"
+			if condition := meta.FindStatusCondition(issueObject.Status.Conditions, DeletionBlockedCondition); condition != nil && condition.Status == metav1.ConditionTrue {
+				ready.Message = condition.Message
+			}
+		default:
+			reconciling = true
+			ready.Reason, ready.Message = "Pending", "The issue has not been synced yet"
+		}
+	}
+	if ready.Message == "" {
+		ready.Message = ready.Reason
+	}
+
+	ready.ObservedGeneration = issueObject.Generation
+	meta.SetStatusCondition(&issueObject.Status.Conditions, ready)
+	setAbnormalCondition(issueObject, ReconcilingCondition, reconciling, ready.Reason, ready.Message)
+	setAbnormalCondition(issueObject, StalledCondition, stalled, ready.Reason, ready.Message)
+}
+
+// syncedMessage summarizes the upstream state reported by IssueIsOpen and IssueHasPR.
+func syncedMessage(issueObject *issuesv1alpha1.GithubIssue) string {
+	var parts []string
+	for _, conditionType := range []string{"IssueIsOpen", "IssueHasPR"} {
+		if condition := meta.FindStatusCondition(issueObject.Status.Conditions, conditionType); condition != nil {
+			parts = append(parts, condition.Message)
+		}
+	}
+	if len(parts) == 0 {
+		return "The issue is synced with GitHub"
+	}
+	return fmt.Sprintf("The issue is synced with GitHub: %s", strings.Join(parts, ", "))
+}
+
+// setAbnormalCondition sets a kstatus abnormal-true condition while it applies and removes it otherwise.
+func setAbnormalCondition(issueObject *issuesv1alpha1.GithubIssue, conditionType string, applies bool, reason, message string) {
+	if !applies {
+		meta.RemoveStatusCondition(&issueObject.Status.Conditions, conditionType)
+		return
+	}
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: issueObject.Generation,
+	})
+}
+
+func firstTrue(conditions []metav1.Condition, types []string) *metav1.Condition {
+	for _, conditionType := range types {
+		if condition := meta.FindStatusCondition(conditions, conditionType); condition != nil && condition.Status == metav1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
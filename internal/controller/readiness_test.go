@@ -0,0 +1,46 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("readiness", func() {
+	var issueObject *issuesv1alpha1.GithubIssue
+
+	BeforeEach(func() {
+		issueObject = &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	})
+
+	It("is reconciling until the first sync", func() {
+		summarizeReadiness(issueObject)
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, ReadyCondition)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, ReconcilingCondition)).To(BeTrue())
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, StalledCondition)).To(BeNil())
+	})
+
+	It("is ready once synced, summarizing the upstream state", func() {
+		summarizeReadiness(issueObject)
+		issueObject.Status.Phase = issuesv1alpha1.PhaseSynced
+		meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{Type: "IssueIsOpen", Status: metav1.ConditionTrue, Reason: "IssueIsOpen", Message: "Issue is open"})
+		summarizeReadiness(issueObject)
+
+		ready := meta.FindStatusCondition(issueObject.Status.Conditions, ReadyCondition)
+		Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		Expect(ready.Message).To(ContainSubstring("Issue is open"))
+		Expect(ready.ObservedGeneration).To(Equal(int64(2)))
+		Expect(meta.FindStatusCondition(issueObject.Status.Conditions, ReconcilingCondition)).To(BeNil())
+	})
+
+	It("is stalled while the user has to act", func() {
+		issueObject.Status.Phase = issuesv1alpha1.PhaseSynced
+		meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{Type: ConflictCondition, Status: metav1.ConditionTrue, Reason: "ExternalEdit", Message: "edited"})
+		summarizeReadiness(issueObject)
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, ReadyCondition)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, StalledCondition)).To(BeTrue())
+	})
+})
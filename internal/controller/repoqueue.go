@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// repoBusyRequeue is how long a reconcile waits when its repository already has MaxInFlightPerRepo reconciles in flight.
+const repoBusyRequeue = time.Second
+
+// repoQueue tracks the queued and in-flight reconciles per repository, so one noisy repository starving the
+// others shows up in the metrics, and caps the reconciles in flight per repository.
+type repoQueue struct {
+	mu       sync.Mutex
+	limit    int
+	queued   map[types.NamespacedName]string
+	inFlight map[string]int
+}
+
+// newRepoQueue returns a repoQueue allowing limit reconciles in flight per repository. Zero is unlimited.
+func newRepoQueue(limit int) *repoQueue {
+	return &repoQueue{limit: limit, queued: map[types.NamespacedName]string{}, inFlight: map[string]int{}}
+}
+
+// enqueued counts a reconcile queued for obj. Requests coalesced into an already queued one are counted once.
+func (q *repoQueue) enqueued(obj client.Object) {
+	issueObject, ok := obj.(*issuesv1alpha1.GithubIssue)
+	if q == nil || !ok {
+		return
+	}
+	q.add(client.ObjectKeyFromObject(issueObject), repositoryLabel(issueObject.RepoURL()))
+}
+
+func (q *repoQueue) add(key types.NamespacedName, repository string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.queued[key]; ok {
+		return
+	}
+	q.queued[key] = repository
+	metrics.PendingReconciles.WithLabelValues(repository).Inc()
+}
+
+// started stops counting the queued reconcile of key.
+func (q *repoQueue) started(key types.NamespacedName) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	repository, ok := q.queued[key]
+	if !ok {
+		return
+	}
+	delete(q.queued, key)
+	metrics.PendingReconciles.WithLabelValues(repository).Dec()
+}
+
+// acquire takes an in-flight slot of repository. It reports false, and counts the reconcile of key as queued
+// again, while the repository has no slot left.
+func (q *repoQueue) acquire(key types.NamespacedName, repository string) bool {
+	if q == nil {
+		return true
+	}
+	q.mu.Lock()
+	if q.limit > 0 && q.inFlight[repository] >= q.limit {
+		q.mu.Unlock()
+		q.add(key, repository)
+		return false
+	}
+	defer q.mu.Unlock()
+	q.inFlight[repository]++
+	metrics.InFlightReconciles.WithLabelValues(repository).Inc()
+	return true
+}
+
+// release returns an in-flight slot of repository.
+func (q *repoQueue) release(repository string) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.inFlight[repository]--
+	if q.inFlight[repository] <= 0 {
+		delete(q.inFlight, repository)
+	}
+	metrics.InFlightReconciles.WithLabelValues(repository).Dec()
+}
+
+// repositoryLabel returns the owner/repo of a repository URL for the per-repository metrics,
+// or the URL itself when it can't be parsed.
+func repositoryLabel(repoURL string) string {
+	owner, repo, err := git.ParseRepoURL(repoURL)
+	if err != nil {
+		return repoURL
+	}
+	return owner + "/" + repo
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("repository queue", func() {
+	issueIn := func(name, repoURL string) *issuesv1alpha1.GithubIssue {
+		return &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: repoURL},
+		}
+	}
+
+	It("counts queued reconciles per repository once", func() {
+		queue := newRepoQueue(0)
+		pending := metrics.PendingReconciles.WithLabelValues("queue-org/noisy")
+
+		queue.enqueued(issueIn("a", "https://github.com/queue-org/noisy"))
+		queue.enqueued(issueIn("a", "https://github.com/queue-org/noisy"))
+		queue.enqueued(issueIn("b", "https://github.com/queue-org/noisy"))
+		Expect(testutil.ToFloat64(pending)).To(Equal(2.0))
+
+		queue.started(types.NamespacedName{Namespace: "default", Name: "a"})
+		queue.started(types.NamespacedName{Namespace: "default", Name: "a"})
+		Expect(testutil.ToFloat64(pending)).To(Equal(1.0))
+	})
+
+	It("caps the reconciles in flight per repository", func() {
+		queue := newRepoQueue(1)
+		inFlight := metrics.InFlightReconciles.WithLabelValues("queue-org/capped")
+		pending := metrics.PendingReconciles.WithLabelValues("queue-org/capped")
+		first := types.NamespacedName{Namespace: "default", Name: "first"}
+		second := types.NamespacedName{Namespace: "default", Name: "second"}
+
+		Expect(queue.acquire(first, "queue-org/capped")).To(BeTrue())
+		Expect(queue.acquire(second, "queue-org/capped")).To(BeFalse())
+		Expect(queue.acquire(second, "queue-org/other")).To(BeTrue())
+		Expect(testutil.ToFloat64(inFlight)).To(Equal(1.0))
+		Expect(testutil.ToFloat64(pending)).To(Equal(1.0))
+
+		queue.release("queue-org/capped")
+		queue.started(second)
+		Expect(queue.acquire(second, "queue-org/capped")).To(BeTrue())
+		Expect(testutil.ToFloat64(pending)).To(BeZero())
+	})
+})
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// errRepositoryNotAllowed is returned for a repository no RepositoryBinding allows the namespace of a GithubIssue.
+var errRepositoryNotAllowed = errors.New("repository not allowed")
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=repositorybindings,verbs=get;list;watch
+
+// authorizeRepository returns errRepositoryNotAllowed, wrapped, when EnforceRepositoryBindings is set and no
+// RepositoryBinding allows namespace to file issues in repoURL.
+func (r *GithubIssueReconciler) authorizeRepository(ctx context.Context, namespace, repoURL string) error {
+	if !r.EnforceRepositoryBindings {
+		return nil
+	}
+	var bindings issuesv1alpha1.RepositoryBindingList
+	if err := r.List(ctx, &bindings); err != nil {
+		return fmt.Errorf("failed to list RepositoryBindings: %v", err)
+	}
+
+	var namespaceObject *corev1.Namespace
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if !binding.Allows(repoURL) {
+			continue
+		}
+		if binding.Spec.NamespaceSelector != nil && namespaceObject == nil {
+			namespaceObject = &corev1.Namespace{}
+			if err := r.Get(ctx, client.ObjectKey{Name: namespace}, namespaceObject); err != nil {
+				return fmt.Errorf("failed to get namespace %s: %v", namespace, err)
+			}
+		}
+		var namespaceLabels map[string]string
+		if namespaceObject != nil {
+			namespaceLabels = namespaceObject.Labels
+		}
+		covers, err := binding.Covers(namespace, namespaceLabels)
+		if err != nil {
+			r.logger(ctx).Warn("Ignoring RepositoryBinding with an invalid namespaceSelector", zap.String("binding", binding.Name), zap.Error(err))
+			continue
+		}
+		if covers {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no RepositoryBinding allows namespace %s to file issues in %s", errRepositoryNotAllowed, namespace, repoURL)
+}
+
+// AuthorizeRepositories checks that the namespace of a GithubIssue may file issues in its repository and in the
+// repositories of its mirrors. A repositoryRef selecting a missing GithubRepository is not checked: the issue
+// can't be reconciled until the GithubRepository exists.
+func (r *GithubIssueReconciler) AuthorizeRepositories(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) error {
+	issueObject := githubIssue.DeepCopy()
+	if err := r.applyRepositoryRef(ctx, issueObject); err != nil && !errors.Is(err, errRepositoryNotFound) {
+		return err
+	}
+	if issueObject.Spec.Repo != "" {
+		if err := r.authorizeRepository(ctx, issueObject.Namespace, issueObject.Spec.Repo); err != nil {
+			return err
+		}
+	}
+	for _, mirror := range issueObject.Spec.Mirrors {
+		if err := r.authorizeRepository(ctx, issueObject.Namespace, mirror.Repo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindingHandler queues every GithubIssue when a RepositoryBinding changes: the repositories their namespace
+// is allowed may have changed.
+func (r *GithubIssueReconciler) bindingHandler() handler.EventHandler {
+	enqueue := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		var issues issuesv1alpha1.GithubIssueList
+		if err := r.List(ctx, &issues); err != nil {
+			r.Log.Error("Failed to list issues after a RepositoryBinding change", zap.Error(err))
+			return
+		}
+		for i := range issues.Items {
+			r.causes.enqueue(&issues.Items[i], causeReference, q)
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, _ event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+				enqueue(ctx, q)
+			}
+		},
+		DeleteFunc: func(ctx context.Context, _ event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q)
+		},
+	}
+}
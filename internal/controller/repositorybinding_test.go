@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("repository bindings", func() {
+	var reconciler *GithubIssueReconciler
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		byName := &issuesv1alpha1.RepositoryBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "docs"},
+			Spec: issuesv1alpha1.RepositoryBindingSpec{
+				Namespaces:   []string{"docs"},
+				Repositories: []string{"org/docs"},
+			},
+		}
+		bySelector := &issuesv1alpha1.RepositoryBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec: issuesv1alpha1.RepositoryBindingSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+				Repositories:      []string{"github.com/platform/*"},
+			},
+		}
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "platform"}}}
+		reconciler = &GithubIssueReconciler{
+			Client:                    fake.NewClientBuilder().WithScheme(testScheme).WithObjects(byName, bySelector, namespace).Build(),
+			Log:                       zap.NewNop(),
+			EnforceRepositoryBindings: true,
+		}
+	})
+
+	It("allows the repositories bound to the namespace", func() {
+		Expect(reconciler.authorizeRepository(context.Background(), "docs", "https://github.example.com/org/docs")).To(Succeed())
+		Expect(reconciler.authorizeRepository(context.Background(), "payments", "https://github.com/Platform/api")).To(Succeed())
+	})
+
+	It("rejects the other repositories and namespaces", func() {
+		err := reconciler.authorizeRepository(context.Background(), "docs", "https://github.com/org/api")
+		Expect(errors.Is(err, errRepositoryNotAllowed)).To(BeTrue())
+		err = reconciler.authorizeRepository(context.Background(), "payments", "https://github.example.com/platform/api")
+		Expect(errors.Is(err, errRepositoryNotAllowed)).To(BeTrue())
+	})
+
+	It("checks the mirrors of a GithubIssue", func() {
+		issueObject := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "payments"},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:    "https://github.com/platform/api",
+				Mirrors: []issuesv1alpha1.IssueMirror{{Repo: "https://github.com/org/docs"}},
+			},
+		}
+		Expect(reconciler.AuthorizeRepositories(context.Background(), issueObject)).To(MatchError(ContainSubstring("https://github.com/org/docs")))
+	})
+
+	It("allows every repository unless enforced", func() {
+		reconciler.EnforceRepositoryBindings = false
+		Expect(reconciler.authorizeRepository(context.Background(), "docs", "https://github.com/org/api")).To(Succeed())
+	})
+})
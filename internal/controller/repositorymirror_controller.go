@@ -0,0 +1,211 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// RepositoryMirrorLabel is set on GithubIssues imported by a RepositoryMirror.
+	RepositoryMirrorLabel = "issues.dana.io/repository-mirror"
+
+	// MirrorSyncedCondition reports whether the repository issues were imported.
+	MirrorSyncedCondition = "MirrorSynced"
+
+	defaultMirrorInterval = 5 * time.Minute
+)
+
+// RepositoryMirrorReconciler reconciles a RepositoryMirror object
+type RepositoryMirrorReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    *zap.Logger
+	// Clients picks the client of the provider serving the mirrored repository.
+	Clients *git.Clients
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=repositorymirrors,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=repositorymirrors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues/status,verbs=get;update;patch
+
+func (r *RepositoryMirrorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("namespace", req.Namespace), zap.String("name", req.Name))
+
+	mirror := &issuesv1alpha1.RepositoryMirror{}
+	if err := r.Get(ctx, req.NamespacedName, mirror); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error("unable to fetch mirror object", zap.Error(err))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if !mirror.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	owner, repo, err := git.ParseRepoURL(mirror.Spec.Repo)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed parse repoURL : %v", err)
+	}
+
+	issueClient, err := r.Clients.ForRepo(mirror.Spec.Repo)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	upstream, err := issueClient.List(ctx, owner, repo)
+	if err != nil {
+		log.Error("Failed to list upstream issues", zap.Error(err))
+		r.setMirrorCondition(mirror, metav1.ConditionFalse, "ListFailed", err.Error())
+		if statusErr := r.Status().Update(ctx, mirror); statusErr != nil {
+			log.Error("Failed to update mirror status", zap.Error(statusErr))
+		}
+		return ctrl.Result{}, err
+	}
+
+	for _, issue := range upstream {
+		if !r.mirrored(mirror, issue) {
+			continue
+		}
+		if err := r.importIssue(ctx, mirror, issue); err != nil {
+			r.setMirrorCondition(mirror, metav1.ConditionFalse, "ImportFailed", err.Error())
+			if statusErr := r.Status().Update(ctx, mirror); statusErr != nil {
+				log.Error("Failed to update mirror status", zap.Error(statusErr))
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	var imported issuesv1alpha1.GithubIssueList
+	if err := r.List(ctx, &imported, client.InNamespace(mirror.Namespace), client.MatchingLabels{RepositoryMirrorLabel: mirror.Name}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list imported issues: %v", err)
+	}
+	mirror.Status.ImportedIssues = int32(len(imported.Items))
+	now := metav1.Now()
+	mirror.Status.LastSyncTime = &now
+	r.setMirrorCondition(mirror, metav1.ConditionTrue, "Synced",
+		fmt.Sprintf("%d issues imported from %s/%s", len(imported.Items), owner, repo))
+	if err := r.Status().Update(ctx, mirror); err != nil {
+		log.Error("Failed to update mirror status", zap.Error(err))
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+
+	interval := mirror.Spec.Interval.Duration
+	if interval <= 0 {
+		interval = defaultMirrorInterval
+	}
+	log.Info("Mirror synced", zap.Int("issues", len(imported.Items)), zap.Duration("requeueAfter", interval))
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// mirrored reports whether the upstream issue is imported by the mirror: it is open, carries the labels of the
+// mirror, and carries no marker or the marker of the GithubIssue the mirror imported it as. Issues created by
+// other GithubIssues are left to them.
+func (r *RepositoryMirrorReconciler) mirrored(mirror *issuesv1alpha1.RepositoryMirror, issue *git.Issue) bool {
+	if issue.State == "closed" {
+		return false
+	}
+	for _, label := range mirror.Spec.Labels {
+		if !slices.Contains(issue.Labels, label) {
+			return false
+		}
+	}
+	key, ok := git.ParseMarker(issue.Description)
+	return !ok || key == mirror.Namespace+"/"+mirroredName(mirror.Name, issue.Number)
+}
+
+// importIssue creates the GithubIssue of an upstream issue, or updates it from the issue when the mirror keeps
+// its issues updated. The fields the mirror does not import, such as the priority, are left as set on the GithubIssue.
+func (r *RepositoryMirrorReconciler) importIssue(ctx context.Context, mirror *issuesv1alpha1.RepositoryMirror, issue *git.Issue) error {
+	name := mirroredName(mirror.Name, issue.Number)
+	issueObject := &issuesv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: mirror.Namespace},
+	}
+	keepUpdated := mirror.Spec.KeepUpdated == nil || *mirror.Spec.KeepUpdated
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, issueObject, func() error {
+		if issueObject.ResourceVersion != "" && !keepUpdated {
+			return nil
+		}
+		if issueObject.Labels == nil {
+			issueObject.Labels = map[string]string{}
+		}
+		issueObject.Labels[RepositoryMirrorLabel] = mirror.Name
+		issueObject.Spec.Repo = mirror.Spec.Repo
+		issueObject.Spec.Title = issue.Title
+		issueObject.Spec.Description = git.StripMarker(issue.Description)
+		issueObject.Spec.Labels = slices.Clone(issue.Labels)
+		issueObject.Spec.Assignees = slices.Clone(issue.Assignees)
+		issueObject.Spec.Locked = issue.Locked
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import issue #%d as %s: %v", issue.Number, name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Log.Info("Imported issue", zap.String("githubIssue", name), zap.Int("issue", issue.Number), zap.String("operation", string(result)))
+	}
+
+	// Linking the GithubIssue to its issue keeps it from opening a new one should the titles diverge.
+	if issueObject.Status.IssueNumber != 0 {
+		return nil
+	}
+	patch := client.MergeFrom(issueObject.DeepCopy())
+	issueObject.Status.IssueNumber = issue.Number
+	if err := r.Status().Patch(ctx, issueObject, patch); err != nil {
+		return fmt.Errorf("failed to link %s to issue #%d: %v", name, issue.Number, err)
+	}
+	return nil
+}
+
+func (r *RepositoryMirrorReconciler) setMirrorCondition(mirror *issuesv1alpha1.RepositoryMirror, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&mirror.Status.Conditions, metav1.Condition{
+		Type:               MirrorSyncedCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: mirror.Generation,
+	})
+}
+
+// mirroredName is the name of the GithubIssue a mirror imports an issue as.
+func mirroredName(mirrorName string, number int) string {
+	return fmt.Sprintf("%s-%d", mirrorName, number)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RepositoryMirrorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.RepositoryMirror{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("repositorymirror").
+		Complete(r)
+}
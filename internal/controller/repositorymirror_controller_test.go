@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// listingIssueClient serves a fixed list of upstream issues.
+type listingIssueClient struct {
+	git.IssueClient
+	issues []*git.Issue
+}
+
+func (f *listingIssueClient) List(_ context.Context, _, _ string) ([]*git.Issue, error) {
+	return f.issues, nil
+}
+
+var _ = Describe("RepositoryMirror controller", func() {
+	const repo = "https://github.com/example-org/example-repo"
+	var (
+		reconciler *RepositoryMirrorReconciler
+		upstream   *listingIssueClient
+		mirror     *issuesv1alpha1.RepositoryMirror
+		key        = types.NamespacedName{Name: "legacy", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		mirror = &issuesv1alpha1.RepositoryMirror{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       issuesv1alpha1.RepositoryMirrorSpec{Repo: repo, Labels: []string{"bug"}},
+		}
+		upstream = &listingIssueClient{issues: []*git.Issue{
+			{Number: 1, Title: "Crash on start", Description: "Stack trace", State: "open", Labels: []string{"bug"}, Assignees: []string{"octocat"}},
+			{Number: 2, Title: "Add dark mode", State: "open", Labels: []string{"enhancement"}},
+			{Number: 3, Title: "Managed", Description: git.WithMarker("Body", "default/other"), State: "open", Labels: []string{"bug"}},
+			{Number: 4, Title: "Linked", Description: git.WithMarker("Body", "default/legacy-4"), State: "open", Labels: []string{"bug"}},
+		}}
+		reconciler = &RepositoryMirrorReconciler{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mirror).
+				WithStatusSubresource(mirror, &issuesv1alpha1.GithubIssue{}).Build(),
+			Scheme:  testScheme,
+			Log:     zap.NewNop(),
+			Clients: &git.Clients{Default: upstream},
+		}
+	})
+
+	reconcile := func() *issuesv1alpha1.RepositoryMirror {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(context.Background(), key, mirror)).To(Succeed())
+		return mirror
+	}
+
+	imported := func(name string) *issuesv1alpha1.GithubIssue {
+		issueObject := &issuesv1alpha1.GithubIssue{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: name, Namespace: key.Namespace}, issueObject)).To(Succeed())
+		return issueObject
+	}
+
+	It("imports the matching issues that no other GithubIssue manages and links them", func() {
+		Expect(reconcile().Status.ImportedIssues).To(Equal(int32(2)))
+		Expect(meta.IsStatusConditionTrue(mirror.Status.Conditions, MirrorSyncedCondition)).To(BeTrue())
+
+		issueObject := imported("legacy-1")
+		Expect(issueObject.Labels).To(HaveKeyWithValue(RepositoryMirrorLabel, "legacy"))
+		Expect(issueObject.Spec.Repo).To(Equal(repo))
+		Expect(issueObject.Spec.Title).To(Equal("Crash on start"))
+		Expect(issueObject.Spec.Assignees).To(Equal([]string{"octocat"}))
+		Expect(issueObject.Status.IssueNumber).To(Equal(1))
+		Expect(issueObject.OwnerReferences).To(BeEmpty())
+
+		Expect(imported("legacy-4").Spec.Description).To(Equal("Body"))
+
+		var all issuesv1alpha1.GithubIssueList
+		Expect(reconciler.List(context.Background(), &all, client.InNamespace(key.Namespace))).To(Succeed())
+		Expect(all.Items).To(HaveLen(2))
+	})
+
+	It("copies upstream edits unless keepUpdated is false", func() {
+		reconcile()
+		upstream.issues[0].Title = "Crash on startup"
+		reconcile()
+		Expect(imported("legacy-1").Spec.Title).To(Equal("Crash on startup"))
+
+		mirror.Spec.KeepUpdated = new(bool)
+		Expect(reconciler.Update(context.Background(), mirror)).To(Succeed())
+		upstream.issues[0].Title = "Crash when starting"
+		reconcile()
+		Expect(imported("legacy-1").Spec.Title).To(Equal("Crash on startup"))
+	})
+})
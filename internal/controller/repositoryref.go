@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// repositoryRefIndex lists the GithubIssues that select a GithubRepository through spec.repositoryRef.
+const repositoryRefIndex = "spec.repositoryRef"
+
+// errRepositoryNotFound is reported in the InvalidSpec condition while spec.repositoryRef selects a missing
+// GithubRepository.
+var errRepositoryNotFound = errors.New("GithubRepository not found")
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubrepositories,verbs=get;list;watch
+
+// applyRepositoryRef fills the in-memory spec of an issue selecting a GithubRepository through spec.repositoryRef
+// with the repository URL and the defaults of the GithubRepository, and records the URL in status.repo.
+// The spec is never written back. A deleted GithubIssue whose GithubRepository is gone keeps closing the issue
+// recorded in status.repo.
+func (r *GithubIssueReconciler) applyRepositoryRef(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	ref := issueObject.Spec.RepositoryRef
+	if ref == nil {
+		return nil
+	}
+
+	repository := &issuesv1alpha1.GithubRepository{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: issueObject.Namespace, Name: ref.Name}, repository)
+	if apierrors.IsNotFound(err) {
+		if !issueObject.DeletionTimestamp.IsZero() && issueObject.Status.Repo != "" {
+			issueObject.Spec.Repo = issueObject.Status.Repo
+			return nil
+		}
+		return fmt.Errorf("%w: %s", errRepositoryNotFound, ref.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get GithubRepository %s: %v", ref.Name, err)
+	}
+
+	spec := &issueObject.Spec
+	spec.Repo = repository.Spec.Repo
+	for _, label := range repository.Spec.DefaultLabels {
+		if !slices.Contains(spec.Labels, label) {
+			spec.Labels = append(spec.Labels, label)
+		}
+	}
+	if len(spec.Assignees) == 0 {
+		spec.Assignees = repository.Spec.DefaultAssignees
+	}
+	if spec.CredentialsSecretRef == nil {
+		spec.CredentialsSecretRef = repository.Spec.CredentialsSecretRef
+	}
+	if spec.SyncIntervalSeconds == nil {
+		spec.SyncIntervalSeconds = repository.Spec.SyncIntervalSeconds
+	}
+	issueObject.Status.Repo = repository.Spec.Repo
+	return nil
+}
+
+func indexRepositoryRefs(obj client.Object) []string {
+	if ref := obj.(*issuesv1alpha1.GithubIssue).Spec.RepositoryRef; ref != nil {
+		return []string{ref.Name}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("repository references", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		repository := &issuesv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-repo", Namespace: "default"},
+			Spec: issuesv1alpha1.GithubRepositorySpec{
+				Repo:                 "https://github.com/org/repo",
+				CredentialsSecretRef: &issuesv1alpha1.CredentialsSecretReference{Name: "token", Key: "token"},
+				DefaultLabels:        []string{"team/platform", "bug"},
+				DefaultAssignees:     []string{"octocat"},
+				SyncIntervalSeconds:  ptr.To[int32](600),
+			},
+		}
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				RepositoryRef: &corev1.LocalObjectReference{Name: "example-repo"},
+				Title:         "Rotate the deploy keys",
+				Labels:        []string{"bug"},
+				Assignees:     []string{"hubot"},
+			},
+		}
+		reconciler = &GithubIssueReconciler{
+			Client:  fake.NewClientBuilder().WithScheme(testScheme).WithObjects(repository, issueObject).Build(),
+			Log:     zap.NewNop(),
+			pending: newPendingWrites(),
+		}
+	})
+
+	It("applies the repository and its defaults without writing them to the spec", func() {
+		Expect(reconciler.applyRepositoryRef(context.Background(), issueObject)).To(Succeed())
+		Expect(issueObject.Spec.Repo).To(Equal("https://github.com/org/repo"))
+		Expect(issueObject.Spec.Labels).To(Equal([]string{"bug", "team/platform"}))
+		Expect(issueObject.Spec.Assignees).To(Equal([]string{"hubot"}))
+		Expect(issueObject.Spec.CredentialsSecretRef.Name).To(Equal("token"))
+		Expect(*issueObject.Spec.SyncIntervalSeconds).To(BeEquivalentTo(600))
+		Expect(issueObject.Status.Repo).To(Equal("https://github.com/org/repo"))
+
+		Expect(reconciler.ensureFinalizer(context.Background(), issueObject)).To(Succeed())
+		stored := &issuesv1alpha1.GithubIssue{}
+		Expect(reconciler.Get(context.Background(), client.ObjectKeyFromObject(issueObject), stored)).To(Succeed())
+		Expect(stored.Finalizers).NotTo(BeEmpty())
+		Expect(stored.Spec.Repo).To(BeEmpty())
+		Expect(stored.Spec.Labels).To(Equal([]string{"bug"}))
+	})
+
+	It("reports a missing GithubRepository as an invalid spec", func() {
+		issueObject.Spec.RepositoryRef.Name = "missing"
+		err := reconciler.applyRepositoryRef(context.Background(), issueObject)
+		Expect(err).To(MatchError(errRepositoryNotFound))
+		Expect(invalidSpecReason(err)).To(Equal("RepositoryNotFound"))
+	})
+
+	It("keeps closing the recorded repository once the GithubRepository is gone", func() {
+		issueObject.Spec.RepositoryRef.Name = "missing"
+		issueObject.Status.Repo = "https://github.com/org/repo"
+		issueObject.DeletionTimestamp = ptr.To(metav1.Now())
+		Expect(reconciler.applyRepositoryRef(context.Background(), issueObject)).To(Succeed())
+		Expect(issueObject.Spec.Repo).To(Equal("https://github.com/org/repo"))
+	})
+})
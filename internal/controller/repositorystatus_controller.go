@@ -0,0 +1,222 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// RepositoryHealthyCondition is true while no GithubIssue of the repository fails to sync or is rate limited.
+	RepositoryHealthyCondition = "Healthy"
+
+	// maxFailingIssues bounds status.failing, keeping the RepositoryStatus small for repositories with many issues.
+	maxFailingIssues = 10
+)
+
+// RepositoryStatusReconciler keeps a RepositoryStatus per repository referenced by GithubIssues, summarizing
+// them across namespaces for platform operators.
+type RepositoryStatusReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Log    *zap.Logger
+	// RateLimits reports until when GitHub rate limits the tokens. Nil leaves status.rateLimitedUntil unset.
+	RateLimits *git.RateLimitTracker
+}
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=repositorystatuses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=issues.dana.io,resources=repositorystatuses/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues,verbs=get;list;watch
+
+func (r *RepositoryStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With(zap.String("name", req.Name))
+
+	var issues issuesv1alpha1.GithubIssueList
+	if err := r.List(ctx, &issues); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list GithubIssues: %v", err)
+	}
+	var managed []*issuesv1alpha1.GithubIssue
+	for i := range issues.Items {
+		issueObject := &issues.Items[i]
+		if name, ok := repositoryStatusName(issueObject.RepoURL()); ok && name == req.Name {
+			managed = append(managed, issueObject)
+		}
+	}
+
+	repositoryStatus := &issuesv1alpha1.RepositoryStatus{ObjectMeta: metav1.ObjectMeta{Name: req.Name}}
+	if len(managed) == 0 {
+		if err := r.Delete(ctx, repositoryStatus); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete RepositoryStatus %s: %v", req.Name, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	sort.Slice(managed, func(i, j int) bool { return objectKey(managed[i]) < objectKey(managed[j]) })
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, repositoryStatus, func() error {
+		if repositoryStatus.Spec.Repo == "" {
+			repositoryStatus.Spec.Repo = managed[0].RepoURL()
+		}
+		return nil
+	}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply RepositoryStatus %s: %v", req.Name, err)
+	}
+
+	now := time.Now()
+	observed := repositoryStatus.Status.DeepCopy()
+	summary := r.summarize(managed, now)
+	summary.Conditions = observed.Conditions
+	summary.LastUpdateTime = observed.LastUpdateTime
+	setRepositoryHealthy(&summary, repositoryStatus.Generation)
+	if equality.Semantic.DeepEqual(observed, &summary) {
+		return r.requeue(summary, now), nil
+	}
+	summary.LastUpdateTime = &metav1.Time{Time: now}
+	repositoryStatus.Status = summary
+	if err := r.Status().Update(ctx, repositoryStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update RepositoryStatus %s: %v", req.Name, err)
+	}
+	log.Debug("Updated repository status", zap.Int32("issues", summary.ManagedIssues), zap.Int32("failing", summary.FailingIssues))
+	return r.requeue(summary, now), nil
+}
+
+// summarize counts the GithubIssues of a repository by upstream state and sync state.
+func (r *RepositoryStatusReconciler) summarize(managed []*issuesv1alpha1.GithubIssue, now time.Time) issuesv1alpha1.RepositoryStatusStatus {
+	summary := issuesv1alpha1.RepositoryStatusStatus{ManagedIssues: int32(len(managed))}
+	credentials := map[string]bool{}
+	for _, issueObject := range managed {
+		credentials[credential(issueObject)] = true
+		if open := meta.FindStatusCondition(issueObject.Status.Conditions, "IssueIsOpen"); open != nil {
+			if open.Status == metav1.ConditionTrue {
+				summary.OpenIssues++
+			} else {
+				summary.ClosedIssues++
+			}
+		}
+		switch issueObject.Status.Phase {
+		case "", issuesv1alpha1.PhasePending:
+			summary.PendingIssues++
+		case issuesv1alpha1.PhaseError:
+			summary.FailingIssues++
+			if len(summary.Failing) < maxFailingIssues {
+				summary.Failing = append(summary.Failing, issuesv1alpha1.FailingIssue{
+					Namespace: issueObject.Namespace, Name: issueObject.Name, Error: issueObject.Status.LastSyncError,
+				})
+			}
+		}
+		if meta.IsStatusConditionTrue(issueObject.Status.Conditions, RateLimitedCondition) {
+			summary.RateLimitedIssues++
+		}
+	}
+	if r.RateLimits != nil {
+		for name := range credentials {
+			if resetAt, limited := r.RateLimits.LimitedUntil(name, now); limited {
+				if summary.RateLimitedUntil == nil || resetAt.After(summary.RateLimitedUntil.Time) {
+					summary.RateLimitedUntil = &metav1.Time{Time: resetAt.Truncate(time.Second)}
+				}
+			}
+		}
+	}
+	return summary
+}
+
+// setRepositoryHealthy sets the Healthy condition from the counts of the summary.
+func setRepositoryHealthy(summary *issuesv1alpha1.RepositoryStatusStatus, generation int64) {
+	condition := metav1.Condition{
+		Type:               RepositoryHealthyCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            fmt.Sprintf("%d GithubIssues, none failing", summary.ManagedIssues),
+		ObservedGeneration: generation,
+	}
+	var problems []string
+	if summary.FailingIssues > 0 {
+		condition.Reason = "SyncFailing"
+		problems = append(problems, fmt.Sprintf("%d of %d GithubIssues fail to sync", summary.FailingIssues, summary.ManagedIssues))
+	}
+	if summary.RateLimitedIssues > 0 || summary.RateLimitedUntil != nil {
+		if condition.Reason == "Synced" {
+			condition.Reason = "RateLimited"
+		}
+		problems = append(problems, "GitHub rate limits the repository tokens")
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = strings.Join(problems, "; ")
+	}
+	meta.SetStatusCondition(&summary.Conditions, condition)
+}
+
+// requeue refreshes the summary once the rate limit of the repository resets, since no GithubIssue changes then.
+func (r *RepositoryStatusReconciler) requeue(summary issuesv1alpha1.RepositoryStatusStatus, now time.Time) ctrl.Result {
+	if summary.RateLimitedUntil == nil {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: summary.RateLimitedUntil.Sub(now) + time.Second}
+}
+
+// repositoryStatusName is the name of the RepositoryStatus of a repository: its host, owner and name.
+func repositoryStatusName(repoURL string) (string, bool) {
+	host, err := git.RepoHost(repoURL)
+	if err != nil {
+		return "", false
+	}
+	owner, repo, err := git.ParseRepoURL(repoURL)
+	if err != nil {
+		return "", false
+	}
+	return issueSetIssueName(host, owner+"-"+repo), true
+}
+
+// issueRepositoryStatus enqueues the RepositoryStatus of the repository of a changed GithubIssue.
+func (r *RepositoryStatusReconciler) issueRepositoryStatus(_ context.Context, obj client.Object) []reconcile.Request {
+	issueObject, ok := obj.(*issuesv1alpha1.GithubIssue)
+	if !ok {
+		return nil
+	}
+	name, ok := repositoryStatusName(issueObject.RepoURL())
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RepositoryStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&issuesv1alpha1.RepositoryStatus{}).
+		Watches(&issuesv1alpha1.GithubIssue{}, handler.EnqueueRequestsFromMapFunc(r.issueRepositoryStatus)).
+		Named("repositorystatus").
+		Complete(r)
+}
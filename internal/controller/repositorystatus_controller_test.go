@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("RepositoryStatus controller", func() {
+	var (
+		reconciler *RepositoryStatusReconciler
+		key        = types.NamespacedName{Name: "github-com-org-repo"}
+	)
+
+	issue := func(namespace, name, repo string, phase issuesv1alpha1.IssuePhase, open metav1.ConditionStatus) *issuesv1alpha1.GithubIssue {
+		issueObject := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: repo, Title: name},
+			Status:     issuesv1alpha1.GithubIssueStatus{Phase: phase},
+		}
+		if phase == issuesv1alpha1.PhaseError {
+			issueObject.Status.LastSyncError = "boom"
+		}
+		if open != "" {
+			issueObject.Status.Conditions = []metav1.Condition{{Type: "IssueIsOpen", Status: open, Reason: "IssueIsOpen"}}
+		}
+		return issueObject
+	}
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		objects := []client.Object{
+			issue("team-a", "open", "https://github.com/Org/Repo", issuesv1alpha1.PhaseSynced, metav1.ConditionTrue),
+			issue("team-b", "closed", "https://github.com/org/repo", issuesv1alpha1.PhaseSynced, metav1.ConditionFalse),
+			issue("team-b", "failing", "https://github.com/org/repo", issuesv1alpha1.PhaseError, metav1.ConditionTrue),
+			issue("team-a", "new", "https://github.com/org/repo", "", ""),
+			issue("team-a", "other", "https://github.com/org/other", issuesv1alpha1.PhaseSynced, metav1.ConditionTrue),
+		}
+		reconciler = &RepositoryStatusReconciler{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objects...).
+				WithStatusSubresource(&issuesv1alpha1.RepositoryStatus{}).Build(),
+			Scheme: testScheme,
+			Log:    zap.NewNop(),
+		}
+	})
+
+	reconcile := func() *issuesv1alpha1.RepositoryStatus {
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		repositoryStatus := &issuesv1alpha1.RepositoryStatus{}
+		Expect(reconciler.Get(context.Background(), key, repositoryStatus)).To(Succeed())
+		return repositoryStatus
+	}
+
+	It("summarizes the GithubIssues of the repository across namespaces", func() {
+		repositoryStatus := reconcile()
+		Expect(repositoryStatus.Spec.Repo).To(Equal("https://github.com/org/repo"))
+		status := repositoryStatus.Status
+		Expect(status.ManagedIssues).To(Equal(int32(4)))
+		Expect(status.OpenIssues).To(Equal(int32(2)))
+		Expect(status.ClosedIssues).To(Equal(int32(1)))
+		Expect(status.PendingIssues).To(Equal(int32(1)))
+		Expect(status.FailingIssues).To(Equal(int32(1)))
+		Expect(status.Failing).To(Equal([]issuesv1alpha1.FailingIssue{{Namespace: "team-b", Name: "failing", Error: "boom"}}))
+		Expect(meta.FindStatusCondition(status.Conditions, RepositoryHealthyCondition).Reason).To(Equal("SyncFailing"))
+
+		lastUpdate := status.LastUpdateTime
+		Expect(reconcile().Status.LastUpdateTime).To(Equal(lastUpdate))
+	})
+
+	It("deletes the RepositoryStatus once the repository has no GithubIssue left", func() {
+		reconcile()
+		var issues issuesv1alpha1.GithubIssueList
+		Expect(reconciler.List(context.Background(), &issues)).To(Succeed())
+		for i := range issues.Items {
+			if issues.Items[i].Name != "other" {
+				Expect(reconciler.Delete(context.Background(), &issues.Items[i])).To(Succeed())
+			}
+		}
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apierrors.IsNotFound(reconciler.Get(context.Background(), key, &issuesv1alpha1.RepositoryStatus{}))).To(BeTrue())
+	})
+})
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// checkSchedule reports how long the creation of the issue is still held back by spec.notBefore,
+// setting the Scheduled condition until then. Zero means the issue may be created.
+func (r *GithubIssueReconciler) checkSchedule(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, now time.Time) (time.Duration, error) {
+	notBefore := issueObject.Spec.NotBefore
+	if notBefore == nil || !now.Before(notBefore.Time) {
+		return 0, r.clearScheduled(ctx, issueObject)
+	}
+
+	wait := notBefore.Sub(now)
+	changed := meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               ScheduledCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NotBefore",
+		Message:            fmt.Sprintf("The issue is created at %s", notBefore.UTC().Format(time.RFC3339)),
+		ObservedGeneration: issueObject.Generation,
+	})
+	if changed {
+		r.logger(ctx).Info("Issue creation scheduled", zap.Time("notBefore", notBefore.Time))
+		if err := r.updateStatus(ctx, issueObject); err != nil {
+			return 0, fmt.Errorf("failed to update status: %v", err)
+		}
+	}
+	return wait, nil
+}
+
+// clearScheduled flips the Scheduled condition once spec.notBefore has passed or was removed.
+func (r *GithubIssueReconciler) clearScheduled(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, ScheduledCondition) {
+		return nil
+	}
+	meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+		Type:               ScheduledCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Due",
+		Message:            "The scheduled creation time has passed",
+		ObservedGeneration: issueObject.Generation,
+	})
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to update status: %v", err)
+	}
+	return nil
+}
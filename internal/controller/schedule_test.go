@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("scheduled creation", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		notBefore   = time.Date(2030, time.January, 4, 9, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{NotBefore: &metav1.Time{Time: notBefore}},
+		}
+		reconciler = &GithubIssueReconciler{
+			Client:  fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).Build(),
+			Log:     zap.NewNop(),
+			pending: newPendingWrites(),
+		}
+	})
+
+	It("holds the creation back until notBefore", func() {
+		wait, err := reconciler.checkSchedule(context.Background(), issueObject, notBefore.Add(-time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wait).To(Equal(time.Hour))
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, ScheduledCondition)).To(BeTrue())
+
+		wait, err = reconciler.checkSchedule(context.Background(), issueObject, notBefore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wait).To(BeZero())
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, ScheduledCondition)).To(BeTrue())
+	})
+})
@@ -21,8 +21,6 @@ import (
 	"fmt"
 	"github.com/google/go-github/v56/github"
 	"github.com/onsi/gomega/gexec"
-	"go.elastic.co/ecszap"
-	uberzap "go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
@@ -46,6 +44,7 @@ import (
 
 	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
 	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -103,15 +102,16 @@ var _ = BeforeSuite(func() {
 	})
 
 	Expect(err).ToNot(HaveOccurred())
-	encoderConfig := ecszap.NewDefaultEncoderConfig()
-	core := ecszap.NewCore(encoderConfig, os.Stdout, uberzap.DebugLevel)
+	operatorLog, err := logging.New(logging.Options{Level: "debug"})
+	Expect(err).ToNot(HaveOccurred())
 	err = (&GithubIssueReconciler{
 		Client: k8sClient,
 		Scheme: k8sManager.GetScheme(),
-		IssueClient: &git.GitHubIssueClient{
+		Clients: &git.Clients{Default: &git.GitHubIssueClient{
 			Client: github.NewClient(MockClient).WithAuthToken(os.Getenv("GITHUB_TOKEN")),
-		},
-		Log: uberzap.New(core, uberzap.AddCaller()),
+		}},
+		Log:      operatorLog.Logger,
+		Recorder: k8sManager.GetEventRecorderFor("githubissue-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 	go func() {
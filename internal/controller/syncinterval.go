@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// syncTracker remembers when each GithubIssue last synced with GitHub, so periodic cache resyncs
+// can be skipped for issues asking to be refreshed less often than --resync-period.
+type syncTracker struct {
+	mu       sync.Mutex
+	lastSync map[string]time.Time
+}
+
+func newSyncTracker() *syncTracker {
+	return &syncTracker{lastSync: map[string]time.Time{}}
+}
+
+func (t *syncTracker) synced(key string, now time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastSync[key] = now
+}
+
+// due reports whether an issue with the given interval should sync again, and otherwise how long is left.
+func (t *syncTracker) due(key string, interval time.Duration, now time.Time) (bool, time.Duration) {
+	if t == nil {
+		return true, 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	last, ok := t.lastSync[key]
+	if !ok {
+		return true, 0
+	}
+	if elapsed := now.Sub(last); elapsed < interval {
+		return false, interval - elapsed
+	}
+	return true, 0
+}
+
+func (t *syncTracker) forget(key string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.lastSync, key)
+}
+
+// syncInterval returns the per-issue sync interval, or zero when the issue follows the global resync period.
+func syncInterval(issueObject *issuesv1alpha1.GithubIssue) time.Duration {
+	if issueObject.Spec.SyncIntervalSeconds == nil {
+		return 0
+	}
+	return time.Duration(*issueObject.Spec.SyncIntervalSeconds) * time.Second
+}
+
+// withSyncInterval requeues the issue after its sync interval unless an earlier requeue is already requested.
+func withSyncInterval(result ctrl.Result, interval time.Duration) ctrl.Result {
+	if interval > 0 && (result.RequeueAfter == 0 || interval < result.RequeueAfter) {
+		result.RequeueAfter = interval
+	}
+	return result
+}
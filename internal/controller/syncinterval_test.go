@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("sync interval", func() {
+	It("skips syncs until the interval has elapsed", func() {
+		tracker := newSyncTracker()
+		start := time.Now()
+
+		due, _ := tracker.due("default/a", time.Hour, start)
+		Expect(due).To(BeTrue())
+
+		tracker.synced("default/a", start)
+		due, wait := tracker.due("default/a", time.Hour, start.Add(20*time.Minute))
+		Expect(due).To(BeFalse())
+		Expect(wait).To(Equal(40 * time.Minute))
+
+		due, _ = tracker.due("default/a", time.Hour, start.Add(time.Hour))
+		Expect(due).To(BeTrue())
+	})
+
+	It("keeps an earlier requeue requested by the reconcile", func() {
+		Expect(withSyncInterval(ctrl.Result{}, time.Minute).RequeueAfter).To(Equal(time.Minute))
+		Expect(withSyncInterval(ctrl.Result{RequeueAfter: time.Second}, time.Minute).RequeueAfter).To(Equal(time.Second))
+		Expect(withSyncInterval(ctrl.Result{}, 0).RequeueAfter).To(BeZero())
+	})
+})
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// templateData is the data passed to the issue body template.
+type templateData struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Description string
+	Values      map[string]string
+}
+
+// renderTemplate renders the issue body from spec.templateRef. Without a template the description is returned unchanged.
+func (r *GithubIssueReconciler) renderTemplate(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, description string) (string, error) {
+	ref := issueObject.Spec.TemplateRef
+	if ref == nil {
+		return description, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: issueObject.Namespace, Name: ref.Name}, configMap); err != nil {
+		return "", fmt.Errorf("failed to read template from ConfigMap %s: %v", ref.Name, err)
+	}
+	text, ok := configMap.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in ConfigMap %s", ref.Key, ref.Name)
+	}
+	return renderBody(text, templateData{
+		Name:        issueObject.Name,
+		Namespace:   issueObject.Namespace,
+		Labels:      issueObject.Labels,
+		Description: description,
+		Values:      issueObject.Spec.TemplateValues,
+	})
+}
+
+// renderBody executes the body template. Referencing a missing value is an error rather than rendering "<no value>".
+func renderBody(text string, data any) (string, error) {
+	tmpl, err := template.New("body").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse issue template: %v", err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("failed to render issue template: %v", err)
+	}
+	return body.String(), nil
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("issue templates", func() {
+	data := templateData{
+		Name:        "outage",
+		Namespace:   "prod",
+		Labels:      map[string]string{"team": "payments"},
+		Description: "Checkout is down.",
+		Values:      map[string]string{"severity": "sev1"},
+	}
+
+	It("renders the object fields and template values", func() {
+		body, err := renderBody("{{.Namespace}}/{{.Name}} ({{.Labels.team}}, {{.Values.severity}}): {{.Description}}", data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(Equal("prod/outage (payments, sev1): Checkout is down."))
+	})
+
+	It("fails on a value missing from templateValues", func() {
+		_, err := renderBody("{{.Values.owner}}", data)
+		Expect(err).To(MatchError(ContainSubstring("failed to render issue template")))
+	})
+
+	It("fails on a malformed template", func() {
+		_, err := renderBody("{{.Name", data)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse issue template")))
+	})
+})
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// terminalErrors maps the errors retrying cannot fix to the condition reporting them.
+var terminalErrors = []struct {
+	err       error
+	condition string
+	message   string
+}{
+	{git.ErrRepoNotFound, RepoNotFoundCondition, "Repository %s does not exist or is not visible to the GitHub token"},
+	{git.ErrAuthenticationFailed, AuthenticationFailedCondition, "GitHub rejected the token used for %s"},
+	{git.ErrIssuesDisabled, IssuesDisabledCondition,
+		"Issues are disabled for %s: enable them under Settings > General > Features, or point spec.repo at another repository"},
+}
+
+// setTerminalError sets the RepoNotFound, AuthenticationFailed or IssuesDisabled condition when the reconcile failed with an
+// error retrying cannot fix, and reports whether it did. The issue is then only reconciled again once the spec
+// or a referenced Secret changes. Failures while deleting keep being retried so the finalizer is not stuck.
+func (r *GithubIssueReconciler) setTerminalError(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, reconcileErr error) bool {
+	if reconcileErr == nil || !issueObject.DeletionTimestamp.IsZero() {
+		return false
+	}
+	for _, terminal := range terminalErrors {
+		if !errors.Is(reconcileErr, terminal.err) {
+			continue
+		}
+		message := fmt.Sprintf(terminal.message, issueObject.Spec.Repo)
+		if meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+			Type:               terminal.condition,
+			Status:             metav1.ConditionTrue,
+			Reason:             terminal.condition,
+			Message:            message,
+			ObservedGeneration: issueObject.Generation,
+		}) {
+			r.logger(ctx).Error("Stopping retries until the spec or the credentials change", zap.Error(reconcileErr))
+			r.Recorder.Event(issueObject, corev1.EventTypeWarning, terminal.condition, message)
+		}
+		return true
+	}
+	return false
+}
+
+// terminallyFailed reports whether the current generation of the issue failed with an error retrying cannot fix.
+func terminallyFailed(issueObject *issuesv1alpha1.GithubIssue) bool {
+	for _, terminal := range terminalErrors {
+		condition := meta.FindStatusCondition(issueObject.Status.Conditions, terminal.condition)
+		if condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == issueObject.Generation {
+			return true
+		}
+	}
+	return false
+}
+
+// clearTerminalErrors flips the RepoNotFound, AuthenticationFailed and IssuesDisabled conditions once the issue synced again.
+// The conditions are written with the sync.
+func clearTerminalErrors(issueObject *issuesv1alpha1.GithubIssue) {
+	for _, terminal := range terminalErrors {
+		if !meta.IsStatusConditionTrue(issueObject.Status.Conditions, terminal.condition) {
+			continue
+		}
+		meta.SetStatusCondition(&issueObject.Status.Conditions, metav1.Condition{
+			Type:               terminal.condition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Resolved",
+			Message:            "The issue synced with GitHub again",
+			ObservedGeneration: issueObject.Generation,
+		})
+	}
+}
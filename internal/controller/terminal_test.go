@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("terminal errors", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		recorder    *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default", Generation: 1},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/example-org/missing"},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &GithubIssueReconciler{Log: zap.NewNop(), Recorder: recorder}
+	})
+
+	It("stops retrying a missing repository until the spec changes", func() {
+		err := fmt.Errorf("error fetching issues: %w", fmt.Errorf("failed to list issues: %w: 404", git.ErrRepoNotFound))
+		Expect(reconciler.setTerminalError(context.Background(), issueObject, err)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, RepoNotFoundCondition)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring(RepoNotFoundCondition)))
+		Expect(terminallyFailed(issueObject)).To(BeTrue())
+
+		issueObject.Generation = 2
+		Expect(terminallyFailed(issueObject)).To(BeFalse())
+
+		clearTerminalErrors(issueObject)
+		Expect(meta.IsStatusConditionFalse(issueObject.Status.Conditions, RepoNotFoundCondition)).To(BeTrue())
+	})
+
+	It("does not retry fetches failing with a terminal error", func() {
+		Expect(reconciler.shouldRetry(context.Background(), fmt.Errorf("failed to list issues: %w", git.ErrRepoNotFound))).To(BeFalse())
+		Expect(reconciler.shouldRetry(context.Background(), fmt.Errorf("failed to list issues: %w", git.ErrAuthenticationFailed))).To(BeFalse())
+		Expect(reconciler.shouldRetry(context.Background(), errors.New("connection reset by peer"))).To(BeTrue())
+	})
+
+	It("reports rejected tokens as AuthenticationFailed", func() {
+		err := fmt.Errorf("failed to create issue: %w", git.ErrAuthenticationFailed)
+		Expect(reconciler.setTerminalError(context.Background(), issueObject, err)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(issueObject.Status.Conditions, AuthenticationFailedCondition)).To(BeTrue())
+	})
+
+	It("explains how to enable issues in a repository with issues disabled", func() {
+		err := fmt.Errorf("error fetching issues: %w", git.ErrIssuesDisabled)
+		Expect(reconciler.setTerminalError(context.Background(), issueObject, err)).To(BeTrue())
+		condition := meta.FindStatusCondition(issueObject.Status.Conditions, IssuesDisabledCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Message).To(ContainSubstring("Settings > General > Features"))
+	})
+
+	It("keeps retrying other errors and failures while deleting", func() {
+		Expect(reconciler.setTerminalError(context.Background(), issueObject, errors.New("connection reset"))).To(BeFalse())
+
+		now := metav1.Now()
+		issueObject.DeletionTimestamp = &now
+		Expect(reconciler.setTerminalError(context.Background(), issueObject, git.ErrRepoNotFound)).To(BeFalse())
+		Expect(issueObject.Status.Conditions).To(BeEmpty())
+	})
+})
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TitlePrefixAnnotation on a namespace sets the prefix of the titles of its GithubIssues,
+// for the repositories whose GithubRepository sets no spec.titlePrefix.
+const TitlePrefixAnnotation = "issues.dana.io/title-prefix"
+
+// titlePrefix returns the title prefix of the issue: spec.titlePrefix of its GithubRepository, else the
+// TitlePrefixAnnotation of its namespace.
+func (r *GithubIssueReconciler) titlePrefix(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) (string, error) {
+	repository, err := r.repository(ctx, issueObject)
+	if err != nil {
+		return "", err
+	}
+	if repository != nil && repository.Spec.TitlePrefix != "" {
+		return repository.Spec.TitlePrefix, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: issueObject.Namespace}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get namespace: %v", err)
+	}
+	return namespace.Annotations[TitlePrefixAnnotation], nil
+}
+
+// withTitlePrefix prepends prefix to title, unless the title already starts with it.
+func withTitlePrefix(prefix, title string) string {
+	if strings.HasPrefix(title, prefix) {
+		return title
+	}
+	return prefix + title
+}
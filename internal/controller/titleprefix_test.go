@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("title prefix", func() {
+	var issueObject *issuesv1alpha1.GithubIssue
+
+	reconcilerWith := func(objects ...client.Object) *GithubIssueReconciler {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(testScheme)).To(Succeed())
+		return &GithubIssueReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objects...).Build()}
+	}
+
+	BeforeEach(func() {
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", Title: "Broken link"},
+		}
+	})
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "default",
+		Annotations: map[string]string{TitlePrefixAnnotation: "[prod-eu] "},
+	}}
+
+	It("prepends the prefix of the namespace", func() {
+		title, err := reconcilerWith(namespace).issueTitle(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(title).To(Equal("[prod-eu] Broken link"))
+	})
+
+	It("prefers the prefix of the repository and never prepends it twice", func() {
+		repository := &issuesv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubRepositorySpec{Repo: "https://github.com/org/repo", TitlePrefix: "[staging] "},
+		}
+		reconciler := reconcilerWith(namespace, repository)
+		title, err := reconciler.issueTitle(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(title).To(Equal("[staging] Broken link"))
+
+		issueObject.Spec.Title = "[staging] Broken link"
+		title, err = reconciler.issueTitle(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(title).To(Equal("[staging] Broken link"))
+	})
+
+	It("keeps the title without a prefix", func() {
+		title, err := reconcilerWith().issueTitle(context.Background(), issueObject)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(title).To(Equal("Broken link"))
+	})
+})
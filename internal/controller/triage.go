@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/triage"
+	"go.uber.org/zap"
+)
+
+// triageIssue applies the triage label the repository policy asks for and drops the other triage labels.
+func (r *GithubIssueReconciler) triageIssue(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	if r.TriagePolicy == nil || !issueExists(issue) {
+		return nil
+	}
+
+	policy, err := r.triagePolicy(ctx, owner, repo, issueObject)
+	if err != nil {
+		return err
+	}
+	if !policy.Enabled {
+		// Triage labels on issues of repositories without triage belong to humans.
+		return nil
+	}
+	desired := triage.Evaluate(policy, issueObject.CreationTimestamp.Time, issue, time.Now())
+	add, remove := triage.Plan(issue.Labels, desired)
+
+	if len(add) > 0 {
+		if err := r.issueClient(ctx).AddLabels(ctx, owner, repo, issue.Number, add); err != nil {
+			return fmt.Errorf("failed to apply triage labels: %v", err)
+		}
+	}
+	for _, label := range remove {
+		if err := r.issueClient(ctx).RemoveLabel(ctx, owner, repo, issue.Number, label); err != nil {
+			return fmt.Errorf("failed to remove triage label: %v", err)
+		}
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		r.logger(ctx).Info("Triage labels updated", zap.Strings("added", add), zap.Strings("removed", remove))
+	}
+
+	r.triageDistribution.Record(objectKey(issueObject), desired)
+	return nil
+}
+
+// triagePolicy returns the triage policy of the repository of the issue: the policy of TriagePolicy, with the
+// fields spec.triage of its GithubRepository sets.
+func (r *GithubIssueReconciler) triagePolicy(ctx context.Context, owner, repo string, issueObject *issuesv1alpha1.GithubIssue) (triage.Policy, error) {
+	policy := r.TriagePolicy.PolicyFor(owner, repo)
+	repository, err := r.repository(ctx, issueObject)
+	if err != nil {
+		return policy, err
+	}
+	if repository == nil || repository.Spec.Triage == nil {
+		return policy, nil
+	}
+	override := repository.Spec.Triage
+	if override.Enabled != nil {
+		policy.Enabled = *override.Enabled
+	}
+	if override.StaleAfter != nil {
+		policy.StaleAfter = override.StaleAfter.Duration
+	}
+	if override.EscalateAfter != nil {
+		policy.EscalateAfter = override.EscalateAfter.Duration
+	}
+	return policy, nil
+}
+
+// objectKey returns the namespace/name key of a GithubIssue.
+func objectKey(issueObject *issuesv1alpha1.GithubIssue) string {
+	return fmt.Sprintf("%s/%s", issueObject.Namespace, issueObject.Name)
+}
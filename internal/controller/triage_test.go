@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/triage"
+)
+
+// fakeTriageClient records the label changes of a reconcile.
+type fakeTriageClient struct {
+	git.IssueClient
+	added, removed []string
+}
+
+func (f *fakeTriageClient) AddLabels(_ context.Context, _, _ string, _ int, labels []string) error {
+	f.added = append(f.added, labels...)
+	return nil
+}
+
+func (f *fakeTriageClient) RemoveLabel(_ context.Context, _, _ string, _ int, label string) error {
+	f.removed = append(f.removed, label)
+	return nil
+}
+
+var _ = Describe("triage labels", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+		labels      *fakeTriageClient
+		issue       *git.Issue
+	)
+
+	build := func(objects ...runtime.Object) {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		reconciler = &GithubIssueReconciler{
+			Client:             fake.NewClientBuilder().WithScheme(testScheme).WithRuntimeObjects(objects...).Build(),
+			Log:                zap.NewNop(),
+			Clients:            &git.Clients{Default: labels},
+			TriagePolicy:       triage.StaticPolicy(triage.Policy{StaleAfter: time.Hour}),
+			triageDistribution: triage.NewDistribution(),
+		}
+	}
+
+	BeforeEach(func() {
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo"},
+		}
+		labels = &fakeTriageClient{}
+		issue = &git.Issue{Number: 1, State: "open", Labels: []string{"bug", triage.LabelStale, triage.LabelEscalated}}
+	})
+
+	It("leaves triage labels alone when triage is disabled", func() {
+		build()
+		Expect(reconciler.triageIssue(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+		Expect(labels.added).To(BeEmpty())
+		Expect(labels.removed).To(BeEmpty())
+	})
+
+	It("applies the policy of the GithubRepository", func() {
+		build(&issuesv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "default"},
+			Spec: issuesv1alpha1.GithubRepositorySpec{
+				Repo:   "https://github.com/org/repo",
+				Triage: &issuesv1alpha1.RepositoryTriage{Enabled: ptr.To(true)},
+			},
+		})
+		Expect(reconciler.triageIssue(context.Background(), "org", "repo", issueObject, issue)).To(Succeed())
+		Expect(labels.added).To(BeEmpty())
+		Expect(labels.removed).To(Equal([]string{triage.LabelEscalated}))
+	})
+})
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpstreamLabelsAnnotation lists the upstream labels of the issue selected by --reflect-upstream-labels,
+// comma separated and sorted, so in-cluster automation can react to triage decisions made on GitHub.
+const UpstreamLabelsAnnotation = "issues.dana.io/upstream-labels"
+
+// reflectUpstreamLabels records the selected upstream labels in UpstreamLabelsAnnotation, removing it once
+// none is left.
+func (r *GithubIssueReconciler) reflectUpstreamLabels(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, issue *git.Issue) error {
+	if len(r.ReflectLabels) == 0 {
+		return nil
+	}
+	reflected := strings.Join(r.ReflectLabels.Select(issue.Labels), ",")
+	current, annotated := issueObject.Annotations[UpstreamLabelsAnnotation]
+	if current == reflected && (annotated || reflected == "") {
+		return nil
+	}
+
+	patch := client.MergeFrom(issueObject.DeepCopy())
+	if reflected == "" {
+		delete(issueObject.Annotations, UpstreamLabelsAnnotation)
+	} else {
+		if issueObject.Annotations == nil {
+			issueObject.Annotations = map[string]string{}
+		}
+		issueObject.Annotations[UpstreamLabelsAnnotation] = reflected
+	}
+	writeCtx, cancel := r.withWriteTimeout(ctx)
+	defer cancel()
+	if err := r.Patch(writeCtx, issueObject, patch); err != nil {
+		return fmt.Errorf("failed to annotate upstream labels: %v", err)
+	}
+	r.logger(ctx).Info("Reflected upstream labels", zap.String("labels", reflected))
+	return nil
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/labels"
+)
+
+var _ = Describe("upstream label reflection", func() {
+	var (
+		reconciler  *GithubIssueReconciler
+		issueObject *issuesv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo"},
+		}
+		reconciler = &GithubIssueReconciler{
+			Client:        fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).Build(),
+			Log:           zap.NewNop(),
+			Recorder:      record.NewFakeRecorder(10),
+			ReflectLabels: labels.ParseSelector("priority/*, wontfix"),
+			pending:       newPendingWrites(),
+		}
+	})
+
+	stored := func() *issuesv1alpha1.GithubIssue {
+		stored := &issuesv1alpha1.GithubIssue{}
+		Expect(reconciler.Get(context.Background(), client.ObjectKeyFromObject(issueObject), stored)).To(Succeed())
+		return stored
+	}
+
+	It("annotates the selected upstream labels and removes the annotation once none is left", func() {
+		issue := &git.Issue{Number: 1, Labels: []string{"wontfix", "bug", "priority/p1"}}
+		Expect(reconciler.reflectUpstreamLabels(context.Background(), issueObject, issue)).To(Succeed())
+		Expect(stored().Annotations).To(HaveKeyWithValue(UpstreamLabelsAnnotation, "priority/p1,wontfix"))
+
+		issue.Labels = []string{"bug"}
+		Expect(reconciler.reflectUpstreamLabels(context.Background(), issueObject, issue)).To(Succeed())
+		Expect(stored().Annotations).NotTo(HaveKey(UpstreamLabelsAnnotation))
+	})
+
+	It("leaves the GithubIssue alone when disabled", func() {
+		reconciler.ReflectLabels = nil
+		Expect(reconciler.reflectUpstreamLabels(context.Background(), issueObject, &git.Issue{Labels: []string{"wontfix"}})).To(Succeed())
+		Expect(stored().Annotations).To(BeEmpty())
+	})
+})
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Warmup tiers, reconciled in this order after startup.
+const (
+	warmupTierError   = iota // objects reporting a problem, or being deleted
+	warmupTierPending        // objects that were never synced
+	warmupTierSynced         // objects in sync at the last reconcile
+	warmupTiers
+)
+
+// warmupErrorConditions are the conditions that put an object in the error tier while true.
+var warmupErrorConditions = []string{
+	InvalidSpecCondition,
+	LimitsExceededCondition,
+	TokenExpiringCondition,
+	PossibleDuplicateCondition,
+	DeletionBlockedCondition,
+}
+
+// warmup spreads the reconciles of the objects that existed before startup over a window,
+// so a restart doesn't hit GitHub with every object at once.
+type warmup struct {
+	start  time.Time
+	window time.Duration
+}
+
+// newWarmup starts the warmup window now.
+func newWarmup(window time.Duration) *warmup {
+	return &warmup{start: time.Now(), window: window}
+}
+
+// delay returns how long the first reconcile of obj is held back. Objects created after startup are not delayed.
+// Each tier gets an equal share of the window, and objects are spread within it by a hash of their key.
+func (w *warmup) delay(issueObject *issuesv1alpha1.GithubIssue) time.Duration {
+	if w.window <= 0 || !issueObject.CreationTimestamp.Time.Before(w.start) {
+		return 0
+	}
+	slot := w.window / warmupTiers
+	if slot <= 0 {
+		return 0
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(objectKey(issueObject)))
+	return time.Duration(warmupTier(issueObject))*slot + time.Duration(hash.Sum32())%slot
+}
+
+// warmupTier ranks an object for the startup reconcile.
+func warmupTier(issueObject *issuesv1alpha1.GithubIssue) int {
+	if !issueObject.DeletionTimestamp.IsZero() {
+		return warmupTierError
+	}
+	conditions := issueObject.Status.Conditions
+	for _, conditionType := range warmupErrorConditions {
+		if meta.IsStatusConditionTrue(conditions, conditionType) {
+			return warmupTierError
+		}
+	}
+	if meta.IsStatusConditionFalse(conditions, ProjectsSyncedCondition) || meta.IsStatusConditionFalse(conditions, MirrorsSyncedCondition) {
+		return warmupTierError
+	}
+	if len(conditions) == 0 {
+		return warmupTierPending
+	}
+	return warmupTierSynced
+}
+
+// handler wraps next so the create events of the initial list are queued after their warmup delay.
+func (w *warmup) handler(causes *causeTracker, next handler.EventHandler) handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			issueObject, ok := e.Object.(*issuesv1alpha1.GithubIssue)
+			if !ok {
+				next.Create(ctx, e, q)
+				return
+			}
+			delay := w.delay(issueObject)
+			if delay == 0 {
+				next.Create(ctx, e, q)
+				return
+			}
+			key := client.ObjectKeyFromObject(issueObject)
+			causes.record(key, causeGeneration)
+			causes.repos.enqueued(issueObject)
+			q.AddAfter(reconcile.Request{NamespacedName: key}, delay)
+		},
+		UpdateFunc:  next.Update,
+		DeleteFunc:  next.Delete,
+		GenericFunc: next.Generic,
+	}
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("startup warmup", func() {
+	start := time.Now()
+	w := &warmup{start: start, window: 3 * time.Minute}
+
+	issue := func(name string, conditions ...metav1.Condition) *issuesv1alpha1.GithubIssue {
+		return &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(start.Add(-time.Hour))},
+			Status:     issuesv1alpha1.GithubIssueStatus{Conditions: conditions},
+		}
+	}
+	failing := issue("failing", metav1.Condition{Type: InvalidSpecCondition, Status: metav1.ConditionTrue})
+	pending := issue("pending")
+	synced := issue("synced", metav1.Condition{Type: "IssueIsOpen", Status: metav1.ConditionTrue})
+
+	It("ranks failing objects first and synced objects last", func() {
+		Expect(warmupTier(failing)).To(Equal(warmupTierError))
+		Expect(warmupTier(pending)).To(Equal(warmupTierPending))
+		Expect(warmupTier(synced)).To(Equal(warmupTierSynced))
+	})
+
+	It("spreads each tier over its share of the window", func() {
+		Expect(w.delay(failing)).To(BeNumerically("<", time.Minute))
+		Expect(w.delay(pending)).To(And(BeNumerically(">=", time.Minute), BeNumerically("<", 2*time.Minute)))
+		Expect(w.delay(synced)).To(And(BeNumerically(">=", 2*time.Minute), BeNumerically("<", 3*time.Minute)))
+	})
+
+	It("does not delay objects created after startup or without a window", func() {
+		created := issue("new")
+		created.CreationTimestamp = metav1.NewTime(start.Add(time.Second))
+		Expect(w.delay(created)).To(BeZero())
+		Expect((&warmup{start: start}).delay(synced)).To(BeZero())
+	})
+})
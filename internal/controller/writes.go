@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/finalizer"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// statusFallbackThreshold is the number of consecutive failed status writes after which the conditions
+	// are reported through an event instead.
+	statusFallbackThreshold = 3
+	// statusRetryBaseDelay and statusRetryMaxDelay bound the backoff between retries of a failed status write.
+	statusRetryBaseDelay = time.Second
+	statusRetryMaxDelay  = 5 * time.Minute
+)
+
+// pendingWrites keeps the API server writes that failed, so a slow or unavailable API server doesn't lose
+// what was already applied to GitHub. The next reconcile of the object records them first.
+type pendingWrites struct {
+	mu       sync.Mutex
+	statuses map[string]issuesv1alpha1.GithubIssueStatus
+	repos    map[string]string
+	failures map[string]int
+	closed   map[string]bool
+}
+
+func newPendingWrites() *pendingWrites {
+	return &pendingWrites{
+		statuses: map[string]issuesv1alpha1.GithubIssueStatus{},
+		repos:    map[string]string{},
+		failures: map[string]int{},
+		closed:   map[string]bool{},
+	}
+}
+
+// setStatus keeps the status of key, an issue of repository, after a failed write and returns the number of
+// consecutive failed writes.
+func (p *pendingWrites) setStatus(key, repository string, status *issuesv1alpha1.GithubIssueStatus) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.statuses[key]; !ok {
+		p.repos[key] = repository
+		metrics.PendingStatusWrites.WithLabelValues(repository).Inc()
+	}
+	p.statuses[key] = *status.DeepCopy()
+	p.failures[key]++
+	return p.failures[key]
+}
+
+// statusWritten resets the failed writes of key.
+func (p *pendingWrites) statusWritten(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.failures, key)
+}
+
+// retryDelay returns how long to wait before writing the pending status of key again, doubling with every
+// consecutive failure. It returns zero when nothing is pending.
+func (p *pendingWrites) retryDelay(key string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.statuses[key]; !ok {
+		return 0
+	}
+	delay := statusRetryBaseDelay
+	for i := 1; i < p.failures[key] && delay < statusRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, statusRetryMaxDelay)
+}
+
+// takeStatus returns the pending status of key and drops it.
+func (p *pendingWrites) takeStatus(key string) (issuesv1alpha1.GithubIssueStatus, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, ok := p.statuses[key]
+	p.dropStatus(key)
+	return status, ok
+}
+
+// dropStatus drops the pending status of key. The caller holds mu.
+func (p *pendingWrites) dropStatus(key string) {
+	if _, ok := p.statuses[key]; !ok {
+		return
+	}
+	metrics.PendingStatusWrites.WithLabelValues(p.repos[key]).Dec()
+	delete(p.statuses, key)
+	delete(p.repos, key)
+}
+
+// setClosed records that the upstream issue of key was closed while its finalizer is still in place.
+func (p *pendingWrites) setClosed(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed[key] = true
+}
+
+func (p *pendingWrites) isClosed(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed[key]
+}
+
+// forget drops everything pending for key.
+func (p *pendingWrites) forget(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dropStatus(key)
+	delete(p.failures, key)
+	delete(p.closed, key)
+}
+
+// withWriteTimeout bounds a write to the API server by APIWriteTimeout.
+func (r *GithubIssueReconciler) withWriteTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.APIWriteTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.APIWriteTimeout)
+}
+
+// updateStatus writes the GithubIssue status, timed as the status phase. A failed write is kept
+// and retried by the next reconcile of the object; once writes keep failing the conditions are
+// reported through an event so they aren't lost.
+func (r *GithubIssueReconciler) updateStatus(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	defer timePhase(ctx, phaseStatus)()
+	summarizeReadiness(issueObject)
+	writeCtx, cancel := r.withWriteTimeout(ctx)
+	defer cancel()
+	if err := r.Client.Status().Update(writeCtx, issueObject); err != nil {
+		if failures := r.pending.setStatus(objectKey(issueObject), repositoryLabel(issueObject.Spec.Repo), &issueObject.Status); failures >= statusFallbackThreshold {
+			r.reportUnwrittenStatus(ctx, issueObject, failures, err)
+		}
+		return err
+	}
+	r.pending.statusWritten(objectKey(issueObject))
+	return nil
+}
+
+// reportUnwrittenStatus emits the conditions of a status that could not be written as a warning event and
+// counts them, e.g. when RBAC or an admission webhook rejects writes to the status subresource.
+func (r *GithubIssueReconciler) reportUnwrittenStatus(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue, failures int, err error) {
+	r.logger(ctx).Warn("Failed to write status repeatedly, reporting conditions as an event",
+		zap.Int("failures", failures), zap.Error(err))
+	conditions := make([]string, 0, len(issueObject.Status.Conditions))
+	for _, condition := range issueObject.Status.Conditions {
+		conditions = append(conditions, fmt.Sprintf("%s=%s (%s)", condition.Type, condition.Status, condition.Reason))
+		metrics.StatusWriteFallbacks.WithLabelValues(condition.Type, string(condition.Status)).Inc()
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(issueObject, corev1.EventTypeWarning, "StatusUpdateFailed",
+			"Failed to write status %d times: %v. Conditions: %s", failures, err, strings.Join(conditions, ", "))
+	}
+}
+
+// retryPendingStatus requeues the object with backoff while a status write is still pending, unless the
+// reconcile already failed and is retried by the workqueue.
+func (r *GithubIssueReconciler) retryPendingStatus(issueObject *issuesv1alpha1.GithubIssue, result ctrl.Result, err error) ctrl.Result {
+	delay := r.pending.retryDelay(objectKey(issueObject))
+	if err != nil || delay == 0 {
+		return result
+	}
+	if result.RequeueAfter == 0 || delay < result.RequeueAfter {
+		result.RequeueAfter = delay
+	}
+	return result
+}
+
+// flushPendingStatus writes the status a previous reconcile failed to record on top of the fetched object.
+func (r *GithubIssueReconciler) flushPendingStatus(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	status, ok := r.pending.takeStatus(objectKey(issueObject))
+	if !ok {
+		return nil
+	}
+	r.logger(ctx).Info("Recording status a previous reconcile failed to write")
+	issueObject.Status = status
+	if err := r.updateStatus(ctx, issueObject); err != nil {
+		return fmt.Errorf("failed to write pending status: %v", err)
+	}
+	return nil
+}
+
+// ensureFinalizer adds the finalizer, bounded by APIWriteTimeout.
+func (r *GithubIssueReconciler) ensureFinalizer(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	writeCtx, cancel := r.withWriteTimeout(ctx)
+	defer cancel()
+	return finalizer.Ensure(writeCtx, r.Client, issueObject, r.logger(ctx))
+}
+
+// removeFinalizer drops the finalizer, bounded by APIWriteTimeout, and forgets the state kept for the object.
+func (r *GithubIssueReconciler) removeFinalizer(ctx context.Context, issueObject *issuesv1alpha1.GithubIssue) error {
+	writeCtx, cancel := r.withWriteTimeout(ctx)
+	defer cancel()
+	if err := finalizer.Cleanup(writeCtx, r.Client, issueObject, r.logger(ctx)); err != nil {
+		r.logger(ctx).Warn("Failed to remove finalizer, retrying", zap.Error(err))
+		return err
+	}
+	r.pending.forget(objectKey(issueObject))
+	return nil
+}
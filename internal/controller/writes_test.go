@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var _ = Describe("pending writes", func() {
+	It("keeps a failed status write and records it on the next reconcile", func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject := &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+
+		apiDown := true
+		k8sClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					if apiDown {
+						return context.DeadlineExceeded
+					}
+					return c.SubResource(subResource).Update(ctx, obj, opts...)
+				},
+			}).Build()
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Log: zap.NewNop(), pending: newPendingWrites()}
+
+		issueObject.Status.IssueNumber = 42
+		err := reconciler.updateStatus(context.Background(), issueObject)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+
+		apiDown = false
+		fetched := &issuesv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "issue", Namespace: "default"}, fetched)).To(Succeed())
+		Expect(fetched.Status.IssueNumber).To(BeZero())
+		Expect(reconciler.flushPendingStatus(context.Background(), fetched)).To(Succeed())
+
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "issue", Namespace: "default"}, fetched)).To(Succeed())
+		Expect(fetched.Status.IssueNumber).To(Equal(42))
+		_, pending := reconciler.pending.takeStatus("default/issue")
+		Expect(pending).To(BeFalse())
+	})
+
+	It("reports the conditions as an event once status writes keep failing", func() {
+		testScheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(testScheme)).To(Succeed())
+		issueObject := &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"}}
+		k8sClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(issueObject).WithStatusSubresource(issueObject).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					return errors.New("forbidden")
+				},
+			}).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Log: zap.NewNop(), Recorder: recorder, pending: newPendingWrites()}
+
+		for i := 1; i < statusFallbackThreshold; i++ {
+			Expect(reconciler.updateStatus(context.Background(), issueObject)).NotTo(Succeed())
+		}
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(reconciler.pending.retryDelay("default/issue")).To(Equal(2 * statusRetryBaseDelay))
+
+		Expect(reconciler.updateStatus(context.Background(), issueObject)).NotTo(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("StatusUpdateFailed")))
+		result := reconciler.retryPendingStatus(issueObject, ctrl.Result{RequeueAfter: time.Hour}, nil)
+		Expect(result.RequeueAfter).To(Equal(4 * statusRetryBaseDelay))
+	})
+
+	It("remembers closed issues until the object is forgotten", func() {
+		pending := newPendingWrites()
+		pending.setClosed("default/issue")
+		Expect(pending.isClosed("default/issue")).To(BeTrue())
+		pending.forget("default/issue")
+		Expect(pending.isClosed("default/issue")).To(BeFalse())
+	})
+})
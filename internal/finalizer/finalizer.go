@@ -12,15 +12,17 @@ import (
 
 const closeIssueFinalizer = "issues.dana.io/finalizer"
 
-// Ensure adds finalizer to GithubIssue CRD if missing
+// Ensure adds finalizer to GithubIssue CRD if missing. Only the finalizers are patched, so spec fields
+// defaulted in memory by the reconciler are never written back.
 func Ensure(ctx context.Context, c client.Client, obj client.Object, logger *zap.Logger) error {
 	githubIssue, ok := obj.(*issues.GithubIssue)
 	if !ok {
 		return fmt.Errorf("unexpected type: expected *issues.GithubIssue, got %T", obj)
 	}
 	if !controllerutil.ContainsFinalizer(obj, closeIssueFinalizer) {
+		patch := client.MergeFromWithOptions(githubIssue.DeepCopy(), client.MergeFromWithOptimisticLock{})
 		controllerutil.AddFinalizer(obj, closeIssueFinalizer)
-		if err := c.Update(ctx, obj); err != nil {
+		if err := c.Patch(ctx, obj, patch); err != nil {
 			return fmt.Errorf("failed to add finalizer: %w", err)
 		}
 		logger.Info("Finalizer added successfully",
@@ -43,8 +45,9 @@ func Cleanup(ctx context.Context, c client.Client, obj client.Object, logger *za
 		zap.String("githubIssue", githubIssue.Name),
 	)
 
+	patch := client.MergeFromWithOptions(githubIssue.DeepCopy(), client.MergeFromWithOptimisticLock{})
 	controllerutil.RemoveFinalizer(obj, closeIssueFinalizer)
-	if err := c.Update(ctx, obj); err != nil {
+	if err := c.Patch(ctx, obj, patch); err != nil {
 		return fmt.Errorf("failed to remove finalizer: %w", err)
 	}
 
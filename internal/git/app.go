@@ -0,0 +1,307 @@
+package git
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Keys of the Secret holding the credentials of a GitHub App.
+const (
+	AppIDKey         = "app-id"
+	AppPrivateKeyKey = "private-key"
+)
+
+func init() {
+	RegisterCredentialsProvider(CredentialsApp, func(options CredentialsOptions) (CredentialsProvider, error) {
+		namespace, name, ok := strings.Cut(options.Source, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("expected the namespace/name of a Secret, got %q", options.Source)
+		}
+		if options.ReadSecret == nil {
+			return nil, fmt.Errorf("credentials provider %s needs a Secret reader", CredentialsApp)
+		}
+		return &appCredentials{namespace: namespace, name: name, readSecret: options.ReadSecret}, nil
+	})
+}
+
+// appCredentials authenticates as the GitHub App whose ID and private key are held in a Secret.
+type appCredentials struct {
+	namespace  string
+	name       string
+	readSecret func(ctx context.Context, namespace, name, key string) (map[string][]byte, error)
+}
+
+func (c *appCredentials) Transport(ctx context.Context, base http.RoundTripper) (http.RoundTripper, error) {
+	data, err := c.readSecret(ctx, c.namespace, c.name, AppPrivateKeyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Secret %s/%s: %v", c.namespace, c.name, err)
+	}
+	appID, err := strconv.ParseInt(strings.TrimSpace(string(data[AppIDKey])), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in Secret %s/%s: %v", AppIDKey, c.namespace, c.name, err)
+	}
+	privateKey, err := ParseAppPrivateKey(data[AppPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in Secret %s/%s: %v", AppPrivateKeyKey, c.namespace, c.name, err)
+	}
+	return &AppTransport{Base: base, AppID: appID, PrivateKey: privateKey}, nil
+}
+
+// installationTokenRefresh is how long before its expiry an installation token is replaced.
+const installationTokenRefresh = 5 * time.Minute
+
+// installationMissTTL is how long an owner the app is not installed on is remembered, so requests for it
+// don't list the installations of the app each time.
+const installationMissTTL = time.Minute
+
+// ParseAppPrivateKey parses the PEM encoded private key of a GitHub App, in PKCS#1 or PKCS#8 form.
+func ParseAppPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in the private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// AppTransport authenticates requests as the installation of a GitHub App on the owner of the repository
+// they target. Installation tokens are minted on first use and cached per installation until shortly before
+// they expire, and each one has its own rate limit, which grows with the size of the installation.
+type AppTransport struct {
+	Base       http.RoundTripper
+	AppID      int64
+	PrivateKey *rsa.PrivateKey
+	// APIURL is the base URL of the API minting the installation tokens. Defaults to https://api.github.com/.
+	APIURL string
+
+	mu            sync.Mutex
+	installations map[string]int64     // Installation IDs by lower-case account login
+	misses        map[string]time.Time // When owners the app is not installed on were last looked up
+	tokens        map[int64]*github.InstallationToken
+}
+
+func (t *AppTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *AppTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	owner, err := requestOwner(req)
+	if err != nil {
+		return nil, err
+	}
+	token, err := t.installationToken(req, owner)
+	if err != nil {
+		return nil, err
+	}
+	return t.base().RoundTrip(authorize(req, token))
+}
+
+// installationToken returns a valid token of the installation on owner. An empty owner is served by the only
+// installation of the app. The token is minted without holding the lock, so requests for other installations
+// aren't held up; concurrent requests finding no valid token may each mint one.
+func (t *AppTransport) installationToken(req *http.Request, owner string) (string, error) {
+	id, err := t.installation(req, owner)
+	if err != nil {
+		return "", err
+	}
+	t.mu.Lock()
+	token, ok := t.tokens[id]
+	t.mu.Unlock()
+	if ok && time.Until(token.GetExpiresAt().Time) > installationTokenRefresh {
+		return token.GetToken(), nil
+	}
+
+	client, err := t.appClient()
+	if err != nil {
+		return "", err
+	}
+	token, _, err = client.Apps.CreateInstallationToken(req.Context(), id, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to mint installation token of GitHub App %d for %s: %v", t.AppID, owner, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tokens == nil {
+		t.tokens = map[int64]*github.InstallationToken{}
+	}
+	t.tokens[id] = token
+	return token.GetToken(), nil
+}
+
+// installation returns the ID of the installation on owner, listing the installations of the app again
+// when owner is unknown, so installing the app on a new account takes no restart. An owner the listing
+// doesn't find is remembered for installationMissTTL.
+func (t *AppTransport) installation(req *http.Request, owner string) (int64, error) {
+	key := strings.ToLower(owner)
+	t.mu.Lock()
+	id, ok := t.lookup(key)
+	missed, known := t.misses[key]
+	var err error
+	if !ok && known && time.Since(missed) < installationMissTTL {
+		err = t.notInstalled(req, owner)
+	}
+	t.mu.Unlock()
+	if ok || err != nil {
+		return id, err
+	}
+
+	client, err := t.appClient()
+	if err != nil {
+		return 0, err
+	}
+	installations := map[string]int64{}
+	options := &github.ListOptions{PerPage: 100}
+	for {
+		page, response, err := client.Apps.ListInstallations(req.Context(), options)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list installations of GitHub App %d: %v", t.AppID, err)
+		}
+		for _, installation := range page {
+			installations[strings.ToLower(installation.GetAccount().GetLogin())] = installation.GetID()
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.installations = installations
+	if id, ok := t.lookup(key); ok {
+		delete(t.misses, key)
+		return id, nil
+	}
+	if t.misses == nil {
+		t.misses = map[string]time.Time{}
+	}
+	t.misses[key] = time.Now()
+	return 0, t.notInstalled(req, owner)
+}
+
+// lookup returns the ID of the known installation on the lower-case owner. Callers hold t.mu.
+func (t *AppTransport) lookup(key string) (int64, bool) {
+	if key == "" && len(t.installations) == 1 {
+		for _, id := range t.installations {
+			return id, true
+		}
+	}
+	id, ok := t.installations[key]
+	return id, ok
+}
+
+// notInstalled returns the error of a request for an owner without installation. Callers hold t.mu.
+func (t *AppTransport) notInstalled(req *http.Request, owner string) error {
+	if owner == "" {
+		return fmt.Errorf("GitHub App %d has %d installations, none can be chosen for %s %s",
+			t.AppID, len(t.installations), req.Method, req.URL.Path)
+	}
+	return fmt.Errorf("GitHub App %d is not installed on %s", t.AppID, owner)
+}
+
+// appClient returns a client authenticated as the app itself.
+func (t *AppTransport) appClient() (*github.Client, error) {
+	client := github.NewClient(&http.Client{Transport: &appJWTTransport{app: t}})
+	if t.APIURL == "" {
+		return client, nil
+	}
+	return client.WithEnterpriseURLs(t.APIURL, t.APIURL)
+}
+
+// appJWTTransport authenticates requests with a JSON Web Token signed by the private key of the app.
+type appJWTTransport struct {
+	app *AppTransport
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return t.app.base().RoundTrip(authorize(req, token))
+}
+
+// jwt returns a token valid for nine minutes, issued a minute in the past to allow for clock drift.
+func (t *AppTransport) jwt(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(t.AppID, 10),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, t.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App token: %v", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// requestOwner returns the account a request targets: the owner in the path of REST requests, or the owner
+// or login variable of GraphQL requests. It returns an empty owner for requests targeting no account.
+func requestOwner(req *http.Request) (string, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/api/v3")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 2 && (segments[0] == "repos" || segments[0] == "orgs" || segments[0] == "users") {
+		return segments[1], nil
+	}
+	if !strings.HasSuffix(path, "/graphql") || req.GetBody == nil {
+		return "", nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", fmt.Errorf("failed to read graphql request: %v", err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read graphql request: %v", err)
+	}
+	var request struct {
+		Variables struct {
+			Owner string `json:"owner"`
+			Login string `json:"login"`
+		} `json:"variables"`
+	}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return "", nil
+	}
+	if request.Variables.Owner != "" {
+		return request.Variables.Owner, nil
+	}
+	return request.Variables.Login, nil
+}
@@ -0,0 +1,171 @@
+package git
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeAppAPI serves the installations of a GitHub App and the repositories of its installations.
+type fakeAppAPI struct {
+	*httptest.Server
+	mu sync.Mutex
+	// tokenLifetime is how long the minted installation tokens are valid.
+	tokenLifetime time.Duration
+	listed        int
+	minted        int
+	appAuth       []string
+	repoAuth      []string
+}
+
+func newFakeAppAPI() *fakeAppAPI {
+	api := &fakeAppAPI{tokenLifetime: time.Hour}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		authorization := r.Header.Get("Authorization")
+		switch path := strings.TrimPrefix(r.URL.Path, "/api/v3"); {
+		case path == "/app/installations":
+			api.listed++
+			api.appAuth = append(api.appAuth, authorization)
+			_, _ = fmt.Fprint(w, `[{"id": 1, "account": {"login": "Org"}}]`)
+		case path == "/app/installations/1/access_tokens":
+			api.minted++
+			api.appAuth = append(api.appAuth, authorization)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"token":      fmt.Sprintf("installation-%d", api.minted),
+				"expires_at": time.Now().Add(api.tokenLifetime).UTC().Format(time.RFC3339),
+			})
+		default:
+			api.repoAuth = append(api.repoAuth, authorization)
+		}
+	}))
+	ginkgo.DeferCleanup(api.Close)
+	return api
+}
+
+var appPrivateKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+var _ = ginkgo.Describe("AppTransport", func() {
+	var (
+		api       *fakeAppAPI
+		transport *AppTransport
+	)
+
+	ginkgo.BeforeEach(func() {
+		api = newFakeAppAPI()
+		transport = &AppTransport{AppID: 42, PrivateKey: appPrivateKey, APIURL: api.URL}
+	})
+
+	get := func(path string) error {
+		req, err := http.NewRequest(http.MethodGet, api.URL+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	ginkgo.It("signs a JSON Web Token issued to the app", func() {
+		now := time.Unix(1700000000, 0)
+		token, err := transport.jwt(now)
+		Expect(err).NotTo(HaveOccurred())
+
+		parts := strings.Split(token, ".")
+		Expect(parts).To(HaveLen(3))
+		decode := func(part string, out interface{}) {
+			data, err := base64.RawURLEncoding.DecodeString(part)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(data, out)).To(Succeed())
+		}
+		var header map[string]string
+		decode(parts[0], &header)
+		Expect(header).To(Equal(map[string]string{"alg": "RS256", "typ": "JWT"}))
+		var claims map[string]interface{}
+		decode(parts[1], &claims)
+		Expect(claims).To(Equal(map[string]interface{}{
+			"iat": float64(now.Add(-time.Minute).Unix()),
+			"exp": float64(now.Add(9 * time.Minute).Unix()),
+			"iss": "42",
+		}))
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		Expect(err).NotTo(HaveOccurred())
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		Expect(rsa.VerifyPKCS1v15(&appPrivateKey.PublicKey, crypto.SHA256, digest[:], signature)).To(Succeed())
+	})
+
+	ginkgo.It("finds the owner a request targets", func() {
+		owner := func(path, body string) string {
+			var req *http.Request
+			var err error
+			if body == "" {
+				req, err = http.NewRequest(http.MethodGet, "https://api.github.com"+path, nil)
+			} else {
+				req, err = http.NewRequest(http.MethodPost, "https://api.github.com"+path, bytes.NewReader([]byte(body)))
+			}
+			Expect(err).NotTo(HaveOccurred())
+			owner, err := requestOwner(req)
+			Expect(err).NotTo(HaveOccurred())
+			return owner
+		}
+
+		Expect(owner("/repos/Org/repo/issues", "")).To(Equal("Org"))
+		Expect(owner("/api/v3/orgs/org/projects", "")).To(Equal("org"))
+		Expect(owner("/users/someone", "")).To(Equal("someone"))
+		Expect(owner("/rate_limit", "")).To(BeEmpty())
+		Expect(owner("/graphql", `{"query": "q", "variables": {"owner": "org", "repo": "repo"}}`)).To(Equal("org"))
+		Expect(owner("/api/graphql", `{"query": "q", "variables": {"login": "someone"}}`)).To(Equal("someone"))
+		Expect(owner("/graphql", `{"query": "q"}`)).To(BeEmpty())
+	})
+
+	ginkgo.It("reuses the installation token until it is about to expire", func() {
+		Expect(get("/repos/org/repo")).To(Succeed())
+		Expect(get("/repos/Org/other")).To(Succeed())
+		Expect(api.minted).To(Equal(1))
+		Expect(api.repoAuth).To(Equal([]string{"Bearer installation-1", "Bearer installation-1"}))
+		for _, authorization := range api.appAuth {
+			Expect(strings.Split(authorization, ".")).To(HaveLen(3))
+		}
+
+		api.tokenLifetime = installationTokenRefresh / 2
+		transport.tokens = nil
+		Expect(get("/repos/org/repo")).To(Succeed())
+		Expect(get("/repos/org/repo")).To(Succeed())
+		Expect(api.minted).To(Equal(3))
+		Expect(api.repoAuth[2:]).To(Equal([]string{"Bearer installation-2", "Bearer installation-3"}))
+		Expect(api.listed).To(Equal(1))
+	})
+
+	ginkgo.It("remembers the owners the app is not installed on", func() {
+		Expect(get("/repos/other/repo")).To(MatchError("GitHub App 42 is not installed on other"))
+		Expect(get("/repos/other/repo")).To(MatchError("GitHub App 42 is not installed on other"))
+		Expect(api.listed).To(Equal(1))
+
+		transport.misses["other"] = time.Now().Add(-installationMissTTL)
+		Expect(get("/repos/other/repo")).To(HaveOccurred())
+		Expect(api.listed).To(Equal(2))
+		Expect(api.repoAuth).To(BeEmpty())
+	})
+})
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Credential names reported by TokenFailoverTransport.
+const (
+	CredentialPrimary   = "primary"
+	CredentialSecondary = "secondary"
+)
+
+// CredentialRead names the token issue reads are sent with when they go to a separate API, see SplitIssueClient.
+const CredentialRead = "read"
+
+// TokenFailoverTransport authenticates requests with a primary token and transparently switches to the
+// secondary token when the active one is rejected with 401, so tokens can be rotated without downtime.
+type TokenFailoverTransport struct {
+	Base      http.RoundTripper
+	Primary   string
+	Secondary string
+	// OnSwitch is called after the active credential changed.
+	OnSwitch func(from, to string)
+
+	mu     sync.RWMutex
+	active string
+}
+
+// Active returns the name of the credential currently used.
+func (t *TokenFailoverTransport) Active() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.active == "" {
+		return CredentialPrimary
+	}
+	return t.active
+}
+
+func (t *TokenFailoverTransport) token(name string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if name == CredentialSecondary {
+		return t.Secondary
+	}
+	return t.Primary
+}
+
+// SetPrimary replaces the primary token, e.g. after the Secret holding it was rotated. A rotated token is
+// assumed to be valid, so requests switch back to it if the secondary one was active.
+func (t *TokenFailoverTransport) SetPrimary(token string) {
+	t.mu.Lock()
+	if t.Primary == token {
+		t.mu.Unlock()
+		return
+	}
+	t.Primary = token
+	switched := t.active == CredentialSecondary
+	t.active = CredentialPrimary
+	t.mu.Unlock()
+
+	if switched && t.OnSwitch != nil {
+		t.OnSwitch(CredentialSecondary, CredentialPrimary)
+	}
+}
+
+func (t *TokenFailoverTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TokenFailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	active := t.Active()
+	resp, err := t.base().RoundTrip(authorize(req, t.token(active)))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || t.Secondary == "" {
+		return resp, err
+	}
+
+	standby := CredentialSecondary
+	if active == CredentialSecondary {
+		standby = CredentialPrimary
+	}
+	if t.token(standby) == "" {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+
+	retryResp, err := t.base().RoundTrip(authorize(retry, t.token(standby)))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("retry with %s credential failed: %w", standby, err)
+	}
+	_ = resp.Body.Close()
+
+	if retryResp.StatusCode != http.StatusUnauthorized {
+		t.switchTo(active, standby)
+	}
+	return retryResp, nil
+}
+
+func (t *TokenFailoverTransport) switchTo(from, to string) {
+	t.mu.Lock()
+	current := t.active
+	if current == "" {
+		current = CredentialPrimary
+	}
+	if current != from {
+		// Another request already switched.
+		t.mu.Unlock()
+		return
+	}
+	t.active = to
+	t.mu.Unlock()
+
+	if t.OnSwitch != nil {
+		t.OnSwitch(from, to)
+	}
+}
+
+// authorize returns a copy of req carrying the given token.
+func authorize(req *http.Request, token string) *http.Request {
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+	return authorized
+}
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// tokenServer accepts the requests carrying one of its valid tokens and rejects the others with 401.
+type tokenServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	valid map[string]bool
+	seen  []string
+}
+
+func newTokenServer(valid ...string) *tokenServer {
+	server := &tokenServer{valid: map[string]bool{}}
+	for _, token := range valid {
+		server.valid[token] = true
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		server.mu.Lock()
+		server.seen = append(server.seen, token)
+		ok := server.valid[token]
+		server.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	ginkgo.DeferCleanup(server.Close)
+	return server
+}
+
+func (s *tokenServer) setValid(valid ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.valid = map[string]bool{}
+	for _, token := range valid {
+		s.valid[token] = true
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// closeTracker is a response body recording whether it was closed.
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+var _ = ginkgo.Describe("TokenFailoverTransport", func() {
+	var switches []string
+
+	newTransport := func(primary, secondary string) *TokenFailoverTransport {
+		switches = nil
+		return &TokenFailoverTransport{
+			Primary:   primary,
+			Secondary: secondary,
+			OnSwitch: func(from, to string) {
+				switches = append(switches, from+"->"+to)
+			},
+		}
+	}
+
+	get := func(transport http.RoundTripper, url string) int {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := transport.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	ginkgo.It("fails over to the secondary token when the primary one is rejected", func() {
+		server := newTokenServer("secondary")
+		transport := newTransport("primary", "secondary")
+
+		Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+		Expect(transport.Active()).To(Equal(CredentialSecondary))
+		Expect(switches).To(Equal([]string{"primary->secondary"}))
+
+		Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+		Expect(server.seen).To(Equal([]string{"primary", "secondary", "secondary"}))
+	})
+
+	ginkgo.It("switches back to the primary token once the secondary one is rejected", func() {
+		server := newTokenServer("secondary")
+		transport := newTransport("primary", "secondary")
+		Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+
+		server.setValid("primary")
+		Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+		Expect(transport.Active()).To(Equal(CredentialPrimary))
+		Expect(switches).To(Equal([]string{"primary->secondary", "secondary->primary"}))
+	})
+
+	ginkgo.It("switches back to a rotated primary token", func() {
+		server := newTokenServer("secondary")
+		transport := newTransport("primary", "secondary")
+		Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+
+		transport.SetPrimary("rotated")
+		Expect(transport.Active()).To(Equal(CredentialPrimary))
+		Expect(switches).To(Equal([]string{"primary->secondary", "secondary->primary"}))
+	})
+
+	ginkgo.It("returns the rejection when the request body can't be replayed", func() {
+		server := newTokenServer("secondary")
+		transport := newTransport("primary", "secondary")
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Body = io.NopCloser(strings.NewReader(`{"title":"t"}`))
+		req.GetBody = nil
+		resp, err := transport.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(transport.Active()).To(Equal(CredentialPrimary))
+		Expect(switches).To(BeEmpty())
+	})
+
+	ginkgo.It("closes the rejected response when the retry fails", func() {
+		rejected := &closeTracker{Reader: strings.NewReader("")}
+		calls := 0
+		transport := newTransport("primary", "secondary")
+		transport.Base = roundTripFunc(func(*http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return &http.Response{StatusCode: http.StatusUnauthorized, Body: rejected}, nil
+			}
+			return nil, errors.New("connection reset")
+		})
+
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = transport.RoundTrip(req)
+		Expect(err).To(MatchError(ContainSubstring("retry with secondary credential failed")))
+		Expect(rejected.closed).To(BeTrue())
+	})
+})
@@ -0,0 +1,152 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Names of the built-in credentials providers.
+const (
+	CredentialsEnv    = "env"
+	CredentialsSecret = "secret"
+	CredentialsFile   = "file"
+	CredentialsApp    = "app"
+	CredentialsExec   = "exec"
+)
+
+// CredentialsProvider authenticates the requests of the operator. Providers register a factory with
+// RegisterCredentialsProvider from an init function, so supporting a new authentication mechanism takes no
+// change outside of its file. Providers refreshing their credentials in the background also implement
+// manager.Runnable.
+type CredentialsProvider interface {
+	// Transport loads the credentials and returns a transport authenticating the requests it sends through base.
+	Transport(ctx context.Context, base http.RoundTripper) (http.RoundTripper, error)
+}
+
+// CredentialsOptions configure the provider built by NewCredentialsProvider.
+type CredentialsOptions struct {
+	// Source is what the credentials are read from, in the form of the provider: the name of an environment
+	// variable, the namespace/name of a Secret, the path of a file or a command line.
+	Source string
+	// Key of the Secret holding the token. Only used by the secret provider.
+	Key string
+	// ReadSecret returns the data of a Secret. Needed by the providers reading Secrets.
+	ReadSecret func(ctx context.Context, namespace, name, key string) (map[string][]byte, error)
+	// OnSwitch is called after the provider switched between its primary and secondary token. Optional.
+	OnSwitch func(from, to string)
+	// OnReload is called with the error of every background reload of the credentials. Optional.
+	OnReload func(err error)
+}
+
+// CredentialsProviderFactory builds a provider from its options.
+type CredentialsProviderFactory func(options CredentialsOptions) (CredentialsProvider, error)
+
+var credentialsProviders = struct {
+	mu        sync.RWMutex
+	factories map[string]CredentialsProviderFactory
+}{factories: map[string]CredentialsProviderFactory{}}
+
+// RegisterCredentialsProvider adds a credentials provider to the registry. Registering a name twice panics.
+func RegisterCredentialsProvider(name string, factory CredentialsProviderFactory) {
+	credentialsProviders.mu.Lock()
+	defer credentialsProviders.mu.Unlock()
+	if _, ok := credentialsProviders.factories[name]; ok {
+		panic(fmt.Sprintf("credentials provider %s registered twice", name))
+	}
+	credentialsProviders.factories[name] = factory
+}
+
+// CredentialsProviders returns the names of the registered credentials providers, sorted.
+func CredentialsProviders() []string {
+	credentialsProviders.mu.RLock()
+	defer credentialsProviders.mu.RUnlock()
+	names := make([]string, 0, len(credentialsProviders.factories))
+	for name := range credentialsProviders.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewCredentialsProvider builds the credentials provider registered under name.
+func NewCredentialsProvider(name string, options CredentialsOptions) (CredentialsProvider, error) {
+	credentialsProviders.mu.RLock()
+	factory, ok := credentialsProviders.factories[name]
+	credentialsProviders.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown credentials provider %q, expected one of %s", name,
+			strings.Join(CredentialsProviders(), ", "))
+	}
+	return factory(options)
+}
+
+func init() {
+	RegisterCredentialsProvider(CredentialsEnv, func(options CredentialsOptions) (CredentialsProvider, error) {
+		variable := options.Source
+		if variable == "" {
+			variable = "GITHUB_TOKEN"
+		}
+		return &envCredentials{variable: variable, onSwitch: options.OnSwitch}, nil
+	})
+	RegisterCredentialsProvider(CredentialsSecret, func(options CredentialsOptions) (CredentialsProvider, error) {
+		namespace, name, ok := strings.Cut(options.Source, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("expected the namespace/name of a Secret, got %q", options.Source)
+		}
+		if options.ReadSecret == nil {
+			return nil, fmt.Errorf("credentials provider %s needs a Secret reader", CredentialsSecret)
+		}
+		return &SecretCredentials{
+			Namespace:  namespace,
+			Name:       name,
+			Key:        options.Key,
+			ReadSecret: options.ReadSecret,
+			Tokens:     &TokenFailoverTransport{OnSwitch: options.OnSwitch},
+		}, nil
+	})
+}
+
+// envCredentials authenticates with the token of an environment variable, failing over to the token of the
+// same variable suffixed with _SECONDARY.
+type envCredentials struct {
+	variable string
+	onSwitch func(from, to string)
+}
+
+func (c *envCredentials) Transport(_ context.Context, base http.RoundTripper) (http.RoundTripper, error) {
+	return &TokenFailoverTransport{
+		Base:      base,
+		Primary:   os.Getenv(c.variable),
+		Secondary: os.Getenv(c.variable + "_SECONDARY"),
+		OnSwitch:  c.onSwitch,
+	}, nil
+}
+
+// SecretCredentials authenticates with the token held in a Secret key. The token is read once by Transport;
+// keeping it in sync with the Secret is up to a watch of the Secret calling Tokens.SetPrimary.
+type SecretCredentials struct {
+	Namespace  string
+	Name       string
+	Key        string
+	ReadSecret func(ctx context.Context, namespace, name, key string) (map[string][]byte, error)
+	Tokens     *TokenFailoverTransport
+}
+
+func (c *SecretCredentials) Transport(ctx context.Context, base http.RoundTripper) (http.RoundTripper, error) {
+	data, err := c.ReadSecret(ctx, c.Namespace, c.Name, c.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Secret %s/%s: %v", c.Namespace, c.Name, err)
+	}
+	token := data[c.Key]
+	if len(token) == 0 {
+		return nil, fmt.Errorf("key %s not found in Secret %s/%s", c.Key, c.Namespace, c.Name)
+	}
+	c.Tokens.Base = base
+	c.Tokens.SetPrimary(string(token))
+	return c.Tokens, nil
+}
@@ -0,0 +1,31 @@
+package git
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("credentials provider registry", func() {
+	ginkgo.It("lists the built-in providers", func() {
+		Expect(CredentialsProviders()).To(ContainElements(
+			CredentialsApp, CredentialsEnv, CredentialsExec, CredentialsFile, CredentialsSecret))
+	})
+
+	ginkgo.It("builds the provider registered under a name", func() {
+		provider, err := NewCredentialsProvider(CredentialsFile, CredentialsOptions{Source: "/var/run/secrets/github/token"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider).To(BeAssignableToTypeOf(&TokenFile{}))
+	})
+
+	ginkgo.It("fails for an unknown provider, naming the registered ones", func() {
+		_, err := NewCredentialsProvider("vault", CredentialsOptions{})
+		Expect(err).To(MatchError(SatisfyAll(
+			ContainSubstring(`unknown credentials provider "vault"`), ContainSubstring(CredentialsSecret))))
+	})
+
+	ginkgo.It("refuses to register a name twice", func() {
+		Expect(func() {
+			RegisterCredentialsProvider(CredentialsEnv, func(CredentialsOptions) (CredentialsProvider, error) { return nil, nil })
+		}).To(PanicWith("credentials provider env registered twice"))
+	})
+})
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// ConvertedToDiscussionError is returned for an issue that was converted to a discussion and no longer exists as an issue.
+type ConvertedToDiscussionError struct {
+	// URL of the discussion, empty when GitHub did not report it.
+	URL string
+}
+
+func (e *ConvertedToDiscussionError) Error() string {
+	if e.URL == "" {
+		return "issue was converted to a discussion"
+	}
+	return fmt.Sprintf("issue was converted to discussion %s", e.URL)
+}
+
+// AsConvertedToDiscussion reports whether err is, or wraps, a *ConvertedToDiscussionError.
+func AsConvertedToDiscussion(err error) (*ConvertedToDiscussionError, bool) {
+	var converted *ConvertedToDiscussionError
+	ok := errors.As(err, &converted)
+	return converted, ok
+}
+
+func convertedToDiscussion(response *github.Response) error {
+	return &ConvertedToDiscussionError{URL: response.Header.Get("Location")}
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// execTokenRefresh is how long before its expiry a token printed by a credentials command is replaced.
+const execTokenRefresh = time.Minute
+
+func init() {
+	RegisterCredentialsProvider(CredentialsExec, func(options CredentialsOptions) (CredentialsProvider, error) {
+		command := strings.Fields(options.Source)
+		if len(command) == 0 {
+			return nil, fmt.Errorf("credentials provider %s needs a command", CredentialsExec)
+		}
+		return &ExecTransport{Command: command}, nil
+	})
+}
+
+// ExecTransport authenticates requests with the token printed by a command, e.g. a plugin fetching it from a
+// vault. The command prints either the bare token, or a JSON object with the token and its RFC 3339
+// expiry: {"token": "...", "expiresAt": "2030-01-01T00:00:00Z"}. The token is cached until shortly before
+// it expires, or without an expiry until GitHub rejects it with 401, then the command is run again.
+type ExecTransport struct {
+	Base    http.RoundTripper
+	Command []string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// execCredential is the JSON output of a credentials command.
+type execCredential struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Transport runs the command once and returns the transport. It implements CredentialsProvider.
+func (t *ExecTransport) Transport(ctx context.Context, base http.RoundTripper) (http.RoundTripper, error) {
+	t.Base = base
+	if _, err := t.currentToken(ctx); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *ExecTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// currentToken returns the cached token, running the command when there is none or it is about to expire.
+func (t *ExecTransport) currentToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && (t.expiresAt.IsZero() || time.Until(t.expiresAt) > execTokenRefresh) {
+		return t.token, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.Command[0], t.Command[1:]...) //nolint:gosec // the command is configured by the operator
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run credentials command: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	credential := execCredential{Token: strings.TrimSpace(stdout.String())}
+	if strings.HasPrefix(credential.Token, "{") {
+		credential = execCredential{}
+		if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+			return "", fmt.Errorf("failed to parse the output of the credentials command: %v", err)
+		}
+	}
+	if credential.Token == "" {
+		return "", fmt.Errorf("credentials command printed no token")
+	}
+	t.token, t.expiresAt = credential.Token, credential.ExpiresAt
+	return t.token, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ExecTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.currentToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base().RoundTrip(authorize(req, token))
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		t.mu.Lock()
+		if t.token == token {
+			t.token = ""
+		}
+		t.mu.Unlock()
+	}
+	return resp, err
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenExpirationHeader is set by GitHub on responses to requests authenticated with an expiring token.
+const TokenExpirationHeader = "GitHub-Authentication-Token-Expiration"
+
+// CredentialOperator names the operator-wide token in an ExpiryTracker.
+const CredentialOperator = "operator"
+
+// tokenExpirationLayouts are the formats GitHub uses for TokenExpirationHeader.
+var tokenExpirationLayouts = []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"}
+
+// ExpiryTracker records the expiry GitHub reports for each credential.
+type ExpiryTracker struct {
+	// OnObserve is called for every response carrying an expiry. Optional.
+	OnObserve func(credential string, expiresAt time.Time)
+
+	mu       sync.RWMutex
+	expiries map[string]time.Time
+}
+
+// Transport returns a RoundTripper recording the expiry of the token base authenticates with as credential.
+func (t *ExpiryTracker) Transport(base http.RoundTripper, credential string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &expiryTransport{base: base, tracker: t, credential: credential}
+}
+
+// ExpiresAt returns when the token of credential expires. It reports false for tokens without a known expiry.
+func (t *ExpiryTracker) ExpiresAt(credential string) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	expiresAt, ok := t.expiries[credential]
+	return expiresAt, ok
+}
+
+func (t *ExpiryTracker) observe(credential string, expiresAt time.Time) {
+	t.mu.Lock()
+	if t.expiries == nil {
+		t.expiries = map[string]time.Time{}
+	}
+	t.expiries[credential] = expiresAt
+	t.mu.Unlock()
+
+	if t.OnObserve != nil {
+		t.OnObserve(credential, expiresAt)
+	}
+}
+
+type expiryTransport struct {
+	base       http.RoundTripper
+	tracker    *ExpiryTracker
+	credential string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *expiryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if expiresAt, ok := ParseTokenExpiration(resp.Header.Get(TokenExpirationHeader)); ok {
+		t.tracker.observe(t.credential, expiresAt)
+	}
+	return resp, nil
+}
+
+// ParseTokenExpiration parses the value of TokenExpirationHeader.
+func ParseTokenExpiration(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range tokenExpirationLayouts {
+		if expiresAt, err := time.Parse(layout, value); err == nil {
+			return expiresAt, true
+		}
+	}
+	return time.Time{}, false
+}
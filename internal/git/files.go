@@ -0,0 +1,61 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v56/github"
+	"net/http"
+)
+
+// File is a file read from a Git repository.
+type File struct {
+	Path    string // Path of the file relative to the repository root
+	Content []byte
+}
+
+// FileClient reads files stored in Git repositories.
+type FileClient interface {
+	// ListFiles returns the regular files directly inside dir at the given ref. An empty ref reads the default branch.
+	ListFiles(ctx context.Context, owner, repo, dir, ref string) ([]*File, error)
+}
+
+// GitHubFileClient reads repository files through the GitHub contents API.
+type GitHubFileClient struct {
+	Client *github.Client
+}
+
+func (c *GitHubFileClient) ListFiles(ctx context.Context, owner, repo, dir, ref string) ([]*File, error) {
+	opts := &github.RepositoryContentGetOptions{Ref: ref}
+	_, entries, response, err := c.Client.Repositories.GetContents(ctx, owner, repo, dir, opts)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to list files: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to list files: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list files: unexpected status code %d", response.StatusCode)
+	}
+
+	var files []*File
+	for _, entry := range entries {
+		if entry.GetType() != "file" {
+			continue
+		}
+		fileContent, _, response, err := c.Client.Repositories.GetContents(ctx, owner, repo, entry.GetPath(), opts)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to read file %s: %s, %v", entry.GetPath(), response.Status, err)
+			}
+			return nil, fmt.Errorf("failed to read file %s: %v", entry.GetPath(), err)
+		}
+		content, err := fileContent.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode file %s: %v", entry.GetPath(), err)
+		}
+		files = append(files, &File{Path: entry.GetPath(), Content: []byte(content)})
+	}
+
+	return files, nil
+}
@@ -2,81 +2,268 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/go-github/v56/github"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Issue represents the generic issue across Git platforms like GitHub, GitLab, etc.
 type Issue struct {
 	Number      int
+	NodeID      string // GraphQL node ID of the issue
 	Title       string // Issue title
 	Description string // Issue description
 	State       string // Issue state (e.g., "open", "closed")
+	StateReason string // Why the issue is in its state (e.g., "completed", "not_planned"), empty when unset
 	HasPR       bool   // Whether the issue has an associated PR or merge request
 	URL         string // URL of the issue on the platform
+	Labels      []string
+	Assignees   []string  // Logins of the users the issue is assigned to
+	Milestone   int       // Number of the milestone the issue is assigned to, 0 when none
+	Locked      bool      // Whether the conversation is locked
+	LockReason  string    // Reason given when the conversation was locked
+	CreatedAt   time.Time // When the issue was opened on the platform
+	UpdatedAt   time.Time // Last upstream activity on the issue
+	// The fields below are only read by GraphQLIssueClient; the REST client leaves them empty.
+	LinkedPRs []string       // URLs of the pull requests that close the issue when merged
+	Projects  []string       // Node IDs of the Projects V2 boards the issue is on
+	Reactions map[string]int // Reaction counts by lower-case GraphQL content, e.g. thumbs_up
+	Pinned    *bool          // Whether the issue is pinned to the repository, nil when the client doesn't read it
+}
+
+// CanonicalIssue is the issue a duplicate issue was marked a duplicate of.
+type CanonicalIssue struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"` // May point to another repository
+}
+
+// LinkedPullRequest is a pull request that closes an issue when merged.
+type LinkedPullRequest struct {
+	URL   string `json:"url"`
+	State string `json:"state"` // OPEN, CLOSED or MERGED
+}
+
+// ErrCommentNotFound is returned when reading or editing a comment that was deleted upstream.
+var ErrCommentNotFound = errors.New("comment not found")
+
+// GitHub limits on a single issue.
+const (
+	MaxAssignees = 10
+	MaxLabels    = 100
+)
+
+// EstimateLabelPrefix prefixes the label carrying the estimate of a GitHub issue.
+const EstimateLabelPrefix = "estimate/"
+
+// EstimateLabel returns the label GitHub issues carry for an estimate.
+func EstimateLabel(estimate int32) string {
+	return fmt.Sprintf("%s%d", EstimateLabelPrefix, estimate)
+}
+
+// ParseRepoURL parses a repository URL and extracts the owner and repository name.
+// Returns an error if the URL format is invalid.
+func ParseRepoURL(repoURL string) (string, string, error) {
+	parts := strings.Split(repoURL, "/")
+	if len(parts) < 5 {
+		return "", "", fmt.Errorf("invalid repository URL: %s", repoURL)
+	}
+	return parts[3], parts[4], nil
+}
+
+// DesiredIssue holds every field written to an issue in a single create or edit call.
+type DesiredIssue struct {
+	Title     string
+	Body      string
+	Labels    []string // Ignored by Edit, which never removes labels
+	Assignees []string // Nil leaves the assignees of an edited issue unchanged
+	Milestone int      // Zero leaves the issue unassigned, or its milestone unchanged on Edit
+	Type      string   // Issue type, such as Bug or Task. Empty leaves the type unset, or unchanged on Edit
 }
 
 // The IssueClient interface defines an interface for issuers in Git, such as GitHub or GitLab.
 type IssueClient interface {
-	// List retrieves a list of issues from the specified GitHub repository.
+	// List returns every open issue of the repository, paging through all of them.
 	List(ctx context.Context, owner, repo string) ([]*Issue, error)
 
-	// Create creates a new issue in the specified GitHub repository.
-	Create(ctx context.Context, owner, repo, title, body string) (*Issue, error)
+	// Get retrieves a single issue by number. It returns a *ConvertedToDiscussionError when the issue
+	// was converted to a discussion.
+	Get(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error)
 
-	// Edit modifies the body of an existing issue in the specified GitHub repository.
-	Edit(ctx context.Context, owner, repo string, issueNumber int, body string) (*Issue, error)
+	// Create creates a new issue with all desired fields in the specified GitHub repository.
+	Create(ctx context.Context, owner, repo string, desired *DesiredIssue) (*Issue, error)
+
+	// Edit modifies the title, body, assignees and milestone of an existing issue in the specified GitHub repository.
+	Edit(ctx context.Context, owner, repo string, issueNumber int, desired *DesiredIssue) (*Issue, error)
 
 	// Close closes an existing issue in the specified GitHub repository.
 	Close(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error)
+
+	// Reopen reopens a closed issue in the specified GitHub repository.
+	Reopen(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error)
+
+	// AddLabels adds labels to an existing issue, creating missing labels in the repository.
+	AddLabels(ctx context.Context, owner, repo string, issueNumber int, labels []string) error
+
+	// RemoveLabel removes a single label from an existing issue.
+	RemoveLabel(ctx context.Context, owner, repo string, issueNumber int, label string) error
+
+	// Lock locks the conversation of an existing issue. The reason may be empty.
+	Lock(ctx context.Context, owner, repo string, issueNumber int, reason string) error
+
+	// Unlock unlocks the conversation of an existing issue.
+	Unlock(ctx context.Context, owner, repo string, issueNumber int) error
+
+	// SetPinned pins or unpins an existing issue on the repository issue list.
+	SetPinned(ctx context.Context, owner, repo string, issueNumber int, pinned bool) error
+
+	// AddToProject adds an existing issue to a Projects V2 board, given by URL or node ID.
+	AddToProject(ctx context.Context, owner, repo string, issueNumber int, project string) error
+
+	// Comment adds a comment to an existing issue and returns the comment ID.
+	Comment(ctx context.Context, owner, repo string, issueNumber int, body string) (int64, error)
+
+	// GetComment returns the body of a comment. It returns ErrCommentNotFound for a deleted comment.
+	GetComment(ctx context.Context, owner, repo string, commentID int64) (string, error)
+
+	// EditComment replaces the body of a comment. It returns ErrCommentNotFound for a deleted comment.
+	EditComment(ctx context.Context, owner, repo string, commentID int64, body string) error
+
+	// DeleteComment deletes a comment. Deleting a comment that no longer exists succeeds.
+	DeleteComment(ctx context.Context, owner, repo string, commentID int64) error
+
+	// ListLabels returns the names of the labels defined in the repository.
+	ListLabels(ctx context.Context, owner, repo string) ([]string, error)
+
+	// FindMilestone returns the number of the milestone with the given title.
+	FindMilestone(ctx context.Context, owner, repo, title string) (int, error)
+
+	// DuplicateOf returns the issue an existing issue is marked a duplicate of according to its timeline,
+	// or nil when it is not marked as a duplicate.
+	DuplicateOf(ctx context.Context, owner, repo string, issueNumber int) (*CanonicalIssue, error)
+
+	// LinkedPullRequests returns the pull requests that close an existing issue when merged, whatever their state.
+	LinkedPullRequests(ctx context.Context, owner, repo string, issueNumber int) ([]LinkedPullRequest, error)
 }
 
 // GitHubIssueClient defines a specific IssueClient implementation for GitHub.
 type GitHubIssueClient struct {
 	Client *github.Client
+	// GraphQL is used for operations missing from the REST API. It defaults to a client derived from Client.
+	GraphQL *GraphQLClient
 }
 
 func mapGitHubIssue(ghIssue *github.Issue) *Issue {
 	if ghIssue == nil {
 		return nil
 	}
+	var labels []string
+	for _, label := range ghIssue.Labels {
+		labels = append(labels, label.GetName())
+	}
+	var assignees []string
+	for _, assignee := range ghIssue.Assignees {
+		assignees = append(assignees, assignee.GetLogin())
+	}
 	return &Issue{
 		Number:      ghIssue.GetNumber(),
+		NodeID:      ghIssue.GetNodeID(),
 		Title:       ghIssue.GetTitle(),
 		Description: ghIssue.GetBody(),
 		State:       ghIssue.GetState(),
+		StateReason: ghIssue.GetStateReason(),
 		HasPR:       ghIssue.GetPullRequestLinks() != nil,
 		URL:         ghIssue.GetHTMLURL(),
+		Labels:      labels,
+		Assignees:   assignees,
+		Milestone:   ghIssue.GetMilestone().GetNumber(),
+		Locked:      ghIssue.GetLocked(),
+		LockReason:  ghIssue.GetActiveLockReason(),
+		CreatedAt:   ghIssue.GetCreatedAt().Time,
+		UpdatedAt:   ghIssue.GetUpdatedAt().Time,
 	}
 }
 
+// List returns the open issues of the repository, paging through all of them.
 func (c *GitHubIssueClient) List(ctx context.Context, owner, repo string) ([]*Issue, error) {
-	issues, response, err := c.Client.Issues.ListByRepo(ctx, owner, repo, nil)
+	var platformIssues []*Issue
+	options := &github.IssueListByRepoOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		issues, response, err := c.Client.Issues.ListByRepo(ctx, owner, repo, options)
+		if err != nil {
+			if terminal := terminalError(response, err); terminal != nil {
+				return nil, fmt.Errorf("failed to list issues: %w: %v", terminal, err)
+			}
+			if response != nil {
+				return nil, fmt.Errorf("failed to list issues: %s, %w", response.Status, err)
+			}
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to list issues: unexpected status code %d", response.StatusCode)
+		}
+
+		for _, ghIssue := range issues {
+			platformIssues = append(platformIssues, mapGitHubIssue(ghIssue))
+		}
+		if response.NextPage == 0 {
+			return platformIssues, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+// Get returns a single issue. An issue converted to a discussion fails with a ConvertedToDiscussionError.
+func (c *GitHubIssueClient) Get(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error) {
+	ghIssue, response, err := c.Client.Issues.Get(ctx, owner, repo, issueNumber)
 	if err != nil {
+		if response != nil && response.StatusCode == http.StatusGone {
+			return nil, convertedToDiscussion(response)
+		}
 		if response != nil {
-			return nil, fmt.Errorf("failed to list issues: %s, %v", response.Status, err)
+			return nil, fmt.Errorf("failed to get issue: %s, %v", response.Status, err)
 		}
-		return nil, fmt.Errorf("failed to list issues: %v", err)
+		return nil, fmt.Errorf("failed to get issue: %v", err)
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list issues: unexpected status code %d", response.StatusCode)
+		return nil, fmt.Errorf("failed to get issue: unexpected status code %d", response.StatusCode)
 	}
-
-	var platformIssues []*Issue
-	for _, ghIssue := range issues {
-		platformIssues = append(platformIssues, mapGitHubIssue(ghIssue))
+	// The issues API redirects a converted issue to its discussion, which decodes with the discussion URL.
+	if strings.Contains(ghIssue.GetHTMLURL(), "/discussions/") {
+		return nil, &ConvertedToDiscussionError{URL: ghIssue.GetHTMLURL()}
 	}
 
-	return platformIssues, nil
+	return mapGitHubIssue(ghIssue), nil
 }
 
 // Create creates a new issue in a GitHub repository
-func (c *GitHubIssueClient) Create(ctx context.Context, owner, repo, title, body string) (*Issue, error) {
-	issueRequest := &github.IssueRequest{Title: &title, Body: &body}
-	ghIssue, response, err := c.Client.Issues.Create(ctx, owner, repo, issueRequest)
+func (c *GitHubIssueClient) Create(ctx context.Context, owner, repo string, desired *DesiredIssue) (*Issue, error) {
+	issueRequest := &github.IssueRequest{Title: &desired.Title, Body: &desired.Body}
+	if len(desired.Labels) > 0 {
+		issueRequest.Labels = &desired.Labels
+	}
+	if len(desired.Assignees) > 0 {
+		issueRequest.Assignees = &desired.Assignees
+	}
+	if desired.Milestone != 0 {
+		issueRequest.Milestone = &desired.Milestone
+	}
+	var ghIssue *github.Issue
+	var response *github.Response
+	var err error
+	if desired.Type != "" {
+		ghIssue, response, err = c.sendIssueRequest(ctx, http.MethodPost, owner, repo, 0, issueRequest, desired.Type)
+	} else {
+		ghIssue, response, err = c.Client.Issues.Create(ctx, owner, repo, issueRequest)
+	}
 	if err != nil {
+		if terminal := terminalError(response, err); terminal != nil {
+			return nil, fmt.Errorf("failed to create issue: %w: %v", terminal, err)
+		}
 		if response != nil {
 			return nil, fmt.Errorf("failed to create issue: %s, %v", response.Status, err)
 		}
@@ -90,11 +277,30 @@ func (c *GitHubIssueClient) Create(ctx context.Context, owner, repo, title, body
 	return mapGitHubIssue(ghIssue), nil
 }
 
-func (c *GitHubIssueClient) Edit(ctx context.Context, owner, repo string, issueNumber int, body string) (*Issue, error) {
-	editRequest := &github.IssueRequest{Body: &body}
+func (c *GitHubIssueClient) Edit(ctx context.Context, owner, repo string, issueNumber int, desired *DesiredIssue) (*Issue, error) {
+	editRequest := &github.IssueRequest{Body: &desired.Body}
+	if desired.Title != "" {
+		editRequest.Title = &desired.Title
+	}
+	if desired.Assignees != nil {
+		editRequest.Assignees = &desired.Assignees
+	}
+	if desired.Milestone != 0 {
+		editRequest.Milestone = &desired.Milestone
+	}
 
-	ghIssue, response, err := c.Client.Issues.Edit(ctx, owner, repo, issueNumber, editRequest)
+	var ghIssue *github.Issue
+	var response *github.Response
+	var err error
+	if desired.Type != "" {
+		ghIssue, response, err = c.sendIssueRequest(ctx, http.MethodPatch, owner, repo, issueNumber, editRequest, desired.Type)
+	} else {
+		ghIssue, response, err = c.Client.Issues.Edit(ctx, owner, repo, issueNumber, editRequest)
+	}
 	if err != nil {
+		if response != nil && response.StatusCode == http.StatusGone {
+			return nil, convertedToDiscussion(response)
+		}
 		if response != nil {
 			return nil, fmt.Errorf("failed to edit issue: %s, %v", response.Status, err)
 		}
@@ -126,3 +332,252 @@ func (c *GitHubIssueClient) Close(ctx context.Context, owner, repo string, issue
 
 	return mapGitHubIssue(ghIssue), nil
 }
+
+func (c *GitHubIssueClient) Reopen(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error) {
+	state := "open"
+	reopenRequest := &github.IssueRequest{State: &state}
+
+	ghIssue, response, err := c.Client.Issues.Edit(ctx, owner, repo, issueNumber, reopenRequest)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to reopen issue: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to reopen issue: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to reopen issue: unexpected status code %d", response.StatusCode)
+	}
+
+	return mapGitHubIssue(ghIssue), nil
+}
+
+func (c *GitHubIssueClient) AddLabels(ctx context.Context, owner, repo string, issueNumber int, labels []string) error {
+	_, response, err := c.Client.Issues.AddLabelsToIssue(ctx, owner, repo, issueNumber, labels)
+	if err != nil {
+		if response != nil {
+			return fmt.Errorf("failed to add labels: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to add labels: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to add labels: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubIssueClient) RemoveLabel(ctx context.Context, owner, repo string, issueNumber int, label string) error {
+	response, err := c.Client.Issues.RemoveLabelForIssue(ctx, owner, repo, issueNumber, label)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		if response != nil {
+			return fmt.Errorf("failed to remove label: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to remove label: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to remove label: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubIssueClient) Lock(ctx context.Context, owner, repo string, issueNumber int, reason string) error {
+	var opts *github.LockIssueOptions
+	if reason != "" {
+		opts = &github.LockIssueOptions{LockReason: reason}
+	}
+	response, err := c.Client.Issues.Lock(ctx, owner, repo, issueNumber, opts)
+	if err != nil {
+		if response != nil {
+			return fmt.Errorf("failed to lock issue: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to lock issue: %v", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to lock issue: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubIssueClient) Unlock(ctx context.Context, owner, repo string, issueNumber int) error {
+	response, err := c.Client.Issues.Unlock(ctx, owner, repo, issueNumber)
+	if err != nil {
+		if response != nil {
+			return fmt.Errorf("failed to unlock issue: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to unlock issue: %v", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to unlock issue: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubIssueClient) SetPinned(ctx context.Context, owner, repo string, issueNumber int, pinned bool) error {
+	graphQL := c.GraphQL
+	if graphQL == nil {
+		graphQL = NewGraphQLClient(c.Client)
+	}
+	return graphQL.SetPinned(ctx, owner, repo, issueNumber, pinned)
+}
+
+func (c *GitHubIssueClient) DuplicateOf(ctx context.Context, owner, repo string, issueNumber int) (*CanonicalIssue, error) {
+	graphQL := c.GraphQL
+	if graphQL == nil {
+		graphQL = NewGraphQLClient(c.Client)
+	}
+	return graphQL.DuplicateOf(ctx, owner, repo, issueNumber)
+}
+
+func (c *GitHubIssueClient) LinkedPullRequests(ctx context.Context, owner, repo string, issueNumber int) ([]LinkedPullRequest, error) {
+	graphQL := c.GraphQL
+	if graphQL == nil {
+		graphQL = NewGraphQLClient(c.Client)
+	}
+	return graphQL.LinkedPullRequests(ctx, owner, repo, issueNumber)
+}
+
+func (c *GitHubIssueClient) AddToProject(ctx context.Context, owner, repo string, issueNumber int, project string) error {
+	graphQL := c.GraphQL
+	if graphQL == nil {
+		graphQL = NewGraphQLClient(c.Client)
+	}
+	return graphQL.AddToProject(ctx, owner, repo, issueNumber, project)
+}
+
+func (c *GitHubIssueClient) Comment(ctx context.Context, owner, repo string, issueNumber int, body string) (int64, error) {
+	comment, response, err := c.Client.Issues.CreateComment(ctx, owner, repo, issueNumber, &github.IssueComment{Body: &body})
+	if err != nil {
+		if response != nil {
+			return 0, fmt.Errorf("failed to comment on issue: %s, %v", response.Status, err)
+		}
+		return 0, fmt.Errorf("failed to comment on issue: %v", err)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return 0, fmt.Errorf("failed to comment on issue: unexpected status code %d", response.StatusCode)
+	}
+
+	return comment.GetID(), nil
+}
+
+func (c *GitHubIssueClient) GetComment(ctx context.Context, owner, repo string, commentID int64) (string, error) {
+	comment, response, err := c.Client.Issues.GetComment(ctx, owner, repo, commentID)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return "", ErrCommentNotFound
+		}
+		if response != nil {
+			return "", fmt.Errorf("failed to get comment: %s, %v", response.Status, err)
+		}
+		return "", fmt.Errorf("failed to get comment: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get comment: unexpected status code %d", response.StatusCode)
+	}
+
+	return comment.GetBody(), nil
+}
+
+func (c *GitHubIssueClient) EditComment(ctx context.Context, owner, repo string, commentID int64, body string) error {
+	_, response, err := c.Client.Issues.EditComment(ctx, owner, repo, commentID, &github.IssueComment{Body: &body})
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return ErrCommentNotFound
+		}
+		if response != nil {
+			return fmt.Errorf("failed to edit comment: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to edit comment: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to edit comment: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubIssueClient) DeleteComment(ctx context.Context, owner, repo string, commentID int64) error {
+	response, err := c.Client.Issues.DeleteComment(ctx, owner, repo, commentID)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			// Already deleted upstream.
+			return nil
+		}
+		if response != nil {
+			return fmt.Errorf("failed to delete comment: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete comment: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubIssueClient) ListLabels(ctx context.Context, owner, repo string) ([]string, error) {
+	var names []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		labels, response, err := c.Client.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to list labels: %s, %v", response.Status, err)
+			}
+			return nil, fmt.Errorf("failed to list labels: %v", err)
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to list labels: unexpected status code %d", response.StatusCode)
+		}
+
+		for _, label := range labels {
+			names = append(names, label.GetName())
+		}
+		if response.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = response.NextPage
+	}
+}
+
+func (c *GitHubIssueClient) FindMilestone(ctx context.Context, owner, repo, title string) (int, error) {
+	opts := &github.MilestoneListOptions{State: "all", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		milestones, response, err := c.Client.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			if response != nil {
+				return 0, fmt.Errorf("failed to list milestones: %s, %v", response.Status, err)
+			}
+			return 0, fmt.Errorf("failed to list milestones: %v", err)
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("failed to list milestones: unexpected status code %d", response.StatusCode)
+		}
+
+		for _, milestone := range milestones {
+			if milestone.GetTitle() == title {
+				return milestone.GetNumber(), nil
+			}
+		}
+		if response.NextPage == 0 {
+			return 0, fmt.Errorf("milestone %q not found in %s/%s", title, owner, repo)
+		}
+		opts.Page = response.NextPage
+	}
+}
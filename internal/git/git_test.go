@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// newRESTClient returns a GitHubIssueClient sending its requests to handler.
+func newRESTClient(handler http.HandlerFunc) *GitHubIssueClient {
+	server := httptest.NewServer(handler)
+	ginkgo.DeferCleanup(server.Close)
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	Expect(err).NotTo(HaveOccurred())
+	client.BaseURL = baseURL
+	return &GitHubIssueClient{Client: client}
+}
+
+var _ = ginkgo.Describe("GitHubIssueClient", func() {
+	ctx := context.Background()
+
+	ginkgo.It("lists the open issues of every page", func() {
+		var queries []url.Values
+		client := newRESTClient(func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.Query())
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=2>; rel="next"`, r.Host, r.URL.Path))
+				_, _ = fmt.Fprint(w, `[{"number": 2}, {"number": 1}]`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `[{"number": 3}]`)
+		})
+
+		issues, err := client.List(ctx, "org", "repo")
+		Expect(err).NotTo(HaveOccurred())
+		var numbers []int
+		for _, issue := range issues {
+			numbers = append(numbers, issue.Number)
+		}
+		Expect(numbers).To(Equal([]int{2, 1, 3}))
+		Expect(queries).To(HaveLen(2))
+		Expect(queries[0].Get("state")).To(Equal("open"))
+		Expect(queries[0].Get("per_page")).To(Equal("100"))
+		Expect(queries[1].Get("page")).To(Equal("2"))
+	})
+
+	ginkgo.It("reports an issue converted to a discussion", func() {
+		client := newRESTClient(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/repos/org/repo/issues/1":
+				w.Header().Set("Location", "https://github.com/org/repo/discussions/7")
+				w.WriteHeader(http.StatusGone)
+			case "/repos/org/repo/issues/2":
+				// The issues API redirects to the discussion, which the client follows.
+				_, _ = fmt.Fprint(w, `{"number": 8, "html_url": "https://github.com/org/repo/discussions/8"}`)
+			default:
+				_, _ = fmt.Fprint(w, `{"number": 3, "html_url": "https://github.com/org/repo/issues/3"}`)
+			}
+		})
+
+		_, err := client.Get(ctx, "org", "repo", 1)
+		converted, ok := AsConvertedToDiscussion(err)
+		Expect(ok).To(BeTrue())
+		Expect(converted.URL).To(Equal("https://github.com/org/repo/discussions/7"))
+
+		_, err = client.Get(ctx, "org", "repo", 2)
+		converted, ok = AsConvertedToDiscussion(err)
+		Expect(ok).To(BeTrue())
+		Expect(converted.URL).To(Equal("https://github.com/org/repo/discussions/8"))
+
+		issue, err := client.Get(ctx, "org", "repo", 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issue.Number).To(Equal(3))
+	})
+})
@@ -0,0 +1,201 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// GraphQLClient sends GitHub GraphQL requests, used for operations the REST API doesn't offer.
+type GraphQLClient struct {
+	HTTPClient *http.Client
+	Endpoint   string
+}
+
+// NewGraphQLClient returns a GraphQL client sharing the HTTP client and host of a REST client.
+func NewGraphQLClient(client *github.Client) *GraphQLClient {
+	return &GraphQLClient{
+		HTTPClient: client.Client(),
+		Endpoint:   graphQLEndpoint(client.BaseURL.String()),
+	}
+}
+
+// graphQLEndpoint derives the GraphQL endpoint from a REST base URL, including GitHub Enterprise ones.
+func graphQLEndpoint(baseURL string) string {
+	if strings.HasSuffix(baseURL, "/api/v3/") {
+		return strings.TrimSuffix(baseURL, "v3/") + "graphql"
+	}
+	return baseURL + "graphql"
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Do runs query with variables and decodes the data of the response into out.
+func (c *GraphQLClient) Do(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to encode graphql request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build graphql request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send graphql request: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("graphql request failed: unexpected status code %d", response.StatusCode)
+	}
+
+	var decoded graphQLResponse
+	if err := json.NewDecoder(response.Body).Decode(&decoded); err != nil {
+		return fmt.Errorf("failed to decode graphql response: %v", err)
+	}
+	if len(decoded.Errors) > 0 {
+		return fmt.Errorf("graphql request failed: %s", decoded.Errors[0].Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(decoded.Data, out)
+}
+
+const issuePinStateQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) { id isPinned }
+  }
+}`
+
+const pinIssueMutation = `mutation($id: ID!) { pinIssue(input: {issueId: $id}) { clientMutationId } }`
+
+const unpinIssueMutation = `mutation($id: ID!) { unpinIssue(input: {issueId: $id}) { clientMutationId } }`
+
+// SetPinned pins or unpins an issue, doing nothing when it is already in the requested state.
+func (c *GraphQLClient) SetPinned(ctx context.Context, owner, repo string, issueNumber int, pinned bool) error {
+	var state struct {
+		Repository struct {
+			Issue *struct {
+				ID       string `json:"id"`
+				IsPinned bool   `json:"isPinned"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	err := c.Do(ctx, issuePinStateQuery, map[string]interface{}{"owner": owner, "repo": repo, "number": issueNumber}, &state)
+	if err != nil {
+		return fmt.Errorf("failed to get pin state: %v", err)
+	}
+	issue := state.Repository.Issue
+	if issue == nil {
+		return fmt.Errorf("failed to get pin state: issue %d not found", issueNumber)
+	}
+	if issue.IsPinned == pinned {
+		return nil
+	}
+
+	mutation := unpinIssueMutation
+	if pinned {
+		mutation = pinIssueMutation
+	}
+	if err := c.Do(ctx, mutation, map[string]interface{}{"id": issue.ID}, nil); err != nil {
+		return fmt.Errorf("failed to set pin state: %v", err)
+	}
+	return nil
+}
+
+const duplicateTimelineQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      timelineItems(last: 1, itemTypes: [MARKED_AS_DUPLICATE_EVENT, UNMARKED_AS_DUPLICATE_EVENT]) {
+        nodes {
+          __typename
+          ... on MarkedAsDuplicateEvent {
+            canonical {
+              ... on Issue { number url }
+              ... on PullRequest { number url }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// DuplicateOf reads the last duplicate event of the issue timeline. It returns the canonical issue of a
+// marked_as_duplicate event, and nil when the issue was never marked or the mark was removed since.
+func (c *GraphQLClient) DuplicateOf(ctx context.Context, owner, repo string, issueNumber int) (*CanonicalIssue, error) {
+	var timeline struct {
+		Repository struct {
+			Issue *struct {
+				TimelineItems struct {
+					Nodes []struct {
+						Typename  string          `json:"__typename"`
+						Canonical *CanonicalIssue `json:"canonical"`
+					} `json:"nodes"`
+				} `json:"timelineItems"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	err := c.Do(ctx, duplicateTimelineQuery, map[string]interface{}{"owner": owner, "repo": repo, "number": issueNumber}, &timeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read duplicate events: %v", err)
+	}
+	issue := timeline.Repository.Issue
+	if issue == nil {
+		return nil, fmt.Errorf("failed to read duplicate events: issue %d not found", issueNumber)
+	}
+	nodes := issue.TimelineItems.Nodes
+	if len(nodes) == 0 || nodes[0].Typename != "MarkedAsDuplicateEvent" || nodes[0].Canonical == nil || nodes[0].Canonical.Number == 0 {
+		return nil, nil
+	}
+	return nodes[0].Canonical, nil
+}
+
+const linkedPullRequestsQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      closedByPullRequestsReferences(first: 10, includeClosedPrs: true) { nodes { url state } }
+    }
+  }
+}`
+
+// LinkedPullRequests returns the pull requests closing the issue when merged, with their state.
+func (c *GraphQLClient) LinkedPullRequests(ctx context.Context, owner, repo string, issueNumber int) ([]LinkedPullRequest, error) {
+	var result struct {
+		Repository struct {
+			Issue *struct {
+				ClosedByPullRequestsReferences struct {
+					Nodes []LinkedPullRequest `json:"nodes"`
+				} `json:"closedByPullRequestsReferences"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	err := c.Do(ctx, linkedPullRequestsQuery, map[string]interface{}{"owner": owner, "repo": repo, "number": issueNumber}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list linked pull requests: %v", err)
+	}
+	if result.Repository.Issue == nil {
+		return nil, fmt.Errorf("failed to list linked pull requests: issue %d not found", issueNumber)
+	}
+	return result.Repository.Issue.ClosedByPullRequestsReferences.Nodes, nil
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// graphQLServer is a fake GraphQL endpoint answering each query with the data returned by handle. Requests
+// other than POSTs are served by rest, when set.
+type graphQLServer struct {
+	*httptest.Server
+	rest     http.Handler
+	mu       sync.Mutex
+	requests []graphQLRequest
+}
+
+func newGraphQLServer(handle func(query string, variables map[string]interface{}) interface{}) *graphQLServer {
+	server := &graphQLServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && server.rest != nil {
+			server.rest.ServeHTTP(w, r)
+			return
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		server.mu.Lock()
+		server.requests = append(server.requests, req)
+		server.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": handle(req.Query, req.Variables)})
+	}))
+	ginkgo.DeferCleanup(server.Close)
+	return server
+}
+
+// variables returns the variables of the requests sending query.
+func (s *graphQLServer) variables(query string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var variables []map[string]interface{}
+	for _, req := range s.requests {
+		if req.Query == query {
+			variables = append(variables, req.Variables)
+		}
+	}
+	return variables
+}
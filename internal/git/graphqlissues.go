@@ -0,0 +1,201 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GraphQLIssueClient reads issues through the GitHub GraphQL API and sends everything else to the embedded
+// client. A single query returns an issue with its labels, assignees, linked pull requests, project boards and
+// reactions, which the REST API spreads over several calls, and List pages through 100 issues per request.
+type GraphQLIssueClient struct {
+	IssueClient
+	GraphQL *GraphQLClient
+}
+
+// NewGraphQLIssueClient returns a GraphQLIssueClient reading through the GraphQL API of client's host.
+func NewGraphQLIssueClient(client *GitHubIssueClient) *GraphQLIssueClient {
+	graphQL := client.GraphQL
+	if graphQL == nil {
+		graphQL = NewGraphQLClient(client.Client)
+	}
+	return &GraphQLIssueClient{IssueClient: client, GraphQL: graphQL}
+}
+
+// issueFields selects everything mapGraphQLIssue reads. Connections are capped at the GitHub limits of an
+// issue, or at a size no issue the operator manages reaches.
+const issueFields = `
+  number id title body state stateReason url locked activeLockReason isPinned createdAt updatedAt
+  labels(first: 100) { nodes { name } }
+  assignees(first: 10) { nodes { login } }
+  milestone { number }
+  closedByPullRequestsReferences(first: 10, includeClosedPrs: true) { nodes { url } }
+  projectItems(first: 20) { nodes { project { id } } }
+  reactionGroups { content reactors { totalCount } }`
+
+const listIssuesQuery = `query($owner: String!, $repo: String!, $after: String) {
+  repository(owner: $owner, name: $repo) {
+    issues(first: 100, after: $after, states: [OPEN], orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes {` + issueFields + `
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+const getIssueQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {` + issueFields + `
+    }
+  }
+}`
+
+type graphQLIssue struct {
+	Number           int       `json:"number"`
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Body             string    `json:"body"`
+	State            string    `json:"state"`
+	StateReason      string    `json:"stateReason"`
+	URL              string    `json:"url"`
+	Locked           bool      `json:"locked"`
+	ActiveLockReason string    `json:"activeLockReason"`
+	IsPinned         bool      `json:"isPinned"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	Labels           struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []struct {
+			Login string `json:"login"`
+		} `json:"nodes"`
+	} `json:"assignees"`
+	Milestone *struct {
+		Number int `json:"number"`
+	} `json:"milestone"`
+	ClosedByPullRequestsReferences struct {
+		Nodes []struct {
+			URL string `json:"url"`
+		} `json:"nodes"`
+	} `json:"closedByPullRequestsReferences"`
+	ProjectItems struct {
+		Nodes []struct {
+			Project struct {
+				ID string `json:"id"`
+			} `json:"project"`
+		} `json:"nodes"`
+	} `json:"projectItems"`
+	ReactionGroups []struct {
+		Content  string `json:"content"`
+		Reactors struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"reactors"`
+	} `json:"reactionGroups"`
+}
+
+// graphQLLockReasons maps the GraphQL lock reasons to the REST ones.
+var graphQLLockReasons = map[string]string{
+	"OFF_TOPIC":  "off-topic",
+	"TOO_HEATED": "too heated",
+	"RESOLVED":   "resolved",
+	"SPAM":       "spam",
+}
+
+// mapGraphQLIssue maps an issue to the values the REST API returns, e.g. lower-case states and reasons.
+func mapGraphQLIssue(node *graphQLIssue) *Issue {
+	issue := &Issue{
+		Number:      node.Number,
+		NodeID:      node.ID,
+		Title:       node.Title,
+		Description: node.Body,
+		State:       strings.ToLower(node.State),
+		StateReason: strings.ToLower(node.StateReason),
+		URL:         node.URL,
+		Locked:      node.Locked,
+		LockReason:  graphQLLockReasons[node.ActiveLockReason],
+		Pinned:      &node.IsPinned,
+		CreatedAt:   node.CreatedAt,
+		UpdatedAt:   node.UpdatedAt,
+	}
+	for _, label := range node.Labels.Nodes {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	for _, assignee := range node.Assignees.Nodes {
+		issue.Assignees = append(issue.Assignees, assignee.Login)
+	}
+	if node.Milestone != nil {
+		issue.Milestone = node.Milestone.Number
+	}
+	for _, pullRequest := range node.ClosedByPullRequestsReferences.Nodes {
+		issue.LinkedPRs = append(issue.LinkedPRs, pullRequest.URL)
+	}
+	issue.HasPR = len(issue.LinkedPRs) > 0
+	for _, item := range node.ProjectItems.Nodes {
+		issue.Projects = append(issue.Projects, item.Project.ID)
+	}
+	for _, group := range node.ReactionGroups {
+		if group.Reactors.TotalCount == 0 {
+			continue
+		}
+		if issue.Reactions == nil {
+			issue.Reactions = map[string]int{}
+		}
+		issue.Reactions[strings.ToLower(group.Content)] = group.Reactors.TotalCount
+	}
+	return issue
+}
+
+// List returns the open issues of the repository, newest first.
+func (c *GraphQLIssueClient) List(ctx context.Context, owner, repo string) ([]*Issue, error) {
+	var issues []*Issue
+	variables := map[string]interface{}{"owner": owner, "repo": repo}
+	for {
+		var page struct {
+			Repository *struct {
+				Issues struct {
+					Nodes    []graphQLIssue `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"issues"`
+			} `json:"repository"`
+		}
+		if err := c.GraphQL.Do(ctx, listIssuesQuery, variables, &page); err != nil {
+			return nil, fmt.Errorf("failed to list issues: %v", err)
+		}
+		if page.Repository == nil {
+			return nil, fmt.Errorf("failed to list issues: repository %s/%s not found", owner, repo)
+		}
+		for i := range page.Repository.Issues.Nodes {
+			issues = append(issues, mapGraphQLIssue(&page.Repository.Issues.Nodes[i]))
+		}
+		if !page.Repository.Issues.PageInfo.HasNextPage {
+			return issues, nil
+		}
+		variables["after"] = page.Repository.Issues.PageInfo.EndCursor
+	}
+}
+
+// Get returns a single issue. GraphQL does not tell a converted issue from a missing one, so when the issue
+// is not found the REST API is asked, which reports the conversion.
+func (c *GraphQLIssueClient) Get(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error) {
+	var result struct {
+		Repository *struct {
+			Issue *graphQLIssue `json:"issue"`
+		} `json:"repository"`
+	}
+	err := c.GraphQL.Do(ctx, getIssueQuery, map[string]interface{}{"owner": owner, "repo": repo, "number": issueNumber}, &result)
+	if err != nil && !strings.Contains(err.Error(), "Could not resolve to an Issue") {
+		return nil, fmt.Errorf("failed to get issue: %v", err)
+	}
+	if err != nil || result.Repository == nil || result.Repository.Issue == nil {
+		return c.IssueClient.Get(ctx, owner, repo, issueNumber)
+	}
+	return mapGraphQLIssue(result.Repository.Issue), nil
+}
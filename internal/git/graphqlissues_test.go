@@ -0,0 +1,120 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+// restIssueJSON is issue 1 of org/repo as the REST API returns it.
+const restIssueJSON = `{
+  "number": 1, "node_id": "I_1", "title": "title", "body": "body",
+  "state": "closed", "state_reason": "not_planned", "html_url": "https://github.com/org/repo/issues/1",
+  "labels": [{"name": "bug"}, {"name": "triage"}], "assignees": [{"login": "alice"}],
+  "milestone": {"number": 3}, "locked": true, "active_lock_reason": "too heated",
+  "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-02T00:00:00Z"
+}`
+
+// graphQLIssueJSON is the same issue as the GraphQL API returns it.
+func graphQLIssueJSON(number int) map[string]interface{} {
+	return map[string]interface{}{
+		"number": number, "id": fmt.Sprintf("I_%d", number), "title": "title", "body": "body",
+		"state": "CLOSED", "stateReason": "NOT_PLANNED", "url": fmt.Sprintf("https://github.com/org/repo/issues/%d", number),
+		"locked": true, "activeLockReason": "TOO_HEATED", "isPinned": true,
+		"createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-02T00:00:00Z",
+		"labels":    map[string]interface{}{"nodes": []map[string]interface{}{{"name": "bug"}, {"name": "triage"}}},
+		"assignees": map[string]interface{}{"nodes": []map[string]interface{}{{"login": "alice"}}},
+		"milestone": map[string]interface{}{"number": 3},
+		"closedByPullRequestsReferences": map[string]interface{}{
+			"nodes": []map[string]interface{}{{"url": "https://github.com/org/repo/pull/2"}},
+		},
+		"projectItems": map[string]interface{}{"nodes": []map[string]interface{}{{"project": map[string]interface{}{"id": "PVT_1"}}}},
+		"reactionGroups": []map[string]interface{}{
+			{"content": "THUMBS_UP", "reactors": map[string]interface{}{"totalCount": 2}},
+			{"content": "HEART", "reactors": map[string]interface{}{"totalCount": 0}},
+		},
+	}
+}
+
+var _ = ginkgo.Describe("GraphQLIssueClient", func() {
+	var (
+		server *graphQLServer
+		rest   *GitHubIssueClient
+		client *GraphQLIssueClient
+		ctx    = context.Background()
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = newGraphQLServer(func(query string, variables map[string]interface{}) interface{} {
+			switch query {
+			case getIssueQuery:
+				return map[string]interface{}{"repository": map[string]interface{}{"issue": graphQLIssueJSON(1)}}
+			case listIssuesQuery:
+				page := map[string]interface{}{
+					"nodes":    []interface{}{graphQLIssueJSON(1)},
+					"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "cursor-1"},
+				}
+				if variables["after"] == "cursor-1" {
+					page = map[string]interface{}{
+						"nodes":    []interface{}{graphQLIssueJSON(2)},
+						"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": "cursor-2"},
+					}
+				}
+				return map[string]interface{}{"repository": map[string]interface{}{"issues": page}}
+			}
+			return map[string]interface{}{}
+		})
+		server.rest = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/repos/org/repo/issues/1" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = fmt.Fprint(w, restIssueJSON)
+		})
+
+		githubClient := github.NewClient(nil)
+		baseURL, err := url.Parse(server.URL + "/")
+		Expect(err).NotTo(HaveOccurred())
+		githubClient.BaseURL = baseURL
+		rest = &GitHubIssueClient{Client: githubClient}
+		client = NewGraphQLIssueClient(rest)
+	})
+
+	ginkgo.It("maps an issue like the REST client, with its linked pull requests, projects and reactions", func() {
+		expected, err := rest.Get(ctx, "org", "repo", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expected.State).To(Equal("closed"))
+		Expect(expected.StateReason).To(Equal("not_planned"))
+		Expect(expected.LockReason).To(Equal("too heated"))
+		Expect(expected.NodeID).To(Equal("I_1"))
+		expected.HasPR = true
+		expected.LinkedPRs = []string{"https://github.com/org/repo/pull/2"}
+		expected.Projects = []string{"PVT_1"}
+		expected.Reactions = map[string]int{"thumbs_up": 2}
+		expected.Pinned = ptr.To(true)
+
+		issue, err := client.Get(ctx, "org", "repo", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issue).To(Equal(expected))
+	})
+
+	ginkgo.It("lists the open issues of every page", func() {
+		issues, err := client.List(ctx, "org", "repo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(2))
+		Expect(issues[0].Number).To(Equal(1))
+		Expect(issues[1].Number).To(Equal(2))
+		Expect(issues[1].Labels).To(Equal([]string{"bug", "triage"}))
+
+		pages := server.variables(listIssuesQuery)
+		Expect(pages).To(HaveLen(2))
+		Expect(pages[0]).NotTo(HaveKey("after"))
+		Expect(pages[1]).To(HaveKeyWithValue("after", "cursor-1"))
+	})
+})
@@ -0,0 +1,179 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// ErrHookNotFound is returned when reading, editing or listing the deliveries of a webhook the repository does not have.
+var ErrHookNotFound = errors.New("webhook not found")
+
+// Hook is a webhook of a repository. GitHub never returns the secret of a webhook, so it is not read back.
+type Hook struct {
+	ID     int64
+	URL    string
+	Events []string
+	Active bool
+}
+
+// HookDelivery is a delivery attempt of a webhook.
+type HookDelivery struct {
+	ID          int64
+	Event       string
+	StatusCode  int    // Zero when GitHub could not connect
+	Status      string // Description of the delivery result, such as "OK" or "Invalid HTTP Response: 503"
+	DeliveredAt time.Time
+}
+
+// HookClient manages the webhooks of Git repositories.
+type HookClient interface {
+	// GetHook returns the webhook with the given ID. It returns ErrHookNotFound for a missing webhook.
+	GetHook(ctx context.Context, owner, repo string, id int64) (*Hook, error)
+
+	// CreateHook creates a webhook signing its deliveries with secret.
+	CreateHook(ctx context.Context, owner, repo string, hook *Hook, secret string) (*Hook, error)
+
+	// EditHook replaces the URL, events and secret of the webhook with the given ID.
+	EditHook(ctx context.Context, owner, repo string, id int64, hook *Hook, secret string) error
+
+	// DeleteHook deletes a webhook. Deleting a missing webhook succeeds.
+	DeleteHook(ctx context.Context, owner, repo string, id int64) error
+
+	// ListHookDeliveries returns the most recent deliveries of the webhook, newest first.
+	ListHookDeliveries(ctx context.Context, owner, repo string, id int64) ([]*HookDelivery, error)
+}
+
+// GitHubHookClient manages repository webhooks through the GitHub webhooks API.
+type GitHubHookClient struct {
+	Client *github.Client
+}
+
+// hookRequest builds the webhook GitHub expects: JSON deliveries signed with secret.
+func hookRequest(hook *Hook, secret string) *github.Hook {
+	return &github.Hook{
+		Config: map[string]interface{}{
+			"url":          hook.URL,
+			"content_type": "json",
+			"secret":       secret,
+			"insecure_ssl": "0",
+		},
+		Events: hook.Events,
+		Active: github.Bool(hook.Active),
+	}
+}
+
+func mapGitHubHook(ghHook *github.Hook) *Hook {
+	hook := &Hook{ID: ghHook.GetID(), Events: ghHook.Events, Active: ghHook.GetActive()}
+	if url, ok := ghHook.Config["url"].(string); ok {
+		hook.URL = url
+	}
+	return hook
+}
+
+func (c *GitHubHookClient) GetHook(ctx context.Context, owner, repo string, id int64) (*Hook, error) {
+	hook, response, err := c.Client.Repositories.GetHook(ctx, owner, repo, id)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, ErrHookNotFound
+		}
+		if response != nil {
+			return nil, fmt.Errorf("failed to get webhook: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to get webhook: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get webhook: unexpected status code %d", response.StatusCode)
+	}
+
+	return mapGitHubHook(hook), nil
+}
+
+func (c *GitHubHookClient) CreateHook(ctx context.Context, owner, repo string, hook *Hook, secret string) (*Hook, error) {
+	created, response, err := c.Client.Repositories.CreateHook(ctx, owner, repo, hookRequest(hook, secret))
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to create webhook: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to create webhook: %v", err)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create webhook: unexpected status code %d", response.StatusCode)
+	}
+
+	return mapGitHubHook(created), nil
+}
+
+func (c *GitHubHookClient) EditHook(ctx context.Context, owner, repo string, id int64, hook *Hook, secret string) error {
+	_, response, err := c.Client.Repositories.EditHook(ctx, owner, repo, id, hookRequest(hook, secret))
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return ErrHookNotFound
+		}
+		if response != nil {
+			return fmt.Errorf("failed to edit webhook: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to edit webhook: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to edit webhook: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubHookClient) DeleteHook(ctx context.Context, owner, repo string, id int64) error {
+	response, err := c.Client.Repositories.DeleteHook(ctx, owner, repo, id)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			// Already deleted upstream.
+			return nil
+		}
+		if response != nil {
+			return fmt.Errorf("failed to delete webhook: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to delete webhook: %v", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete webhook: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubHookClient) ListHookDeliveries(ctx context.Context, owner, repo string, id int64) ([]*HookDelivery, error) {
+	deliveries, response, err := c.Client.Repositories.ListHookDeliveries(ctx, owner, repo, id, &github.ListCursorOptions{PerPage: 30})
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, ErrHookNotFound
+		}
+		if response != nil {
+			return nil, fmt.Errorf("failed to list webhook deliveries: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to list webhook deliveries: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list webhook deliveries: unexpected status code %d", response.StatusCode)
+	}
+
+	var result []*HookDelivery
+	for _, delivery := range deliveries {
+		result = append(result, &HookDelivery{
+			ID:          delivery.GetID(),
+			Event:       delivery.GetEvent(),
+			StatusCode:  delivery.GetStatusCode(),
+			Status:      delivery.GetStatus(),
+			DeliveredAt: delivery.GetDeliveredAt().Time,
+		})
+	}
+	return result, nil
+}
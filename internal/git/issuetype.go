@@ -0,0 +1,33 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// typedIssueRequest adds the issue type, which the go-github request type doesn't know yet, to an issue request.
+type typedIssueRequest struct {
+	*github.IssueRequest
+	Type *string `json:"type,omitempty"`
+}
+
+// sendIssueRequest creates (POST) or edits (PATCH) an issue with an issue request carrying the issue type.
+func (c *GitHubIssueClient) sendIssueRequest(ctx context.Context, method, owner, repo string, issueNumber int, issueRequest *github.IssueRequest, issueType string) (*github.Issue, *github.Response, error) {
+	url := fmt.Sprintf("repos/%v/%v/issues", owner, repo)
+	if method != http.MethodPost {
+		url = fmt.Sprintf("%v/%d", url, issueNumber)
+	}
+	req, err := c.Client.NewRequest(method, url, &typedIssueRequest{IssueRequest: issueRequest, Type: &issueType})
+	if err != nil {
+		return nil, nil, err
+	}
+	ghIssue := new(github.Issue)
+	response, err := c.Client.Do(ctx, req, ghIssue)
+	if err != nil {
+		return nil, response, err
+	}
+	return ghIssue, response, nil
+}
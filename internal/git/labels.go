@@ -0,0 +1,122 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// ErrLabelNotFound is returned when reading, editing or deleting a label the repository does not define.
+var ErrLabelNotFound = errors.New("label not found")
+
+// Label is a label defined in a repository.
+type Label struct {
+	Name        string
+	Color       string // Hexadecimal RGB value without the leading #
+	Description string
+}
+
+// LabelClient manages the labels defined in Git repositories.
+type LabelClient interface {
+	// GetLabel returns the label with the given name. It returns ErrLabelNotFound for a missing label.
+	GetLabel(ctx context.Context, owner, repo, name string) (*Label, error)
+
+	// CreateLabel defines a new label in the repository.
+	CreateLabel(ctx context.Context, owner, repo string, label *Label) error
+
+	// EditLabel replaces the label with the given name, renaming it when label.Name differs.
+	EditLabel(ctx context.Context, owner, repo, name string, label *Label) error
+
+	// DeleteLabel deletes a label, removing it from every issue. Deleting a missing label succeeds.
+	DeleteLabel(ctx context.Context, owner, repo, name string) error
+}
+
+// GitHubLabelClient manages repository labels through the GitHub labels API.
+type GitHubLabelClient struct {
+	Client *github.Client
+}
+
+func (c *GitHubLabelClient) GetLabel(ctx context.Context, owner, repo, name string) (*Label, error) {
+	label, response, err := c.Client.Issues.GetLabel(ctx, owner, repo, name)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, ErrLabelNotFound
+		}
+		if response != nil {
+			return nil, fmt.Errorf("failed to get label: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to get label: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get label: unexpected status code %d", response.StatusCode)
+	}
+
+	return &Label{Name: label.GetName(), Color: label.GetColor(), Description: label.GetDescription()}, nil
+}
+
+func (c *GitHubLabelClient) CreateLabel(ctx context.Context, owner, repo string, label *Label) error {
+	_, response, err := c.Client.Issues.CreateLabel(ctx, owner, repo, &github.Label{
+		Name:        &label.Name,
+		Color:       &label.Color,
+		Description: &label.Description,
+	})
+	if err != nil {
+		if response != nil {
+			return fmt.Errorf("failed to create label: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to create label: %v", err)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to create label: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubLabelClient) EditLabel(ctx context.Context, owner, repo, name string, label *Label) error {
+	_, response, err := c.Client.Issues.EditLabel(ctx, owner, repo, name, &github.Label{
+		Name:        &label.Name,
+		Color:       &label.Color,
+		Description: &label.Description,
+	})
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return ErrLabelNotFound
+		}
+		if response != nil {
+			return fmt.Errorf("failed to edit label: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to edit label: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to edit label: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *GitHubLabelClient) DeleteLabel(ctx context.Context, owner, repo, name string) error {
+	response, err := c.Client.Issues.DeleteLabel(ctx, owner, repo, name)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			// Already deleted upstream.
+			return nil
+		}
+		if response != nil {
+			return fmt.Errorf("failed to delete label: %s, %v", response.Status, err)
+		}
+		return fmt.Errorf("failed to delete label: %v", err)
+	}
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete label: unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
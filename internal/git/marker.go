@@ -0,0 +1,36 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var markerPattern = regexp.MustCompile(`<!-- issues\.dana\.io/githubissue: (\S+) -->`)
+
+// Marker returns the hidden marker embedded in the body of issues created for the GithubIssue with the given key.
+func Marker(key string) string {
+	return fmt.Sprintf("<!-- issues.dana.io/githubissue: %s -->", key)
+}
+
+// WithMarker appends the marker for key to body.
+func WithMarker(body, key string) string {
+	if body == "" {
+		return Marker(key)
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + Marker(key)
+}
+
+// ParseMarker returns the GithubIssue key embedded in an issue body.
+func ParseMarker(body string) (string, bool) {
+	match := markerPattern.FindStringSubmatch(body)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// StripMarker removes the marker from an issue body, undoing WithMarker.
+func StripMarker(body string) string {
+	return strings.TrimRight(markerPattern.ReplaceAllString(body, ""), "\n")
+}
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// ErrMilestoneNotFound is returned when reading or editing a milestone the repository does not define.
+var ErrMilestoneNotFound = errors.New("milestone not found")
+
+// Milestone is a milestone defined in a repository.
+type Milestone struct {
+	Number      int
+	Title       string
+	Description string
+	DueOn       *time.Time // Nil when the milestone has no due date
+	State       string     // "open" or "closed"
+	URL         string
+}
+
+// MilestoneClient manages the milestones defined in Git repositories.
+type MilestoneClient interface {
+	// GetMilestone returns the milestone with the given number. It returns ErrMilestoneNotFound for a missing milestone.
+	GetMilestone(ctx context.Context, owner, repo string, number int) (*Milestone, error)
+
+	// GetMilestoneByTitle returns the open or closed milestone with the given title.
+	// It returns ErrMilestoneNotFound when no milestone has that title.
+	GetMilestoneByTitle(ctx context.Context, owner, repo, title string) (*Milestone, error)
+
+	// CreateMilestone defines a new milestone in the repository.
+	CreateMilestone(ctx context.Context, owner, repo string, milestone *Milestone) (*Milestone, error)
+
+	// EditMilestone replaces the title, description, due date and state of a milestone.
+	EditMilestone(ctx context.Context, owner, repo string, number int, milestone *Milestone) (*Milestone, error)
+}
+
+// GitHubMilestoneClient manages repository milestones through the GitHub milestones API.
+type GitHubMilestoneClient struct {
+	Client *github.Client
+}
+
+func mapGitHubMilestone(ghMilestone *github.Milestone) *Milestone {
+	milestone := &Milestone{
+		Number:      ghMilestone.GetNumber(),
+		Title:       ghMilestone.GetTitle(),
+		Description: ghMilestone.GetDescription(),
+		State:       ghMilestone.GetState(),
+		URL:         ghMilestone.GetHTMLURL(),
+	}
+	if ghMilestone.DueOn != nil {
+		dueOn := ghMilestone.DueOn.Time
+		milestone.DueOn = &dueOn
+	}
+	return milestone
+}
+
+func milestoneRequest(milestone *Milestone) *github.Milestone {
+	request := &github.Milestone{
+		Title:       &milestone.Title,
+		Description: &milestone.Description,
+		State:       &milestone.State,
+	}
+	if milestone.DueOn != nil {
+		request.DueOn = &github.Timestamp{Time: *milestone.DueOn}
+	}
+	return request
+}
+
+func (c *GitHubMilestoneClient) GetMilestone(ctx context.Context, owner, repo string, number int) (*Milestone, error) {
+	milestone, response, err := c.Client.Issues.GetMilestone(ctx, owner, repo, number)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, ErrMilestoneNotFound
+		}
+		if response != nil {
+			return nil, fmt.Errorf("failed to get milestone: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to get milestone: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get milestone: unexpected status code %d", response.StatusCode)
+	}
+
+	return mapGitHubMilestone(milestone), nil
+}
+
+func (c *GitHubMilestoneClient) GetMilestoneByTitle(ctx context.Context, owner, repo, title string) (*Milestone, error) {
+	opts := &github.MilestoneListOptions{State: "all", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		milestones, response, err := c.Client.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to list milestones: %s, %v", response.Status, err)
+			}
+			return nil, fmt.Errorf("failed to list milestones: %v", err)
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to list milestones: unexpected status code %d", response.StatusCode)
+		}
+
+		for _, milestone := range milestones {
+			if milestone.GetTitle() == title {
+				return mapGitHubMilestone(milestone), nil
+			}
+		}
+		if response.NextPage == 0 {
+			return nil, ErrMilestoneNotFound
+		}
+		opts.Page = response.NextPage
+	}
+}
+
+func (c *GitHubMilestoneClient) CreateMilestone(ctx context.Context, owner, repo string, milestone *Milestone) (*Milestone, error) {
+	created, response, err := c.Client.Issues.CreateMilestone(ctx, owner, repo, milestoneRequest(milestone))
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to create milestone: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to create milestone: %v", err)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create milestone: unexpected status code %d", response.StatusCode)
+	}
+
+	return mapGitHubMilestone(created), nil
+}
+
+func (c *GitHubMilestoneClient) EditMilestone(ctx context.Context, owner, repo string, number int, milestone *Milestone) (*Milestone, error) {
+	edited, response, err := c.Client.Issues.EditMilestone(ctx, owner, repo, number, milestoneRequest(milestone))
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, ErrMilestoneNotFound
+		}
+		if response != nil {
+			return nil, fmt.Errorf("failed to edit milestone: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to edit milestone: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to edit milestone: unexpected status code %d", response.StatusCode)
+	}
+
+	return mapGitHubMilestone(edited), nil
+}
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// OAuthScopesHeader lists the scopes of a classic personal access token. Fine-grained tokens and GitHub App
+// installation tokens don't send it.
+const OAuthScopesHeader = "X-OAuth-Scopes"
+
+// RepoAccess is what the token of a client may do in a repository.
+type RepoAccess struct {
+	Found bool // False when the repository does not exist or is not visible to the token
+	// Permissions are the roles of the token in the repository, e.g. pull, triage and push.
+	Permissions map[string]bool
+	HasIssues   bool
+	// Scopes of a classic token. Nil for tokens without scopes.
+	Scopes []string
+}
+
+// Missing explains why the token can't manage the issues of the repository, or returns an empty string when
+// it can. Labeling and assigning issues takes the triage role.
+func (a *RepoAccess) Missing() string {
+	switch {
+	case !a.Found:
+		return "the repository does not exist or is not visible to the token"
+	case !a.HasIssues:
+		return "issues are disabled in the repository"
+	case a.Scopes != nil && !slices.Contains(a.Scopes, "repo") && !slices.Contains(a.Scopes, "public_repo"):
+		return fmt.Sprintf("the token lacks the repo or public_repo scope, it has %v", a.Scopes)
+	case !a.Permissions["triage"] && !a.Permissions["push"] && !a.Permissions["maintain"] && !a.Permissions["admin"]:
+		return "the token lacks the triage role in the repository"
+	}
+	return ""
+}
+
+// PermissionClient reads the access of its token to repositories.
+type PermissionClient interface {
+	// RepoAccess returns the access of the token to a repository.
+	RepoAccess(ctx context.Context, owner, repo string) (*RepoAccess, error)
+}
+
+// GitHubPermissionClient reads the access of its token through the GitHub repositories API.
+type GitHubPermissionClient struct {
+	Client *github.Client
+}
+
+func (c *GitHubPermissionClient) RepoAccess(ctx context.Context, owner, repo string) (*RepoAccess, error) {
+	repository, response, err := c.Client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return &RepoAccess{}, nil
+		}
+		if response != nil {
+			return nil, fmt.Errorf("failed to get repository: %s, %v", response.Status, err)
+		}
+		return nil, fmt.Errorf("failed to get repository: %v", err)
+	}
+
+	access := &RepoAccess{Found: true, Permissions: repository.Permissions, HasIssues: repository.GetHasIssues()}
+	if header, ok := response.Header[http.CanonicalHeaderKey(OAuthScopesHeader)]; ok {
+		access.Scopes = []string{}
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				access.Scopes = append(access.Scopes, scope)
+			}
+		}
+	}
+	return access, nil
+}
@@ -0,0 +1,157 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// PublicHost is the host of github.com repositories. Repositories on other hosts are served by the
+// GitHub Enterprise Server API of their host.
+const PublicHost = "github.com"
+
+// defaultPoolIdleTimeout is how long a pooled client may go unused before it is dropped.
+const defaultPoolIdleTimeout = time.Hour
+
+// RepoHost returns the host of a repository URL, e.g. github.com for https://github.com/org/repo.
+func RepoHost(repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid repository URL: %s", repoURL)
+	}
+	return parsed.Host, nil
+}
+
+// NewPooledTransport returns the HTTP transport shared by the clients of a ClientPool:
+// keep-alive connections are reused across clients and HTTP/2 is negotiated when the server supports it.
+func NewPooledTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	transport.MaxIdleConnsPerHost = 20
+	return transport
+}
+
+// ClientPool hands out one issue client per host and credential. Every client shares a single transport,
+// so serving many credentials or GitHub Enterprise hosts does not leak connections.
+type ClientPool struct {
+	// Transport carries the requests of every client. Defaults to NewPooledTransport().
+	Transport http.RoundTripper
+	// Instrument wraps the transport of the clients of a credential, e.g. to log requests. Optional.
+	Instrument func(base http.RoundTripper, credential string) http.RoundTripper
+	// OnRequest is called for every request sent by a pooled client. Optional.
+	OnRequest func(host, credential string)
+	// OnResize is called with the number of pooled clients of a host whenever it changes. Optional.
+	OnResize func(host string, clients int)
+	// IdleTimeout drops clients unused for this long. Defaults to an hour.
+	IdleTimeout time.Duration
+
+	mu      sync.Mutex
+	once    sync.Once
+	clients map[poolKey]*pooledClient
+}
+
+type poolKey struct {
+	host       string
+	credential string
+}
+
+type pooledClient struct {
+	token    string
+	client   IssueClient
+	lastUsed time.Time
+}
+
+// IssueClient returns the client of credential for host, authenticated with token.
+// A new token for a pooled credential replaces its client, keeping the shared transport.
+func (p *ClientPool) IssueClient(host, credential, token string) (IssueClient, error) {
+	p.once.Do(func() {
+		if p.Transport == nil {
+			p.Transport = NewPooledTransport()
+		}
+		if p.IdleTimeout == 0 {
+			p.IdleTimeout = defaultPoolIdleTimeout
+		}
+		p.clients = map[poolKey]*pooledClient{}
+	})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	p.evictIdle(now)
+
+	key := poolKey{host: host, credential: credential}
+	if pooled, ok := p.clients[key]; ok && pooled.token == token {
+		pooled.lastUsed = now
+		return pooled.client, nil
+	}
+
+	client, err := p.newClient(host, credential, token)
+	if err != nil {
+		return nil, err
+	}
+	_, replaced := p.clients[key]
+	p.clients[key] = &pooledClient{token: token, client: client, lastUsed: now}
+	if !replaced {
+		p.resized(host)
+	}
+	return client, nil
+}
+
+func (p *ClientPool) newClient(host, credential, token string) (IssueClient, error) {
+	var transport http.RoundTripper = &countingTransport{base: p.Transport, pool: p, host: host, credential: credential}
+	if p.Instrument != nil {
+		transport = p.Instrument(transport, credential)
+	}
+	client := github.NewClient(&http.Client{Transport: transport}).WithAuthToken(token)
+	if host != PublicHost {
+		enterpriseURL := fmt.Sprintf("https://%s/", host)
+		var err error
+		if client, err = client.WithEnterpriseURLs(enterpriseURL, enterpriseURL); err != nil {
+			return nil, fmt.Errorf("failed to build client for %s: %v", host, err)
+		}
+	}
+	return &GitHubIssueClient{Client: client}, nil
+}
+
+// evictIdle drops the clients unused for IdleTimeout. Callers hold p.mu.
+func (p *ClientPool) evictIdle(now time.Time) {
+	for key, pooled := range p.clients {
+		if now.Sub(pooled.lastUsed) >= p.IdleTimeout {
+			delete(p.clients, key)
+			p.resized(key.host)
+		}
+	}
+}
+
+// resized reports the number of clients of host. Callers hold p.mu.
+func (p *ClientPool) resized(host string) {
+	if p.OnResize == nil {
+		return
+	}
+	clients := 0
+	for key := range p.clients {
+		if key.host == host {
+			clients++
+		}
+	}
+	p.OnResize(host, clients)
+}
+
+type countingTransport struct {
+	base       http.RoundTripper
+	pool       *ClientPool
+	host       string
+	credential string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.pool.OnRequest != nil {
+		t.pool.OnRequest(t.host, t.credential)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// projectURLPattern matches the URL of an organization or user Projects V2 board.
+var projectURLPattern = regexp.MustCompile(`^https://[^/]+/(orgs|users)/([^/]+)/projects/(\d+)/?$`)
+
+const organizationProjectQuery = `query($login: String!, $number: Int!) {
+  organization(login: $login) { projectV2(number: $number) { id } }
+}`
+
+const userProjectQuery = `query($login: String!, $number: Int!) {
+  user(login: $login) { projectV2(number: $number) { id } }
+}`
+
+const issueIDQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) { issue(number: $number) { id } }
+}`
+
+const addProjectItemMutation = `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+
+// AddToProject adds an issue to a Projects V2 board, given by URL or node ID. Adding an issue that
+// is already on the board is a no-op.
+func (c *GraphQLClient) AddToProject(ctx context.Context, owner, repo string, issueNumber int, project string) error {
+	projectID, err := c.projectID(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	var issue struct {
+		Repository struct {
+			Issue *struct {
+				ID string `json:"id"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	err = c.Do(ctx, issueIDQuery, map[string]interface{}{"owner": owner, "repo": repo, "number": issueNumber}, &issue)
+	if err != nil {
+		return fmt.Errorf("failed to get issue id: %v", err)
+	}
+	if issue.Repository.Issue == nil {
+		return fmt.Errorf("failed to get issue id: issue %d not found", issueNumber)
+	}
+
+	variables := map[string]interface{}{"project": projectID, "content": issue.Repository.Issue.ID}
+	if err := c.Do(ctx, addProjectItemMutation, variables, nil); err != nil {
+		return fmt.Errorf("failed to add issue to project %s: %v", project, err)
+	}
+	return nil
+}
+
+// projectID resolves a project URL to its node ID. Anything that isn't a project URL is taken as a node ID.
+func (c *GraphQLClient) projectID(ctx context.Context, project string) (string, error) {
+	match := projectURLPattern.FindStringSubmatch(project)
+	if match == nil {
+		return project, nil
+	}
+	number, err := strconv.Atoi(match[3])
+	if err != nil {
+		return "", fmt.Errorf("invalid project number in %s: %v", project, err)
+	}
+
+	query := organizationProjectQuery
+	if match[1] == "users" {
+		query = userProjectQuery
+	}
+	var owner struct {
+		Organization *struct {
+			ProjectV2 *struct {
+				ID string `json:"id"`
+			} `json:"projectV2"`
+		} `json:"organization"`
+		User *struct {
+			ProjectV2 *struct {
+				ID string `json:"id"`
+			} `json:"projectV2"`
+		} `json:"user"`
+	}
+	if err := c.Do(ctx, query, map[string]interface{}{"login": match[2], "number": number}, &owner); err != nil {
+		return "", fmt.Errorf("failed to resolve project %s: %v", project, err)
+	}
+	switch {
+	case owner.Organization != nil && owner.Organization.ProjectV2 != nil:
+		return owner.Organization.ProjectV2.ID, nil
+	case owner.User != nil && owner.User.ProjectV2 != nil:
+		return owner.User.ProjectV2.ID, nil
+	}
+	return "", fmt.Errorf("project %s not found", project)
+}
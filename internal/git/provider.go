@@ -0,0 +1,170 @@
+package git
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	"golang.org/x/time/rate"
+)
+
+// ErrUnsupportedProvider is returned for a provider type the operator has no client for.
+var ErrUnsupportedProvider = errors.New("unsupported provider type")
+
+// ErrUnsupportedOperation is returned by issue clients for operations their platform does not offer,
+// e.g. pinning an issue on SourceHut.
+var ErrUnsupportedOperation = errors.New("operation not supported by the provider")
+
+// ProviderGitHub is the provider type of github.com and GitHub Enterprise Server.
+const ProviderGitHub = "github"
+
+func init() {
+	Register(Provider{
+		Name: ProviderGitHub,
+		ServesHost: func(host string) bool {
+			return host == PublicHost || strings.HasPrefix(host, "github.")
+		},
+		NewIssueClient: newGitHubIssueClient,
+	})
+}
+
+// ProviderConfig describes the endpoint, credentials, TLS, proxy and rate-limit budget of a provider.
+type ProviderConfig struct {
+	Type               string
+	Host               string
+	APIURL             string // Defaults to the API of Host for the provider type
+	Token              string
+	CABundle           string // PEM encoded certificates trusted in addition to the system ones
+	InsecureSkipVerify bool
+	RequestsPerHour    int32  // Zero is unlimited
+	ProxyURL           string // Proxy every request goes through. Empty uses the proxy of the pool
+}
+
+// ProviderPool hands out one issue client per provider, rebuilt whenever the provider configuration changes.
+type ProviderPool struct {
+	// Instrument wraps the transport of the client of a provider, e.g. to log requests. Optional.
+	Instrument func(base http.RoundTripper, credential string) http.RoundTripper
+	// Proxy selects the proxy of the providers without a proxy URL. Defaults to the environment variables.
+	Proxy ProxyFunc
+
+	mu      sync.Mutex
+	clients map[string]*providerClient
+}
+
+type providerClient struct {
+	config ProviderConfig
+	client IssueClient
+}
+
+// IssueClient returns the client of the provider name for config.
+func (p *ProviderPool) IssueClient(name string, config ProviderConfig) (IssueClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pooled, ok := p.clients[name]; ok && pooled.config == config {
+		return pooled.client, nil
+	}
+
+	client, err := p.newClient(name, config)
+	if err != nil {
+		return nil, err
+	}
+	if p.clients == nil {
+		p.clients = map[string]*providerClient{}
+	}
+	p.clients[name] = &providerClient{config: config, client: client}
+	return client, nil
+}
+
+// Forget drops the client of the provider name.
+func (p *ProviderPool) Forget(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, name)
+}
+
+func (p *ProviderPool) newClient(name string, config ProviderConfig) (IssueClient, error) {
+	provider, ok := LookupProvider(config.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, config.Type)
+	}
+	transport, err := ProviderTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.ProxyURL == "" && p.Proxy != nil {
+		transport.Proxy = p.Proxy
+	}
+	var roundTripper http.RoundTripper = transport
+	if config.RequestsPerHour > 0 {
+		roundTripper = &budgetTransport{base: roundTripper, limiter: rate.NewLimiter(rate.Every(time.Hour/time.Duration(config.RequestsPerHour)), 1)}
+	}
+	if p.Instrument != nil {
+		roundTripper = p.Instrument(roundTripper, "provider/"+name)
+	}
+	client, err := provider.NewIssueClient(config, roundTripper)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client for provider %s: %v", name, err)
+	}
+	return client, nil
+}
+
+// newGitHubIssueClient builds a GitHub client for the API of config.Host, or config.APIURL when set.
+func newGitHubIssueClient(config ProviderConfig, transport http.RoundTripper) (IssueClient, error) {
+	client := github.NewClient(&http.Client{Transport: transport}).WithAuthToken(config.Token)
+	apiURL := config.APIURL
+	if apiURL == "" && config.Host != PublicHost {
+		apiURL = fmt.Sprintf("https://%s/", config.Host)
+	}
+	if apiURL != "" {
+		var err error
+		if client, err = client.WithEnterpriseURLs(apiURL, apiURL); err != nil {
+			return nil, err
+		}
+	}
+	return &GitHubIssueClient{Client: client}, nil
+}
+
+// ProviderTransport returns a transport verifying the provider certificate and going through the proxy as
+// config asks.
+func ProviderTransport(config ProviderConfig) (*http.Transport, error) {
+	transport := NewPooledTransport()
+	if config.ProxyURL != "" {
+		proxy, err := FixedProxy(config.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxy
+	}
+	if config.CABundle == "" && !config.InsecureSkipVerify {
+		return transport, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: config.InsecureSkipVerify} //nolint:gosec // opt-in
+	if config.CABundle != "" {
+		roots, err := certPool([]byte(config.CABundle))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = roots
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// budgetTransport holds requests back to stay within the request budget of a provider.
+type budgetTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("request budget exhausted: %v", err)
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// DefaultNoProxy lists the hosts reached without the proxy by default: the local host and in-cluster services.
+const DefaultNoProxy = "localhost,127.0.0.1,.svc,.cluster.local"
+
+// ProxyFunc selects the proxy of a request, as http.Transport.Proxy.
+type ProxyFunc func(*http.Request) (*url.URL, error)
+
+// ProxyOptions configures the proxy requests to the git providers go through, instead of the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables.
+type ProxyOptions struct {
+	// URL of the proxy, e.g. http://proxy.example.com:3128. Empty uses the environment variables.
+	URL string
+	// NoProxy lists the hosts reached without the proxy, in the NO_PROXY format: comma separated hosts, domain
+	// suffixes such as .svc, IP addresses and CIDR ranges.
+	NoProxy string
+}
+
+// ProxyFunc returns the proxy selection of the options.
+func (o ProxyOptions) ProxyFunc() (ProxyFunc, error) {
+	if o.URL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	if err := validateProxyURL(o.URL); err != nil {
+		return nil, err
+	}
+	proxy := (&httpproxy.Config{HTTPProxy: o.URL, HTTPSProxy: o.URL, NoProxy: o.NoProxy}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// FixedProxy returns a proxy selection sending every request through proxyURL.
+func FixedProxy(proxyURL string) (ProxyFunc, error) {
+	if err := validateProxyURL(proxyURL); err != nil {
+		return nil, err
+	}
+	parsed, _ := url.Parse(proxyURL)
+	return http.ProxyURL(parsed), nil
+}
+
+func validateProxyURL(proxyURL string) error {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid proxy URL: %s", proxyURL)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+		return nil
+	}
+	return fmt.Errorf("unsupported proxy scheme %s, expected http, https or socks5", parsed.Scheme)
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Headers GitHub sets on rate limited responses.
+const (
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+	RetryAfterHeader         = "Retry-After"
+)
+
+// RateLimitTracker records until when GitHub rate limits each credential.
+type RateLimitTracker struct {
+	mu     sync.RWMutex
+	resets map[string]time.Time
+}
+
+// Transport returns a RoundTripper recording the rate limits GitHub reports for the token base authenticates
+// with as credential.
+func (t *RateLimitTracker) Transport(base http.RoundTripper, credential string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{base: base, tracker: t, credential: credential}
+}
+
+// LimitedUntil returns when the rate limit of credential resets. It reports false once the limit was reset,
+// or when credential was never rate limited.
+func (t *RateLimitTracker) LimitedUntil(credential string, now time.Time) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	resetAt, ok := t.resets[credential]
+	if !ok || !resetAt.After(now) {
+		return time.Time{}, false
+	}
+	return resetAt, true
+}
+
+func (t *RateLimitTracker) observe(credential string, resetAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.resets == nil {
+		t.resets = map[string]time.Time{}
+	}
+	t.resets[credential] = resetAt
+}
+
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	tracker    *RateLimitTracker
+	credential string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resetAt, ok := RateLimitReset(resp, time.Now()); ok {
+		t.tracker.observe(t.credential, resetAt)
+	}
+	return resp, nil
+}
+
+// RateLimitReset returns when the rate limit reported by a 403 or 429 response resets: after Retry-After for
+// secondary rate limits, at X-RateLimit-Reset once the primary rate limit is exhausted.
+func RateLimitReset(resp *http.Response, now time.Time) (time.Time, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get(RetryAfterHeader)); err == nil && seconds >= 0 {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	if resp.Header.Get(RateLimitRemainingHeader) != "0" {
+		return time.Time{}, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get(RateLimitResetHeader), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(reset, 0), true
+}
+
+// IsRateLimited reports whether err is GitHub reporting a primary or secondary rate limit.
+func IsRateLimited(err error) bool {
+	var rateLimit *github.RateLimitError
+	var abuseRateLimit *github.AbuseRateLimitError
+	if errors.As(err, &rateLimit) || errors.As(err, &abuseRateLimit) {
+		return true
+	}
+	var response *github.ErrorResponse
+	if errors.As(err, &response) && response.Response != nil {
+		_, limited := RateLimitReset(response.Response, time.Now())
+		return limited
+	}
+	return false
+}
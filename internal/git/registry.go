@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Provider is an implementation of the issue API of a Git platform. Implementations register themselves
+// with Register from an init function, so supporting a platform takes no change outside of its file.
+type Provider struct {
+	// Name is the provider type, as set in spec.type of a GitProvider.
+	Name string
+	// ServesHost reports whether the provider serves the repositories of a host no GitProvider configures,
+	// e.g. github.com for GitHub. Nil serves no host by default.
+	ServesHost func(host string) bool
+	// NewIssueClient builds a client for config, sending its requests through transport.
+	NewIssueClient func(config ProviderConfig, transport http.RoundTripper) (IssueClient, error)
+}
+
+var registry = struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}{providers: map[string]Provider{}}
+
+// Register adds a provider to the registry. Registering a name twice panics.
+func Register(provider Provider) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.providers[provider.Name]; ok {
+		panic(fmt.Sprintf("git provider %s registered twice", provider.Name))
+	}
+	registry.providers[provider.Name] = provider
+}
+
+// LookupProvider returns the provider registered under name.
+func LookupProvider(name string) (Provider, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	provider, ok := registry.providers[name]
+	return provider, ok
+}
+
+// ProviderForHost returns the registered provider serving host by default. When several do, the first by
+// name wins, so the routing does not depend on the registration order.
+func ProviderForHost(host string) (Provider, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	names := make([]string, 0, len(registry.providers))
+	for name := range registry.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if provider := registry.providers[name]; provider.ServesHost != nil && provider.ServesHost(host) {
+			return provider, true
+		}
+	}
+	return Provider{}, false
+}
+
+// Clients routes a repository to the issue client of the provider serving its host.
+type Clients struct {
+	// ByProvider holds the client of each provider, by provider name.
+	ByProvider map[string]IssueClient
+	// Default serves the repositories of the hosts no provider with a client in ByProvider serves.
+	// Nil fails them with ErrUnsupportedProvider.
+	Default IssueClient
+}
+
+// ForRepo returns the client of the repository at repoURL.
+func (c *Clients) ForRepo(repoURL string) (IssueClient, error) {
+	host, err := RepoHost(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if provider, ok := ProviderForHost(host); ok {
+		if client, ok := c.ByProvider[provider.Name]; ok {
+			return client, nil
+		}
+	}
+	if c.Default == nil {
+		return nil, fmt.Errorf("%w: no provider serves %s", ErrUnsupportedProvider, host)
+	}
+	return c.Default, nil
+}
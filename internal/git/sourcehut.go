@@ -0,0 +1,538 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderSourceHut is the provider type of todo.sr.ht and self-hosted SourceHut todo trackers.
+const ProviderSourceHut = "sourcehut"
+
+// SourceHutHost is the host of the public SourceHut todo trackers.
+const SourceHutHost = "todo.sr.ht"
+
+func init() {
+	Register(Provider{
+		Name: ProviderSourceHut,
+		ServesHost: func(host string) bool {
+			return host == SourceHutHost
+		},
+		NewIssueClient: func(config ProviderConfig, transport http.RoundTripper) (IssueClient, error) {
+			return NewSourceHutIssueClient(config.Host, config.APIURL, config.Token, transport), nil
+		},
+	})
+}
+
+// SourceHutIssueClient files issues as tickets of a todo.sr.ht tracker through its GraphQL API. A repository
+// URL names a tracker, e.g. https://todo.sr.ht/~owner/tracker, so the owner passed to its methods is the
+// canonical name of the tracker owner, starting with ~.
+//
+// Trackers have no milestones, issue types, locking, pinning or projects. Those operations fail with
+// ErrUnsupportedOperation, except DuplicateOf, which reports no canonical issue since tickets resolved as
+// duplicates don't record one. Comments can be added, but not read back, edited or deleted.
+type SourceHutIssueClient struct {
+	GraphQL *GraphQLClient
+	// Host is the host of the tracker web interface, used to build ticket URLs.
+	Host string
+
+	mu       sync.Mutex
+	trackers map[string]int
+}
+
+// NewSourceHutIssueClient returns a client of the trackers of host, authenticating with token. apiURL
+// defaults to https://<host>/query.
+func NewSourceHutIssueClient(host, apiURL, token string, transport http.RoundTripper) *SourceHutIssueClient {
+	if host == "" {
+		host = SourceHutHost
+	}
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://%s/query", host)
+	}
+	return &SourceHutIssueClient{
+		GraphQL: &GraphQLClient{
+			HTTPClient: &http.Client{Transport: &TokenFailoverTransport{Base: transport, Primary: token}},
+			Endpoint:   apiURL,
+		},
+		Host: host,
+	}
+}
+
+const sourceHutTicketFields = `id subject body status resolution created updated labels { name } assignees { canonicalName }`
+
+const sourceHutTicketsQuery = `query($owner: String!, $tracker: String!, $cursor: Cursor) {
+  user(username: $owner) {
+    tracker(name: $tracker) {
+      tickets(cursor: $cursor) { results { ` + sourceHutTicketFields + ` } cursor }
+    }
+  }
+}`
+
+const sourceHutTicketQuery = `query($owner: String!, $tracker: String!, $id: Int!) {
+  user(username: $owner) {
+    tracker(name: $tracker) { ticket(id: $id) { ` + sourceHutTicketFields + ` } }
+  }
+}`
+
+const sourceHutTrackerQuery = `query($owner: String!, $tracker: String!) {
+  user(username: $owner) { tracker(name: $tracker) { id } }
+}`
+
+const sourceHutLabelsQuery = `query($owner: String!, $tracker: String!, $cursor: Cursor) {
+  user(username: $owner) {
+    tracker(name: $tracker) { labels(cursor: $cursor) { results { id name } cursor } }
+  }
+}`
+
+const sourceHutUserQuery = `query($username: String!) { user(username: $username) { id } }`
+
+const sourceHutSubmitTicketMutation = `mutation($tracker: Int!, $subject: String!, $body: String) {
+  submitTicket(trackerId: $tracker, input: {subject: $subject, body: $body}) { ` + sourceHutTicketFields + ` }
+}`
+
+const sourceHutUpdateTicketMutation = `mutation($tracker: Int!, $id: Int!, $subject: String, $body: String) {
+  updateTicket(trackerId: $tracker, ticketId: $id, input: {subject: $subject, body: $body}) { id }
+}`
+
+const sourceHutUpdateStatusMutation = `mutation($tracker: Int!, $id: Int!, $status: TicketStatus!, $resolution: TicketResolution) {
+  updateTicketStatus(trackerId: $tracker, ticketId: $id, input: {status: $status, resolution: $resolution}) { id }
+}`
+
+const sourceHutCreateLabelMutation = `mutation($tracker: Int!, $name: String!) {
+  createLabel(trackerId: $tracker, name: $name, foregroundColor: "#ffffff", backgroundColor: "#6a737d") { id }
+}`
+
+const sourceHutLabelTicketMutation = `mutation($tracker: Int!, $id: Int!, $label: Int!) {
+  labelTicket(trackerId: $tracker, ticketId: $id, labelId: $label) { id }
+}`
+
+const sourceHutUnlabelTicketMutation = `mutation($tracker: Int!, $id: Int!, $label: Int!) {
+  unlabelTicket(trackerId: $tracker, ticketId: $id, labelId: $label) { id }
+}`
+
+const sourceHutAssignMutation = `mutation($tracker: Int!, $id: Int!, $user: Int!) {
+  assignUser(trackerId: $tracker, ticketId: $id, userId: $user) { id }
+}`
+
+const sourceHutUnassignMutation = `mutation($tracker: Int!, $id: Int!, $user: Int!) {
+  unassignUser(trackerId: $tracker, ticketId: $id, userId: $user) { id }
+}`
+
+const sourceHutCommentMutation = `mutation($tracker: Int!, $id: Int!, $text: String!) {
+  submitComment(trackerId: $tracker, ticketId: $id, input: {text: $text}) { id }
+}`
+
+type sourceHutTicket struct {
+	ID         int       `json:"id"`
+	Subject    string    `json:"subject"`
+	Body       string    `json:"body"`
+	Status     string    `json:"status"`
+	Resolution string    `json:"resolution"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+	Labels     []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		CanonicalName string `json:"canonicalName"`
+	} `json:"assignees"`
+}
+
+type sourceHutLabel struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// sourceHutStateReasons maps the resolutions of resolved tickets to the state reasons of GitHub issues.
+var sourceHutStateReasons = map[string]string{
+	"CLOSED":      "completed",
+	"FIXED":       "completed",
+	"IMPLEMENTED": "completed",
+	"WONT_FIX":    "not_planned",
+	"BY_DESIGN":   "not_planned",
+	"INVALID":     "not_planned",
+	"NOT_OUR_BUG": "not_planned",
+	"DUPLICATE":   "duplicate",
+}
+
+// username strips the ~ of a canonical user name.
+func username(owner string) string {
+	return strings.TrimPrefix(owner, "~")
+}
+
+func (c *SourceHutIssueClient) mapTicket(owner, tracker string, ticket *sourceHutTicket) *Issue {
+	issue := &Issue{
+		Number:      ticket.ID,
+		Title:       ticket.Subject,
+		Description: ticket.Body,
+		State:       "open",
+		URL:         fmt.Sprintf("https://%s/~%s/%s/%d", c.Host, username(owner), tracker, ticket.ID),
+		CreatedAt:   ticket.Created,
+		UpdatedAt:   ticket.Updated,
+	}
+	if ticket.Status == "RESOLVED" {
+		issue.State = "closed"
+		issue.StateReason = sourceHutStateReasons[ticket.Resolution]
+	}
+	for _, label := range ticket.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	for _, assignee := range ticket.Assignees {
+		issue.Assignees = append(issue.Assignees, username(assignee.CanonicalName))
+	}
+	return issue
+}
+
+// trackerID returns the ID of the tracker, which the mutations take instead of its name.
+func (c *SourceHutIssueClient) trackerID(ctx context.Context, owner, tracker string) (int, error) {
+	key := username(owner) + "/" + tracker
+	c.mu.Lock()
+	id, ok := c.trackers[key]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	var result struct {
+		User *struct {
+			Tracker *struct {
+				ID int `json:"id"`
+			} `json:"tracker"`
+		} `json:"user"`
+	}
+	err := c.GraphQL.Do(ctx, sourceHutTrackerQuery, map[string]interface{}{"owner": username(owner), "tracker": tracker}, &result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tracker: %v", err)
+	}
+	if result.User == nil || result.User.Tracker == nil {
+		return 0, fmt.Errorf("failed to get tracker: tracker %s not found", key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.trackers == nil {
+		c.trackers = map[string]int{}
+	}
+	c.trackers[key] = result.User.Tracker.ID
+	return result.User.Tracker.ID, nil
+}
+
+// mutate runs a mutation on the ticket of the tracker.
+func (c *SourceHutIssueClient) mutate(ctx context.Context, owner, tracker string, ticket int, mutation string, variables map[string]interface{}) error {
+	trackerID, err := c.trackerID(ctx, owner, tracker)
+	if err != nil {
+		return err
+	}
+	variables["tracker"], variables["id"] = trackerID, ticket
+	return c.GraphQL.Do(ctx, mutation, variables, nil)
+}
+
+// List returns the unresolved tickets of the tracker.
+func (c *SourceHutIssueClient) List(ctx context.Context, owner, tracker string) ([]*Issue, error) {
+	var issues []*Issue
+	variables := map[string]interface{}{"owner": username(owner), "tracker": tracker}
+	for {
+		var page struct {
+			User *struct {
+				Tracker *struct {
+					Tickets struct {
+						Results []sourceHutTicket `json:"results"`
+						Cursor  *string           `json:"cursor"`
+					} `json:"tickets"`
+				} `json:"tracker"`
+			} `json:"user"`
+		}
+		if err := c.GraphQL.Do(ctx, sourceHutTicketsQuery, variables, &page); err != nil {
+			return nil, fmt.Errorf("failed to list tickets: %v", err)
+		}
+		if page.User == nil || page.User.Tracker == nil {
+			return nil, fmt.Errorf("failed to list tickets: tracker %s/%s not found", owner, tracker)
+		}
+		tickets := page.User.Tracker.Tickets
+		for i := range tickets.Results {
+			if tickets.Results[i].Status != "RESOLVED" {
+				issues = append(issues, c.mapTicket(owner, tracker, &tickets.Results[i]))
+			}
+		}
+		if tickets.Cursor == nil {
+			return issues, nil
+		}
+		variables["cursor"] = *tickets.Cursor
+	}
+}
+
+func (c *SourceHutIssueClient) Get(ctx context.Context, owner, tracker string, issueNumber int) (*Issue, error) {
+	var result struct {
+		User *struct {
+			Tracker *struct {
+				Ticket *sourceHutTicket `json:"ticket"`
+			} `json:"tracker"`
+		} `json:"user"`
+	}
+	variables := map[string]interface{}{"owner": username(owner), "tracker": tracker, "id": issueNumber}
+	if err := c.GraphQL.Do(ctx, sourceHutTicketQuery, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to get ticket: %v", err)
+	}
+	if result.User == nil || result.User.Tracker == nil || result.User.Tracker.Ticket == nil {
+		return nil, fmt.Errorf("failed to get ticket: ticket %d not found", issueNumber)
+	}
+	return c.mapTicket(owner, tracker, result.User.Tracker.Ticket), nil
+}
+
+// Create submits a ticket, then labels and assigns it. The milestone and type of desired are ignored.
+func (c *SourceHutIssueClient) Create(ctx context.Context, owner, tracker string, desired *DesiredIssue) (*Issue, error) {
+	trackerID, err := c.trackerID(ctx, owner, tracker)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		SubmitTicket sourceHutTicket `json:"submitTicket"`
+	}
+	variables := map[string]interface{}{"tracker": trackerID, "subject": desired.Title, "body": desired.Body}
+	if err := c.GraphQL.Do(ctx, sourceHutSubmitTicketMutation, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to create ticket: %v", err)
+	}
+	issue := c.mapTicket(owner, tracker, &result.SubmitTicket)
+
+	if len(desired.Labels) > 0 {
+		if err := c.AddLabels(ctx, owner, tracker, issue.Number, desired.Labels); err != nil {
+			return nil, err
+		}
+		issue.Labels = append(issue.Labels, desired.Labels...)
+	}
+	if len(desired.Assignees) > 0 {
+		if err := c.assign(ctx, owner, tracker, issue.Number, nil, desired.Assignees); err != nil {
+			return nil, err
+		}
+		issue.Assignees = desired.Assignees
+	}
+	return issue, nil
+}
+
+// Edit updates the subject and body of a ticket and replaces its assignees. The milestone and type of desired
+// are ignored.
+func (c *SourceHutIssueClient) Edit(ctx context.Context, owner, tracker string, issueNumber int, desired *DesiredIssue) (*Issue, error) {
+	variables := map[string]interface{}{"body": desired.Body}
+	if desired.Title != "" {
+		variables["subject"] = desired.Title
+	}
+	if err := c.mutate(ctx, owner, tracker, issueNumber, sourceHutUpdateTicketMutation, variables); err != nil {
+		return nil, fmt.Errorf("failed to edit ticket: %v", err)
+	}
+	issue, err := c.Get(ctx, owner, tracker, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	if desired.Assignees != nil {
+		if err := c.assign(ctx, owner, tracker, issueNumber, issue.Assignees, desired.Assignees); err != nil {
+			return nil, err
+		}
+		issue.Assignees = desired.Assignees
+	}
+	return issue, nil
+}
+
+// assign assigns the ticket to desired instead of current.
+func (c *SourceHutIssueClient) assign(ctx context.Context, owner, tracker string, issueNumber int, current, desired []string) error {
+	for _, user := range current {
+		if slices.Contains(desired, user) {
+			continue
+		}
+		if err := c.setAssigned(ctx, owner, tracker, issueNumber, user, sourceHutUnassignMutation); err != nil {
+			return fmt.Errorf("failed to unassign %s: %v", user, err)
+		}
+	}
+	for _, user := range desired {
+		if slices.Contains(current, user) {
+			continue
+		}
+		if err := c.setAssigned(ctx, owner, tracker, issueNumber, user, sourceHutAssignMutation); err != nil {
+			return fmt.Errorf("failed to assign %s: %v", user, err)
+		}
+	}
+	return nil
+}
+
+func (c *SourceHutIssueClient) setAssigned(ctx context.Context, owner, tracker string, issueNumber int, user, mutation string) error {
+	var result struct {
+		User *struct {
+			ID int `json:"id"`
+		} `json:"user"`
+	}
+	if err := c.GraphQL.Do(ctx, sourceHutUserQuery, map[string]interface{}{"username": username(user)}, &result); err != nil {
+		return err
+	}
+	if result.User == nil {
+		return fmt.Errorf("user %s not found", user)
+	}
+	return c.mutate(ctx, owner, tracker, issueNumber, mutation, map[string]interface{}{"user": result.User.ID})
+}
+
+// Close resolves a ticket as closed.
+func (c *SourceHutIssueClient) Close(ctx context.Context, owner, tracker string, issueNumber int) (*Issue, error) {
+	variables := map[string]interface{}{"status": "RESOLVED", "resolution": "CLOSED"}
+	if err := c.mutate(ctx, owner, tracker, issueNumber, sourceHutUpdateStatusMutation, variables); err != nil {
+		return nil, fmt.Errorf("failed to close ticket: %v", err)
+	}
+	return c.Get(ctx, owner, tracker, issueNumber)
+}
+
+// Reopen sets a resolved ticket back to reported.
+func (c *SourceHutIssueClient) Reopen(ctx context.Context, owner, tracker string, issueNumber int) (*Issue, error) {
+	variables := map[string]interface{}{"status": "REPORTED", "resolution": "UNRESOLVED"}
+	if err := c.mutate(ctx, owner, tracker, issueNumber, sourceHutUpdateStatusMutation, variables); err != nil {
+		return nil, fmt.Errorf("failed to reopen ticket: %v", err)
+	}
+	return c.Get(ctx, owner, tracker, issueNumber)
+}
+
+// labels returns the labels of the tracker by name.
+func (c *SourceHutIssueClient) labels(ctx context.Context, owner, tracker string) (map[string]int, error) {
+	labels := map[string]int{}
+	variables := map[string]interface{}{"owner": username(owner), "tracker": tracker}
+	for {
+		var page struct {
+			User *struct {
+				Tracker *struct {
+					Labels struct {
+						Results []sourceHutLabel `json:"results"`
+						Cursor  *string          `json:"cursor"`
+					} `json:"labels"`
+				} `json:"tracker"`
+			} `json:"user"`
+		}
+		if err := c.GraphQL.Do(ctx, sourceHutLabelsQuery, variables, &page); err != nil {
+			return nil, fmt.Errorf("failed to list labels: %v", err)
+		}
+		if page.User == nil || page.User.Tracker == nil {
+			return nil, fmt.Errorf("failed to list labels: tracker %s/%s not found", owner, tracker)
+		}
+		for _, label := range page.User.Tracker.Labels.Results {
+			labels[label.Name] = label.ID
+		}
+		if page.User.Tracker.Labels.Cursor == nil {
+			return labels, nil
+		}
+		variables["cursor"] = *page.User.Tracker.Labels.Cursor
+	}
+}
+
+// AddLabels adds labels to a ticket, creating the labels the tracker doesn't define yet.
+func (c *SourceHutIssueClient) AddLabels(ctx context.Context, owner, tracker string, issueNumber int, labels []string) error {
+	defined, err := c.labels(ctx, owner, tracker)
+	if err != nil {
+		return err
+	}
+	trackerID, err := c.trackerID(ctx, owner, tracker)
+	if err != nil {
+		return err
+	}
+	for _, name := range labels {
+		id, ok := defined[name]
+		if !ok {
+			var created struct {
+				CreateLabel sourceHutLabel `json:"createLabel"`
+			}
+			if err := c.GraphQL.Do(ctx, sourceHutCreateLabelMutation, map[string]interface{}{"tracker": trackerID, "name": name}, &created); err != nil {
+				return fmt.Errorf("failed to create label %s: %v", name, err)
+			}
+			id = created.CreateLabel.ID
+		}
+		if err := c.mutate(ctx, owner, tracker, issueNumber, sourceHutLabelTicketMutation, map[string]interface{}{"label": id}); err != nil {
+			return fmt.Errorf("failed to add label %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *SourceHutIssueClient) RemoveLabel(ctx context.Context, owner, tracker string, issueNumber int, label string) error {
+	defined, err := c.labels(ctx, owner, tracker)
+	if err != nil {
+		return err
+	}
+	id, ok := defined[label]
+	if !ok {
+		return nil
+	}
+	if err := c.mutate(ctx, owner, tracker, issueNumber, sourceHutUnlabelTicketMutation, map[string]interface{}{"label": id}); err != nil {
+		return fmt.Errorf("failed to remove label %s: %v", label, err)
+	}
+	return nil
+}
+
+func (c *SourceHutIssueClient) ListLabels(ctx context.Context, owner, tracker string) ([]string, error) {
+	defined, err := c.labels(ctx, owner, tracker)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(defined))
+	for name := range defined {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Comment adds a comment to a ticket and returns the ID of its event.
+func (c *SourceHutIssueClient) Comment(ctx context.Context, owner, tracker string, issueNumber int, body string) (int64, error) {
+	trackerID, err := c.trackerID(ctx, owner, tracker)
+	if err != nil {
+		return 0, err
+	}
+	var result struct {
+		SubmitComment struct {
+			ID int64 `json:"id"`
+		} `json:"submitComment"`
+	}
+	variables := map[string]interface{}{"tracker": trackerID, "id": issueNumber, "text": body}
+	if err := c.GraphQL.Do(ctx, sourceHutCommentMutation, variables, &result); err != nil {
+		return 0, fmt.Errorf("failed to comment: %v", err)
+	}
+	return result.SubmitComment.ID, nil
+}
+
+// DuplicateOf returns nil: a ticket resolved as a duplicate does not record the ticket it duplicates.
+func (c *SourceHutIssueClient) DuplicateOf(context.Context, string, string, int) (*CanonicalIssue, error) {
+	return nil, nil
+}
+
+// LinkedPullRequests returns nil: trackers have no pull requests.
+func (c *SourceHutIssueClient) LinkedPullRequests(context.Context, string, string, int) ([]LinkedPullRequest, error) {
+	return nil, nil
+}
+
+func (c *SourceHutIssueClient) GetComment(_ context.Context, _, _ string, commentID int64) (string, error) {
+	return "", fmt.Errorf("%w: reading comment %d", ErrUnsupportedOperation, commentID)
+}
+
+func (c *SourceHutIssueClient) EditComment(_ context.Context, _, _ string, commentID int64, _ string) error {
+	return fmt.Errorf("%w: editing comment %d", ErrUnsupportedOperation, commentID)
+}
+
+func (c *SourceHutIssueClient) DeleteComment(_ context.Context, _, _ string, commentID int64) error {
+	return fmt.Errorf("%w: deleting comment %d", ErrUnsupportedOperation, commentID)
+}
+
+func (c *SourceHutIssueClient) Lock(context.Context, string, string, int, string) error {
+	return fmt.Errorf("%w: locking", ErrUnsupportedOperation)
+}
+
+func (c *SourceHutIssueClient) Unlock(context.Context, string, string, int) error {
+	return fmt.Errorf("%w: unlocking", ErrUnsupportedOperation)
+}
+
+func (c *SourceHutIssueClient) SetPinned(context.Context, string, string, int, bool) error {
+	return fmt.Errorf("%w: pinning", ErrUnsupportedOperation)
+}
+
+func (c *SourceHutIssueClient) AddToProject(_ context.Context, _, _ string, _ int, project string) error {
+	return fmt.Errorf("%w: adding to project %s", ErrUnsupportedOperation, project)
+}
+
+func (c *SourceHutIssueClient) FindMilestone(_ context.Context, _, _, title string) (int, error) {
+	return 0, fmt.Errorf("%w: milestone %s", ErrUnsupportedOperation, title)
+}
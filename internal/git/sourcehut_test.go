@@ -0,0 +1,213 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeTracker serves the todo.sr.ht GraphQL API of the ~owner/tracker tracker.
+type fakeTracker struct {
+	tickets []map[string]interface{}
+	// pageSize is the number of tickets listed per page.
+	pageSize int
+	labels   map[string]int
+	users    map[string]int
+}
+
+func (t *fakeTracker) handle(query string, variables map[string]interface{}) interface{} {
+	tracker := func(data map[string]interface{}) interface{} {
+		return map[string]interface{}{"user": map[string]interface{}{"tracker": data}}
+	}
+	switch query {
+	case sourceHutTrackerQuery:
+		return tracker(map[string]interface{}{"id": 7})
+	case sourceHutTicketsQuery:
+		start := 0
+		if cursor, ok := variables["cursor"].(string); ok {
+			start, _ = strconv.Atoi(cursor)
+		}
+		end := min(start+t.pageSize, len(t.tickets))
+		page := map[string]interface{}{"results": t.tickets[start:end], "cursor": nil}
+		if end < len(t.tickets) {
+			page["cursor"] = strconv.Itoa(end)
+		}
+		return tracker(map[string]interface{}{"tickets": page})
+	case sourceHutTicketQuery:
+		for _, ticket := range t.tickets {
+			if ticket["id"] == int(variables["id"].(float64)) {
+				return tracker(map[string]interface{}{"ticket": ticket})
+			}
+		}
+		return tracker(map[string]interface{}{"ticket": nil})
+	case sourceHutLabelsQuery:
+		var results []map[string]interface{}
+		for name, id := range t.labels {
+			results = append(results, map[string]interface{}{"id": id, "name": name})
+		}
+		return tracker(map[string]interface{}{"labels": map[string]interface{}{"results": results, "cursor": nil}})
+	case sourceHutUserQuery:
+		return map[string]interface{}{"user": map[string]interface{}{"id": t.users[variables["username"].(string)]}}
+	case sourceHutSubmitTicketMutation:
+		ticket := map[string]interface{}{
+			"id": len(t.tickets) + 1, "subject": variables["subject"], "body": variables["body"], "status": "REPORTED",
+			"resolution": "UNRESOLVED", "created": "2024-01-01T00:00:00Z", "updated": "2024-01-01T00:00:00Z",
+		}
+		t.tickets = append(t.tickets, ticket)
+		return map[string]interface{}{"submitTicket": ticket}
+	case sourceHutCreateLabelMutation:
+		return map[string]interface{}{"createLabel": map[string]interface{}{"id": 99, "name": variables["name"]}}
+	}
+	return map[string]interface{}{}
+}
+
+func sourceHutTicketJSON(id int, subject, status, resolution string, labels, assignees []string) map[string]interface{} {
+	var labelObjects, assigneeObjects []map[string]interface{}
+	for _, label := range labels {
+		labelObjects = append(labelObjects, map[string]interface{}{"name": label})
+	}
+	for _, assignee := range assignees {
+		assigneeObjects = append(assigneeObjects, map[string]interface{}{"canonicalName": assignee})
+	}
+	return map[string]interface{}{
+		"id": id, "subject": subject, "body": subject + " body", "status": status, "resolution": resolution,
+		"created": "2024-01-01T00:00:00Z", "updated": "2024-01-02T00:00:00Z",
+		"labels": labelObjects, "assignees": assigneeObjects,
+	}
+}
+
+var _ = ginkgo.Describe("SourceHutIssueClient", func() {
+	var (
+		tracker *fakeTracker
+		server  *graphQLServer
+		client  *SourceHutIssueClient
+		ctx     = context.Background()
+	)
+
+	ginkgo.BeforeEach(func() {
+		tracker = &fakeTracker{
+			pageSize: 2,
+			labels:   map[string]int{"bug": 1},
+			users:    map[string]int{"alice": 11, "bob": 12},
+		}
+		server = newGraphQLServer(tracker.handle)
+		client = NewSourceHutIssueClient("", server.URL, "token", nil)
+	})
+
+	ginkgo.It("lists the unresolved tickets of every page", func() {
+		tracker.tickets = []map[string]interface{}{
+			sourceHutTicketJSON(1, "first", "REPORTED", "UNRESOLVED", []string{"bug"}, []string{"~alice"}),
+			sourceHutTicketJSON(2, "fixed", "RESOLVED", "FIXED", nil, nil),
+			sourceHutTicketJSON(3, "third", "CONFIRMED", "UNRESOLVED", nil, nil),
+		}
+
+		issues, err := client.List(ctx, "~owner", "tracker")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(2))
+		Expect(*issues[0]).To(Equal(Issue{
+			Number:      1,
+			Title:       "first",
+			Description: "first body",
+			State:       "open",
+			URL:         "https://todo.sr.ht/~owner/tracker/1",
+			Labels:      []string{"bug"},
+			Assignees:   []string{"alice"},
+			CreatedAt:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		}))
+		Expect(issues[1].Number).To(Equal(3))
+
+		pages := server.variables(sourceHutTicketsQuery)
+		Expect(pages).To(HaveLen(2))
+		Expect(pages[0]).NotTo(HaveKey("cursor"))
+		Expect(pages[1]).To(HaveKeyWithValue("cursor", "2"))
+		Expect(pages[1]).To(HaveKeyWithValue("owner", "owner"))
+	})
+
+	ginkgo.It("creates a ticket with its labels and assignees", func() {
+		issue, err := client.Create(ctx, "~owner", "tracker", &DesiredIssue{
+			Title:     "title",
+			Body:      "body",
+			Labels:    []string{"bug", "new"},
+			Assignees: []string{"alice"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issue.Number).To(Equal(1))
+		Expect(issue.Title).To(Equal("title"))
+		Expect(issue.State).To(Equal("open"))
+		Expect(issue.Labels).To(Equal([]string{"bug", "new"}))
+		Expect(issue.Assignees).To(Equal([]string{"alice"}))
+
+		Expect(server.variables(sourceHutSubmitTicketMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "subject": "title", "body": "body"}))
+		Expect(server.variables(sourceHutCreateLabelMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "name": "new"}))
+		Expect(server.variables(sourceHutLabelTicketMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "id": 1.0, "label": 1.0},
+			map[string]interface{}{"tracker": 7.0, "id": 1.0, "label": 99.0}))
+		Expect(server.variables(sourceHutAssignMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "id": 1.0, "user": 11.0}))
+		Expect(server.variables(sourceHutTrackerQuery)).To(HaveLen(1))
+	})
+
+	ginkgo.It("edits a ticket and replaces its assignees", func() {
+		tracker.tickets = []map[string]interface{}{
+			sourceHutTicketJSON(1, "title", "REPORTED", "UNRESOLVED", nil, []string{"~alice"}),
+		}
+
+		issue, err := client.Edit(ctx, "~owner", "tracker", 1, &DesiredIssue{
+			Title:     "renamed",
+			Body:      "new body",
+			Assignees: []string{"bob"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issue.Assignees).To(Equal([]string{"bob"}))
+
+		Expect(server.variables(sourceHutUpdateTicketMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "id": 1.0, "subject": "renamed", "body": "new body"}))
+		Expect(server.variables(sourceHutUnassignMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "id": 1.0, "user": 11.0}))
+		Expect(server.variables(sourceHutAssignMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "id": 1.0, "user": 12.0}))
+	})
+
+	ginkgo.It("resolves a closed ticket and maps its resolution", func() {
+		tracker.tickets = []map[string]interface{}{
+			sourceHutTicketJSON(1, "title", "RESOLVED", "WONT_FIX", nil, nil),
+		}
+
+		issue, err := client.Close(ctx, "~owner", "tracker", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issue.State).To(Equal("closed"))
+		Expect(issue.StateReason).To(Equal("not_planned"))
+		Expect(server.variables(sourceHutUpdateStatusMutation)).To(ConsistOf(
+			map[string]interface{}{"tracker": 7.0, "id": 1.0, "status": "RESOLVED", "resolution": "CLOSED"}))
+	})
+
+	ginkgo.It("fails the operations trackers don't offer", func() {
+		unsupported := []error{
+			client.EditComment(ctx, "~owner", "tracker", 1, "body"),
+			client.DeleteComment(ctx, "~owner", "tracker", 1),
+			client.Lock(ctx, "~owner", "tracker", 1, "spam"),
+			client.Unlock(ctx, "~owner", "tracker", 1),
+			client.SetPinned(ctx, "~owner", "tracker", 1, true),
+			client.AddToProject(ctx, "~owner", "tracker", 1, "board"),
+		}
+		_, err := client.GetComment(ctx, "~owner", "tracker", 1)
+		unsupported = append(unsupported, err)
+		_, err = client.FindMilestone(ctx, "~owner", "tracker", "v1")
+		unsupported = append(unsupported, err)
+		for _, err := range unsupported {
+			Expect(errors.Is(err, ErrUnsupportedOperation)).To(BeTrue(), "unexpected error %v", err)
+		}
+
+		canonical, err := client.DuplicateOf(ctx, "~owner", "tracker", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(canonical).To(BeNil())
+		Expect(server.requests).To(BeEmpty())
+	})
+})
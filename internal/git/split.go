@@ -0,0 +1,151 @@
+package git
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SplitIssueClient sends the reads of an IssueClient (List, Get, GetComment, ListLabels, FindMilestone and
+// DuplicateOf) to Reader and everything else to the embedded client, for GitHub Enterprise deployments serving
+// reads from a replica or a caching proxy.
+//
+// Replicas lag behind the primary: for ReadAfterWrite after a write to a repository, its reads go to the
+// embedded client as well, so a reconcile finds the issue it just created instead of creating it again.
+type SplitIssueClient struct {
+	IssueClient
+	Reader         IssueClient
+	ReadAfterWrite time.Duration
+
+	mu     sync.Mutex
+	writes map[string]time.Time
+}
+
+// NewSplitIssueClient returns writer when reader is nil, else a SplitIssueClient reading from reader.
+func NewSplitIssueClient(reader, writer IssueClient, readAfterWrite time.Duration) IssueClient {
+	if reader == nil {
+		return writer
+	}
+	return &SplitIssueClient{IssueClient: writer, Reader: reader, ReadAfterWrite: readAfterWrite}
+}
+
+// reader returns the client serving the reads of owner/repo.
+func (c *SplitIssueClient) reader(owner, repo string) IssueClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	written, ok := c.writes[owner+"/"+repo]
+	if !ok {
+		return c.Reader
+	}
+	if time.Since(written) < c.ReadAfterWrite {
+		return c.IssueClient
+	}
+	delete(c.writes, owner+"/"+repo)
+	return c.Reader
+}
+
+// wrote records a write to owner/repo.
+func (c *SplitIssueClient) wrote(owner, repo string) {
+	if c.ReadAfterWrite <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writes == nil {
+		c.writes = map[string]time.Time{}
+	}
+	c.writes[owner+"/"+repo] = time.Now()
+}
+
+func (c *SplitIssueClient) List(ctx context.Context, owner, repo string) ([]*Issue, error) {
+	return c.reader(owner, repo).List(ctx, owner, repo)
+}
+
+func (c *SplitIssueClient) Get(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error) {
+	return c.reader(owner, repo).Get(ctx, owner, repo, issueNumber)
+}
+
+func (c *SplitIssueClient) GetComment(ctx context.Context, owner, repo string, commentID int64) (string, error) {
+	return c.reader(owner, repo).GetComment(ctx, owner, repo, commentID)
+}
+
+func (c *SplitIssueClient) ListLabels(ctx context.Context, owner, repo string) ([]string, error) {
+	return c.reader(owner, repo).ListLabels(ctx, owner, repo)
+}
+
+func (c *SplitIssueClient) FindMilestone(ctx context.Context, owner, repo, title string) (int, error) {
+	return c.reader(owner, repo).FindMilestone(ctx, owner, repo, title)
+}
+
+func (c *SplitIssueClient) DuplicateOf(ctx context.Context, owner, repo string, issueNumber int) (*CanonicalIssue, error) {
+	return c.reader(owner, repo).DuplicateOf(ctx, owner, repo, issueNumber)
+}
+
+func (c *SplitIssueClient) LinkedPullRequests(ctx context.Context, owner, repo string, issueNumber int) ([]LinkedPullRequest, error) {
+	return c.reader(owner, repo).LinkedPullRequests(ctx, owner, repo, issueNumber)
+}
+
+func (c *SplitIssueClient) Create(ctx context.Context, owner, repo string, desired *DesiredIssue) (*Issue, error) {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.Create(ctx, owner, repo, desired)
+}
+
+func (c *SplitIssueClient) Edit(ctx context.Context, owner, repo string, issueNumber int, desired *DesiredIssue) (*Issue, error) {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.Edit(ctx, owner, repo, issueNumber, desired)
+}
+
+func (c *SplitIssueClient) Close(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error) {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.Close(ctx, owner, repo, issueNumber)
+}
+
+func (c *SplitIssueClient) Reopen(ctx context.Context, owner, repo string, issueNumber int) (*Issue, error) {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.Reopen(ctx, owner, repo, issueNumber)
+}
+
+func (c *SplitIssueClient) AddLabels(ctx context.Context, owner, repo string, issueNumber int, labels []string) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.AddLabels(ctx, owner, repo, issueNumber, labels)
+}
+
+func (c *SplitIssueClient) RemoveLabel(ctx context.Context, owner, repo string, issueNumber int, label string) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.RemoveLabel(ctx, owner, repo, issueNumber, label)
+}
+
+func (c *SplitIssueClient) Lock(ctx context.Context, owner, repo string, issueNumber int, reason string) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.Lock(ctx, owner, repo, issueNumber, reason)
+}
+
+func (c *SplitIssueClient) Unlock(ctx context.Context, owner, repo string, issueNumber int) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.Unlock(ctx, owner, repo, issueNumber)
+}
+
+func (c *SplitIssueClient) Comment(ctx context.Context, owner, repo string, issueNumber int, body string) (int64, error) {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.Comment(ctx, owner, repo, issueNumber, body)
+}
+
+func (c *SplitIssueClient) EditComment(ctx context.Context, owner, repo string, commentID int64, body string) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.EditComment(ctx, owner, repo, commentID, body)
+}
+
+func (c *SplitIssueClient) DeleteComment(ctx context.Context, owner, repo string, commentID int64) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.DeleteComment(ctx, owner, repo, commentID)
+}
+
+func (c *SplitIssueClient) SetPinned(ctx context.Context, owner, repo string, issueNumber int, pinned bool) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.SetPinned(ctx, owner, repo, issueNumber, pinned)
+}
+
+func (c *SplitIssueClient) AddToProject(ctx context.Context, owner, repo string, issueNumber int, project string) error {
+	defer c.wrote(owner, repo)
+	return c.IssueClient.AddToProject(ctx, owner, repo, issueNumber, project)
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Errors retrying cannot fix: the repository or the credentials have to change first.
+var (
+	// ErrRepoNotFound is returned when the repository does not exist or is not visible to the token.
+	ErrRepoNotFound = errors.New("repository not found")
+	// ErrAuthenticationFailed is returned when GitHub rejects the token or denies it access to the repository.
+	ErrAuthenticationFailed = errors.New("authentication failed")
+	// ErrIssuesDisabled is returned when issues are disabled in the repository settings.
+	ErrIssuesDisabled = errors.New("issues are disabled for the repository")
+)
+
+// terminalError classifies a failed repository-level response as ErrRepoNotFound, ErrAuthenticationFailed
+// or ErrIssuesDisabled, which GitHub reports with 410 Gone. Rate limited 403 responses are not terminal.
+// It returns nil for every other failure.
+func terminalError(response *github.Response, err error) error {
+	var rateLimit *github.RateLimitError
+	var abuseRateLimit *github.AbuseRateLimitError
+	if response == nil || errors.As(err, &rateLimit) || errors.As(err, &abuseRateLimit) {
+		return nil
+	}
+	switch response.StatusCode {
+	case http.StatusNotFound:
+		return ErrRepoNotFound
+	case http.StatusGone:
+		return ErrIssuesDisabled
+	case http.StatusUnauthorized:
+		return ErrAuthenticationFailed
+	case http.StatusForbidden:
+		if _, limited := RateLimitReset(response.Response, time.Now()); !limited {
+			return ErrAuthenticationFailed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSOptions configures how the operator connects to the GitHub API, e.g. a GitHub Enterprise Server whose
+// certificate is issued by a corporate CA, or which asks for a client certificate.
+type TLSOptions struct {
+	// CABundleFile holds PEM encoded certificates trusted in addition to the system ones. Optional.
+	CABundleFile string
+	// CertFile and KeyFile hold the PEM encoded client certificate and key presented to the server. Optional.
+	CertFile string
+	KeyFile  string
+	// MinVersion is the lowest TLS version accepted: 1.2 or 1.3. Defaults to 1.2.
+	MinVersion string
+	// InsecureSkipVerify disables the verification of the server certificate. Only meant for testing.
+	InsecureSkipVerify bool
+}
+
+// tlsVersions are the TLS versions TLSOptions.MinVersion accepts.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Config returns the TLS configuration of the options.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: o.InsecureSkipVerify} //nolint:gosec // opt-in
+	if o.MinVersion != "" {
+		version, ok := tlsVersions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q, expected 1.2 or 1.3", o.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+	if o.CABundleFile != "" {
+		data, err := os.ReadFile(o.CABundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %v", err)
+		}
+		if tlsConfig.RootCAs, err = certPool(data); err != nil {
+			return nil, err
+		}
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+	}
+	if o.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// Transport returns a pooled transport connecting with the TLS configuration of the options.
+func (o TLSOptions) Transport() (*http.Transport, error) {
+	tlsConfig, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	transport := NewPooledTransport()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// certPool returns the system certificates with the PEM encoded certificates of bundle added.
+func certPool(bundle []byte) (*x509.CertPool, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("no certificate found in the CA bundle")
+	}
+	return roots, nil
+}
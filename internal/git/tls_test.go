@@ -0,0 +1,51 @@
+package git
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("TLSOptions", func() {
+	var (
+		server   *httptest.Server
+		caBundle string
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		ginkgo.DeferCleanup(server.Close)
+		caBundle = filepath.Join(ginkgo.GinkgoT().TempDir(), "ca.crt")
+		certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		Expect(os.WriteFile(caBundle, certificate, 0o600)).To(Succeed())
+	})
+
+	get := func(options TLSOptions) error {
+		transport, err := options.Transport()
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	ginkgo.It("trusts a server whose certificate is issued by the CA bundle", func() {
+		Expect(get(TLSOptions{CABundleFile: caBundle})).To(Succeed())
+	})
+
+	ginkgo.It("rejects the server without the CA bundle", func() {
+		Expect(get(TLSOptions{})).To(MatchError(ContainSubstring("certificate")))
+	})
+
+	ginkgo.It("rejects a CA bundle without certificates", func() {
+		Expect(os.WriteFile(caBundle, []byte("not a certificate"), 0o600)).To(Succeed())
+		_, err := TLSOptions{CABundleFile: caBundle}.Config()
+		Expect(err).To(MatchError("no certificate found in the CA bundle"))
+	})
+})
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func init() {
+	RegisterCredentialsProvider(CredentialsFile, func(options CredentialsOptions) (CredentialsProvider, error) {
+		if options.Source == "" {
+			return nil, fmt.Errorf("credentials provider %s needs the path of the token file", CredentialsFile)
+		}
+		return &TokenFile{
+			Path:        options.Source,
+			Credentials: &TokenFailoverTransport{OnSwitch: options.OnSwitch},
+			OnReload:    options.OnReload,
+		}, nil
+	})
+}
+
+// TokenFile keeps the primary token of Credentials in sync with a file, e.g. a short-lived token mounted
+// from a projected volume. The directory of the file is watched rather than the file itself, since the
+// kubelet updates mounted volumes by swapping a symlink, which a watch on the file would not see.
+type TokenFile struct {
+	Path        string
+	Credentials *TokenFailoverTransport
+	// OnReload is called after every reload with its error, nil when the token was read. Optional.
+	OnReload func(err error)
+}
+
+// Load reads the token from the file and makes it the primary token.
+func (f *TokenFile) Load() error {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read token file: %v", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return fmt.Errorf("token file %s is empty", f.Path)
+	}
+	f.Credentials.SetPrimary(token)
+	return nil
+}
+
+// Transport loads the token and returns Credentials, sending its requests through base. It implements
+// CredentialsProvider.
+func (f *TokenFile) Transport(_ context.Context, base http.RoundTripper) (http.RoundTripper, error) {
+	f.Credentials.Base = base
+	if err := f.Load(); err != nil {
+		return nil, err
+	}
+	return f.Credentials, nil
+}
+
+// Start reloads the token whenever the directory of the file changes, until ctx is done. A failed reload
+// keeps the previous token. It implements manager.Runnable.
+func (f *TokenFile) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch token file: %v", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(f.Path)); err != nil {
+		return fmt.Errorf("failed to watch token file: %v", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			err := f.Load()
+			if f.OnReload != nil {
+				f.OnReload(err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			if f.OnReload != nil {
+				f.OnReload(fmt.Errorf("failed to watch token file: %v", err))
+			}
+		}
+	}
+}
+
+// NeedLeaderElection lets every replica reload the token: each sends requests.
+func (f *TokenFile) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("TokenFile", func() {
+	// writeVersion writes token to a new timestamped directory of dir and points ..data at it, swapping the
+	// symlink atomically like the kubelet updates a projected volume.
+	writeVersion := func(dir, version, token string) {
+		Expect(os.Mkdir(filepath.Join(dir, version), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, version, "token"), []byte(token+"\n"), 0o600)).To(Succeed())
+		Expect(os.Symlink(version, filepath.Join(dir, "..data_tmp"))).To(Succeed())
+		Expect(os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data"))).To(Succeed())
+	}
+
+	ginkgo.It("reloads the token when the kubelet swaps the volume symlink", func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		writeVersion(dir, "..2024_01_01", "first")
+		Expect(os.Symlink(filepath.Join("..data", "token"), filepath.Join(dir, "token"))).To(Succeed())
+
+		reloads := make(chan error, 10)
+		file := &TokenFile{
+			Path:        filepath.Join(dir, "token"),
+			Credentials: &TokenFailoverTransport{},
+			OnReload:    func(err error) { reloads <- err },
+		}
+		_, err := file.Transport(context.Background(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Credentials.token(CredentialPrimary)).To(Equal("first"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- file.Start(ctx) }()
+		ginkgo.DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		// The watch is set up asynchronously: swap in new versions until a reload sees the new token.
+		versions := 0
+		Eventually(func() string {
+			versions++
+			writeVersion(dir, "..2024_01_02_"+strconv.Itoa(versions), "second")
+			return file.Credentials.token(CredentialPrimary)
+		}).Should(Equal("second"))
+		Eventually(reloads).Should(Receive(BeNil()))
+	})
+
+	ginkgo.It("keeps the previous token when the file can't be read", func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		path := filepath.Join(dir, "token")
+		Expect(os.WriteFile(path, []byte("first"), 0o600)).To(Succeed())
+		file := &TokenFile{Path: path, Credentials: &TokenFailoverTransport{}}
+		Expect(file.Load()).To(Succeed())
+
+		Expect(os.WriteFile(path, []byte("\n"), 0o600)).To(Succeed())
+		Expect(file.Load()).To(MatchError(ContainSubstring("is empty")))
+		Expect(file.Credentials.token(CredentialPrimary)).To(Equal("first"))
+	})
+})
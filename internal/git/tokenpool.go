@@ -0,0 +1,162 @@
+package git
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Strategies choosing the token of a TokenPoolTransport request.
+const (
+	// TokenPoolRoundRobin uses the tokens in turn.
+	TokenPoolRoundRobin = "round-robin"
+	// TokenPoolLeastDepleted uses the token with the most remaining quota. Tokens whose quota is not known
+	// yet are used first.
+	TokenPoolLeastDepleted = "least-depleted"
+)
+
+// TokenPoolTransport spreads requests over several tokens to spread the rate-limit consumption, tracking the
+// remaining quota GitHub reports for each. Exhausted tokens are skipped until their quota resets, and a
+// request rate limited on one token is retried on the next, so callers only see a rate limit once every
+// token is exhausted.
+type TokenPoolTransport struct {
+	Base     http.RoundTripper
+	Tokens   []string
+	Strategy string // Defaults to TokenPoolRoundRobin
+	// OnQuota is called with the quota GitHub reported for a token, named by PoolCredential. Optional.
+	OnQuota func(credential string, remaining int, resetAt time.Time)
+
+	mu     sync.Mutex
+	next   int
+	quotas map[int]tokenQuota
+}
+
+type tokenQuota struct {
+	remaining int
+	resetAt   time.Time
+}
+
+// PoolCredential names the token at index of a TokenPoolTransport, e.g. in metrics. Tokens are secrets, so
+// they are named by position.
+func PoolCredential(index int) string {
+	return fmt.Sprintf("pool-%d", index)
+}
+
+func (t *TokenPoolTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// exhausted reports whether the quota of the token at index is used up until after now.
+func (t *TokenPoolTransport) exhausted(index int, now time.Time) bool {
+	quota, ok := t.quotas[index]
+	return ok && quota.remaining == 0 && quota.resetAt.After(now)
+}
+
+// pick returns the index of the token for the next request, skipping tried tokens. It reports false once
+// every untried token is exhausted.
+func (t *TokenPoolTransport) pick(tried map[int]bool, now time.Time) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.Strategy == TokenPoolLeastDepleted {
+		best, bestRemaining := -1, -1
+		for index := range t.Tokens {
+			if tried[index] || t.exhausted(index, now) {
+				continue
+			}
+			remaining := math.MaxInt
+			if quota, ok := t.quotas[index]; ok && quota.resetAt.After(now) {
+				remaining = quota.remaining
+			}
+			if remaining > bestRemaining {
+				best, bestRemaining = index, remaining
+			}
+		}
+		return best, best >= 0
+	}
+
+	for range t.Tokens {
+		index := t.next % len(t.Tokens)
+		t.next++
+		if !tried[index] && !t.exhausted(index, now) {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// observe records the quota reported by resp for the token at index.
+func (t *TokenPoolTransport) observe(index int, resp *http.Response, now time.Time) {
+	quota := tokenQuota{remaining: -1}
+	if remaining, err := strconv.Atoi(resp.Header.Get(RateLimitRemainingHeader)); err == nil {
+		quota.remaining = remaining
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get(RateLimitResetHeader), 10, 64); err == nil {
+		quota.resetAt = time.Unix(reset, 0)
+	}
+	if resetAt, ok := RateLimitReset(resp, now); ok {
+		quota.remaining, quota.resetAt = 0, resetAt
+	}
+	if quota.remaining < 0 {
+		return
+	}
+
+	t.mu.Lock()
+	if t.quotas == nil {
+		t.quotas = map[int]tokenQuota{}
+	}
+	t.quotas[index] = quota
+	t.mu.Unlock()
+
+	if t.OnQuota != nil {
+		t.OnQuota(PoolCredential(index), quota.remaining, quota.resetAt)
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TokenPoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.Tokens) == 0 {
+		return nil, fmt.Errorf("token pool is empty")
+	}
+	tried := map[int]bool{}
+	index, ok := t.pick(tried, time.Now())
+	if !ok {
+		// Every token is exhausted: let GitHub report the rate limit.
+		index = 0
+	}
+	for {
+		tried[index] = true
+		attempt := req
+		if len(tried) > 1 {
+			attempt = req.Clone(req.Context())
+			if req.Body != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, fmt.Errorf("failed to replay request body: %v", err)
+				}
+				attempt.Body = body
+			}
+		}
+		resp, err := t.base().RoundTrip(authorize(attempt, t.Tokens[index]))
+		if err != nil {
+			return resp, err
+		}
+		now := time.Now()
+		t.observe(index, resp, now)
+		if _, limited := RateLimitReset(resp, now); !limited || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		next, ok := t.pick(tried, now)
+		if !ok {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		index = next
+	}
+}
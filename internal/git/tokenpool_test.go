@@ -0,0 +1,126 @@
+package git
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// quotaServer rate limits each token to its own quota, reporting it in the rate-limit headers.
+type quotaServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	quotas  map[string]int
+	resetAt time.Time
+	seen    []string
+	bodies  []string
+}
+
+func newQuotaServer(quotas map[string]int) *quotaServer {
+	server := &quotaServer{quotas: quotas, resetAt: time.Now().Add(time.Hour)}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		body, _ := io.ReadAll(r.Body)
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		server.seen = append(server.seen, token)
+		server.bodies = append(server.bodies, string(body))
+		w.Header().Set(RateLimitResetHeader, strconv.FormatInt(server.resetAt.Unix(), 10))
+		if server.quotas[token] == 0 {
+			w.Header().Set(RateLimitRemainingHeader, "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		server.quotas[token]--
+		w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(server.quotas[token]))
+	}))
+	ginkgo.DeferCleanup(server.Close)
+	return server
+}
+
+var _ = ginkgo.Describe("TokenPoolTransport", func() {
+	send := func(transport http.RoundTripper, req *http.Request) int {
+		resp, err := transport.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	get := func(transport http.RoundTripper, url string) int {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		Expect(err).NotTo(HaveOccurred())
+		return send(transport, req)
+	}
+
+	ginkgo.It("uses the tokens in turn", func() {
+		server := newQuotaServer(map[string]int{"a": 10, "b": 10, "c": 10})
+		quotas := map[string]int{}
+		transport := &TokenPoolTransport{
+			Tokens: []string{"a", "b", "c"},
+			OnQuota: func(credential string, remaining int, _ time.Time) {
+				quotas[credential] = remaining
+			},
+		}
+
+		for range 4 {
+			Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+		}
+		Expect(server.seen).To(Equal([]string{"a", "b", "c", "a"}))
+		Expect(quotas).To(Equal(map[string]int{"pool-0": 8, "pool-1": 9, "pool-2": 9}))
+	})
+
+	ginkgo.It("uses the token with the most remaining quota", func() {
+		server := newQuotaServer(map[string]int{"a": 5, "b": 10})
+		transport := &TokenPoolTransport{Tokens: []string{"a", "b"}, Strategy: TokenPoolLeastDepleted}
+
+		for range 3 {
+			Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+		}
+		Expect(server.seen).To(Equal([]string{"a", "b", "b"}))
+	})
+
+	ginkgo.It("retries a rate limited request on the next token, replaying its body", func() {
+		server := newQuotaServer(map[string]int{"a": 0, "b": 10})
+		transport := &TokenPoolTransport{Tokens: []string{"a", "b"}}
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"title":"t"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(send(transport, req)).To(Equal(http.StatusOK))
+		Expect(server.seen).To(Equal([]string{"a", "b"}))
+		Expect(server.bodies).To(Equal([]string{`{"title":"t"}`, `{"title":"t"}`}))
+
+		Expect(get(transport, server.URL)).To(Equal(http.StatusOK))
+		Expect(server.seen[2:]).To(Equal([]string{"b"}))
+	})
+
+	ginkgo.It("returns the rate limit once every token is exhausted", func() {
+		server := newQuotaServer(map[string]int{"a": 0, "b": 0})
+		for _, strategy := range []string{TokenPoolRoundRobin, TokenPoolLeastDepleted} {
+			server.seen = nil
+			transport := &TokenPoolTransport{Tokens: []string{"a", "b"}, Strategy: strategy}
+
+			Expect(get(transport, server.URL)).To(Equal(http.StatusForbidden))
+			Expect(server.seen).To(Equal([]string{"a", "b"}), strategy)
+			Expect(get(transport, server.URL)).To(Equal(http.StatusForbidden))
+			Expect(server.seen[2:]).To(Equal([]string{"a"}), strategy)
+		}
+	})
+
+	ginkgo.It("doesn't retry a request whose body can't be replayed", func() {
+		server := newQuotaServer(map[string]int{"a": 0, "b": 10})
+		transport := &TokenPoolTransport{Tokens: []string{"a", "b"}}
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Body = io.NopCloser(strings.NewReader(`{"title":"t"}`))
+		Expect(send(transport, req)).To(Equal(http.StatusForbidden))
+		Expect(server.seen).To(Equal([]string{"a"}))
+	})
+})
@@ -0,0 +1,66 @@
+package labels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priorities accepted by spec.priority, from highest to lowest.
+var Priorities = []string{"critical", "high", "medium", "low"}
+
+// PriorityLabels maps each priority to the label applied for it.
+type PriorityLabels map[string]string
+
+// DefaultPriorityLabels is used when no mapping is configured.
+var DefaultPriorityLabels = PriorityLabels{
+	"critical": "priority/P0",
+	"high":     "priority/P1",
+	"medium":   "priority/P2",
+	"low":      "priority/P3",
+}
+
+// ParsePriorityLabels parses a comma separated priority=label list, such as "critical=P0,high=P1".
+// Priorities left out keep their default label.
+func ParsePriorityLabels(value string) (PriorityLabels, error) {
+	mapping := PriorityLabels{}
+	for priority, label := range DefaultPriorityLabels {
+		mapping[priority] = label
+	}
+	if value == "" {
+		return mapping, nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		priority, label, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || label == "" {
+			return nil, fmt.Errorf("invalid priority label %q, expected priority=label", entry)
+		}
+		if _, known := DefaultPriorityLabels[priority]; !known {
+			return nil, fmt.Errorf("unknown priority %q, expected one of %s", priority, strings.Join(Priorities, ", "))
+		}
+		mapping[priority] = label
+	}
+	return mapping, nil
+}
+
+// Label returns the label for priority, empty for an unset priority.
+func (p PriorityLabels) Label(priority string) string {
+	return p[priority]
+}
+
+// Stale returns the priority labels of current that don't belong to priority.
+func (p PriorityLabels) Stale(priority string, current []string) []string {
+	want := p.Label(priority)
+	var stale []string
+	for _, label := range current {
+		if label == want {
+			continue
+		}
+		for _, mapped := range p {
+			if label == mapped {
+				stale = append(stale, label)
+				break
+			}
+		}
+	}
+	return stale
+}
@@ -0,0 +1,28 @@
+package labels
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PriorityLabels", func() {
+	It("keeps the defaults for priorities left out", func() {
+		mapping, err := ParsePriorityLabels("critical=sev1, high=sev2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mapping.Label("critical")).To(Equal("sev1"))
+		Expect(mapping.Label("high")).To(Equal("sev2"))
+		Expect(mapping.Label("low")).To(Equal("priority/P3"))
+		Expect(mapping.Label("")).To(BeEmpty())
+	})
+
+	It("rejects unknown priorities and malformed entries", func() {
+		_, err := ParsePriorityLabels("urgent=P0")
+		Expect(err).To(MatchError(ContainSubstring("unknown priority")))
+		_, err = ParsePriorityLabels("critical")
+		Expect(err).To(MatchError(ContainSubstring("expected priority=label")))
+	})
+
+	It("finds the labels of other priorities", func() {
+		Expect(DefaultPriorityLabels.Stale("high", []string{"bug", "priority/P1", "priority/P3"})).To(Equal([]string{"priority/P3"}))
+	})
+})
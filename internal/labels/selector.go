@@ -0,0 +1,47 @@
+package labels
+
+import (
+	"slices"
+	"strings"
+)
+
+// Selector selects labels by name. Entries ending with "*" select every label starting with the rest of the entry.
+type Selector []string
+
+// ParseSelector parses a comma separated list of label names and prefixes, such as "priority/*,wontfix".
+// An empty value selects nothing.
+func ParseSelector(value string) Selector {
+	var selector Selector
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			selector = append(selector, entry)
+		}
+	}
+	return selector
+}
+
+// Matches reports whether label is selected.
+func (s Selector) Matches(label string) bool {
+	for _, entry := range s {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if strings.HasPrefix(label, prefix) {
+				return true
+			}
+		} else if label == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the selected labels, sorted and without duplicates.
+func (s Selector) Select(labels []string) []string {
+	var selected []string
+	for _, label := range labels {
+		if s.Matches(label) {
+			selected = append(selected, label)
+		}
+	}
+	slices.Sort(selected)
+	return slices.Compact(selected)
+}
@@ -0,0 +1,19 @@
+package labels
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Selector", func() {
+	It("selects labels by name and by prefix", func() {
+		selector := ParseSelector(" priority/*, wontfix ,")
+		Expect(selector).To(Equal(Selector{"priority/*", "wontfix"}))
+		Expect(selector.Select([]string{"wontfix", "bug", "priority/P1", "wontfix", "area/priority"})).
+			To(Equal([]string{"priority/P1", "wontfix"}))
+	})
+
+	It("selects nothing when empty", func() {
+		Expect(ParseSelector("").Select([]string{"bug"})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,96 @@
+// Package labels holds the operator-wide label taxonomy that spec labels are checked against
+// and the mapping of spec.priority to labels.
+package labels
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Taxonomy restricts the labels GithubIssues may request. A nil Taxonomy allows every label.
+type Taxonomy struct {
+	// Prefixes lists allowed label prefixes, e.g. "area/".
+	Prefixes []string `json:"prefixes,omitempty"`
+	// Patterns lists regular expressions an allowed label must fully match.
+	Patterns []string `json:"patterns,omitempty"`
+	// AllowCreate lets the operator apply labels that don't exist in the repository yet,
+	// which makes GitHub create them. When false, such labels are skipped.
+	AllowCreate bool `json:"allowCreate,omitempty"`
+
+	compiled []*regexp.Regexp
+}
+
+// New returns a taxonomy allowing the given prefixes and patterns.
+func New(prefixes, patterns []string, allowCreate bool) (*Taxonomy, error) {
+	taxonomy := &Taxonomy{Prefixes: prefixes, Patterns: patterns, AllowCreate: allowCreate}
+	if err := taxonomy.compile(); err != nil {
+		return nil, err
+	}
+	return taxonomy, nil
+}
+
+// Load reads a taxonomy from a YAML file.
+func Load(path string) (*Taxonomy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read label taxonomy: %v", err)
+	}
+	taxonomy := &Taxonomy{}
+	if err := yaml.UnmarshalStrict(content, taxonomy); err != nil {
+		return nil, fmt.Errorf("failed to parse label taxonomy: %v", err)
+	}
+	if err := taxonomy.compile(); err != nil {
+		return nil, err
+	}
+	return taxonomy, nil
+}
+
+func (t *Taxonomy) compile() error {
+	t.compiled = nil
+	for _, pattern := range t.Patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid label pattern %q: %v", pattern, err)
+		}
+		t.compiled = append(t.compiled, re)
+	}
+	return nil
+}
+
+// Allows reports whether label belongs to the taxonomy. A taxonomy without prefixes and patterns allows every label.
+func (t *Taxonomy) Allows(label string) bool {
+	if t == nil || (len(t.Prefixes) == 0 && len(t.Patterns) == 0) {
+		return true
+	}
+	for _, prefix := range t.Prefixes {
+		if strings.HasPrefix(label, prefix) {
+			return true
+		}
+	}
+	for _, re := range t.compiled {
+		if re.MatchString(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// Disallowed returns the labels outside the taxonomy.
+func (t *Taxonomy) Disallowed(labels []string) []string {
+	var disallowed []string
+	for _, label := range labels {
+		if !t.Allows(label) {
+			disallowed = append(disallowed, label)
+		}
+	}
+	return disallowed
+}
+
+// CanCreate reports whether labels missing from the repository may be created by applying them.
+func (t *Taxonomy) CanCreate() bool {
+	return t == nil || t.AllowCreate
+}
@@ -0,0 +1,43 @@
+package labels
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Taxonomy", func() {
+	It("allows every label when unset", func() {
+		var taxonomy *Taxonomy
+		Expect(taxonomy.Allows("anything")).To(BeTrue())
+		Expect(taxonomy.CanCreate()).To(BeTrue())
+	})
+
+	It("allows labels matching a prefix or a full pattern", func() {
+		taxonomy, err := New([]string{"area/"}, []string{"priority/p[0-3]"}, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(taxonomy.Disallowed([]string{"area/api", "priority/p1", "priority/p10", "bug"})).
+			To(ConsistOf("priority/p10", "bug"))
+		Expect(taxonomy.CanCreate()).To(BeFalse())
+	})
+
+	It("loads the taxonomy from a file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "taxonomy.yaml")
+		Expect(os.WriteFile(path, []byte("prefixes: [kind/]\npatterns: ['good first issue']\nallowCreate: true\n"), 0o600)).To(Succeed())
+
+		taxonomy, err := Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taxonomy.Allows("kind/bug")).To(BeTrue())
+		Expect(taxonomy.Allows("good first issue")).To(BeTrue())
+		Expect(taxonomy.Allows("wontfix")).To(BeFalse())
+		Expect(taxonomy.CanCreate()).To(BeTrue())
+	})
+
+	It("rejects invalid patterns", func() {
+		_, err := New(nil, []string{"("}, false)
+		Expect(err).To(HaveOccurred())
+	})
+})
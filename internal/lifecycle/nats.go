@@ -0,0 +1,169 @@
+package lifecycle
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const natsTimeout = 5 * time.Second
+
+// NATSPublisher publishes events on "<Subject>.<type>" using the NATS core text protocol.
+// The connection is opened lazily and reopened after a failed write or once the server closed it.
+// An -ERR the server sends after a publish fails the next one.
+type NATSPublisher struct {
+	Address string
+	Subject string
+	// User and Password, or Token, authenticate the connection. Optional.
+	User     string
+	Password string
+	Token    string
+
+	mu   sync.Mutex
+	conn net.Conn
+	err  error
+}
+
+// natsConnect is the CONNECT message of the NATS protocol.
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+// Publish implements Publisher.
+func (p *NATSPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %v", err)
+	}
+	message := fmt.Sprintf("PUB %s.%s %d\r\n%s\r\n", p.Subject, event.Type, len(payload), payload)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		err := p.err
+		p.err = nil
+		return err
+	}
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	deadline := time.Now().Add(natsTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = p.conn.SetWriteDeadline(deadline)
+	if _, err := p.conn.Write([]byte(message)); err != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+		return fmt.Errorf("failed to publish event: %v", err)
+	}
+	return nil
+}
+
+// connect opens the connection, waiting for the PONG of a PING sent after CONNECT so a rejected CONNECT
+// fails here, and answers server PINGs in the background so it is kept alive.
+func (p *NATSPublisher) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: natsTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.Address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	_ = conn.SetDeadline(time.Now().Add(natsTimeout))
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO") {
+		_ = conn.Close()
+		return fmt.Errorf("failed to read NATS server info: %v", err)
+	}
+
+	connect, _ := json.Marshal(natsConnect{
+		Name:      "github-issue-operator",
+		User:      p.User,
+		Pass:      p.Password,
+		AuthToken: p.Token,
+	})
+	if _, err := conn.Write([]byte("CONNECT " + string(connect) + "\r\nPING\r\n")); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to connect to NATS: %v", err)
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("failed to connect to NATS: %v", err)
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			_ = conn.Close()
+			return fmt.Errorf("NATS rejected the connection: %s", natsError(line))
+		}
+		if strings.HasPrefix(line, "PONG") {
+			break
+		}
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	p.conn = conn
+	go p.keepAlive(conn, reader)
+	return nil
+}
+
+// keepAlive answers the PINGs of the server and records its -ERR messages, which fail the next Publish. The
+// connection is dropped once the server closed it.
+func (p *NATSPublisher) keepAlive(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			p.mu.Lock()
+			if p.conn == conn {
+				_ = conn.Close()
+				p.conn = nil
+			}
+			p.mu.Unlock()
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			p.mu.Lock()
+			_, _ = conn.Write([]byte("PONG\r\n"))
+			p.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			p.mu.Lock()
+			if p.conn == conn {
+				p.err = fmt.Errorf("NATS server error: %s", natsError(line))
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// natsError returns the message of an -ERR line.
+func natsError(line string) string {
+	return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'")
+}
+
+// Close closes the connection.
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
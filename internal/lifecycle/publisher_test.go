@@ -0,0 +1,119 @@
+package lifecycle
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeNATS accepts a single connection, answers its PINGs and forwards the other lines it receives. reply
+// returns the lines sent back to a received line, e.g. an -ERR.
+func fakeNATS(reply func(line string) []string) (string, chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(listener.Close)
+
+	lines := make(chan string, 10)
+	go func() {
+		defer GinkgoRecover()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("INFO {}\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			if line == "PING" {
+				_, _ = conn.Write([]byte("PONG\r\n"))
+				continue
+			}
+			select {
+			case lines <- line:
+			default:
+			}
+			if reply != nil {
+				for _, answer := range reply(line) {
+					_, _ = conn.Write([]byte(answer + "\r\n"))
+				}
+			}
+		}
+	}()
+	return listener.Addr().String(), lines
+}
+
+var _ = Describe("Publisher", func() {
+	newPublisher := func(busURL string) *NATSPublisher {
+		publisher, err := NewPublisher(busURL, "issues")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(publisher.(*NATSPublisher).Close)
+		return publisher.(*NATSPublisher)
+	}
+
+	It("rejects unsupported buses", func() {
+		_, err := NewPublisher("kafka://broker:9092", "issues")
+		Expect(err).To(MatchError(ContainSubstring("unsupported")))
+	})
+
+	It("publishes events on a NATS subject per event type", func() {
+		address, lines := fakeNATS(nil)
+		publisher := newPublisher("nats://" + address)
+
+		Expect(publisher.Publish(context.Background(), Event{Type: Created, Namespace: "default", Name: "a"})).To(Succeed())
+
+		Eventually(lines).Should(Receive(HavePrefix("CONNECT")))
+		Eventually(lines).Should(Receive(HavePrefix("PUB issues.created ")))
+		Eventually(lines).Should(Receive(ContainSubstring(`"name":"a"`)))
+	})
+
+	It("passes the credentials of the URL in CONNECT", func() {
+		address, lines := fakeNATS(nil)
+		publisher := newPublisher("nats://alice:secret@" + address)
+		Expect(publisher.Publish(context.Background(), Event{Type: Created})).To(Succeed())
+		Eventually(lines).Should(Receive(SatisfyAll(
+			ContainSubstring(`"user":"alice"`), ContainSubstring(`"pass":"secret"`), Not(ContainSubstring("auth_token")))))
+
+		address, lines = fakeNATS(nil)
+		publisher = newPublisher("nats://s3cr3t@" + address)
+		Expect(publisher.Publish(context.Background(), Event{Type: Created})).To(Succeed())
+		Eventually(lines).Should(Receive(SatisfyAll(ContainSubstring(`"auth_token":"s3cr3t"`), Not(ContainSubstring(`"user"`)))))
+	})
+
+	It("fails when the server rejects the connection", func() {
+		address, _ := fakeNATS(func(line string) []string {
+			if strings.HasPrefix(line, "CONNECT") {
+				return []string{"-ERR 'Authorization Violation'"}
+			}
+			return nil
+		})
+		publisher := newPublisher("nats://wrong@" + address)
+
+		err := publisher.Publish(context.Background(), Event{Type: Created})
+		Expect(err).To(MatchError("NATS rejected the connection: Authorization Violation"))
+	})
+
+	It("surfaces the errors the server sends after a publish", func() {
+		address, lines := fakeNATS(func(line string) []string {
+			if strings.HasPrefix(line, "PUB") {
+				return []string{"-ERR 'Permissions Violation for Publish to issues.created'"}
+			}
+			return nil
+		})
+		publisher := newPublisher("nats://" + address)
+
+		Expect(publisher.Publish(context.Background(), Event{Type: Created})).To(Succeed())
+		Eventually(lines).Should(Receive(HavePrefix("PUB")))
+		Eventually(func() error {
+			return publisher.Publish(context.Background(), Event{Type: Synced})
+		}).Should(MatchError("NATS server error: Permissions Violation for Publish to issues.created"))
+	})
+})
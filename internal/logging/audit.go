@@ -0,0 +1,23 @@
+package logging
+
+import "go.uber.org/zap"
+
+// AuditSecretRead records a Secret read in audit, naming the resource it was read for, so every access to
+// credentials can be reviewed. A nil audit logger records nothing.
+func AuditSecretRead(audit *zap.Logger, consumerKind, consumer, secret, key, purpose string, err error) {
+	if audit == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("consumer.kind", consumerKind),
+		zap.String("consumer.name", consumer),
+		zap.String("secret", secret),
+		zap.String("key", key),
+		zap.String("purpose", purpose),
+	}
+	if err != nil {
+		audit.Warn("Secret read failed", append(fields, zap.Error(err))...)
+		return
+	}
+	audit.Info("Secret read", fields...)
+}
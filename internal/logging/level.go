@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LevelServer serves the level handler of Logger at LevelPath on its own address. Changing the level is
+// unauthenticated, so it is kept off the metrics server and meant to be bound to localhost and reached
+// through a port-forward.
+type LevelServer struct {
+	Logger *Logger
+	// Addr is the address the server listens on.
+	Addr string
+}
+
+// Start serves the level handler until ctx is done.
+func (s *LevelServer) Start(ctx context.Context) error {
+	server := &http.Server{Addr: s.Addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			s.Logger.Error("Failed to shut down log level server", zap.Error(err))
+		}
+	}()
+	s.Logger.Info("Starting log level server", zap.String("address", s.Addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve the log level: %v", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection allows the level of every replica to be changed.
+func (s *LevelServer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *LevelServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != LevelPath {
+		http.NotFound(w, req)
+		return
+	}
+	s.Logger.Level.ServeHTTP(w, req)
+}
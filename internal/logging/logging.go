@@ -0,0 +1,105 @@
+// Package logging builds the operator's ECS formatted zap logger with a runtime adjustable level.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.elastic.co/ecszap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelPath is the HTTP path serving the log level handler.
+// GET returns the current level, PUT with {"level":"info"} changes it.
+const LevelPath = "/loglevel"
+
+// Options configures the operator logger.
+type Options struct {
+	// Level is the initial minimum enabled level.
+	Level string
+	// SamplingInitial is the number of identical entries logged per second before sampling kicks in.
+	// Zero disables sampling.
+	SamplingInitial int
+	// SamplingThereafter logs every Nth identical entry once SamplingInitial is exceeded.
+	SamplingThereafter int
+}
+
+// Logger is the operator logger together with the handle used to change its level at runtime.
+type Logger struct {
+	*zap.Logger
+	Level zap.AtomicLevel
+
+	baseLevel zapcore.Level
+}
+
+// New builds an ECS formatted logger writing to stdout.
+func New(opts Options) (*Logger, error) {
+	baseLevel, err := zapcore.ParseLevel(opts.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", opts.Level, err)
+	}
+	level := zap.NewAtomicLevelAt(baseLevel)
+
+	encoderConfig := ecszap.NewDefaultEncoderConfig()
+	core := ecszap.NewCore(encoderConfig, os.Stdout, level)
+	if opts.SamplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opts.SamplingInitial, opts.SamplingThereafter)
+	}
+
+	return &Logger{
+		Logger:    zap.New(core, zap.AddCaller()),
+		Level:     level,
+		baseLevel: baseLevel,
+	}, nil
+}
+
+// WatchSignals toggles the log level between debug and the configured level on every SIGUSR1
+// until ctx is done.
+func (l *Logger) WatchSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				next := zapcore.DebugLevel
+				if l.Level.Level() == zapcore.DebugLevel {
+					next = l.baseLevel
+				}
+				l.Level.SetLevel(next)
+				l.Info("Log level changed by signal", zap.String("level", next.String()))
+			}
+		}
+	}()
+}
+
+// Logr returns the logger as a logr.Logger for controller-runtime and other logr consumers.
+func (l *Logger) Logr() logr.Logger {
+	return zapr.NewLogger(l.Logger)
+}
+
+type contextKey struct{}
+
+// IntoContext returns a copy of ctx carrying logger.
+func IntoContext(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or fallback when ctx carries none.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*zap.Logger); ok && logger != nil {
+		return logger
+	}
+	return fallback
+}
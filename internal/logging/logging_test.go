@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ = Describe("Logger", func() {
+	It("starts at the configured level", func() {
+		logger, err := New(Options{Level: "warn", SamplingInitial: 10, SamplingThereafter: 100})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logger.Level.Level()).To(Equal(zapcore.WarnLevel))
+		Expect(logger.Core().Enabled(zapcore.InfoLevel)).To(BeFalse())
+		Expect(logger.Core().Enabled(zapcore.ErrorLevel)).To(BeTrue())
+	})
+
+	It("rejects an unknown level", func() {
+		_, err := New(Options{Level: "verbose"})
+		Expect(err).To(MatchError(ContainSubstring(`invalid log level "verbose"`)))
+	})
+})
+
+var _ = Describe("LevelServer", func() {
+	var (
+		logger *Logger
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		logger, err = New(Options{Level: "info"})
+		Expect(err).NotTo(HaveOccurred())
+		server = httptest.NewServer(&LevelServer{Logger: logger})
+		DeferCleanup(server.Close)
+	})
+
+	send := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return resp.StatusCode, string(content)
+	}
+
+	It("returns the current level", func() {
+		status, body := send(http.MethodGet, LevelPath, "")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"level":"info"}`))
+	})
+
+	It("changes the level", func() {
+		status, body := send(http.MethodPut, LevelPath, `{"level":"debug"}`)
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"level":"debug"}`))
+		Expect(logger.Level.Level()).To(Equal(zapcore.DebugLevel))
+	})
+
+	It("keeps the level when the new one is invalid", func() {
+		status, _ := send(http.MethodPut, LevelPath, `{"level":"verbose"}`)
+		Expect(status).To(Equal(http.StatusBadRequest))
+		Expect(logger.Level.Level()).To(Equal(zapcore.InfoLevel))
+	})
+
+	It("serves nothing but the level", func() {
+		status, _ := send(http.MethodGet, "/metrics", "")
+		Expect(status).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Transport is an http.RoundTripper middleware that logs every outgoing request with the
+// contextual logger of the request, falling back to Log.
+type Transport struct {
+	Base http.RoundTripper
+	Log  *zap.Logger
+}
+
+// NewTransport wraps base, defaulting to http.DefaultTransport.
+func NewTransport(base http.RoundTripper, log *zap.Logger) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Log: log}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := FromContext(req.Context(), t.Log)
+	start := time.Now()
+
+	resp, err := t.Base.RoundTrip(req)
+	fields := []zap.Field{
+		zap.String("http.request.method", req.Method),
+		zap.String("url.path", req.URL.Path),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		log.Debug("HTTP request failed", append(fields, zap.Error(err))...)
+		return resp, err
+	}
+
+	log.Debug("HTTP request completed", append(fields, zap.Int("http.response.status_code", resp.StatusCode))...)
+	return resp, nil
+}
@@ -0,0 +1,192 @@
+// Package metrics defines the operator's Prometheus metrics, registered with the controller-runtime registry
+// so they are served on the manager's metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "githubissue"
+
+var (
+	// TriageIssues is the number of managed issues per triage label. Issues without a triage label use "none".
+	TriageIssues = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "triage_issues",
+		Help:      "Number of managed issues by triage label.",
+	}, []string{"label"})
+
+	// DeprecatedFieldUsage counts admission requests that set a deprecated field.
+	DeprecatedFieldUsage = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "deprecated_field_usage_total",
+		Help:      "Number of admission requests setting a deprecated field, by kind and field.",
+	}, []string{"kind", "field"})
+
+	// ActiveCredential is 1 for the GitHub credential currently used and 0 for the other.
+	ActiveCredential = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_credential",
+		Help:      "GitHub credential currently used to authenticate, by credential (primary or secondary).",
+	}, []string{"credential"})
+
+	// ReconcileTriggers counts GithubIssue reconciles by the event that caused them.
+	ReconcileTriggers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_triggers_total",
+		Help:      "Number of GithubIssue reconciles by cause (generation, resync, webhook, annotation, deletion, reference, other, requeue).",
+	}, []string{"cause"})
+
+	// DuplicateIssuesClosed counts upstream duplicates closed by the duplicate cleanup job.
+	DuplicateIssuesClosed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_issues_closed_total",
+		Help:      "Number of duplicate upstream issues closed by the cleanup job, by repository.",
+	}, []string{"repository"})
+
+	// DuplicateCleanupRuns counts duplicate cleanup passes by result.
+	DuplicateCleanupRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_cleanup_runs_total",
+		Help:      "Number of duplicate cleanup passes, by result (success or error).",
+	}, []string{"result"})
+
+	// ReconcilePhaseDuration is the time GithubIssue reconciles spend per phase.
+	ReconcilePhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_phase_duration_seconds",
+		Help:      "Time spent per GithubIssue reconcile phase (find, fetch, backoff, create, edit, status).",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"phase"})
+
+	// TokenQuotaRemaining is the rate-limit quota GitHub last reported for each token of the token pool.
+	TokenQuotaRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "github_token_quota_remaining",
+		Help:      "Requests left in the current rate-limit window, per token of the token pool.",
+	}, []string{"credential"})
+
+	// TokenExpiry is the time left, as of the last GitHub response, before a GitHub token expires.
+	TokenExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "github_token_expiry_seconds",
+		Help:      "Seconds until the GitHub token expires, per credential. Absent for tokens that never expire.",
+	}, []string{"credential"})
+
+	// WebhookDeliveries counts GitHub webhook deliveries by result
+	// (accepted, invalid_signature, unknown_repository, error).
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "GitHub webhook deliveries received, by result.",
+	}, []string{"result"})
+
+	// UnknownUpstreamStates counts upstream issues observed with a state or state reason the operator does not know,
+	// an early sign of an upstream API change.
+	UnknownUpstreamStates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "unknown_upstream_states_total",
+		Help:      "Upstream issues observed with an unknown value, by field (state or state_reason) and value.",
+	}, []string{"field", "value"})
+
+	// ClientPoolClients is the number of pooled GitHub clients, one per credential, by host.
+	ClientPoolClients = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "client_pool_clients",
+		Help:      "Number of pooled GitHub clients for per-issue credentials, by host.",
+	}, []string{"host"})
+
+	// ClientPoolRequests counts the requests sent by pooled GitHub clients by host.
+	ClientPoolRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "client_pool_requests_total",
+		Help:      "Requests sent by pooled GitHub clients for per-issue credentials, by host.",
+	}, []string{"host"})
+
+	// StatusWriteFallbacks counts the conditions reported through events because the status subresource could not
+	// be written, by condition type and status.
+	StatusWriteFallbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "status_write_fallbacks_total",
+		Help:      "Conditions reported through an event after repeated status write failures, by type and status.",
+	}, []string{"type", "status"})
+
+	// PendingReconciles is the number of GithubIssue reconciles queued and not yet started, by repository.
+	PendingReconciles = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pending_reconciles",
+		Help:      "GithubIssue reconciles queued and not yet started, by repository.",
+	}, []string{"repository"})
+
+	// InFlightReconciles is the number of GithubIssue reconciles in progress, by repository.
+	InFlightReconciles = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "inflight_reconciles",
+		Help:      "GithubIssue reconciles in progress, by repository.",
+	}, []string{"repository"})
+
+	// PendingStatusWrites is the number of GithubIssue statuses waiting to be written again after a failed write,
+	// by repository.
+	PendingStatusWrites = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pending_status_writes",
+		Help:      "GithubIssue statuses waiting to be written again after a failed write, by repository.",
+	}, []string{"repository"})
+
+	// AlertNotifications counts the alerts received from Alertmanager by status (firing, resolved) and
+	// result (applied, error).
+	AlertNotifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alert_notifications_total",
+		Help:      "Alerts received from Alertmanager, by status and result.",
+	}, []string{"status", "result"})
+
+	// SyncLatency is the time from a GithubIssue spec change to its upstream issue reflecting it, by repository.
+	// Its buckets are set with SetSyncLatencyBuckets.
+	SyncLatency = newSyncLatency(DefaultSyncLatencyBuckets)
+)
+
+// DefaultSyncLatencyBuckets are the SyncLatency buckets, in seconds, unless SetSyncLatencyBuckets replaces them.
+var DefaultSyncLatencyBuckets = []float64{1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800}
+
+func newSyncLatency(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sync_latency_seconds",
+		Help:      "Time from a GithubIssue spec change (generation bump) to its upstream issue reflecting it, by repository.",
+		Buckets:   buckets,
+	}, []string{"repository"})
+}
+
+// SetSyncLatencyBuckets replaces the SyncLatency buckets, in seconds, so the histogram resolves the SLO of the
+// installation. It must be called before the first observation, since the recorded samples are dropped.
+func SetSyncLatencyBuckets(buckets []float64) {
+	metrics.Registry.Unregister(SyncLatency)
+	SyncLatency = newSyncLatency(buckets)
+	metrics.Registry.MustRegister(SyncLatency)
+}
+
+func init() {
+	metrics.Registry.MustRegister(
+		TriageIssues,
+		DeprecatedFieldUsage,
+		ActiveCredential,
+		ReconcileTriggers,
+		DuplicateIssuesClosed,
+		DuplicateCleanupRuns,
+		ReconcilePhaseDuration,
+		WebhookDeliveries,
+		TokenExpiry,
+		TokenQuotaRemaining,
+		UnknownUpstreamStates,
+		ClientPoolClients,
+		ClientPoolRequests,
+		StatusWriteFallbacks,
+		PendingReconciles,
+		InFlightReconciles,
+		PendingStatusWrites,
+		AlertNotifications,
+		SyncLatency,
+	)
+}
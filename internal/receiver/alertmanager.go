@@ -0,0 +1,209 @@
+package receiver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// AlertFingerprintLabel is set on the GithubIssues filed for Alertmanager alerts.
+	AlertFingerprintLabel = "issues.dana.io/alert-fingerprint"
+	// AlertRepoAnnotation on an alert files its issue in that repository instead of the default one.
+	AlertRepoAnnotation = "github_repo"
+
+	// maxAlertPayloadSize bounds the notifications read; Alertmanager groups rarely come close.
+	maxAlertPayloadSize = 4 << 20
+)
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubissues,verbs=get;list;watch;create;update;patch;delete
+
+// AlertReceiver accepts Alertmanager webhook notifications and keeps a GithubIssue per alert fingerprint:
+// a firing alert creates or updates it, a resolved alert deletes it, which closes its upstream issue.
+// Notifications repeated by Alertmanager update the same GithubIssue, so an alert is tracked by a single issue.
+type AlertReceiver struct {
+	Client client.Client
+	// Addr is the address the receiver listens on.
+	Addr string
+	// Namespace the GithubIssues are created in.
+	Namespace string
+	// Repo is the repository URL of the issues of the alerts without the AlertRepoAnnotation.
+	Repo string
+	// BearerToken, when set, must be sent by Alertmanager in the Authorization header.
+	BearerToken string
+	Log         *zap.Logger
+}
+
+// alertNotification is the Alertmanager webhook payload, version 4.
+type alertNotification struct {
+	Version string  `json:"version"`
+	Status  string  `json:"status"`
+	Alerts  []alert `json:"alerts"`
+}
+
+type alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Start serves notifications until ctx is done. It implements manager.Runnable.
+func (r *AlertReceiver) Start(ctx context.Context) error {
+	server := &http.Server{Addr: r.Addr, Handler: r, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			r.Log.Error("Failed to shut down Alertmanager receiver", zap.Error(err))
+		}
+	}()
+	r.Log.Info("Starting Alertmanager receiver", zap.String("address", r.Addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve Alertmanager notifications: %v", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection allows every replica to receive notifications. Applying an alert is idempotent.
+func (r *AlertReceiver) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP applies every alert of a notification. A failure answers 500 so Alertmanager retries the notification.
+func (r *AlertReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.BearerToken != "" {
+		token, _ := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(r.BearerToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+	var notification alertNotification
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxAlertPayloadSize)).Decode(&notification); err != nil {
+		http.Error(w, "malformed notification", http.StatusBadRequest)
+		return
+	}
+
+	var failed int
+	for _, alert := range notification.Alerts {
+		if err := r.apply(req.Context(), alert); err != nil {
+			r.Log.Error("Failed to apply alert", zap.String("fingerprint", alert.Fingerprint),
+				zap.String("alertname", alert.Labels["alertname"]), zap.Error(err))
+			metrics.AlertNotifications.WithLabelValues(alert.Status, "error").Inc()
+			failed++
+			continue
+		}
+		metrics.AlertNotifications.WithLabelValues(alert.Status, "applied").Inc()
+	}
+	if failed > 0 {
+		http.Error(w, fmt.Sprintf("failed to apply %d of %d alerts", failed, len(notification.Alerts)), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// apply creates or updates the GithubIssue of a firing alert and deletes the GithubIssue of a resolved one.
+func (r *AlertReceiver) apply(ctx context.Context, alert alert) error {
+	if alert.Fingerprint == "" {
+		return fmt.Errorf("alert has no fingerprint")
+	}
+	key := types.NamespacedName{Namespace: r.Namespace, Name: "alert-" + strings.ToLower(alert.Fingerprint)}
+	if alert.Status == "resolved" {
+		issueObject := &issuesv1alpha1.GithubIssue{}
+		if err := r.Client.Get(ctx, key, issueObject); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if issueObject.Labels[AlertFingerprintLabel] != alert.Fingerprint {
+			return nil
+		}
+		if err := r.Client.Delete(ctx, issueObject); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete GithubIssue %s: %v", key, err)
+		}
+		r.Log.Info("Resolved alert", zap.String("githubIssue", key.String()), zap.String("alertname", alert.Labels["alertname"]))
+		return nil
+	}
+
+	repo := r.Repo
+	if override := alert.Annotations[AlertRepoAnnotation]; override != "" {
+		repo = override
+	}
+	if repo == "" {
+		return fmt.Errorf("no repository for alert: set the %s annotation or a default repository", AlertRepoAnnotation)
+	}
+	issueObject := &issuesv1alpha1.GithubIssue{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, issueObject, func() error {
+		if !issueObject.DeletionTimestamp.IsZero() {
+			// The alert fired again while its previous issue is being closed. Alertmanager retries.
+			return fmt.Errorf("GithubIssue %s is being deleted", key)
+		}
+		if issueObject.Labels == nil {
+			issueObject.Labels = map[string]string{}
+		}
+		issueObject.Labels[AlertFingerprintLabel] = alert.Fingerprint
+		issueObject.Spec.Repo = repo
+		issueObject.Spec.Title = alertTitle(alert)
+		issueObject.Spec.Description = alertDescription(alert)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply GithubIssue %s: %v", key, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Log.Info("Applied firing alert", zap.String("githubIssue", key.String()), zap.String("operation", string(result)))
+	}
+	return nil
+}
+
+// alertTitle is the alert name followed by its summary annotation.
+func alertTitle(alert alert) string {
+	title := alert.Labels["alertname"]
+	if summary := alert.Annotations["summary"]; summary != "" {
+		title = fmt.Sprintf("[%s] %s", title, summary)
+	}
+	return title
+}
+
+// alertDescription renders the description annotation, when the alert started, and its labels.
+func alertDescription(alert alert) string {
+	var b strings.Builder
+	if description := alert.Annotations["description"]; description != "" {
+		b.WriteString(description + "\n\n")
+	}
+	fmt.Fprintf(&b, "**Firing since:** %s\n", alert.StartsAt.UTC().Format(time.RFC3339))
+	if alert.GeneratorURL != "" {
+		fmt.Fprintf(&b, "**Source:** %s\n", alert.GeneratorURL)
+	}
+	names := make([]string, 0, len(alert.Labels))
+	for name := range alert.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.WriteString("\n| Label | Value |\n| --- | --- |\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "| %s | %s |\n", name, alert.Labels[name])
+	}
+	return b.String()
+}
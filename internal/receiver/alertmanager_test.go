@@ -0,0 +1,77 @@
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+func notification(status string) string {
+	return `{"version":"4","status":"` + status + `","alerts":[{"status":"` + status + `",` +
+		`"labels":{"alertname":"HighLatency","service":"checkout"},` +
+		`"annotations":{"summary":"Checkout p99 above 2s","description":"Latency is high."},` +
+		`"startsAt":"2026-01-02T03:04:05Z","generatorURL":"http://prometheus/graph","fingerprint":"ABC123"}]}`
+}
+
+var _ = Describe("AlertReceiver", func() {
+	var (
+		receiver *AlertReceiver
+		key      = types.NamespacedName{Namespace: "alerts", Name: "alert-abc123"}
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(issuesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		receiver = &AlertReceiver{
+			Client:      fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Namespace:   "alerts",
+			Repo:        "https://github.com/org/oncall",
+			BearerToken: "token",
+			Log:         zap.NewNop(),
+		}
+	})
+
+	post := func(body, token string) int {
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		request.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	It("files one GithubIssue per fingerprint while the alert fires and deletes it once resolved", func() {
+		Expect(post(notification("firing"), "token")).To(Equal(http.StatusOK))
+		Expect(post(notification("firing"), "token")).To(Equal(http.StatusOK))
+		issueObject := &issuesv1alpha1.GithubIssue{}
+		Expect(receiver.Client.Get(context.Background(), key, issueObject)).To(Succeed())
+		Expect(issueObject.Labels).To(HaveKeyWithValue(AlertFingerprintLabel, "ABC123"))
+		Expect(issueObject.Spec.Repo).To(Equal("https://github.com/org/oncall"))
+		Expect(issueObject.Spec.Title).To(Equal("[HighLatency] Checkout p99 above 2s"))
+		Expect(issueObject.Spec.Description).To(ContainSubstring("Latency is high."))
+		Expect(issueObject.Spec.Description).To(ContainSubstring("| service | checkout |"))
+
+		Expect(post(notification("resolved"), "token")).To(Equal(http.StatusOK))
+		Expect(apierrors.IsNotFound(receiver.Client.Get(context.Background(), key, issueObject))).To(BeTrue())
+	})
+
+	It("rejects notifications without the bearer token and malformed ones", func() {
+		Expect(post(notification("firing"), "wrong")).To(Equal(http.StatusUnauthorized))
+		Expect(post("{", "token")).To(Equal(http.StatusBadRequest))
+	})
+
+	It("fails alerts without a repository so Alertmanager retries", func() {
+		receiver.Repo = ""
+		Expect(post(notification("firing"), "token")).To(Equal(http.StatusInternalServerError))
+	})
+})
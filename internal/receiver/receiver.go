@@ -0,0 +1,256 @@
+// Package receiver accepts GitHub webhook deliveries and triggers reconciles of the affected GithubIssues.
+package receiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// maxPayloadSize is the largest payload GitHub delivers.
+const maxPayloadSize = 25 << 20
+
+// errUnknownRepository is returned for deliveries of repositories without a GithubRepository holding a webhook secret.
+var errUnknownRepository = errors.New("no webhook secret configured for repository")
+
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubrepositories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=issues.dana.io,resources=githubwebhooks,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Receiver validates GitHub webhook deliveries against the secret of the delivering repository and
+// sends the GithubIssues an issue event refers to on Events.
+type Receiver struct {
+	Client client.Reader
+	// Addr is the address the receiver listens on.
+	Addr string
+	// Events receives the GithubIssues to reconcile.
+	Events chan<- event.GenericEvent
+	Log    *zap.Logger
+	// AuditLog records every webhook secret read. Optional.
+	AuditLog *zap.Logger
+}
+
+// payload holds the fields of a delivery the receiver needs.
+type payload struct {
+	Repository struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"repository"`
+	Issue *struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	} `json:"issue"`
+}
+
+// Start serves deliveries until ctx is done. It implements manager.Runnable.
+func (r *Receiver) Start(ctx context.Context) error {
+	server := &http.Server{Addr: r.Addr, Handler: r, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			r.Log.Error("Failed to shut down webhook receiver", zap.Error(err))
+		}
+	}()
+	r.Log.Info("Starting webhook receiver", zap.String("address", r.Addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve webhooks: %v", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection allows every replica to receive deliveries.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPayloadSize))
+	if err != nil {
+		metrics.WebhookDeliveries.WithLabelValues("error").Inc()
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+	var delivery payload
+	if err := json.Unmarshal(body, &delivery); err != nil {
+		metrics.WebhookDeliveries.WithLabelValues("error").Inc()
+		http.Error(w, "malformed payload", http.StatusBadRequest)
+		return
+	}
+
+	log := r.Log.With(zap.String("repository", delivery.Repository.HTMLURL), zap.String("delivery", github.DeliveryID(req)))
+	secrets, err := r.secrets(req.Context(), delivery.Repository.HTMLURL)
+	if err != nil {
+		if errors.Is(err, errUnknownRepository) {
+			log.Warn("Rejecting delivery for unknown repository")
+			metrics.WebhookDeliveries.WithLabelValues("unknown_repository").Inc()
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		log.Error("Failed to look up webhook secret", zap.Error(err))
+		metrics.WebhookDeliveries.WithLabelValues("error").Inc()
+		http.Error(w, "failed to look up webhook secret", http.StatusInternalServerError)
+		return
+	}
+	if !validSignature(req.Header.Get(github.SHA256SignatureHeader), body, secrets) {
+		log.Warn("Rejecting delivery with an invalid signature")
+		metrics.WebhookDeliveries.WithLabelValues("invalid_signature").Inc()
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	metrics.WebhookDeliveries.WithLabelValues("accepted").Inc()
+	if github.WebHookType(req) == "issues" && delivery.Issue != nil {
+		if err := r.enqueue(req.Context(), delivery); err != nil {
+			log.Error("Failed to enqueue GithubIssues for delivery", zap.Error(err))
+			http.Error(w, "failed to enqueue", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// secrets returns the webhook secrets of every GithubRepository and GithubWebhook for repoURL.
+func (r *Receiver) secrets(ctx context.Context, repoURL string) ([][]byte, error) {
+	key, err := repositoryKey(repoURL)
+	if err != nil {
+		return nil, errUnknownRepository
+	}
+	var repositories issuesv1alpha1.GithubRepositoryList
+	if err := r.Client.List(ctx, &repositories); err != nil {
+		return nil, fmt.Errorf("failed to list GithubRepositories: %v", err)
+	}
+
+	var secrets [][]byte
+	for _, repository := range repositories.Items {
+		ref := repository.Spec.WebhookSecretRef
+		if ref == nil {
+			continue
+		}
+		if repositoryKey, err := repositoryKey(repository.Spec.Repo); err != nil || repositoryKey != key {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: repository.Namespace, Name: ref.Name}, secret)
+		logging.AuditSecretRead(r.AuditLog, "GithubRepository", repository.Namespace+"/"+repository.Name,
+			repository.Namespace+"/"+ref.Name, ref.Key, "webhook", err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get webhook secret %s/%s: %v", repository.Namespace, ref.Name, err)
+		}
+		if value, ok := secret.Data[ref.Key]; ok {
+			secrets = append(secrets, value)
+		}
+	}
+	webhookSecrets, err := r.webhookSecrets(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	secrets = append(secrets, webhookSecrets...)
+	if len(secrets) == 0 {
+		return nil, errUnknownRepository
+	}
+	return secrets, nil
+}
+
+// webhookSecrets returns the current and previous secrets of the GithubWebhooks registered on the repository
+// with the given key. The previous secret signs the deliveries sent before a rotation reached GitHub.
+func (r *Receiver) webhookSecrets(ctx context.Context, key string) ([][]byte, error) {
+	var webhooks issuesv1alpha1.GithubWebhookList
+	if err := r.Client.List(ctx, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to list GithubWebhooks: %v", err)
+	}
+
+	var secrets [][]byte
+	for _, webhook := range webhooks.Items {
+		name := webhook.Status.SecretName
+		if name == "" {
+			continue
+		}
+		if webhookKey, err := repositoryKey(webhook.Spec.Repo); err != nil || webhookKey != key {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: webhook.Namespace, Name: name}, secret)
+		logging.AuditSecretRead(r.AuditLog, "GithubWebhook", webhook.Namespace+"/"+webhook.Name,
+			webhook.Namespace+"/"+name, issuesv1alpha1.WebhookSecretKey, "webhook", err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get webhook secret %s/%s: %v", webhook.Namespace, name, err)
+		}
+		for _, secretKey := range []string{issuesv1alpha1.WebhookSecretKey, issuesv1alpha1.WebhookPreviousSecretKey} {
+			if value, ok := secret.Data[secretKey]; ok {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+	return secrets, nil
+}
+
+// enqueue sends every GithubIssue of the delivering repository that manages the delivered issue.
+// Issues are matched by their operator marker, falling back to the title for issues created before markers.
+func (r *Receiver) enqueue(ctx context.Context, delivery payload) error {
+	key, _ := repositoryKey(delivery.Repository.HTMLURL)
+	marker, hasMarker := git.ParseMarker(delivery.Issue.Body)
+
+	var issues issuesv1alpha1.GithubIssueList
+	if err := r.Client.List(ctx, &issues); err != nil {
+		return fmt.Errorf("failed to list GithubIssues: %v", err)
+	}
+	for i := range issues.Items {
+		issueObject := &issues.Items[i]
+		if issueKey, err := repositoryKey(issueObject.RepoURL()); err != nil || issueKey != key {
+			continue
+		}
+		matches := issueObject.Spec.Title == delivery.Issue.Title
+		if hasMarker {
+			matches = marker == issueObject.Namespace+"/"+issueObject.Name
+		}
+		if !matches {
+			continue
+		}
+		select {
+		case r.Events <- event.GenericEvent{Object: issueObject}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// validSignature reports whether signature was computed over body with one of the secrets.
+func validSignature(signature string, body []byte, secrets [][]byte) bool {
+	for _, secret := range secrets {
+		if github.ValidateSignature(signature, body, secret) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// repositoryKey normalizes a repository URL to its lower-cased owner/name.
+func repositoryKey(repoURL string) (string, error) {
+	owner, repo, err := git.ParseRepoURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(owner + "/" + repo), nil
+}
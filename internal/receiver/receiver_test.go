@@ -0,0 +1,123 @@
+package receiver
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/google/go-github/v56/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+)
+
+const delivery = `{"repository":{"html_url":"https://github.com/org/repo"},` +
+	`"issue":{"title":"Flaky test","body":"text\n\n<!-- issues.dana.io/githubissue: team-a/flaky -->"}}`
+
+func sign(body, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+var _ = Describe("Receiver", func() {
+	var (
+		receiver *Receiver
+		events   chan event.GenericEvent
+		scheme   *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(issuesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		secretRef := func(name string) *corev1.SecretKeySelector {
+			return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "secret"}
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&issuesv1alpha1.GithubRepository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "team-a"},
+				Spec:       issuesv1alpha1.GithubRepositorySpec{Repo: "https://github.com/Org/Repo", WebhookSecretRef: secretRef("hook")},
+			},
+			&issuesv1alpha1.GithubRepository{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-b"},
+				Spec:       issuesv1alpha1.GithubRepositorySpec{Repo: "https://github.com/org/other", WebhookSecretRef: secretRef("hook")},
+			},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "hook", Namespace: "team-a"}, Data: map[string][]byte{"secret": []byte("a-secret")}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "hook", Namespace: "team-b"}, Data: map[string][]byte{"secret": []byte("b-secret")}},
+			&issuesv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: "flaky", Namespace: "team-a"},
+				Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", Title: "Renamed upstream"},
+			},
+			&issuesv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "team-a"},
+				Spec:       issuesv1alpha1.GithubIssueSpec{Repo: "https://github.com/org/repo", Title: "Flaky test"},
+			},
+		).Build()
+		events = make(chan event.GenericEvent, 10)
+		receiver = &Receiver{Client: k8sClient, Events: events, Log: zap.NewNop()}
+	})
+
+	deliver := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(delivery))
+		req.Header.Set(github.EventTypeHeader, "issues")
+		req.Header.Set(github.SHA256SignatureHeader, signature)
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	It("accepts a delivery signed with the repository secret and enqueues the marked GithubIssue", func() {
+		Expect(deliver(sign(delivery, "a-secret"))).To(Equal(http.StatusAccepted))
+		Expect(events).To(HaveLen(1))
+		Expect((<-events).Object.GetName()).To(Equal("flaky"))
+	})
+
+	It("rejects a delivery signed with the secret of another repository", func() {
+		counter := metrics.WebhookDeliveries.WithLabelValues("invalid_signature")
+		before := testutil.ToFloat64(counter)
+
+		Expect(deliver(sign(delivery, "b-secret"))).To(Equal(http.StatusUnauthorized))
+		Expect(events).To(BeEmpty())
+		Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+	})
+
+	It("accepts deliveries signed with the current or previous secret of a GithubWebhook", func() {
+		receiver.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&issuesv1alpha1.GithubWebhook{
+				ObjectMeta: metav1.ObjectMeta{Name: "events", Namespace: "team-a"},
+				Spec:       issuesv1alpha1.GithubWebhookSpec{Repo: "https://github.com/org/repo"},
+				Status:     issuesv1alpha1.GithubWebhookStatus{SecretName: "events-webhook"},
+			},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "events-webhook", Namespace: "team-a"}, Data: map[string][]byte{
+				issuesv1alpha1.WebhookSecretKey:         []byte("rotated"),
+				issuesv1alpha1.WebhookPreviousSecretKey: []byte("original"),
+			}},
+		).Build()
+		Expect(deliver(sign(delivery, "rotated"))).To(Equal(http.StatusAccepted))
+		Expect(deliver(sign(delivery, "original"))).To(Equal(http.StatusAccepted))
+		Expect(deliver(sign(delivery, "b-secret"))).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("rejects deliveries for repositories without a webhook secret", func() {
+		counter := metrics.WebhookDeliveries.WithLabelValues("unknown_repository")
+		before := testutil.ToFloat64(counter)
+
+		receiver.Client = fake.NewClientBuilder().WithScheme(scheme).Build()
+		Expect(deliver(sign(delivery, "a-secret"))).To(Equal(http.StatusForbidden))
+		Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+	})
+})
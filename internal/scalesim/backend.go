@@ -0,0 +1,338 @@
+package scalesim
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// Backend is an in-memory GitHub serving every repository, counting the API calls it receives.
+// Every call waits Latency, standing in for the network round trip.
+type Backend struct {
+	Latency time.Duration
+
+	mu       sync.Mutex
+	repos    map[string]*repository
+	calls    map[string]int
+	comments map[int64]string
+	nextID   int64
+}
+
+type repository struct {
+	issues map[int]*git.Issue
+	labels []string
+}
+
+var _ git.IssueClient = &Backend{}
+
+// NewBackend returns an empty Backend.
+func NewBackend(latency time.Duration) *Backend {
+	return &Backend{Latency: latency, repos: map[string]*repository{}, calls: map[string]int{}, comments: map[int64]string{}}
+}
+
+// Calls returns the number of calls received per method.
+func (b *Backend) Calls() map[string]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	calls := make(map[string]int, len(b.calls))
+	for method, count := range b.calls {
+		calls[method] = count
+	}
+	return calls
+}
+
+// call counts a call to method and waits the latency.
+func (b *Backend) call(ctx context.Context, method string) error {
+	if b.Latency > 0 {
+		select {
+		case <-time.After(b.Latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls[method]++
+	return nil
+}
+
+// repository returns the repository owner/repo, creating it on first use. Callers hold b.mu.
+func (b *Backend) repository(owner, repo string) *repository {
+	key := owner + "/" + repo
+	r, ok := b.repos[key]
+	if !ok {
+		r = &repository{issues: map[int]*git.Issue{}}
+		b.repos[key] = r
+	}
+	return r
+}
+
+func (b *Backend) issue(r *repository, number int) (*git.Issue, error) {
+	issue, ok := r.issues[number]
+	if !ok {
+		return nil, fmt.Errorf("issue %d not found", number)
+	}
+	return issue, nil
+}
+
+func copyIssue(issue *git.Issue) *git.Issue {
+	copied := *issue
+	copied.Labels = slices.Clone(issue.Labels)
+	copied.Assignees = slices.Clone(issue.Assignees)
+	return &copied
+}
+
+func (b *Backend) List(ctx context.Context, owner, repo string) ([]*git.Issue, error) {
+	if err := b.call(ctx, "List"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issues := make([]*git.Issue, 0, len(r.issues))
+	for _, issue := range r.issues {
+		issues = append(issues, copyIssue(issue))
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	return issues, nil
+}
+
+func (b *Backend) Get(ctx context.Context, owner, repo string, issueNumber int) (*git.Issue, error) {
+	if err := b.call(ctx, "Get"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issue, err := b.issue(r, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	return copyIssue(issue), nil
+}
+
+func (b *Backend) Create(ctx context.Context, owner, repo string, desired *git.DesiredIssue) (*git.Issue, error) {
+	if err := b.call(ctx, "Create"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	number := len(r.issues) + 1
+	now := time.Now()
+	r.issues[number] = &git.Issue{
+		Number:      number,
+		NodeID:      fmt.Sprintf("I_%s_%s_%d", owner, repo, number),
+		Title:       desired.Title,
+		Description: desired.Body,
+		State:       "open",
+		URL:         fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number),
+		Labels:      slices.Clone(desired.Labels),
+		Assignees:   slices.Clone(desired.Assignees),
+		Milestone:   desired.Milestone,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	return copyIssue(r.issues[number]), nil
+}
+
+func (b *Backend) Edit(ctx context.Context, owner, repo string, issueNumber int, desired *git.DesiredIssue) (*git.Issue, error) {
+	if err := b.call(ctx, "Edit"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issue, err := b.issue(r, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	if desired.Title != "" {
+		issue.Title = desired.Title
+	}
+	issue.Description = desired.Body
+	if desired.Assignees != nil {
+		issue.Assignees = slices.Clone(desired.Assignees)
+	}
+	if desired.Milestone != 0 {
+		issue.Milestone = desired.Milestone
+	}
+	issue.UpdatedAt = time.Now()
+	return copyIssue(issue), nil
+}
+
+func (b *Backend) setState(ctx context.Context, method, owner, repo string, issueNumber int, state string) (*git.Issue, error) {
+	if err := b.call(ctx, method); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issue, err := b.issue(r, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	issue.State = state
+	issue.UpdatedAt = time.Now()
+	return copyIssue(issue), nil
+}
+
+func (b *Backend) Close(ctx context.Context, owner, repo string, issueNumber int) (*git.Issue, error) {
+	return b.setState(ctx, "Close", owner, repo, issueNumber, "closed")
+}
+
+func (b *Backend) Reopen(ctx context.Context, owner, repo string, issueNumber int) (*git.Issue, error) {
+	return b.setState(ctx, "Reopen", owner, repo, issueNumber, "open")
+}
+
+func (b *Backend) AddLabels(ctx context.Context, owner, repo string, issueNumber int, labels []string) error {
+	if err := b.call(ctx, "AddLabels"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issue, err := b.issue(r, issueNumber)
+	if err != nil {
+		return err
+	}
+	for _, label := range labels {
+		if !slices.Contains(issue.Labels, label) {
+			issue.Labels = append(issue.Labels, label)
+		}
+		if !slices.Contains(r.labels, label) {
+			r.labels = append(r.labels, label)
+		}
+	}
+	return nil
+}
+
+func (b *Backend) RemoveLabel(ctx context.Context, owner, repo string, issueNumber int, label string) error {
+	if err := b.call(ctx, "RemoveLabel"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issue, err := b.issue(r, issueNumber)
+	if err != nil {
+		return err
+	}
+	issue.Labels = slices.DeleteFunc(issue.Labels, func(l string) bool { return l == label })
+	return nil
+}
+
+func (b *Backend) Lock(ctx context.Context, owner, repo string, issueNumber int, reason string) error {
+	if err := b.call(ctx, "Lock"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issue, err := b.issue(r, issueNumber)
+	if err != nil {
+		return err
+	}
+	issue.Locked, issue.LockReason = true, reason
+	return nil
+}
+
+func (b *Backend) Unlock(ctx context.Context, owner, repo string, issueNumber int) error {
+	if err := b.call(ctx, "Unlock"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	issue, err := b.issue(r, issueNumber)
+	if err != nil {
+		return err
+	}
+	issue.Locked, issue.LockReason = false, ""
+	return nil
+}
+
+func (b *Backend) SetPinned(ctx context.Context, _, _ string, _ int, _ bool) error {
+	return b.call(ctx, "SetPinned")
+}
+
+func (b *Backend) AddToProject(ctx context.Context, _, _ string, _ int, _ string) error {
+	return b.call(ctx, "AddToProject")
+}
+
+func (b *Backend) Comment(ctx context.Context, _, _ string, _ int, body string) (int64, error) {
+	if err := b.call(ctx, "Comment"); err != nil {
+		return 0, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	b.comments[b.nextID] = body
+	return b.nextID, nil
+}
+
+func (b *Backend) GetComment(ctx context.Context, _, _ string, commentID int64) (string, error) {
+	if err := b.call(ctx, "GetComment"); err != nil {
+		return "", err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	body, ok := b.comments[commentID]
+	if !ok {
+		return "", git.ErrCommentNotFound
+	}
+	return body, nil
+}
+
+func (b *Backend) EditComment(ctx context.Context, _, _ string, commentID int64, body string) error {
+	if err := b.call(ctx, "EditComment"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.comments[commentID]; !ok {
+		return git.ErrCommentNotFound
+	}
+	b.comments[commentID] = body
+	return nil
+}
+
+func (b *Backend) DeleteComment(ctx context.Context, _, _ string, commentID int64) error {
+	if err := b.call(ctx, "DeleteComment"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.comments, commentID)
+	return nil
+}
+
+func (b *Backend) ListLabels(ctx context.Context, owner, repo string) ([]string, error) {
+	if err := b.call(ctx, "ListLabels"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r := b.repository(owner, repo)
+	return slices.Clone(r.labels), nil
+}
+
+func (b *Backend) FindMilestone(ctx context.Context, _, _, title string) (int, error) {
+	if err := b.call(ctx, "FindMilestone"); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("milestone %q not found", title)
+}
+
+func (b *Backend) DuplicateOf(ctx context.Context, _, _ string, _ int) (*git.CanonicalIssue, error) {
+	return nil, b.call(ctx, "DuplicateOf")
+}
+
+func (b *Backend) LinkedPullRequests(ctx context.Context, _, _ string, _ int) ([]git.LinkedPullRequest, error) {
+	return nil, b.call(ctx, "LinkedPullRequests")
+}
@@ -0,0 +1,231 @@
+// Package scalesim drives the GithubIssue reconciler over synthetic GithubIssues against an in-memory GitHub,
+// so the effect of performance work can be measured with reproducible numbers.
+package scalesim
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/controller"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// Namespace holds the synthetic GithubIssues.
+const Namespace = "scale-sim"
+
+// requeueHorizon bounds the requeues replayed within a pass. Later requeues, such as the sync interval,
+// are left to the next pass.
+const requeueHorizon = 5 * time.Second
+
+// Options configures a simulation.
+type Options struct {
+	// Issues is the number of synthetic GithubIssues.
+	Issues int
+	// Repositories spreads the issues over this many repositories.
+	Repositories int
+	// Workers is the number of concurrent reconciles.
+	Workers int
+	// Passes is the number of times every issue is queued. The first pass creates the issues, the next ones
+	// resync them.
+	Passes int
+	// APILatency is added to every GitHub API call.
+	APILatency time.Duration
+	// MaxInFlightPerRepo caps the reconciles in flight per repository. Zero is unlimited.
+	MaxInFlightPerRepo int
+}
+
+// Result holds the measurements of a simulation.
+type Result struct {
+	Options
+	// Duration is the wall time of all passes.
+	Duration time.Duration
+	// Reconciles counts the reconciles, requeues included.
+	Reconciles int
+	// Errors counts the reconciles that returned an error.
+	Errors int
+	// Calls counts the GitHub API calls per method.
+	Calls map[string]int
+	// QueueLatency is the time requests waited in the queue.
+	QueueLatency Percentiles
+	// ReconcileDuration is the time spent in Reconcile.
+	ReconcileDuration Percentiles
+}
+
+// Percentiles summarizes a distribution of durations.
+type Percentiles struct {
+	P50, P95, P99, Max time.Duration
+}
+
+type request struct {
+	key      types.NamespacedName
+	enqueued time.Time
+}
+
+// Run creates the synthetic GithubIssues and reconciles them opts.Passes times with opts.Workers workers.
+func Run(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Issues <= 0 || opts.Repositories <= 0 || opts.Workers <= 0 || opts.Passes <= 0 {
+		return nil, fmt.Errorf("issues, repositories, workers and passes must be positive")
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := issuesv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	objects := []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: Namespace}}}
+	keys := make([]types.NamespacedName, 0, opts.Issues)
+	for i := 0; i < opts.Issues; i++ {
+		issueObject := &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("sim-%05d", i), Namespace: Namespace},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        fmt.Sprintf("https://github.com/scale-sim/repo-%d", i%opts.Repositories),
+				Title:       fmt.Sprintf("Synthetic issue %d", i),
+				Description: "Created by cmd/scale-sim.",
+				Labels:      []string{"scale-sim"},
+			},
+		}
+		objects = append(objects, issueObject)
+		keys = append(keys, client.ObjectKeyFromObject(issueObject))
+	}
+
+	backend := NewBackend(opts.APILatency)
+	reconciler := &controller.GithubIssueReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+			WithStatusSubresource(&issuesv1alpha1.GithubIssue{}).Build(),
+		Scheme:             scheme,
+		Log:                zap.NewNop(),
+		Clients:            &git.Clients{Default: backend},
+		Recorder:           &record.FakeRecorder{},
+		MaxInFlightPerRepo: opts.MaxInFlightPerRepo,
+	}
+	reconciler.Prepare()
+
+	report := &Result{Options: opts}
+	var queueLatencies, durations []time.Duration
+	var mu sync.Mutex
+	start := time.Now()
+	for pass := 0; pass < opts.Passes; pass++ {
+		queue := make(chan request, opts.Issues)
+		var pending sync.WaitGroup
+		enqueue := func(key types.NamespacedName) {
+			queue <- request{key: key, enqueued: time.Now()}
+		}
+		pending.Add(len(keys))
+		for _, key := range keys {
+			enqueue(key)
+		}
+
+		var workers sync.WaitGroup
+		for w := 0; w < opts.Workers; w++ {
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				for req := range queue {
+					started := time.Now()
+					result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: req.key})
+					finished := time.Now()
+
+					mu.Lock()
+					report.Reconciles++
+					if err != nil {
+						report.Errors++
+					}
+					queueLatencies = append(queueLatencies, started.Sub(req.enqueued))
+					durations = append(durations, finished.Sub(started))
+					mu.Unlock()
+
+					if err == nil && (result.Requeue || (result.RequeueAfter > 0 && result.RequeueAfter <= requeueHorizon)) {
+						pending.Add(1)
+						key := req.key
+						time.AfterFunc(result.RequeueAfter, func() { enqueue(key) })
+					}
+					pending.Done()
+				}
+			}()
+		}
+		pending.Wait()
+		close(queue)
+		workers.Wait()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	report.Duration = time.Since(start)
+	report.Calls = backend.Calls()
+	report.QueueLatency = percentiles(queueLatencies)
+	report.ReconcileDuration = percentiles(durations)
+	return report, nil
+}
+
+func percentiles(durations []time.Duration) Percentiles {
+	if len(durations) == 0 {
+		return Percentiles{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	at := func(q float64) time.Duration {
+		return durations[int(q*float64(len(durations)-1))]
+	}
+	return Percentiles{P50: at(0.50), P95: at(0.95), P99: at(0.99), Max: durations[len(durations)-1]}
+}
+
+// TotalCalls returns the number of GitHub API calls.
+func (r *Result) TotalCalls() int {
+	total := 0
+	for _, count := range r.Calls {
+		total += count
+	}
+	return total
+}
+
+// Throughput returns the reconciles per second.
+func (r *Result) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Reconciles) / r.Duration.Seconds()
+}
+
+// Write prints the result as a table.
+func (r *Result) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "issues\t%d\n", r.Issues)
+	fmt.Fprintf(tw, "repositories\t%d\n", r.Repositories)
+	fmt.Fprintf(tw, "workers\t%d\n", r.Workers)
+	fmt.Fprintf(tw, "passes\t%d\n", r.Passes)
+	fmt.Fprintf(tw, "api latency\t%s\n", r.APILatency)
+	fmt.Fprintf(tw, "duration\t%s\n", r.Duration.Round(time.Millisecond))
+	fmt.Fprintf(tw, "reconciles\t%d (%d errors)\n", r.Reconciles, r.Errors)
+	fmt.Fprintf(tw, "throughput\t%.1f reconciles/s\n", r.Throughput())
+	fmt.Fprintf(tw, "api calls\t%d (%.2f per reconcile)\n", r.TotalCalls(), float64(r.TotalCalls())/float64(max(r.Reconciles, 1)))
+	methods := make([]string, 0, len(r.Calls))
+	for method := range r.Calls {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		fmt.Fprintf(tw, "  %s\t%d\n", method, r.Calls[method])
+	}
+	fmt.Fprintf(tw, "queue latency\tp50 %s  p95 %s  p99 %s  max %s\n", r.QueueLatency.P50, r.QueueLatency.P95, r.QueueLatency.P99, r.QueueLatency.Max)
+	fmt.Fprintf(tw, "reconcile duration\tp50 %s  p95 %s  p99 %s  max %s\n",
+		r.ReconcileDuration.P50, r.ReconcileDuration.P95, r.ReconcileDuration.P99, r.ReconcileDuration.Max)
+	return tw.Flush()
+}
@@ -0,0 +1,23 @@
+package scalesim
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Run", func() {
+	It("creates every synthetic issue once and reports the calls", func() {
+		result, err := Run(context.Background(), Options{Issues: 20, Repositories: 3, Workers: 4, Passes: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Errors).To(BeZero())
+		Expect(result.Reconciles).To(BeNumerically(">=", 40))
+		Expect(result.Calls["Create"]).To(Equal(20))
+
+		var out bytes.Buffer
+		Expect(result.Write(&out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("api calls"))
+	})
+})
@@ -0,0 +1,13 @@
+package scalesim
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestScaleSim(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ScaleSim Suite")
+}
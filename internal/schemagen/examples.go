@@ -0,0 +1,327 @@
+package schemagen
+
+import (
+	"time"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// Example is an annotated GithubIssue manifest that documents one supported feature.
+type Example struct {
+	// Name is used both as the object name and as the file name of the example.
+	Name string
+	// Title is a one-line summary rendered at the top of the manifest.
+	Title string
+	// Description explains the feature and is rendered as a comment block.
+	Description string
+	// Annotations are added to the example object metadata.
+	Annotations map[string]string
+	// Spec is the example spec.
+	Spec issuesv1alpha1.GithubIssueSpec
+}
+
+// Examples returns one example per supported feature. Every spec field must be used by at least one example.
+func Examples() []Example {
+	return []Example{
+		{
+			Name:  "basic-issue",
+			Title: "Basic issue",
+			Description: `Creates an issue with the given title and description in the target repository.
+Deleting the GithubIssue closes the upstream issue.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Example issue managed by the operator",
+				Description: "This issue was created from a GithubIssue resource.",
+			},
+		},
+		{
+			Name:  "milestone-issue",
+			Title: "Issue in a milestone",
+			Description: `Assigns the issue to a milestone, referenced either by title or by number.
+The resolved milestone number is reported in status.milestoneNumber.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Ship the v1.0 release notes",
+				Description: "Collect the changes that went into v1.0.",
+				Milestone:   &intstr.IntOrString{Type: intstr.String, StrVal: "v1.0"},
+			},
+		},
+		{
+			Name:  "milestone-ref-issue",
+			Title: "Issue in a managed milestone",
+			Description: `Assigns the issue to the milestone managed by the "v1-0" GithubMilestone, so the milestone and its
+issues are declared together. The issue waits until the GithubMilestone has created its milestone.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:         "https://github.com/example-org/example-repo",
+				Title:        "Write the v1.0 upgrade guide",
+				Description:  "Document the breaking changes of v1.0.",
+				MilestoneRef: &corev1.LocalObjectReference{Name: "v1-0"},
+			},
+		},
+		{
+			Name:  "repository-ref-issue",
+			Title: "Issue in a configured repository",
+			Description: `Creates the issue in the repository of the "example-repo" GithubRepository, with its credentials,
+default labels, default assignees and sync interval, so repository settings are declared once.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				RepositoryRef: &corev1.LocalObjectReference{Name: "example-repo"},
+				Title:         "Rotate the deploy keys",
+				Description:   "The deploy keys expire at the end of the quarter.",
+			},
+		},
+		{
+			Name:  "labeled-issue",
+			Title: "Labeled and assigned issue",
+			Description: `Creates the issue with its labels and assignees in a single call.
+Labels added upstream are kept; spec labels missing upstream are added back.
+The issue type must be one of the types defined by the organization. The priority is applied as the
+"priority/P1" label, or the label configured with --priority-labels. The estimate is applied as the "estimate/3" label.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Flaky integration test on main",
+				Description: "The integration suite fails intermittently on main.",
+				Labels:      []string{"bug", "ci"},
+				Assignees:   []string{"octocat"},
+				IssueType:   "Bug",
+				Priority:    "high",
+				Estimate:    ptr.To[int32](3),
+			},
+		},
+		{
+			Name:  "locked-issue",
+			Title: "Locked conversation",
+			Description: `Locks the issue conversation so only collaborators can comment.
+The lock state is enforced on every sync, so unlocking it upstream is reverted.
+When the GithubIssue is deleted, closeComment is posted before the issue is closed.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:         "https://github.com/example-org/example-repo",
+				Title:        "Release 2.0 announcement",
+				Description:  "Questions about the release go to the discussion board.",
+				Locked:       true,
+				LockReason:   "resolved",
+				CloseComment: "Closing: the release announcement has been archived.",
+			},
+		},
+		{
+			Name:  "pinned-issue",
+			Title: "Pinned issue",
+			Description: `Pins the issue to the top of the repository issue list.
+Pinning is done through the GitHub GraphQL API; setting pinned back to false unpins it.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Known issues in the current release",
+				Description: "Check this list before filing a new bug.",
+				Pinned:      true,
+			},
+		},
+		{
+			Name:  "commented-issue",
+			Title: "Issue with managed comments",
+			Description: `Posts one comment per spec.comments entry. Editing a body edits the comment upstream
+and removing an entry deletes its comment. Posted comment IDs are tracked in status.comments.
+A comment edited on GitHub is never overwritten or deleted, and replies are never touched.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Database failover drill",
+				Description: "Quarterly failover drill of the primary database.",
+				Comments: []issuesv1alpha1.IssueComment{
+					{Name: "checklist", Body: "- [ ] Announce the drill\n- [ ] Fail over\n- [ ] Fail back"},
+					{Name: "owner", Body: "Drill owner: @octocat"},
+				},
+			},
+		},
+		{
+			Name:  "project-issue",
+			Title: "Issue on a project board",
+			Description: `Adds the issue to a Projects V2 board through the GitHub GraphQL API.
+The boards the issue was added to are reported in status.projects and the ProjectsSynced condition.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Plan the Q3 migration",
+				Description: "Tracked on the platform roadmap board.",
+				Projects:    []string{"https://github.com/orgs/example-org/projects/7"},
+			},
+		},
+		{
+			Name:  "protected-issue",
+			Title: "Issue protected from deletion",
+			Description: `Holds the deletion of the GithubIssue while the issue has an open linked pull request,
+so automation cleaning up resources never closes an issue whose fix is still in review.
+The DeletionBlocked condition reports a held deletion.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:               "https://github.com/example-org/example-repo",
+				Title:              "Memory leak in the cache layer",
+				Description:        "Fix in review.",
+				DeletionProtection: issuesv1alpha1.DeletionProtectionWhileLinkedPROpen,
+			},
+		},
+		{
+			Name:  "localized-issue",
+			Title: "Localized issue",
+			Description: `Renders the French localization upstream and appends the other localizations as collapsible
+sections. Without spec.language the defaultLanguage of the repository's GithubRepository is used.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Update the onboarding guide",
+				Description: "The onboarding guide is out of date.",
+				Localizations: map[string]issuesv1alpha1.IssueLocalization{
+					"en": {Title: "Update the onboarding guide", Body: "The onboarding guide is out of date."},
+					"fr": {Title: "Mettre à jour le guide d'accueil", Body: "Le guide d'accueil n'est plus à jour."},
+				},
+				Language:            "fr",
+				IncludeTranslations: true,
+			},
+		},
+		{
+			Name:  "label-policy-issue",
+			Title: "Issue enforcing its label set",
+			Description: `Removes the labels added to the issue on GitHub, so it carries exactly the spec labels
+plus the priority, estimate and triage labels the operator manages. The default Merge policy keeps them.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Release checklist",
+				Description: "Labels are owned by the release tooling.",
+				Labels:      []string{"release", "tracking"},
+				LabelPolicy: issuesv1alpha1.LabelPolicyReplace,
+			},
+		},
+		{
+			Name:  "conflict-policy-issue",
+			Title: "Issue keeping upstream description edits",
+			Description: `By default the operator overwrites description edits made on GitHub.
+GitHubWins keeps them and records them in status.externalDescription; Manual sets the Conflict condition
+and stops editing the issue until the spec changes.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:           "https://github.com/example-org/example-repo",
+				Title:          "Incident postmortem",
+				Description:    "Draft, refined on GitHub by the incident commander.",
+				ConflictPolicy: issuesv1alpha1.ConflictPolicyGitHubWins,
+			},
+		},
+		{
+			Name:  "duplicate-policy-issue",
+			Title: "Issue following the issue it duplicates",
+			Description: `When the upstream issue is closed as a duplicate of another issue, Repoint records the canonical
+issue in status.duplicateOf and stops editing the duplicate; Delete deletes the GithubIssue.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:            "https://github.com/example-org/example-repo",
+				Title:           "Login page times out",
+				Description:     "Reported by the support rota.",
+				DuplicatePolicy: issuesv1alpha1.DuplicatePolicyRepoint,
+			},
+		},
+		{
+			Name:  "scheduled-issue",
+			Title: "Issue opened at a future time",
+			Description: `Creates the upstream issue only once notBefore has passed, e.g. to announce scheduled maintenance.
+The Scheduled condition is set until then.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Scheduled maintenance on Saturday",
+				Description: "The API is read-only between 06:00 and 08:00 UTC.",
+				NotBefore:   &metav1.Time{Time: time.Date(2030, time.January, 4, 9, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			Name:  "due-date",
+			Title: "Issue with a due date",
+			Description: `Writes the due date in the issue body, comments on the issue a day before it is due and sets
+the Overdue condition while the issue is open past the due date.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Renew the TLS certificates",
+				Description: "The certificates of the public endpoints expire at the end of the month.",
+				DueDate:     &metav1.Time{Time: time.Date(2030, time.January, 25, 12, 0, 0, 0, time.UTC)},
+				DueReminder: &issuesv1alpha1.DueReminder{Before: metav1.Duration{Duration: 24 * time.Hour}},
+			},
+		},
+		{
+			Name:  "suspended-issue",
+			Title: "Suspended issue",
+			Description: `Stops all GitHub API calls for the issue, e.g. during incident response or a migration.
+The Suspended condition is set while suspended; set suspend back to false to resume.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Migrate CI to the new runners",
+				Description: "Frozen while the repository is being migrated.",
+				Suspend:     true,
+			},
+		},
+		{
+			Name:  "sync-interval-issue",
+			Title: "Per-issue sync interval",
+			Description: `Refreshes the issue from GitHub every 5 minutes instead of following --resync-period.
+Longer intervals than the global resync period skip the periodic resyncs in between.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:                "https://github.com/example-org/example-repo",
+				Title:               "Quarterly roadmap",
+				Description:         "Rarely changes, no need to sync it every minute.",
+				SyncIntervalSeconds: ptr.To[int32](300),
+			},
+		},
+		{
+			Name:  "description-from-configmap",
+			Title: "Description rendered from a ConfigMap",
+			Description: `Uses the "body.md" key of the "incident-report" ConfigMap as the issue description.
+The upstream issue is updated whenever the ConfigMap changes. Use secretKeyRef to read a Secret instead.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:  "https://github.com/example-org/example-repo",
+				Title: "Incident report",
+				DescriptionFrom: &issuesv1alpha1.DescriptionSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "incident-report"},
+						Key:                  "body.md",
+					},
+				},
+			},
+		},
+		{
+			Name:  "team-credentials-issue",
+			Title: "Issue using its own GitHub token",
+			Description: `Creates the issue with the token stored under "token" in the "team-github-token" Secret
+instead of the operator token, so the issue is authored by the team's account.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:                 "https://github.com/example-org/team-repo",
+				Title:                "Rotate the staging certificates",
+				Description:          "Certificates expire at the end of the month.",
+				CredentialsSecretRef: &issuesv1alpha1.CredentialsSecretReference{Name: "team-github-token", Key: "token"},
+			},
+		},
+		{
+			Name:  "mirrored-issue",
+			Title: "Issue mirrored to a second repository",
+			Description: `Keeps a copy of the issue in another repository, here on a GitHub Enterprise host during a migration.
+The mirror follows the title and the open or closed state of the issue and is closed when the GithubIssue is deleted.
+Mirror issues are reported in status.mirrors and the MirrorsSynced condition.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Deprecate the v1 API",
+				Description: "Tracked in both trackers until the migration completes.",
+				Mirrors: []issuesv1alpha1.IssueMirror{{
+					Repo:                 "https://github.example.com/platform/example-repo",
+					CredentialsSecretRef: &issuesv1alpha1.CredentialsSecretReference{Name: "ghe-token", Key: "token"},
+				}},
+			},
+		},
+		{
+			Name:  "templated-issue",
+			Title: "Issue body rendered from a template",
+			Description: `Renders the issue body from the Go template stored under "incident.tmpl" in the "issue-templates" ConfigMap.
+The template sees .Name, .Namespace, .Labels, .Description and the templateValues as .Values.`,
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/example-org/example-repo",
+				Title:       "Checkout latency above SLO",
+				Description: "p99 latency has been above 800ms for 30 minutes.",
+				TemplateRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "issue-templates"},
+					Key:                  "incident.tmpl",
+				},
+				TemplateValues: map[string]string{"severity": "sev2", "runbook": "https://runbooks.example.com/checkout"},
+			},
+		},
+	}
+}
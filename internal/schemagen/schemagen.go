@@ -0,0 +1,173 @@
+// Package schemagen renders the GithubIssue CRD schema as a standalone JSON Schema document
+// and produces annotated example manifests for every supported spec feature.
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// CRDPath is the controller-gen output the JSON Schema is derived from, relative to the repo root.
+	CRDPath = "config/crd/bases/issues.dana.io_githubissues.yaml"
+	// SchemaPath is where the generated JSON Schema is written, relative to the repo root.
+	SchemaPath = "config/schema/issues.dana.io_githubissues.json"
+	// ExamplesDir is where the generated example manifests are written, relative to the repo root.
+	ExamplesDir = "config/samples/examples"
+
+	jsonSchemaDialect = "http://json-schema.org/draft-07/schema#"
+)
+
+// Generate renders all generated files for the repository rooted at root.
+// The returned map is keyed by path relative to root.
+func Generate(root string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	schema, err := JSONSchema(filepath.Join(root, CRDPath))
+	if err != nil {
+		return nil, err
+	}
+	files[SchemaPath] = schema
+
+	for _, example := range Examples() {
+		manifest, err := renderExample(example)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render example %s: %w", example.Name, err)
+		}
+		files[filepath.Join(ExamplesDir, example.Name+".yaml")] = manifest
+	}
+
+	return files, nil
+}
+
+// Write generates all files and writes them below root, replacing stale examples.
+func Write(root string) error {
+	files, err := Generate(root)
+	if err != nil {
+		return err
+	}
+
+	stale, err := filepath.Glob(filepath.Join(root, ExamplesDir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove stale example %s: %w", path, err)
+		}
+	}
+
+	for path, content := range files {
+		target := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// JSONSchema converts the storage version schema of the CRD at crdPath into a JSON Schema document.
+func JSONSchema(crdPath string) ([]byte, error) {
+	raw, err := os.ReadFile(crdPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRD: %w", err)
+	}
+
+	var crd apiextensionsv1.CustomResourceDefinition
+	if err := yaml.UnmarshalStrict(raw, &crd); err != nil {
+		return nil, fmt.Errorf("failed to parse CRD: %w", err)
+	}
+
+	version := storageVersion(crd)
+	if version == nil || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		return nil, fmt.Errorf("CRD %s has no storage version schema", crd.Name)
+	}
+
+	props, err := json.Marshal(version.Schema.OpenAPIV3Schema)
+	if err != nil {
+		return nil, err
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(props, &schema); err != nil {
+		return nil, err
+	}
+
+	schema["$schema"] = jsonSchemaDialect
+	schema["title"] = fmt.Sprintf("%s (%s/%s)", crd.Spec.Names.Kind, crd.Spec.Group, version.Name)
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		properties["apiVersion"] = map[string]interface{}{
+			"type": "string",
+			"enum": []string{crd.Spec.Group + "/" + version.Name},
+		}
+		properties["kind"] = map[string]interface{}{
+			"type": "string",
+			"enum": []string{crd.Spec.Names.Kind},
+		}
+	}
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func storageVersion(crd apiextensionsv1.CustomResourceDefinition) *apiextensionsv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Storage {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
+}
+
+// manifest is the subset of a GithubIssue that is rendered into examples, without server-populated fields.
+type manifest struct {
+	APIVersion string                         `json:"apiVersion"`
+	Kind       string                         `json:"kind"`
+	Metadata   manifestMetadata               `json:"metadata"`
+	Spec       issuesv1alpha1.GithubIssueSpec `json:"spec"`
+}
+
+type manifestMetadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func renderExample(example Example) ([]byte, error) {
+	body, err := yaml.Marshal(manifest{
+		APIVersion: issuesv1alpha1.GroupVersion.String(),
+		Kind:       "GithubIssue",
+		Metadata: manifestMetadata{
+			Name:        example.Name,
+			Namespace:   "default",
+			Annotations: example.Annotations,
+		},
+		Spec: example.Spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Code generated by schemagen. DO NOT EDIT.\n")
+	buf.WriteString("#\n")
+	buf.WriteString("# " + example.Title + "\n")
+	for _, line := range strings.Split(strings.TrimSpace(example.Description), "\n") {
+		buf.WriteString("# " + strings.TrimSpace(line) + "\n")
+	}
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,87 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var repoRoot = filepath.Join("..", "..")
+
+// specFields returns the JSON names of all GithubIssueSpec fields.
+func specFields() []string {
+	var fields []string
+	t := reflect.TypeOf(issuesv1alpha1.GithubIssueSpec{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+var _ = Describe("schemagen", func() {
+	It("matches the committed files (run `make generate-schema` on drift)", func() {
+		files, err := Generate(repoRoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		for path, want := range files {
+			got, err := os.ReadFile(filepath.Join(repoRoot, path))
+			Expect(err).NotTo(HaveOccurred(), "missing generated file %s", path)
+			Expect(string(got)).To(Equal(string(want)), "generated file %s is out of date", path)
+		}
+
+		onDisk, err := filepath.Glob(filepath.Join(repoRoot, ExamplesDir, "*.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, path := range onDisk {
+			rel, err := filepath.Rel(repoRoot, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveKey(rel), "stale example %s", rel)
+		}
+	})
+
+	It("describes every spec field of the Go types in the JSON Schema", func() {
+		raw, err := JSONSchema(filepath.Join(repoRoot, CRDPath))
+		Expect(err).NotTo(HaveOccurred())
+
+		var schema struct {
+			Properties struct {
+				Spec struct {
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"spec"`
+			} `json:"properties"`
+		}
+		Expect(json.Unmarshal(raw, &schema)).To(Succeed())
+
+		fields := specFields()
+		Expect(schema.Properties.Spec.Properties).To(HaveLen(len(fields)))
+		for _, field := range fields {
+			Expect(schema.Properties.Spec.Properties).To(HaveKey(field), "CRD is missing spec.%s, run `make manifests`", field)
+		}
+	})
+
+	It("uses every spec field in at least one example", func() {
+		used := map[string]bool{}
+		for _, example := range Examples() {
+			raw, err := json.Marshal(example.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			var spec map[string]interface{}
+			Expect(json.Unmarshal(raw, &spec)).To(Succeed())
+			for field := range spec {
+				used[field] = true
+			}
+		}
+
+		for _, field := range specFields() {
+			Expect(used).To(HaveKey(field), "no example covers spec.%s", field)
+		}
+	})
+})
@@ -0,0 +1,137 @@
+// Package triage decides which triage label an upstream issue should carry based on the age of its
+// GithubIssue and on the upstream activity.
+package triage
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+)
+
+// Triage labels managed by the operator.
+const (
+	LabelNeedsTriage = "needs-triage"
+	LabelStale       = "stale"
+	LabelEscalated   = "escalated"
+)
+
+// noLabel is the metric label value used for issues without a triage label.
+const noLabel = "none"
+
+// ManagedLabels lists every label owned by the triage subsystem.
+var ManagedLabels = []string{LabelNeedsTriage, LabelStale, LabelEscalated}
+
+// Policy holds the triage thresholds for a repository.
+type Policy struct {
+	// Enabled turns triage labeling on for the repository.
+	Enabled bool
+	// StaleAfter is how long a triaged issue may go without upstream activity before it is marked stale.
+	StaleAfter time.Duration
+	// EscalateAfter is how long an issue may stay untriaged, measured from the GithubIssue creation, before it is escalated.
+	EscalateAfter time.Duration
+}
+
+// PolicyResolver returns the triage policy of a repository.
+type PolicyResolver interface {
+	PolicyFor(owner, repo string) Policy
+}
+
+// StaticPolicy applies the same policy to every repository.
+type StaticPolicy Policy
+
+// PolicyFor implements PolicyResolver.
+func (p StaticPolicy) PolicyFor(_, _ string) Policy {
+	return Policy(p)
+}
+
+// Evaluate returns the triage label the issue should carry, or an empty string for none.
+// An issue counts as triaged once it carries any label not owned by the triage subsystem.
+func Evaluate(policy Policy, created time.Time, issue *git.Issue, now time.Time) string {
+	if !policy.Enabled || issue == nil || issue.State != "open" {
+		return ""
+	}
+
+	if !triaged(issue.Labels) {
+		if policy.EscalateAfter > 0 && now.Sub(created) >= policy.EscalateAfter {
+			return LabelEscalated
+		}
+		return LabelNeedsTriage
+	}
+
+	// The stale label is sticky: applying it counts as upstream activity, so it is only cleared by humans.
+	if slices.Contains(issue.Labels, LabelStale) {
+		return LabelStale
+	}
+	if policy.StaleAfter > 0 && !issue.UpdatedAt.IsZero() && now.Sub(issue.UpdatedAt) >= policy.StaleAfter {
+		return LabelStale
+	}
+	return ""
+}
+
+// Plan returns the triage labels to add to and remove from an issue carrying current so it ends up with desired.
+func Plan(current []string, desired string) (add []string, remove []string) {
+	if desired != "" && !slices.Contains(current, desired) {
+		add = append(add, desired)
+	}
+	for _, label := range ManagedLabels {
+		if label != desired && slices.Contains(current, label) {
+			remove = append(remove, label)
+		}
+	}
+	return add, remove
+}
+
+func triaged(labels []string) bool {
+	for _, label := range labels {
+		if !slices.Contains(ManagedLabels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// Distribution tracks the triage label of every managed issue and exports the totals as metrics.
+type Distribution struct {
+	mu       sync.Mutex
+	byObject map[string]string
+}
+
+// NewDistribution returns an empty Distribution.
+func NewDistribution() *Distribution {
+	return &Distribution{byObject: map[string]string{}}
+}
+
+// Record sets the triage label of the object identified by key.
+func (d *Distribution) Record(key, label string) {
+	if label == "" {
+		label = noLabel
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.byObject[key] = label
+	d.export()
+}
+
+// Forget drops the object identified by key.
+func (d *Distribution) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.byObject, key)
+	d.export()
+}
+
+func (d *Distribution) export() {
+	counts := map[string]int{noLabel: 0}
+	for _, label := range ManagedLabels {
+		counts[label] = 0
+	}
+	for _, label := range d.byObject {
+		counts[label]++
+	}
+	for label, count := range counts {
+		metrics.TriageIssues.WithLabelValues(label).Set(float64(count))
+	}
+}
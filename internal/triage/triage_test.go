@@ -0,0 +1,46 @@
+package triage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+var _ = Describe("Evaluate", func() {
+	now := time.Now()
+	policy := Policy{Enabled: true, StaleAfter: 30 * 24 * time.Hour, EscalateAfter: 7 * 24 * time.Hour}
+
+	DescribeTable("selects the triage label",
+		func(p Policy, created time.Time, issue *git.Issue, expected string) {
+			Expect(Evaluate(p, created, issue, now)).To(Equal(expected))
+		},
+		Entry("disabled policy", Policy{}, now, &git.Issue{State: "open"}, ""),
+		Entry("closed issue", policy, now, &git.Issue{State: "closed"}, ""),
+		Entry("new untriaged issue", policy, now, &git.Issue{State: "open", UpdatedAt: now}, LabelNeedsTriage),
+		Entry("old untriaged issue", policy, now.Add(-8*24*time.Hour),
+			&git.Issue{State: "open", Labels: []string{LabelNeedsTriage}, UpdatedAt: now}, LabelEscalated),
+		Entry("active triaged issue", policy, now.Add(-60*24*time.Hour),
+			&git.Issue{State: "open", Labels: []string{"bug"}, UpdatedAt: now}, ""),
+		Entry("inactive triaged issue", policy, now.Add(-60*24*time.Hour),
+			&git.Issue{State: "open", Labels: []string{"bug"}, UpdatedAt: now.Add(-31 * 24 * time.Hour)}, LabelStale),
+		Entry("stale label is kept after its own update", policy, now.Add(-60*24*time.Hour),
+			&git.Issue{State: "open", Labels: []string{"bug", LabelStale}, UpdatedAt: now}, LabelStale),
+	)
+})
+
+var _ = Describe("Plan", func() {
+	It("adds the desired label and removes the other triage labels", func() {
+		add, remove := Plan([]string{"bug", LabelNeedsTriage}, LabelEscalated)
+		Expect(add).To(ConsistOf(LabelEscalated))
+		Expect(remove).To(ConsistOf(LabelNeedsTriage))
+	})
+
+	It("removes every triage label when none is desired", func() {
+		add, remove := Plan([]string{LabelStale, "bug"}, "")
+		Expect(add).To(BeEmpty())
+		Expect(remove).To(ConsistOf(LabelStale))
+	})
+})
@@ -0,0 +1,37 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+)
+
+// deprecatedField describes a GithubIssue field that is scheduled for removal.
+type deprecatedField struct {
+	// path is the JSON path of the field, e.g. spec.description.
+	path string
+	// replacement tells users what to use instead.
+	replacement string
+	// inUse reports whether the object sets the field.
+	inUse func(*issuesv1alpha1.GithubIssue) bool
+}
+
+// deprecatedGithubIssueFields lists the deprecated GithubIssue fields. Add an entry when a field is renamed or
+// superseded, and remove the field itself only once the usage metric shows it is no longer set.
+var deprecatedGithubIssueFields []deprecatedField
+
+// deprecationWarnings returns an admission warning for every deprecated field the object sets
+// and counts the usage per field.
+func deprecationWarnings(githubIssue *issuesv1alpha1.GithubIssue) []string {
+	var warnings []string
+	for _, field := range deprecatedGithubIssueFields {
+		if !field.inUse(githubIssue) {
+			continue
+		}
+		metrics.DeprecatedFieldUsage.WithLabelValues("GithubIssue", field.path).Inc()
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated and will be removed in a future API version; use %s instead",
+			field.path, field.replacement))
+	}
+	return warnings
+}
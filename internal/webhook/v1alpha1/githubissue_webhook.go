@@ -0,0 +1,171 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/labels"
+)
+
+// SetupGithubIssueWebhookWithManager registers the webhook for GithubIssue in the manager.
+// A nil taxonomy allows every label. A nil previewer disables the dry-run preview. Credentials Secrets may be
+// referenced in centralSecretsNamespace, when set, besides the namespace of the GithubIssue. A nil authorizer
+// allows every repository.
+func SetupGithubIssueWebhookWithManager(mgr ctrl.Manager, log *zap.Logger, taxonomy *labels.Taxonomy, previewer Previewer, centralSecretsNamespace string, authorizer RepositoryAuthorizer) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&issuesv1alpha1.GithubIssue{}).
+		WithValidator(&GithubIssueCustomValidator{
+			Log:                     log,
+			Taxonomy:                taxonomy,
+			Previewer:               previewer,
+			CentralSecretsNamespace: centralSecretsNamespace,
+			Authorizer:              authorizer,
+		}).
+		Complete()
+}
+
+// RepositoryAuthorizer checks that the namespace of a GithubIssue may file issues in its repositories.
+type RepositoryAuthorizer interface {
+	AuthorizeRepositories(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) error
+}
+
+// +kubebuilder:webhook:path=/validate-issues-dana-io-v1alpha1-githubissue,mutating=false,failurePolicy=fail,sideEffects=None,groups=issues.dana.io,resources=githubissues,verbs=create;update,versions=v1alpha1,name=vgithubissue-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// GithubIssueCustomValidator validates GithubIssue resources on create and update.
+type GithubIssueCustomValidator struct {
+	Log      *zap.Logger
+	Taxonomy *labels.Taxonomy
+	// Previewer renders the GitHub payload returned as warnings on dry-run requests. Optional.
+	Previewer Previewer
+	// CentralSecretsNamespace is the namespace credentials Secrets may be referenced in besides the namespace
+	// of the GithubIssue. Optional.
+	CentralSecretsNamespace string
+	// Authorizer rejects GithubIssues filed in repositories their namespace is not allowed. Optional.
+	Authorizer RepositoryAuthorizer
+}
+
+var _ webhook.CustomValidator = &GithubIssueCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type GithubIssue.
+func (v *GithubIssueCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	githubIssue, ok := obj.(*issuesv1alpha1.GithubIssue)
+	if !ok {
+		return nil, fmt.Errorf("expected a GithubIssue object but got %T", obj)
+	}
+	v.Log.Debug("Validation for GithubIssue upon creation", zap.String("name", githubIssue.GetName()))
+
+	if err := v.validate(githubIssue); err != nil {
+		return deprecationWarnings(githubIssue), err
+	}
+	if err := v.authorize(ctx, githubIssue); err != nil {
+		return deprecationWarnings(githubIssue), err
+	}
+	return append(deprecationWarnings(githubIssue), v.preview(ctx, githubIssue)...), nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type GithubIssue.
+func (v *GithubIssueCustomValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	githubIssue, ok := newObj.(*issuesv1alpha1.GithubIssue)
+	if !ok {
+		return nil, fmt.Errorf("expected a GithubIssue object for the newObj but got %T", newObj)
+	}
+	v.Log.Debug("Validation for GithubIssue upon update", zap.String("name", githubIssue.GetName()))
+
+	if err := v.validate(githubIssue); err != nil {
+		return deprecationWarnings(githubIssue), err
+	}
+	if err := v.authorize(ctx, githubIssue); err != nil {
+		return deprecationWarnings(githubIssue), err
+	}
+	return append(deprecationWarnings(githubIssue), v.preview(ctx, githubIssue)...), nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type GithubIssue.
+func (v *GithubIssueCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the GithubIssue against the operator configuration.
+func (v *GithubIssueCustomValidator) validate(githubIssue *issuesv1alpha1.GithubIssue) error {
+	var allErrs field.ErrorList
+	if githubIssue.Spec.Description != "" && githubIssue.Spec.DescriptionFrom != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "descriptionFrom"), "may not be set together with spec.description"))
+	}
+	labelsPath := field.NewPath("spec", "labels")
+	if len(githubIssue.Spec.Labels) > git.MaxLabels {
+		allErrs = append(allErrs, field.TooMany(labelsPath, len(githubIssue.Spec.Labels), git.MaxLabels))
+	}
+	if len(githubIssue.Spec.Assignees) > git.MaxAssignees {
+		allErrs = append(allErrs, field.TooMany(field.NewPath("spec", "assignees"), len(githubIssue.Spec.Assignees), git.MaxAssignees))
+	}
+	for i, label := range githubIssue.Spec.Labels {
+		if !v.Taxonomy.Allows(label) {
+			allErrs = append(allErrs, field.NotSupported(labelsPath.Index(i), label, v.allowedLabels()))
+		}
+	}
+	allErrs = append(allErrs, v.validateSecretRef(githubIssue, field.NewPath("spec", "credentialsSecretRef"), githubIssue.Spec.CredentialsSecretRef)...)
+	for i, mirror := range githubIssue.Spec.Mirrors {
+		allErrs = append(allErrs, v.validateSecretRef(githubIssue, field.NewPath("spec", "mirrors").Index(i).Child("credentialsSecretRef"), mirror.CredentialsSecretRef)...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(issuesv1alpha1.GroupVersion.WithKind("GithubIssue").GroupKind(), githubIssue.Name, allErrs)
+}
+
+// validateSecretRef rejects credentials Secrets referenced outside of the namespace of the GithubIssue
+// and the central secrets namespace.
+func (v *GithubIssueCustomValidator) validateSecretRef(githubIssue *issuesv1alpha1.GithubIssue, path *field.Path, ref *issuesv1alpha1.CredentialsSecretReference) field.ErrorList {
+	if ref == nil {
+		return nil
+	}
+	if _, err := ref.SecretNamespace(githubIssue.Namespace, v.CentralSecretsNamespace); err != nil {
+		return field.ErrorList{field.Forbidden(path.Child("namespace"), err.Error())}
+	}
+	return nil
+}
+
+// authorize rejects a GithubIssue filed in a repository its namespace is not allowed.
+func (v *GithubIssueCustomValidator) authorize(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) error {
+	if v.Authorizer == nil {
+		return nil
+	}
+	if err := v.Authorizer.AuthorizeRepositories(ctx, githubIssue); err != nil {
+		return apierrors.NewForbidden(issuesv1alpha1.GroupVersion.WithResource("githubissues").GroupResource(), githubIssue.Name, err)
+	}
+	return nil
+}
+
+// allowedLabels describes the taxonomy in validation errors.
+func (v *GithubIssueCustomValidator) allowedLabels() []string {
+	var allowed []string
+	for _, prefix := range v.Taxonomy.Prefixes {
+		allowed = append(allowed, prefix+"*")
+	}
+	return append(allowed, v.Taxonomy.Patterns...)
+}
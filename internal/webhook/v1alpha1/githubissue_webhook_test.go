@@ -0,0 +1,212 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/labels"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/metrics"
+)
+
+// previewFunc adapts a function to the Previewer interface.
+type previewFunc func(context.Context, *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error)
+
+func (f previewFunc) Preview(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error) {
+	return f(ctx, githubIssue)
+}
+
+// authorizerFunc adapts a function to the RepositoryAuthorizer interface.
+type authorizerFunc func(context.Context, *issuesv1alpha1.GithubIssue) error
+
+func (f authorizerFunc) AuthorizeRepositories(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) error {
+	return f(ctx, githubIssue)
+}
+
+var _ = Describe("GithubIssue Webhook", func() {
+	var (
+		obj       *issuesv1alpha1.GithubIssue
+		validator GithubIssueCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &issuesv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec: issuesv1alpha1.GithubIssueSpec{
+				Repo:        "https://github.com/org/repo",
+				Title:       "title",
+				Description: "description",
+			},
+		}
+		validator = GithubIssueCustomValidator{Log: zap.NewNop()}
+	})
+
+	Context("When a deprecated field is set", func() {
+		BeforeEach(func() {
+			original := deprecatedGithubIssueFields
+			deprecatedGithubIssueFields = []deprecatedField{{
+				path:        "spec.description",
+				replacement: "spec.body",
+				inUse:       func(i *issuesv1alpha1.GithubIssue) bool { return i.Spec.Description != "" },
+			}}
+			DeferCleanup(func() { deprecatedGithubIssueFields = original })
+		})
+
+		It("admits the object with a warning and counts the usage", func() {
+			counter := metrics.DeprecatedFieldUsage.WithLabelValues("GithubIssue", "spec.description")
+			before := testutil.ToFloat64(counter)
+
+			warnings, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("spec.description is deprecated")))
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+		})
+
+		It("does not warn when the field is unset", func() {
+			obj.Spec.Description = ""
+			warnings, err := validator.ValidateUpdate(context.Background(), obj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("When a label taxonomy is configured", func() {
+		BeforeEach(func() {
+			taxonomy, err := labels.New([]string{"area/"}, []string{"bug"}, false)
+			Expect(err).NotTo(HaveOccurred())
+			validator.Taxonomy = taxonomy
+		})
+
+		It("admits labels inside the taxonomy", func() {
+			obj.Spec.Labels = []string{"area/api", "bug"}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects labels outside the taxonomy", func() {
+			obj.Spec.Labels = []string{"area/api", "wontfix"}
+			_, err := validator.ValidateUpdate(context.Background(), obj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.labels[1]")))
+		})
+	})
+
+	Context("When the spec exceeds GitHub limits", func() {
+		It("rejects more than 10 assignees and 100 labels", func() {
+			for i := 0; i < 11; i++ {
+				obj.Spec.Assignees = append(obj.Spec.Assignees, fmt.Sprintf("user%d", i))
+			}
+			for i := 0; i < 101; i++ {
+				obj.Spec.Labels = append(obj.Spec.Labels, fmt.Sprintf("label%d", i))
+			}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(And(ContainSubstring("spec.assignees"), ContainSubstring("spec.labels"))))
+		})
+	})
+
+	Context("When the request is a dry run", func() {
+		var dryRun context.Context
+
+		BeforeEach(func() {
+			validator.Previewer = previewFunc(func(_ context.Context, githubIssue *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error) {
+				return &git.DesiredIssue{Title: githubIssue.Spec.Title, Body: "rendered " + githubIssue.Spec.Description, Labels: []string{"bug"}}, nil
+			})
+			dryRun = admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(true)},
+			})
+		})
+
+		It("previews the GitHub payload as warnings", func() {
+			warnings, err := validator.ValidateCreate(dryRun, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"GitHub preview title: title",
+				"GitHub preview body: rendered description",
+				"GitHub preview labels: bug",
+			))
+		})
+
+		It("does not show a body read from a Secret", func() {
+			obj.Spec.Description = ""
+			obj.Spec.DescriptionFrom = &issuesv1alpha1.DescriptionSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "body"}, Key: "body.md"},
+			}
+			warnings, err := validator.ValidateUpdate(dryRun, obj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement("GitHub preview body: (rendered from Secret body, not shown)"))
+		})
+
+		It("does not preview regular requests", func() {
+			warnings, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("When the description is set twice", func() {
+		It("rejects description together with descriptionFrom", func() {
+			obj.Spec.DescriptionFrom = &issuesv1alpha1.DescriptionSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "body"},
+					Key:                  "body.md",
+				},
+			}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.descriptionFrom")))
+		})
+	})
+
+	Context("When credentials are referenced in another namespace", func() {
+		It("rejects Secrets outside of the central secrets namespace", func() {
+			validator.CentralSecretsNamespace = "secrets"
+			obj.Spec.CredentialsSecretRef = &issuesv1alpha1.CredentialsSecretReference{Namespace: "secrets", Name: "token", Key: "token"}
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Mirrors = []issuesv1alpha1.IssueMirror{{
+				Repo:                 "https://github.com/org/mirror",
+				CredentialsSecretRef: &issuesv1alpha1.CredentialsSecretReference{Namespace: "team-b", Name: "token", Key: "token"},
+			}}
+			_, err = validator.ValidateCreate(context.Background(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.mirrors[0].credentialsSecretRef.namespace")))
+		})
+	})
+
+	Context("When the namespace is not allowed the repository", func() {
+		It("rejects the GithubIssue as forbidden", func() {
+			validator.Authorizer = authorizerFunc(func(_ context.Context, githubIssue *issuesv1alpha1.GithubIssue) error {
+				return fmt.Errorf("no RepositoryBinding allows namespace %s to file issues in %s", githubIssue.Namespace, githubIssue.Spec.Repo)
+			})
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("no RepositoryBinding allows namespace default")))
+		})
+	})
+})
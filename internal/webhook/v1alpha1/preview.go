@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+)
+
+// previewBodyLength caps the body shown in the preview: the API server truncates long warnings.
+const previewBodyLength = 200
+
+// Previewer renders the issue the operator would send to GitHub for a GithubIssue.
+type Previewer interface {
+	Preview(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) (*git.DesiredIssue, error)
+}
+
+// preview returns the rendered GitHub payload as admission warnings on dry-run requests
+// (kubectl --dry-run=server). A preview that cannot be rendered is reported as a warning too.
+func (v *GithubIssueCustomValidator) preview(ctx context.Context, githubIssue *issuesv1alpha1.GithubIssue) []string {
+	if v.Previewer == nil {
+		return nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || !ptr.Deref(req.DryRun, false) {
+		return nil
+	}
+
+	desired, err := v.Previewer.Preview(ctx, githubIssue)
+	if err != nil {
+		v.Log.Debug("Failed to render the GitHub preview", zap.String("name", githubIssue.GetName()), zap.Error(err))
+		return []string{fmt.Sprintf("GitHub preview unavailable: %v", err)}
+	}
+	if source := githubIssue.Spec.DescriptionFrom; source != nil && source.SecretKeyRef != nil {
+		// Admission warnings reach users who may not be allowed to read the Secret.
+		desired.Body = fmt.Sprintf("(rendered from Secret %s, not shown)", source.SecretKeyRef.Name)
+	}
+	return previewWarnings(desired)
+}
+
+func previewWarnings(desired *git.DesiredIssue) []string {
+	body := desired.Body
+	if runes := []rune(body); len(runes) > previewBodyLength {
+		body = string(runes[:previewBodyLength]) + "..."
+	}
+	warnings := []string{
+		fmt.Sprintf("GitHub preview title: %s", desired.Title),
+		fmt.Sprintf("GitHub preview body: %s", body),
+	}
+	if len(desired.Labels) > 0 {
+		warnings = append(warnings, fmt.Sprintf("GitHub preview labels: %s", strings.Join(desired.Labels, ", ")))
+	}
+	if len(desired.Assignees) > 0 {
+		warnings = append(warnings, fmt.Sprintf("GitHub preview assignees: %s", strings.Join(desired.Assignees, ", ")))
+	}
+	if desired.Type != "" {
+		warnings = append(warnings, fmt.Sprintf("GitHub preview type: %s", desired.Type))
+	}
+	return warnings
+}
@@ -0,0 +1,134 @@
+//go:build e2e
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package e2e runs the GithubIssue lifecycle against a real GitHub repository.
+//
+// The suite needs a scratch repository it is free to open and close issues in:
+//
+//	E2E_GITHUB_TOKEN=<token> E2E_GITHUB_REPO=https://github.com/<owner>/<repo> make test-e2e
+//
+// The API server and etcd are started with envtest, so KUBEBUILDER_ASSETS must point at their binaries.
+package e2e
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/controller"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/git"
+	"github.com/matanamar10/github-issue-operator-hhome-assignment/internal/logging"
+)
+
+const (
+	namespace = "e2e"
+	// resyncPeriod is short so drift is corrected within the test timeouts.
+	resyncPeriod = 10 * time.Second
+)
+
+var (
+	k8sClient client.Client
+	gh        *github.Client
+	repoURL   string
+	owner     string
+	repo      string
+	testEnv   *envtest.Environment
+	ctx       context.Context
+	cancel    context.CancelFunc
+)
+
+func TestE2E(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GitHub Conformance Suite")
+}
+
+var _ = BeforeSuite(func() {
+	token := os.Getenv("E2E_GITHUB_TOKEN")
+	repoURL = os.Getenv("E2E_GITHUB_REPO")
+	if token == "" || repoURL == "" {
+		Skip("E2E_GITHUB_TOKEN and E2E_GITHUB_REPO must be set to run the conformance suite")
+	}
+	var err error
+	owner, repo, err = git.ParseRepoURL(repoURL)
+	Expect(err).NotTo(HaveOccurred())
+	gh = github.NewClient(nil).WithAuthToken(token)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	By("bootstrapping test environment")
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(issuesv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(Succeed())
+
+	syncPeriod := resyncPeriod
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		Cache:   cache.Options{SyncPeriod: &syncPeriod},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	operatorLog, err := logging.New(logging.Options{Level: "debug"})
+	Expect(err).NotTo(HaveOccurred())
+	Expect((&controller.GithubIssueReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Clients:  &git.Clients{Default: &git.GitHubIssueClient{Client: gh}},
+		Log:      operatorLog.Logger,
+		Recorder: mgr.GetEventRecorderFor("githubissue-controller"),
+	}).SetupWithManager(mgr)).To(Succeed())
+
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed(), "failed to run manager")
+	}()
+})
+
+var _ = AfterSuite(func() {
+	if testEnv == nil || cancel == nil {
+		return
+	}
+	cancel()
+	By("tearing down the test environment")
+	Expect(testEnv.Stop()).To(Succeed())
+})
@@ -0,0 +1,162 @@
+//go:build e2e
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	issuesv1alpha1 "github.com/matanamar10/github-issue-operator-hhome-assignment/api/v1alpha1"
+)
+
+var (
+	timeout  = 2 * time.Minute
+	interval = 2 * time.Second
+)
+
+// newIssue returns a GithubIssue with a title unique to this run, so runs never adopt each other's issues.
+func newIssue(description string) *issuesv1alpha1.GithubIssue {
+	suffix := rand.String(8)
+	return &issuesv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{Name: "e2e-" + suffix, Namespace: namespace},
+		Spec: issuesv1alpha1.GithubIssueSpec{
+			Repo:        repoURL,
+			Title:       fmt.Sprintf("e2e conformance %s", suffix),
+			Description: description,
+		},
+	}
+}
+
+// upstream waits for the GithubIssue to record its upstream issue and returns it.
+func upstream(issueObject *issuesv1alpha1.GithubIssue) *github.Issue {
+	var number int
+	Eventually(func(g Gomega) {
+		current := &issuesv1alpha1.GithubIssue{}
+		g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: issueObject.Name, Namespace: namespace}, current)).To(Succeed())
+		g.Expect(current.Status.IssueNumber).NotTo(BeZero())
+		number = current.Status.IssueNumber
+	}, timeout, interval).Should(Succeed())
+	issue, _, err := gh.Issues.Get(ctx, owner, repo, number)
+	Expect(err).NotTo(HaveOccurred())
+	return issue
+}
+
+// eventuallyUpstream polls the upstream issue until check passes.
+func eventuallyUpstream(number int, check func(g Gomega, issue *github.Issue)) {
+	Eventually(func(g Gomega) {
+		issue, _, err := gh.Issues.Get(ctx, owner, repo, number)
+		g.Expect(err).NotTo(HaveOccurred())
+		check(g, issue)
+	}, timeout, interval).Should(Succeed())
+}
+
+func labelNames(issue *github.Issue) []string {
+	names := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
+
+// deleteAndWaitClosed deletes the GithubIssue and waits for its upstream issue to be closed.
+func deleteAndWaitClosed(issueObject *issuesv1alpha1.GithubIssue, number int) {
+	Expect(k8sClient.Delete(ctx, issueObject)).To(Succeed())
+	eventuallyUpstream(number, func(g Gomega, issue *github.Issue) {
+		g.Expect(issue.GetState()).To(Equal("closed"))
+	})
+	Eventually(func() bool {
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: issueObject.Name, Namespace: namespace}, &issuesv1alpha1.GithubIssue{})
+		return k8serrors.IsNotFound(err)
+	}, timeout, interval).Should(BeTrue())
+}
+
+var _ = Describe("GitHub conformance", Ordered, func() {
+	It("creates, edits and closes an issue", func() {
+		issueObject := newIssue("created by the conformance suite")
+		Expect(k8sClient.Create(ctx, issueObject)).To(Succeed())
+		issue := upstream(issueObject)
+		Expect(issue.GetTitle()).To(Equal(issueObject.Spec.Title))
+		Expect(issue.GetBody()).To(ContainSubstring("created by the conformance suite"))
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: issueObject.Name, Namespace: namespace}, issueObject)).To(Succeed())
+		issueObject.Spec.Description = "edited by the conformance suite"
+		Expect(k8sClient.Update(ctx, issueObject)).To(Succeed())
+		eventuallyUpstream(issue.GetNumber(), func(g Gomega, issue *github.Issue) {
+			g.Expect(issue.GetBody()).To(ContainSubstring("edited by the conformance suite"))
+		})
+
+		deleteAndWaitClosed(issueObject, issue.GetNumber())
+	})
+
+	It("applies spec labels", func() {
+		issueObject := newIssue("labeled by the conformance suite")
+		issueObject.Spec.Labels = []string{"e2e"}
+		Expect(k8sClient.Create(ctx, issueObject)).To(Succeed())
+		issue := upstream(issueObject)
+		eventuallyUpstream(issue.GetNumber(), func(g Gomega, issue *github.Issue) {
+			g.Expect(labelNames(issue)).To(ContainElement("e2e"))
+		})
+
+		deleteAndWaitClosed(issueObject, issue.GetNumber())
+	})
+
+	It("adopts an existing issue with the same title", func() {
+		issueObject := newIssue("adopted by the conformance suite")
+		existing, _, err := gh.Issues.Create(ctx, owner, repo, &github.IssueRequest{
+			Title: github.String(issueObject.Spec.Title),
+			Body:  github.String("opened outside of the operator"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Create(ctx, issueObject)).To(Succeed())
+		Expect(upstream(issueObject).GetNumber()).To(Equal(existing.GetNumber()))
+		eventuallyUpstream(existing.GetNumber(), func(g Gomega, issue *github.Issue) {
+			g.Expect(issue.GetBody()).To(ContainSubstring("adopted by the conformance suite"))
+		})
+
+		deleteAndWaitClosed(issueObject, existing.GetNumber())
+	})
+
+	It("corrects drift of the upstream issue", func() {
+		issueObject := newIssue("restored by the conformance suite")
+		Expect(k8sClient.Create(ctx, issueObject)).To(Succeed())
+		issue := upstream(issueObject)
+		eventuallyUpstream(issue.GetNumber(), func(g Gomega, issue *github.Issue) {
+			g.Expect(issue.GetBody()).To(ContainSubstring("restored by the conformance suite"))
+		})
+
+		_, _, err := gh.Issues.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{
+			Body: github.String("edited outside of the operator"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		eventuallyUpstream(issue.GetNumber(), func(g Gomega, issue *github.Issue) {
+			g.Expect(issue.GetBody()).To(ContainSubstring("restored by the conformance suite"))
+		})
+
+		deleteAndWaitClosed(issueObject, issue.GetNumber())
+	})
+})